@@ -16,9 +16,12 @@ import (
 	"github.com/talos-systems/go-cmd/pkg/cmd"
 
 	"github.com/talos-systems/talos/cmd/installer/pkg"
+	"github.com/talos-systems/talos/cmd/installer/pkg/gcp"
 	"github.com/talos-systems/talos/cmd/installer/pkg/install"
 	"github.com/talos-systems/talos/cmd/installer/pkg/ova"
-	"github.com/talos-systems/talos/cmd/installer/pkg/qemuimg"
+	"github.com/talos-systems/talos/cmd/installer/pkg/qcow2"
+	"github.com/talos-systems/talos/cmd/installer/pkg/vhd"
+	"github.com/talos-systems/talos/cmd/installer/pkg/vhdx"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/platform"
 	"github.com/talos-systems/talos/pkg/archiver"
@@ -28,8 +31,23 @@ import (
 var (
 	outputArg   string
 	tarToStdout bool
+	imageFormat string
 )
 
+// imageFormats lists the formats of the images which can be requested explicitly.
+var imageFormats = map[string]struct {
+	extension string
+	write     pkg.ImageWriter
+	// diskSize is the size of the raw disk in MiB, if it is required by the format
+	diskSize int
+}{
+	"raw":   {extension: "raw"},
+	"vhd":   {extension: "vhd", write: vhd.Write},
+	"vhdx":  {extension: "vhdx", write: vhdx.Write},
+	"qcow2": {extension: "qcow2", write: qcow2.Write},
+	"gcp":   {extension: "tar.gz", write: gcp.Write, diskSize: gcp.Alignment >> 20},
+}
+
 // imageCmd represents the image command.
 var imageCmd = &cobra.Command{
 	Use:   "image",
@@ -45,6 +63,7 @@ var imageCmd = &cobra.Command{
 func init() {
 	imageCmd.Flags().StringVar(&outputArg, "output", "/out", "The output path")
 	imageCmd.Flags().BoolVar(&tarToStdout, "tar-to-stdout", false, "Tar output and send to stdout")
+	imageCmd.Flags().StringVar(&imageFormat, "image-format", "", "The image format: raw, vhd (Azure), vhdx (Hyper-V), qcow2 (OpenNebula, KVM) or gcp (disk.raw in tar.gz); defaults to the platform format")
 	rootCmd.AddCommand(imageCmd)
 }
 
@@ -59,11 +78,26 @@ func runImageCmd() (err error) {
 		return err
 	}
 
+	diskSize := pkg.RAWDiskSize
+
+	if imageFormat != "" {
+		format, ok := imageFormats[imageFormat]
+		if !ok {
+			return fmt.Errorf("unsupported image format %q", imageFormat)
+		}
+
+		if format.diskSize != 0 {
+			diskSize = format.diskSize
+		}
+	} else if p.Name() == "gcp" {
+		diskSize = imageFormats["gcp"].diskSize
+	}
+
 	log.Printf("creating image for %s", p.Name())
 
 	log.Print("creating RAW disk")
 
-	img, err := pkg.CreateRawDisk()
+	img, err := pkg.CreateRawDisk(diskSize)
 	if err != nil {
 		return err
 	}
@@ -96,7 +130,13 @@ func runImageCmd() (err error) {
 		return err
 	}
 
-	if err := finalize(p, img, options.Arch); err != nil {
+	if imageFormat != "" {
+		err = convert(p, img, options.Arch)
+	} else {
+		err = finalize(p, img, options.Arch)
+	}
+
+	if err != nil {
 		return err
 	}
 
@@ -124,7 +164,7 @@ func finalize(platform runtime.Platform, img, arch string) (err error) {
 	case "azure":
 		file = name + ".vhd"
 
-		if err = qemuimg.Convert("raw", "vpc", "subformat=fixed,force_size", img, filepath.Join(dir, file)); err != nil {
+		if err = pkg.ConvertRawDisk(img, filepath.Join(dir, file), vhd.Write); err != nil {
 			return err
 		}
 
@@ -136,7 +176,7 @@ func finalize(platform runtime.Platform, img, arch string) (err error) {
 			return err
 		}
 	case "gcp":
-		if err = tar(fmt.Sprintf("gcp-%s.tar.gz", arch), file, dir); err != nil {
+		if err = pkg.ConvertRawDisk(img, filepath.Join(outputArg, fmt.Sprintf("gcp-%s.tar.gz", arch)), gcp.Write); err != nil {
 			return err
 		}
 	case "openstack":
@@ -177,6 +217,20 @@ func finalize(platform runtime.Platform, img, arch string) (err error) {
 	return nil
 }
 
+// convert writes the image in the explicitly requested format.
+func convert(platform runtime.Platform, img, arch string) error {
+	format := imageFormats[imageFormat]
+	dest := filepath.Join(outputArg, fmt.Sprintf("%s-%s.%s", platform.Name(), arch, format.extension))
+
+	log.Printf("writing %s image %s", imageFormat, dest)
+
+	if format.write == nil {
+		return os.Rename(img, dest)
+	}
+
+	return pkg.ConvertRawDisk(img, dest, format.write)
+}
+
 func tar(filename, src, dir string) error {
 	if _, err := cmd.Run("tar", "-czvf", filepath.Join(outputArg, filename), src, "-C", dir); err != nil {
 		return err
@@ -78,6 +78,15 @@ func runInstallCmd() (err error) {
 		if config.Machine().Install().LegacyBIOSSupport() {
 			options.LegacyBIOSSupport = true
 		}
+
+		options.EphemeralSize = config.Machine().Install().EphemeralSize()
+
+		for _, part := range config.Machine().Install().ExtraPartitions() {
+			options.ExtraPartitions = append(options.ExtraPartitions, install.ExtraPartition{
+				Label: part.Label(),
+				Size:  part.Size(),
+			})
+		}
 	}
 
 	return install.Install(p, seq, options)
@@ -57,8 +57,8 @@ func runISOCmd() error {
 	}
 
 	files := map[string]string{
-		fmt.Sprintf("/usr/install/%s/vmlinuz", options.Arch):      "/mnt/boot/vmlinuz",
-		fmt.Sprintf("/usr/install/%s/initramfs.xz", options.Arch): "/mnt/boot/initramfs.xz",
+		fmt.Sprintf("/usr/install/%s/vmlinuz", options.Arch): "/mnt/boot/vmlinuz",
+		options.InitramfsPath():                              "/mnt/boot/initramfs.xz",
 	}
 
 	for src, dest := range files {
@@ -20,6 +20,9 @@ var rootCmd = &cobra.Command{
 	Use:   "installer",
 	Short: "",
 	Long:  ``,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return install.PrepareExtensions(options)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -43,5 +46,7 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&options.Bootloader, "bootloader", true, "Install a booloader to the specified disk")
 	rootCmd.PersistentFlags().BoolVar(&options.Upgrade, "upgrade", false, "Indicates that the install is being performed by an upgrade")
 	rootCmd.PersistentFlags().BoolVar(&options.Force, "force", false, "Indicates that the install should forcefully format the partition")
+	rootCmd.PersistentFlags().StringArrayVar(&options.Extensions, "extension", []string{}, "The path to the signed system extension image to layer on top of the rootfs")
+	rootCmd.PersistentFlags().StringArrayVar(&options.ExtensionKeys, "extension-key", []string{}, "The path to the public key to verify system extensions")
 	rootCmd.PersistentFlags().BoolVar(&options.Zero, "zero", false, "Indicates that the install should write zeros to the disk before installing")
 }
@@ -0,0 +1,55 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package gcp implements writing images importable to Google Compute Engine.
+//
+// Image format reference: https://cloud.google.com/compute/docs/import/import-existing-image#requirements.
+package gcp
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// DiskName is the name of the disk image in the archive required by GCE.
+	DiskName = "disk.raw"
+
+	// Alignment of the disk size required by GCE.
+	Alignment = 1 << 30
+)
+
+// Write the disk as disk.raw in gzip compressed GNU tar archive.
+func Write(w io.Writer, src io.ReaderAt, size int64) error {
+	if size%Alignment != 0 {
+		return fmt.Errorf("disk size %d is not aligned to %d bytes", size, Alignment)
+	}
+
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     DiskName,
+		Size:     size,
+		Mode:     0o644,
+		ModTime:  time.Now(),
+		Format:   tar.FormatGNU,
+	}); err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(tw, io.NewSectionReader(src, 0, size)); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
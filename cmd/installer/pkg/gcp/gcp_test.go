@@ -0,0 +1,77 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package gcp_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/cmd/installer/pkg/gcp"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	disk, err := os.Create(filepath.Join(dir, "disk.raw"))
+	require.NoError(t, err)
+
+	defer disk.Close() //nolint:errcheck
+
+	size := int64(gcp.Alignment)
+
+	require.NoError(t, disk.Truncate(size))
+
+	data := make([]byte, 1<<20)
+	rand.Read(data) //nolint:errcheck
+
+	_, err = disk.WriteAt(data, 0)
+	require.NoError(t, err)
+
+	_, err = disk.WriteAt(data, size-int64(len(data)))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, gcp.Write(&buf, disk, size))
+
+	zr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+
+	tr := tar.NewReader(zr)
+
+	hdr, err := tr.Next()
+	require.NoError(t, err)
+
+	assert.Equal(t, gcp.DiskName, hdr.Name)
+	assert.Equal(t, size, hdr.Size)
+	assert.Equal(t, tar.FormatGNU, hdr.Format)
+
+	expected := make([]byte, 1<<20)
+	actual := make([]byte, 1<<20)
+
+	for offset := int64(0); offset < size; offset += int64(len(expected)) {
+		_, err = disk.ReadAt(expected, offset)
+		require.NoError(t, err)
+
+		_, err = io.ReadFull(tr, actual)
+		require.NoError(t, err)
+
+		require.True(t, bytes.Equal(expected, actual), "chunk at %d", offset)
+	}
+
+	_, err = tr.Next()
+	assert.Equal(t, io.EOF, err)
+
+	assert.Error(t, gcp.Write(&buf, disk, size-512))
+}
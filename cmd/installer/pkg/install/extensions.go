@@ -0,0 +1,195 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package install
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/talos-systems/go-cmd/pkg/cmd"
+
+	"github.com/talos-systems/talos/internal/pkg/extensions"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// InitramfsPath returns the path to the initramfs to install.
+func (opts *Options) InitramfsPath() string {
+	if opts.Initramfs != "" {
+		return opts.Initramfs
+	}
+
+	return fmt.Sprintf(constants.InitramfsAssetPath, opts.Arch)
+}
+
+// PrepareExtensions verifies the system extensions and layers them into the copy of the initramfs.
+//
+// Extension images are taken from the options and from the installer image extensions directory,
+// signatures are verified against the keys from the options and from the installer image keys directory.
+//
+//nolint:gocyclo
+func PrepareExtensions(opts *Options) error {
+	images := append([]string(nil), opts.Extensions...)
+
+	builtin, err := filepath.Glob(filepath.Join(fmt.Sprintf(constants.SystemExtensionsPath, opts.Arch), "*.sqsh"))
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(builtin)
+
+	images = append(images, builtin...)
+
+	if len(images) == 0 {
+		return nil
+	}
+
+	keyPaths := append([]string(nil), opts.ExtensionKeys...)
+
+	builtinKeys, err := filepath.Glob(filepath.Join(constants.SystemExtensionKeysPath, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	keyPaths = append(keyPaths, builtinKeys...)
+
+	if len(keyPaths) == 0 {
+		return fmt.Errorf("no keys to verify system extensions")
+	}
+
+	keys := make([][]byte, 0, len(keyPaths))
+
+	for _, path := range keyPaths {
+		var key []byte
+
+		if key, err = ioutil.ReadFile(path); err != nil {
+			return err
+		}
+
+		if _, err = extensions.ParsePublicKey(key); err != nil {
+			return fmt.Errorf("error loading %q: %w", path, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	config := &extensions.Config{}
+	sources := map[string]string{}
+
+	for _, image := range images {
+		var ext *extensions.Extension
+
+		if ext, err = inspectExtension(image, keys); err != nil {
+			return fmt.Errorf("error verifying extension %q: %w", image, err)
+		}
+
+		if _, ok := sources[ext.Image]; ok {
+			return fmt.Errorf("duplicate extension %q", ext.Metadata.Name)
+		}
+
+		sources[ext.Image] = image
+
+		config.Extensions = append(config.Extensions, *ext)
+
+		log.Printf("adding extension %q version %s", ext.Metadata.Name, ext.Metadata.Version)
+	}
+
+	initramfs, err := ioutil.TempFile("", "initramfs")
+	if err != nil {
+		return err
+	}
+
+	defer initramfs.Close() //nolint:errcheck
+
+	if err = copyFile(initramfs, opts.InitramfsPath()); err != nil {
+		return err
+	}
+
+	if err = initramfs.Close(); err != nil {
+		return err
+	}
+
+	// keys trusted by the installer are trusted on boot, keys are never taken from the extensions config
+	if err = extensions.AppendArchive(initramfs.Name(), config, sources, keys); err != nil {
+		return fmt.Errorf("error appending extensions to the initramfs: %w", err)
+	}
+
+	opts.Initramfs = initramfs.Name()
+
+	return nil
+}
+
+const squashfsRoot = "squashfs-root"
+
+func inspectExtension(image string, keys [][]byte) (*extensions.Extension, error) {
+	digest, err := extensions.Digest(image)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := ioutil.ReadFile(image + extensions.SignatureSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("error reading signature: %w", err)
+	}
+
+	if _, err = extensions.Verify(digest, signature, keys); err != nil {
+		return nil, err
+	}
+
+	manifestData, err := cmd.Run("unsquashfs", "-cat", image, extensions.ManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	manifest, err := extensions.ParseManifest([]byte(manifestData))
+	if err != nil {
+		return nil, err
+	}
+
+	listing, err := cmd.Run("unsquashfs", "-l", image)
+	if err != nil {
+		return nil, fmt.Errorf("error listing contents: %w", err)
+	}
+
+	var paths []string
+
+	// unsquashfs lists paths prefixed with the default extraction directory
+	for _, line := range strings.Split(listing, "\n") {
+		if strings.HasPrefix(line, squashfsRoot) {
+			paths = append(paths, "/"+strings.TrimPrefix(line, squashfsRoot))
+		}
+	}
+
+	if err = extensions.ValidateLayout(paths); err != nil {
+		return nil, err
+	}
+
+	return &extensions.Extension{
+		Image:     manifest.Metadata.Name + ".sqsh",
+		Metadata:  manifest.Metadata,
+		Digest:    hex.EncodeToString(digest),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	}, nil
+}
+
+func copyFile(dst io.Writer, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	_, err = io.Copy(dst, f)
+
+	return err
+}
@@ -37,6 +37,17 @@ type Options struct {
 	Force             bool
 	Zero              bool
 	LegacyBIOSSupport bool
+	EphemeralSize     uint64
+	ExtraPartitions   []ExtraPartition
+	Extensions        []string
+	ExtensionKeys     []string
+	Initramfs         string
+}
+
+// ExtraPartition describes an extra partition created on the install disk after EPHEMERAL.
+type ExtraPartition struct {
+	Label string
+	Size  uint64
 }
 
 // Install installs Talos.
@@ -129,7 +129,7 @@ func NewManifest(label string, sequence runtime.Sequence, bootPartitionFound boo
 					Destination: filepath.Join(constants.BootMountPoint, label, constants.KernelAsset),
 				},
 				{
-					Source:      fmt.Sprintf(constants.InitramfsAssetPath, opts.Arch),
+					Source:      opts.InitramfsPath(),
 					Destination: filepath.Join(constants.BootMountPoint, label, constants.InitramfsAsset),
 				},
 			},
@@ -148,6 +148,7 @@ func NewManifest(label string, sequence runtime.Sequence, bootPartitionFound boo
 	})
 
 	ephemeralTarget := EphemeralTarget(opts.Disk, NoFilesystem)
+	ephemeralTarget.Size = opts.EphemeralSize
 
 	targets := []*Target{efiTarget, biosTarget, bootTarget, metaTarget, stateTarget, ephemeralTarget}
 
@@ -158,6 +159,11 @@ func NewManifest(label string, sequence runtime.Sequence, bootPartitionFound boo
 		}
 	}
 
+	// extra partitions are kept if they exist, and created otherwise
+	for _, part := range opts.ExtraPartitions {
+		targets = append(targets, ExtraPartitionTarget(opts.Disk, part))
+	}
+
 	for _, target := range targets {
 		if target == nil {
 			continue
@@ -309,7 +315,18 @@ func (m *Manifest) executeOnDevice(device Device, targets []*Target) (err error)
 	}
 
 	if !created {
-		if device.ResetPartitionTable {
+		keepExisting := false
+
+		for _, target := range targets {
+			if target.PreserveExisting && pt.Partitions().FindByName(target.Label) != nil {
+				log.Printf("keeping existing partition %s", target.Label)
+
+				target.Skip = true
+				keepExisting = true
+			}
+		}
+
+		if device.ResetPartitionTable && !keepExisting {
 			log.Printf("resetting partition table on %s", device.Device)
 
 			// TODO: how should it work with zero option above?
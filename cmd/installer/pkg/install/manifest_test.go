@@ -324,6 +324,94 @@ func (suite *manifestSuite) TestExecuteManifestPreserve() {
 	suite.verifyBlockdevice(manifest, "A", "B", true, true)
 }
 
+func (suite *manifestSuite) TestExecuteManifestExtraPartitions() {
+	suite.skipUnderBuildkit()
+
+	const ephemeralSize = 1024 * 1024 * 1024
+
+	opts := &install.Options{
+		Disk:          suite.loopbackDevice.Name(),
+		Bootloader:    true,
+		Force:         true,
+		Board:         constants.BoardNone,
+		EphemeralSize: ephemeralSize,
+		ExtraPartitions: []install.ExtraPartition{
+			{
+				Label: "DATA",
+				Size:  512 * 1024 * 1024,
+			},
+			{
+				Label: "CEPH",
+			},
+		},
+	}
+
+	verify := func() string {
+		bd, err := blockdevice.Open(suite.loopbackDevice.Name())
+		suite.Require().NoError(err)
+
+		defer bd.Close() //nolint:errcheck
+
+		table, err := bd.PartitionTable()
+		suite.Require().NoError(err)
+
+		suite.Require().Len(table.Partitions().Items(), 8)
+
+		ephemeral := table.Partitions().Items()[5]
+		suite.Assert().Equal(constants.EphemeralPartitionLabel, ephemeral.Name)
+		suite.Assert().EqualValues(ephemeralSize/lbaSize, ephemeral.Length())
+
+		data := table.Partitions().Items()[6]
+		suite.Assert().Equal("DATA", data.Name)
+		suite.Assert().EqualValues(512*1024*1024/lbaSize, data.Length())
+		suite.Assert().Greater(data.FirstLBA, ephemeral.LastLBA)
+
+		ceph := table.Partitions().Items()[7]
+		suite.Assert().Equal("CEPH", ceph.Name)
+		suite.Assert().Greater(ceph.FirstLBA, data.LastLBA)
+
+		return fmt.Sprintf("%sp%d", suite.loopbackDevice.Name(), data.Number)
+	}
+
+	manifest, err := install.NewManifest("A", runtime.SequenceInstall, false, opts)
+	suite.Require().NoError(err)
+
+	// in the tests overlay mounts should be ignored
+	dev := manifest.Devices[suite.loopbackDevice.Name()]
+	dev.SkipOverlayMountsCheck = true
+	manifest.Devices[suite.loopbackDevice.Name()] = dev
+
+	suite.Require().NoError(manifest.Execute())
+
+	dataPath := verify()
+
+	suite.Require().NoError(ioutil.WriteFile(dataPath, []byte("precious"), 0))
+
+	// upgrade without preserve recreates system partitions, but keeps extra partitions
+
+	manifest, err = install.NewManifest("B", runtime.SequenceUpgrade, true, opts)
+	suite.Require().NoError(err)
+
+	dev = manifest.Devices[suite.loopbackDevice.Name()]
+	dev.SkipOverlayMountsCheck = true
+	manifest.Devices[suite.loopbackDevice.Name()] = dev
+
+	suite.Require().NoError(manifest.Execute())
+
+	dataPath = verify()
+
+	f, err := os.Open(dataPath)
+	suite.Require().NoError(err)
+
+	buf := make([]byte, len("precious"))
+
+	_, err = io.ReadFull(f, buf)
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal("precious", string(buf))
+	suite.Assert().NoError(f.Close())
+}
+
 func (suite *manifestSuite) TestTargetInstall() {
 	// Create Temp dirname for mountpoint
 	dir, err := ioutil.TempDir("", "talostest")
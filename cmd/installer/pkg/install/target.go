@@ -50,6 +50,10 @@ type Target struct {
 	// Skipped partitions should exist on the disk by the time manifest execution starts.
 	Skip bool
 
+	// PreserveExisting makes manifest skip the partition if it already exists on the disk,
+	// even if the partition table is reset.
+	PreserveExisting bool
+
 	// set during execution
 	PartitionName string
 	Contents      *bytes.Buffer
@@ -136,6 +140,23 @@ func EphemeralTarget(device string, extra *Target) *Target {
 	return target.enhance(extra)
 }
 
+// ExtraPartitionTarget builds the target for the extra partition which follows the ephemeral partition.
+//
+// Extra partitions are created without a filesystem and are never recreated if they exist.
+func ExtraPartitionTarget(device string, part ExtraPartition) *Target {
+	return &Target{
+		FormatOptions: &partition.FormatOptions{
+			Label:          part.Label,
+			PartitionType:  partition.LinuxFilesystemData,
+			FileSystemType: partition.FilesystemTypeNone,
+			Size:           part.Size,
+			FastWipe:       true,
+		},
+		Device:           device,
+		PreserveExisting: true,
+	}
+}
+
 func (t *Target) enhance(extra *Target) *Target {
 	if extra == nil {
 		return t
@@ -0,0 +1,198 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package qcow2 implements writing qcow2 (version 2) images.
+//
+// qcow2 format reference: https://github.com/qemu/qemu/blob/master/docs/interop/qcow2.txt.
+package qcow2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const (
+	// ClusterBits is the log2 of the cluster size.
+	ClusterBits = 16
+	// ClusterSize is the size of the cluster.
+	ClusterSize = 1 << ClusterBits
+
+	magic   = 0x514649fb // "QFI\xfb"
+	version = 2
+
+	// l2Entries is the number of entries in the L2 table.
+	l2Entries = ClusterSize / 8
+	// refcountEntries is the number of 16-bit refcounts in the refcount block.
+	refcountEntries = ClusterSize / 2
+
+	// oflagCopied marks the clusters with refcount one.
+	oflagCopied = 1 << 63
+)
+
+// layout of the image, all sizes are in clusters.
+type layout struct {
+	refcountTable  int64
+	refcountBlocks int64
+	l1Table        int64
+	l2Tables       int64
+	data           int64
+}
+
+func (l layout) total() int64 {
+	return 1 + l.refcountTable + l.refcountBlocks + l.l1Table + l.l2Tables + l.data
+}
+
+// Write the qcow2 image, only non-zero clusters are stored in the image.
+//
+// The image is laid out as: header, refcount table, refcount blocks, L1 table, L2 tables, data clusters.
+//
+//nolint:gocyclo
+func Write(w io.Writer, src io.ReaderAt, size int64) error {
+	clusters := (size + ClusterSize - 1) / ClusterSize
+	cluster := make([]byte, ClusterSize)
+
+	// first pass: find the clusters which should be stored
+	present := make([]bool, clusters)
+	l1Size := (clusters + l2Entries - 1) / l2Entries
+	l2Present := make([]bool, l1Size)
+
+	l := layout{
+		l1Table: (l1Size*8 + ClusterSize - 1) / ClusterSize,
+	}
+
+	for i := range present {
+		n, err := readCluster(src, cluster, int64(i), size)
+		if err != nil {
+			return err
+		}
+
+		if isZero(cluster[:n]) {
+			continue
+		}
+
+		present[i] = true
+		l.data++
+
+		if !l2Present[i/l2Entries] {
+			l2Present[i/l2Entries] = true
+			l.l2Tables++
+		}
+	}
+
+	// refcount blocks cover all the clusters including themselves, so iterate until the layout settles
+	for {
+		refcountBlocks := (l.total() + refcountEntries - 1) / refcountEntries
+		refcountTable := (refcountBlocks*8 + ClusterSize - 1) / ClusterSize
+
+		if refcountBlocks == l.refcountBlocks && refcountTable == l.refcountTable {
+			break
+		}
+
+		l.refcountBlocks, l.refcountTable = refcountBlocks, refcountTable
+	}
+
+	refcountTableOffset := int64(ClusterSize)
+	refcountBlocksOffset := refcountTableOffset + l.refcountTable*ClusterSize
+	l1TableOffset := refcountBlocksOffset + l.refcountBlocks*ClusterSize
+	l2TablesOffset := l1TableOffset + l.l1Table*ClusterSize
+	dataOffset := l2TablesOffset + l.l2Tables*ClusterSize
+
+	header := make([]byte, ClusterSize)
+
+	binary.BigEndian.PutUint32(header[0:4], magic)
+	binary.BigEndian.PutUint32(header[4:8], version)
+	binary.BigEndian.PutUint32(header[20:24], ClusterBits)
+	binary.BigEndian.PutUint64(header[24:32], uint64(size))
+	binary.BigEndian.PutUint32(header[36:40], uint32(l1Size))
+	binary.BigEndian.PutUint64(header[40:48], uint64(l1TableOffset))
+	binary.BigEndian.PutUint64(header[48:56], uint64(refcountTableOffset))
+	binary.BigEndian.PutUint32(header[56:60], uint32(l.refcountTable))
+
+	refcountTable := make([]byte, l.refcountTable*ClusterSize)
+
+	for i := int64(0); i < l.refcountBlocks; i++ {
+		binary.BigEndian.PutUint64(refcountTable[i*8:], uint64(refcountBlocksOffset+i*ClusterSize))
+	}
+
+	// all the clusters of the image are used exactly once
+	refcountBlocks := make([]byte, l.refcountBlocks*ClusterSize)
+
+	for i := int64(0); i < l.total(); i++ {
+		binary.BigEndian.PutUint16(refcountBlocks[i*2:], 1)
+	}
+
+	l1Table := make([]byte, l.l1Table*ClusterSize)
+	l2Tables := make([]byte, l.l2Tables*ClusterSize)
+
+	l2Index := int64(-1)
+	offset := dataOffset
+
+	for i := range present {
+		if !present[i] {
+			continue
+		}
+
+		l1Index := int64(i / l2Entries)
+
+		if binary.BigEndian.Uint64(l1Table[l1Index*8:]) == 0 {
+			l2Index++
+
+			binary.BigEndian.PutUint64(l1Table[l1Index*8:], uint64(l2TablesOffset+l2Index*ClusterSize)|oflagCopied)
+		}
+
+		binary.BigEndian.PutUint64(l2Tables[l2Index*ClusterSize+int64(i%l2Entries)*8:], uint64(offset)|oflagCopied)
+
+		offset += ClusterSize
+	}
+
+	for _, b := range [][]byte{header, refcountTable, refcountBlocks, l1Table, l2Tables} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	// second pass: write the clusters
+	for i := range present {
+		if !present[i] {
+			continue
+		}
+
+		n, err := readCluster(src, cluster, int64(i), size)
+		if err != nil {
+			return err
+		}
+
+		// the last cluster is padded with zeroes
+		for j := n; j < len(cluster); j++ {
+			cluster[j] = 0
+		}
+
+		if _, err = w.Write(cluster); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readCluster(src io.ReaderAt, cluster []byte, index, size int64) (int, error) {
+	length := size - index*ClusterSize
+	if length > ClusterSize {
+		length = ClusterSize
+	}
+
+	n, err := src.ReadAt(cluster[:length], index*ClusterSize)
+	if err == io.EOF && int64(n) == length {
+		err = nil
+	}
+
+	return n, err
+}
+
+var zeroes = make([]byte, ClusterSize)
+
+func isZero(data []byte) bool {
+	return bytes.Equal(data, zeroes[:len(data)])
+}
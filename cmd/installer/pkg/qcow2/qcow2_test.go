@@ -0,0 +1,164 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package qcow2_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/cmd/installer/pkg/qcow2"
+)
+
+func TestWrite(t *testing.T) {
+	// spans two L2 tables, the last cluster is partial
+	size := int64(600<<20 + 4096)
+
+	disk, err := os.Create(filepath.Join(t.TempDir(), "disk.raw"))
+	require.NoError(t, err)
+
+	defer disk.Close() //nolint:errcheck
+
+	require.NoError(t, disk.Truncate(size))
+
+	for _, r := range [][2]int64{
+		{0, 100},
+		{1 << 20, 3 * qcow2.ClusterSize},
+		{513 << 20, qcow2.ClusterSize},
+		{size - 100, 100},
+	} {
+		data := make([]byte, r[1])
+		rand.Read(data) //nolint:errcheck
+
+		_, err = disk.WriteAt(data, r[0])
+		require.NoError(t, err)
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, qcow2.Write(&buf, disk, size))
+
+	image := buf.Bytes()
+
+	// header, refcount table, refcount block, L1 table, 2 L2 tables, 6 data clusters
+	assert.Len(t, image, 12*qcow2.ClusterSize)
+
+	assertEqual(t, disk, size, image)
+}
+
+func TestWriteEmpty(t *testing.T) {
+	size := int64(10 << 20)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, qcow2.Write(&buf, bytes.NewReader(make([]byte, size)), size))
+
+	// header, refcount table, refcount block, L1 table
+	assert.Len(t, buf.Bytes(), 4*qcow2.ClusterSize)
+
+	assertEqual(t, bytes.NewReader(make([]byte, size)), size, buf.Bytes())
+}
+
+// assertEqual compares the disk with the contents of the image cluster by cluster.
+func assertEqual(t *testing.T, disk io.ReaderAt, size int64, image []byte) {
+	virtualSize, clusters := parse(t, image)
+	require.EqualValues(t, size, virtualSize)
+
+	expected := make([]byte, qcow2.ClusterSize)
+
+	for offset := int64(0); offset < size; offset += qcow2.ClusterSize {
+		for i := range expected {
+			expected[i] = 0
+		}
+
+		_, err := disk.ReadAt(expected, offset)
+		if err != io.EOF {
+			require.NoError(t, err)
+		}
+
+		actual, ok := clusters[offset]
+		if !ok {
+			actual = make([]byte, qcow2.ClusterSize)
+		}
+
+		require.True(t, bytes.Equal(expected, actual), "cluster at %d", offset)
+	}
+}
+
+const copied = 1 << 63
+
+//nolint:gocyclo
+func parse(t *testing.T, image []byte) (uint64, map[int64][]byte) {
+	require.Equal(t, "QFI\xfb", string(image[0:4]))
+	require.EqualValues(t, 2, binary.BigEndian.Uint32(image[4:8]))
+	require.Zero(t, binary.BigEndian.Uint64(image[8:16]), "backing file")
+	require.Zero(t, binary.BigEndian.Uint32(image[32:36]), "encryption")
+	require.Zero(t, binary.BigEndian.Uint32(image[60:64]), "snapshots")
+
+	clusterBits := binary.BigEndian.Uint32(image[20:24])
+	clusterSize := uint64(1) << clusterBits
+	size := binary.BigEndian.Uint64(image[24:32])
+	l1Size := uint64(binary.BigEndian.Uint32(image[36:40]))
+	l1TableOffset := binary.BigEndian.Uint64(image[40:48])
+	refcountTableOffset := binary.BigEndian.Uint64(image[48:56])
+	refcountTableClusters := uint64(binary.BigEndian.Uint32(image[56:60]))
+
+	require.Zero(t, uint64(len(image))%clusterSize)
+
+	// every cluster of the image should be referenced exactly once
+	refcount := func(offset uint64) uint16 {
+		index := offset / clusterSize
+		refcountEntries := clusterSize / 2
+
+		blockOffset := binary.BigEndian.Uint64(image[refcountTableOffset+index/refcountEntries*8:])
+		require.NotZero(t, blockOffset)
+
+		return binary.BigEndian.Uint16(image[blockOffset+index%refcountEntries*2:])
+	}
+
+	for offset := uint64(0); offset < uint64(len(image)); offset += clusterSize {
+		assert.EqualValues(t, 1, refcount(offset), "cluster at %d", offset)
+	}
+
+	assert.Zero(t, refcount(uint64(len(image))), "cluster past the end")
+	assert.Less(t, uint64(0), refcountTableClusters)
+
+	l2Entries := clusterSize / 8
+	clusters := map[int64][]byte{}
+
+	for i := uint64(0); i < l1Size; i++ {
+		l1Entry := binary.BigEndian.Uint64(image[l1TableOffset+i*8:])
+		if l1Entry == 0 {
+			continue
+		}
+
+		require.NotZero(t, l1Entry&copied)
+
+		l2TableOffset := l1Entry &^ copied
+
+		for j := uint64(0); j < l2Entries; j++ {
+			l2Entry := binary.BigEndian.Uint64(image[l2TableOffset+j*8:])
+			if l2Entry == 0 {
+				continue
+			}
+
+			require.NotZero(t, l2Entry&copied)
+
+			offset := l2Entry &^ copied
+			guestOffset := (i*l2Entries + j) * clusterSize
+
+			clusters[int64(guestOffset)] = image[offset : offset+clusterSize]
+		}
+	}
+
+	return size, clusters
+}
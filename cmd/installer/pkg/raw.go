@@ -6,6 +6,8 @@ package pkg
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/talos-systems/go-cmd/pkg/cmd"
 )
@@ -15,11 +17,11 @@ const (
 	RAWDiskSize = 546
 )
 
-// CreateRawDisk creates a raw disk by invoking the `dd` command.
-func CreateRawDisk() (img string, err error) {
+// CreateRawDisk creates a raw disk of the specified size (in MiB) by invoking the `dd` command.
+func CreateRawDisk(size int) (img string, err error) {
 	img = "/tmp/disk.raw"
 
-	seek := fmt.Sprintf("seek=%d", RAWDiskSize)
+	seek := fmt.Sprintf("seek=%d", size)
 
 	if _, err = cmd.Run("dd", "if=/dev/zero", "of="+img, "bs=1M", "count=0", seek); err != nil {
 		return "", fmt.Errorf("failed to create RAW disk: %w", err)
@@ -27,3 +29,34 @@ func CreateRawDisk() (img string, err error) {
 
 	return img, nil
 }
+
+// ImageWriter writes the disk image of the specified size in some format.
+type ImageWriter func(w io.Writer, src io.ReaderAt, size int64) error
+
+// ConvertRawDisk converts the raw disk to the image at dest using the writer.
+func ConvertRawDisk(src, dest string, write ImageWriter) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer in.Close() //nolint:errcheck
+
+	st, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+
+	defer out.Close() //nolint:errcheck
+
+	if err = write(out, in, st.Size()); err != nil {
+		return fmt.Errorf("failed to convert RAW disk to %q: %w", dest, err)
+	}
+
+	return out.Close()
+}
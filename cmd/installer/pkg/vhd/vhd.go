@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package vhd implements writing fixed VHD images.
+//
+// VHD format reference: https://www.microsoft.com/en-us/download/details.aspx?id=23850.
+package vhd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// FooterSize is the size of the VHD footer.
+	FooterSize = 512
+
+	// Alignment of the virtual disk size required by Azure.
+	Alignment = 1 << 20
+
+	diskTypeFixed = 2
+	sectorSize    = 512
+)
+
+// epoch is the base of VHD timestamps.
+var epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Write the fixed VHD image: the contents of the disk followed by the footer.
+func Write(w io.Writer, src io.ReaderAt, size int64) error {
+	if size%Alignment != 0 {
+		return fmt.Errorf("disk size %d is not aligned to %d bytes", size, Alignment)
+	}
+
+	if _, err := io.Copy(w, io.NewSectionReader(src, 0, size)); err != nil {
+		return err
+	}
+
+	footer := Footer(size, uuid.New(), time.Now())
+
+	_, err := w.Write(footer[:])
+
+	return err
+}
+
+// Footer builds the footer of the fixed VHD image.
+func Footer(size int64, id uuid.UUID, timestamp time.Time) [FooterSize]byte {
+	var footer [FooterSize]byte
+
+	copy(footer[0:8], "conectix")
+	binary.BigEndian.PutUint32(footer[8:12], 2)           // features: reserved bit is always set
+	binary.BigEndian.PutUint32(footer[12:16], 0x00010000) // file format version
+	binary.BigEndian.PutUint64(footer[16:24], ^uint64(0)) // data offset: none for fixed disks
+	binary.BigEndian.PutUint32(footer[24:28], uint32(timestamp.Sub(epoch)/time.Second))
+	copy(footer[28:32], "tals")                           // creator application
+	binary.BigEndian.PutUint32(footer[32:36], 0x00010000) // creator version
+	copy(footer[36:40], "Wi2k")                           // creator host OS
+	binary.BigEndian.PutUint64(footer[40:48], uint64(size))
+	binary.BigEndian.PutUint64(footer[48:56], uint64(size))
+
+	cylinders, heads, sectors := geometry(size)
+
+	binary.BigEndian.PutUint16(footer[56:58], cylinders)
+	footer[58] = heads
+	footer[59] = sectors
+
+	binary.BigEndian.PutUint32(footer[60:64], diskTypeFixed)
+	copy(footer[68:84], id[:])
+
+	binary.BigEndian.PutUint32(footer[64:68], Checksum(footer[:]))
+
+	return footer
+}
+
+// Checksum calculates the footer checksum: one's complement of the sum of the bytes excluding the checksum field.
+func Checksum(footer []byte) uint32 {
+	var sum uint32
+
+	for i, b := range footer {
+		if i >= 64 && i < 68 {
+			continue
+		}
+
+		sum += uint32(b)
+	}
+
+	return ^sum
+}
+
+// geometry calculates CHS geometry as defined by the VHD specification.
+func geometry(size int64) (cylinders uint16, heads, sectorsPerTrack uint8) {
+	totalSectors := size / sectorSize
+
+	if totalSectors > 65535*16*255 {
+		totalSectors = 65535 * 16 * 255
+	}
+
+	var h, spt, cylinderTimesHeads int64
+
+	if totalSectors >= 65535*16*63 {
+		spt = 255
+		h = 16
+		cylinderTimesHeads = totalSectors / spt
+	} else {
+		spt = 17
+		cylinderTimesHeads = totalSectors / spt
+
+		h = (cylinderTimesHeads + 1023) / 1024
+
+		if h < 4 {
+			h = 4
+		}
+
+		if cylinderTimesHeads >= h*1024 || h > 16 {
+			spt = 31
+			h = 16
+			cylinderTimesHeads = totalSectors / spt
+		}
+
+		if cylinderTimesHeads >= h*1024 {
+			spt = 63
+			h = 16
+			cylinderTimesHeads = totalSectors / spt
+		}
+	}
+
+	return uint16(cylinderTimesHeads / h), uint8(h), uint8(spt)
+}
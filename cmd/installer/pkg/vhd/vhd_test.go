@@ -0,0 +1,67 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vhd_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/cmd/installer/pkg/vhd"
+)
+
+func TestWrite(t *testing.T) {
+	disk := make([]byte, 3*vhd.Alignment)
+	rand.Read(disk[vhd.Alignment : vhd.Alignment+4096]) //nolint:errcheck
+
+	var buf bytes.Buffer
+
+	require.NoError(t, vhd.Write(&buf, bytes.NewReader(disk), int64(len(disk))))
+
+	image := buf.Bytes()
+	require.Len(t, image, len(disk)+vhd.FooterSize)
+
+	// fixed VHD is the raw disk followed by the footer
+	assert.Equal(t, disk, image[:len(disk)])
+
+	footer := image[len(disk):]
+
+	assert.Equal(t, "conectix", string(footer[0:8]))
+	assert.Equal(t, ^uint64(0), binary.BigEndian.Uint64(footer[16:24]))
+	assert.EqualValues(t, len(disk), binary.BigEndian.Uint64(footer[40:48]))
+	assert.EqualValues(t, len(disk), binary.BigEndian.Uint64(footer[48:56]))
+	assert.EqualValues(t, 2, binary.BigEndian.Uint32(footer[60:64]))
+	assert.Equal(t, vhd.Checksum(footer), binary.BigEndian.Uint32(footer[64:68]))
+
+	assert.Error(t, vhd.Write(&buf, bytes.NewReader(disk), int64(len(disk))-512))
+}
+
+func TestFooter(t *testing.T) {
+	id := uuid.MustParse("6f0e5b5c-3c8c-4b3a-9d63-3b0cbd1d8a91")
+	timestamp := time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	footer := vhd.Footer(546<<20, id, timestamp)
+
+	assert.EqualValues(t, 678412800, binary.BigEndian.Uint32(footer[24:28]))
+
+	// CHS geometry
+	assert.EqualValues(t, 1109, binary.BigEndian.Uint16(footer[56:58]))
+	assert.EqualValues(t, 16, footer[58])
+	assert.EqualValues(t, 63, footer[59])
+
+	assert.Equal(t, id[:], footer[68:84])
+
+	checksum := binary.BigEndian.Uint32(footer[64:68])
+	assert.Equal(t, vhd.Checksum(footer[:]), checksum)
+
+	footer[100] = 1
+	assert.NotEqual(t, vhd.Checksum(footer[:]), checksum)
+}
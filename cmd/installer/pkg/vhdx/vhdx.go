@@ -0,0 +1,308 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package vhdx implements writing dynamic VHDX images.
+//
+// VHDX format reference: https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-vhdx.
+package vhdx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"unicode/utf16"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// BlockSize is the size of the payload block.
+	BlockSize = 32 * mib
+	// LogicalSectorSize is the virtual disk logical sector size.
+	LogicalSectorSize = 512
+	// PhysicalSectorSize is the virtual disk physical sector size.
+	PhysicalSectorSize = 4096
+)
+
+// Layout of the image, all offsets and sizes are in bytes.
+const (
+	mib = 1 << 20
+
+	headerOffset1      = 64 << 10
+	headerOffset2      = 128 << 10
+	headerSize         = 4 << 10
+	regionTableOffset1 = 192 << 10
+	regionTableOffset2 = 256 << 10
+	regionTableSize    = 64 << 10
+	logOffset          = 1 * mib
+	logLength          = 1 * mib
+	metadataOffset     = 2 * mib
+	metadataLength     = 1 * mib
+	batOffset          = 3 * mib
+
+	// metadata items are placed after the metadata table.
+	metadataItemsOffset = 64 << 10
+
+	// chunkRatio is the number of payload blocks per sector bitmap block.
+	chunkRatio = (1 << 23) * LogicalSectorSize / BlockSize
+
+	payloadBlockFullyPresent = 6
+
+	metadataIsVirtualDisk = 1 << 1
+	metadataIsRequired    = 1 << 2
+)
+
+// Region and metadata item identifiers.
+var (
+	batRegion      = uuid.MustParse("2DC27766-F623-4200-9D64-115E9BFD4A08")
+	metadataRegion = uuid.MustParse("8B7CA206-4790-4B9A-B8FE-575F050F886E")
+
+	fileParametersItem     = uuid.MustParse("CAA16737-FA36-4D43-B3B6-33F0AA44E76B")
+	virtualDiskSizeItem    = uuid.MustParse("2FA54224-CD1B-4876-B211-5DBED83BF4B8")
+	page83DataItem         = uuid.MustParse("BECA12AB-B2E6-4523-93EF-C309E000C746")
+	logicalSectorSizeItem  = uuid.MustParse("8141BF1D-A96F-4709-BA47-F233A8FAAB5F")
+	physicalSectorSizeItem = uuid.MustParse("CDA348C7-445D-4471-9CC9-E9885251C556")
+)
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// checksum calculates CRC-32C of the structure, checksum field (at offset 4) should be zero.
+func checksum(data []byte) uint32 {
+	return crc32.Checksum(data, crc32c)
+}
+
+// putGUID encodes GUID in the Windows (mixed-endian) format.
+func putGUID(b []byte, id uuid.UUID) {
+	binary.LittleEndian.PutUint32(b[0:4], binary.BigEndian.Uint32(id[0:4]))
+	binary.LittleEndian.PutUint16(b[4:6], binary.BigEndian.Uint16(id[4:6]))
+	binary.LittleEndian.PutUint16(b[6:8], binary.BigEndian.Uint16(id[6:8]))
+	copy(b[8:16], id[8:16])
+}
+
+// batEntryIndex returns the index of the BAT entry for the payload block.
+//
+// BAT entries for payload blocks are interleaved with the sector bitmap block entries.
+func batEntryIndex(block int64) int64 {
+	return block + block/chunkRatio
+}
+
+// Write the dynamic VHDX image, only non-zero payload blocks are stored in the image.
+//
+//nolint:gocyclo
+func Write(w io.Writer, src io.ReaderAt, size int64) error {
+	if size%LogicalSectorSize != 0 {
+		return fmt.Errorf("disk size %d is not aligned to %d bytes", size, LogicalSectorSize)
+	}
+
+	payloadBlocks := (size + BlockSize - 1) / BlockSize
+	batEntries := batEntryIndex(payloadBlocks-1) + 1
+	batLength := align(batEntries*8, mib)
+
+	block := make([]byte, BlockSize)
+
+	// first pass: find the blocks which should be stored
+	present := make([]bool, payloadBlocks)
+
+	for i := range present {
+		n, err := readBlock(src, block, int64(i), size)
+		if err != nil {
+			return err
+		}
+
+		present[i] = !isZero(block[:n])
+	}
+
+	bat := make([]byte, batLength)
+	offset := int64(batOffset) + batLength
+
+	for i := range present {
+		if !present[i] {
+			continue
+		}
+
+		binary.LittleEndian.PutUint64(bat[batEntryIndex(int64(i))*8:], uint64(offset/mib)<<20|payloadBlockFullyPresent)
+
+		offset += BlockSize
+	}
+
+	header := make([]byte, batOffset)
+
+	// file type identifier
+	copy(header[0:8], "vhdxfile")
+
+	for i, c := range utf16.Encode([]rune("Talos")) {
+		binary.LittleEndian.PutUint16(header[8+i*2:], c)
+	}
+
+	fileWriteGUID, dataWriteGUID := uuid.New(), uuid.New()
+
+	for i, at := range []int{headerOffset1, headerOffset2} {
+		h := header[at : at+headerSize]
+
+		copy(h[0:4], "head")
+		binary.LittleEndian.PutUint64(h[8:16], uint64(i)) // sequence number, the header with the greater one is current
+		putGUID(h[16:32], fileWriteGUID)
+		putGUID(h[32:48], dataWriteGUID)
+		// log GUID is zero: there are no log entries to replay
+		binary.LittleEndian.PutUint16(h[64:66], 0) // log version
+		binary.LittleEndian.PutUint16(h[66:68], 1) // version
+		binary.LittleEndian.PutUint32(h[68:72], logLength)
+		binary.LittleEndian.PutUint64(h[72:80], logOffset)
+		binary.LittleEndian.PutUint32(h[4:8], checksum(h))
+	}
+
+	for _, at := range []int{regionTableOffset1, regionTableOffset2} {
+		r := header[at : at+regionTableSize]
+
+		copy(r[0:4], "regi")
+		binary.LittleEndian.PutUint32(r[8:12], 2) // entry count
+
+		for i, region := range []struct {
+			id     uuid.UUID
+			offset int64
+			length int64
+		}{
+			{batRegion, batOffset, batLength},
+			{metadataRegion, metadataOffset, metadataLength},
+		} {
+			entry := r[16+i*32 : 16+(i+1)*32]
+
+			putGUID(entry[0:16], region.id)
+			binary.LittleEndian.PutUint64(entry[16:24], uint64(region.offset))
+			binary.LittleEndian.PutUint32(entry[24:28], uint32(region.length))
+			binary.LittleEndian.PutUint32(entry[28:32], 1) // required
+		}
+
+		binary.LittleEndian.PutUint32(r[4:8], checksum(r))
+	}
+
+	writeMetadata(header[metadataOffset:metadataOffset+metadataLength], size)
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(bat); err != nil {
+		return err
+	}
+
+	// second pass: write the blocks
+	for i := range present {
+		if !present[i] {
+			continue
+		}
+
+		n, err := readBlock(src, block, int64(i), size)
+		if err != nil {
+			return err
+		}
+
+		// the last block is padded with zeroes
+		for j := n; j < len(block); j++ {
+			block[j] = 0
+		}
+
+		if _, err = w.Write(block); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeMetadata(m []byte, size int64) {
+	copy(m[0:8], "metadata")
+
+	items := []struct {
+		id    uuid.UUID
+		flags uint32
+		data  []byte
+	}{
+		{fileParametersItem, metadataIsRequired, le32(BlockSize, 0)},
+		{virtualDiskSizeItem, metadataIsVirtualDisk | metadataIsRequired, le64(uint64(size))},
+		{page83DataItem, metadataIsVirtualDisk | metadataIsRequired, guid(uuid.New())},
+		{logicalSectorSizeItem, metadataIsVirtualDisk | metadataIsRequired, le32(LogicalSectorSize)},
+		{physicalSectorSizeItem, metadataIsVirtualDisk | metadataIsRequired, le32(PhysicalSectorSize)},
+	}
+
+	binary.LittleEndian.PutUint16(m[10:12], uint16(len(items)))
+
+	offset := metadataItemsOffset
+
+	for i, item := range items {
+		entry := m[32+i*32 : 32+(i+1)*32]
+
+		putGUID(entry[0:16], item.id)
+		binary.LittleEndian.PutUint32(entry[16:20], uint32(offset))
+		binary.LittleEndian.PutUint32(entry[20:24], uint32(len(item.data)))
+		binary.LittleEndian.PutUint32(entry[24:28], item.flags)
+
+		copy(m[offset:], item.data)
+
+		offset += len(item.data)
+	}
+}
+
+func readBlock(src io.ReaderAt, block []byte, index, size int64) (int, error) {
+	length := size - index*BlockSize
+	if length > BlockSize {
+		length = BlockSize
+	}
+
+	n, err := src.ReadAt(block[:length], index*BlockSize)
+	if err == io.EOF && int64(n) == length {
+		err = nil
+	}
+
+	return n, err
+}
+
+var zeroes = make([]byte, 64<<10)
+
+func isZero(data []byte) bool {
+	for len(data) > 0 {
+		n := len(zeroes)
+		if n > len(data) {
+			n = len(data)
+		}
+
+		if !bytes.Equal(data[:n], zeroes[:n]) {
+			return false
+		}
+
+		data = data[n:]
+	}
+
+	return true
+}
+
+func align(size, alignment int64) int64 {
+	return (size + alignment - 1) / alignment * alignment
+}
+
+func le32(values ...uint32) []byte {
+	b := make([]byte, 4*len(values))
+
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(b[i*4:], v)
+	}
+
+	return b
+}
+
+func le64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+
+	return b
+}
+
+func guid(id uuid.UUID) []byte {
+	b := make([]byte, 16)
+	putGUID(b, id)
+
+	return b
+}
@@ -0,0 +1,169 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vhdx_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"math/rand"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/cmd/installer/pkg/vhdx"
+)
+
+const mib = 1 << 20
+
+func TestWrite(t *testing.T) {
+	// the last block is partial, and the third block is empty
+	size := int64(3*vhdx.BlockSize + 5*mib + 1536)
+
+	disk := make([]byte, size)
+	rand.Read(disk[mib : 2*mib])                             //nolint:errcheck
+	rand.Read(disk[vhdx.BlockSize+100 : vhdx.BlockSize+200]) //nolint:errcheck
+	rand.Read(disk[size-512:])                               //nolint:errcheck
+
+	var buf bytes.Buffer
+
+	require.NoError(t, vhdx.Write(&buf, bytes.NewReader(disk), size))
+
+	image := buf.Bytes()
+
+	// 3 MiB of headers and metadata, 1 MiB of BAT, 3 stored blocks
+	assert.Len(t, image, 4*mib+3*vhdx.BlockSize)
+
+	parsed := parse(t, image)
+
+	assert.EqualValues(t, size, parsed.virtualSize)
+	assert.EqualValues(t, vhdx.BlockSize, parsed.blockSize)
+	assert.EqualValues(t, vhdx.LogicalSectorSize, parsed.logicalSectorSize)
+	assert.EqualValues(t, vhdx.PhysicalSectorSize, parsed.physicalSectorSize)
+	assert.Equal(t, []int{6, 6, 0, 6}, parsed.blockStates)
+
+	assert.True(t, bytes.Equal(disk, parsed.data))
+
+	assert.Error(t, vhdx.Write(&buf, bytes.NewReader(disk), size-1))
+}
+
+type parsedImage struct {
+	virtualSize        uint64
+	blockSize          uint32
+	logicalSectorSize  uint32
+	physicalSectorSize uint32
+	blockStates        []int
+	data               []byte
+}
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+func verifyChecksum(t *testing.T, data []byte) {
+	buf := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(buf[4:8], 0)
+
+	require.Equal(t, binary.LittleEndian.Uint32(data[4:8]), crc32.Checksum(buf, crc32c))
+}
+
+func parseGUID(b []byte) uuid.UUID {
+	var id uuid.UUID
+
+	binary.BigEndian.PutUint32(id[0:4], binary.LittleEndian.Uint32(b[0:4]))
+	binary.BigEndian.PutUint16(id[4:6], binary.LittleEndian.Uint16(b[4:6]))
+	binary.BigEndian.PutUint16(id[6:8], binary.LittleEndian.Uint16(b[6:8]))
+	copy(id[8:16], b[8:16])
+
+	return id
+}
+
+//nolint:gocyclo
+func parse(t *testing.T, image []byte) parsedImage {
+	require.Equal(t, "vhdxfile", string(image[0:8]))
+
+	var sequenceNumbers []uint64
+
+	for _, offset := range []int{64 << 10, 128 << 10} {
+		header := image[offset : offset+4096]
+
+		require.Equal(t, "head", string(header[0:4]))
+		verifyChecksum(t, header)
+
+		assert.EqualValues(t, 1, binary.LittleEndian.Uint16(header[66:68]))
+		assert.Equal(t, uuid.Nil, parseGUID(header[48:64]), "log GUID")
+
+		sequenceNumbers = append(sequenceNumbers, binary.LittleEndian.Uint64(header[8:16]))
+	}
+
+	assert.NotEqual(t, sequenceNumbers[0], sequenceNumbers[1])
+
+	regions := map[uuid.UUID][2]uint64{}
+
+	for _, offset := range []int{192 << 10, 256 << 10} {
+		table := image[offset : offset+64<<10]
+
+		require.Equal(t, "regi", string(table[0:4]))
+		verifyChecksum(t, table)
+
+		for i := 0; i < int(binary.LittleEndian.Uint32(table[8:12])); i++ {
+			entry := table[16+i*32 : 16+(i+1)*32]
+
+			regions[parseGUID(entry[0:16])] = [2]uint64{binary.LittleEndian.Uint64(entry[16:24]), uint64(binary.LittleEndian.Uint32(entry[24:28]))}
+		}
+	}
+
+	bat, ok := regions[uuid.MustParse("2DC27766-F623-4200-9D64-115E9BFD4A08")]
+	require.True(t, ok)
+
+	metadata, ok := regions[uuid.MustParse("8B7CA206-4790-4B9A-B8FE-575F050F886E")]
+	require.True(t, ok)
+
+	meta := image[metadata[0] : metadata[0]+metadata[1]]
+	require.Equal(t, "metadata", string(meta[0:8]))
+
+	items := map[uuid.UUID][]byte{}
+
+	for i := 0; i < int(binary.LittleEndian.Uint16(meta[10:12])); i++ {
+		entry := meta[32+i*32 : 32+(i+1)*32]
+		offset := binary.LittleEndian.Uint32(entry[16:20])
+		length := binary.LittleEndian.Uint32(entry[20:24])
+
+		items[parseGUID(entry[0:16])] = meta[offset : offset+length]
+	}
+
+	var parsed parsedImage
+
+	parsed.blockSize = binary.LittleEndian.Uint32(items[uuid.MustParse("CAA16737-FA36-4D43-B3B6-33F0AA44E76B")][0:4])
+	parsed.virtualSize = binary.LittleEndian.Uint64(items[uuid.MustParse("2FA54224-CD1B-4876-B211-5DBED83BF4B8")])
+	parsed.logicalSectorSize = binary.LittleEndian.Uint32(items[uuid.MustParse("8141BF1D-A96F-4709-BA47-F233A8FAAB5F")])
+	parsed.physicalSectorSize = binary.LittleEndian.Uint32(items[uuid.MustParse("CDA348C7-445D-4471-9CC9-E9885251C556")])
+	assert.Len(t, items[uuid.MustParse("BECA12AB-B2E6-4523-93EF-C309E000C746")], 16)
+
+	chunkRatio := uint64(1<<23) * uint64(parsed.logicalSectorSize) / uint64(parsed.blockSize)
+	blocks := (parsed.virtualSize + uint64(parsed.blockSize) - 1) / uint64(parsed.blockSize)
+
+	parsed.data = make([]byte, blocks*uint64(parsed.blockSize))
+
+	for i := uint64(0); i < blocks; i++ {
+		entry := binary.LittleEndian.Uint64(image[bat[0]+(i+i/chunkRatio)*8:])
+		state := int(entry & 7)
+
+		parsed.blockStates = append(parsed.blockStates, state)
+
+		if state != 6 {
+			continue
+		}
+
+		offset := entry >> 20 * mib
+
+		require.Zero(t, offset%mib)
+		copy(parsed.data[i*uint64(parsed.blockSize):], image[offset:offset+uint64(parsed.blockSize)])
+	}
+
+	parsed.data = parsed.data[:parsed.virtualSize]
+
+	return parsed
+}
@@ -109,6 +109,7 @@ var (
 	configPatchWorker         string
 	configPatchJoin           string
 	badRTC                    bool
+	withWatchdog              bool
 )
 
 // createCmd represents the cluster up command.
@@ -374,6 +375,10 @@ func create(ctx context.Context) (err error) {
 			genOptions = append(genOptions, generate.WithSystemDiskEncryption(diskEncryptionConfig))
 		}
 
+		if withWatchdog {
+			genOptions = append(genOptions, generate.WithWatchdog(&v1alpha1.WatchdogConfig{}))
+		}
+
 		if useVIP {
 			genOptions = append(genOptions,
 				generate.WithNetworkOptions(
@@ -822,6 +827,7 @@ func init() {
 	createCmd.Flags().StringVar(&configPatchControlPlane, "config-patch-control-plane", "", "patch generated machineconfigs (applied to 'init' and 'controlplane' types)")
 	createCmd.Flags().StringVar(&configPatchWorker, "config-patch-worker", "", "patch generated machineconfigs (applied to 'worker' type)")
 	createCmd.Flags().BoolVar(&badRTC, "bad-rtc", false, "launch VM with bad RTC state (QEMU only)")
+	createCmd.Flags().BoolVar(&withWatchdog, "with-watchdog", false, "enable watchdog timer, softdog is used if there's no hardware watchdog (QEMU only)")
 
 	// remove in 0.13: https://github.com/talos-systems/talos/issues/3910
 	createCmd.Flags().StringVar(&configPatchJoin, "config-patch-join", "", "")
@@ -14,20 +14,41 @@ import (
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
+	"github.com/talos-systems/go-blockdevice/blockdevice/util/disk"
+	"gopkg.in/yaml.v3"
 
 	"github.com/talos-systems/talos/pkg/cli"
 	"github.com/talos-systems/talos/pkg/machinery/client"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 )
 
 var disksCmdFlags struct {
-	insecure bool
+	insecure        bool
+	explainSelector string
 }
 
 var disksCmd = &cobra.Command{
 	Use:   "disks",
 	Short: "Get the list of disks from /sys/block on the machine",
-	Long:  ``,
+	Long: `Get the list of disks from /sys/block on the machine.
+
+With --explain-selector, the install disk selector (in the machine config YAML syntax) is matched against the disks:
+the output shows which disk the selector picks and why other disks don't match.`,
+	Example: `  talosctl disks --explain-selector '{busPath: "/pci0000:00/0000:00:1f.2/*", size: ">= 1TB"}'`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		run := printDisks
+
+		if disksCmdFlags.explainSelector != "" {
+			selector, err := parseDiskSelector(disksCmdFlags.explainSelector)
+			if err != nil {
+				return err
+			}
+
+			run = func(ctx context.Context, c *client.Client) error {
+				return explainDiskSelector(ctx, c, selector)
+			}
+		}
+
 		if disksCmdFlags.insecure {
 			ctx := context.Background()
 
@@ -38,10 +59,10 @@ var disksCmd = &cobra.Command{
 				return err
 			}
 
-			return printDisks(ctx, c)
+			return run(ctx, c)
 		}
 
-		return WithClient(printDisks)
+		return WithClient(run)
 	},
 }
 
@@ -69,6 +90,7 @@ func printDisks(ctx context.Context, c *client.Client) error {
 			"WWID",
 			"MODALIAS",
 			"NAME",
+			"BUS PATH",
 			"SIZE",
 		}, "\t")
 
@@ -113,6 +135,7 @@ func printDisks(ctx context.Context, c *client.Client) error {
 				getWithPlaceholder(disk.Wwid),
 				getWithPlaceholder(disk.Modalias),
 				getWithPlaceholder(disk.Name),
+				getWithPlaceholder(disk.BusPath),
 				humanize.Bytes(disk.Size),
 			}...)
 
@@ -126,7 +149,117 @@ func printDisks(ctx context.Context, c *client.Client) error {
 	return w.Flush()
 }
 
+func parseDiskSelector(in string) (*v1alpha1.InstallDiskSelector, error) {
+	var selector v1alpha1.InstallDiskSelector
+
+	decoder := yaml.NewDecoder(strings.NewReader(in))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&selector); err != nil {
+		return nil, fmt.Errorf("error parsing disk selector: %w", err)
+	}
+
+	return &selector, nil
+}
+
+// explainDiskSelector matches the disks of each node against the selector in the same order as the installer does.
+//
+//nolint:gocyclo
+func explainDiskSelector(ctx context.Context, c *client.Client, selector *v1alpha1.InstallDiskSelector) error {
+	response, err := c.Disks(ctx)
+	if err != nil {
+		if response == nil {
+			return fmt.Errorf("error getting disks: %w", err)
+		}
+
+		cli.Warning("%s", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(w, "NODE\tDEV\tSIZE\tSERIAL\tWWID\tBUS PATH\tSELECTED\tREASON")
+
+	placeholder := func(in string) string {
+		if in == "" {
+			return "-"
+		}
+
+		return in
+	}
+
+	for _, message := range response.Messages {
+		node := "-"
+
+		if message.Metadata != nil && message.Metadata.Hostname != "" {
+			node = message.Metadata.Hostname
+		}
+
+		var matched []string
+
+		for _, d := range message.Disks {
+			mismatches := selector.Explain(&disk.Disk{
+				Size:       d.Size,
+				Model:      d.Model,
+				DeviceName: d.DeviceName,
+				Name:       d.Name,
+				Serial:     d.Serial,
+				Modalias:   d.Modalias,
+				WWID:       d.Wwid,
+				UUID:       d.Uuid,
+				Type:       disk.Type(d.Type),
+			}, d.BusPath)
+
+			var selected, reason string
+
+			switch {
+			case len(mismatches) > 0:
+				selected = "no"
+
+				reasons := make([]string, 0, len(mismatches))
+
+				for _, mismatch := range mismatches {
+					reasons = append(reasons, mismatch.String())
+				}
+
+				reason = strings.Join(reasons, "; ")
+			case len(matched) == 0:
+				selected = "yes"
+				reason = "first matching disk"
+			default:
+				selected = "no"
+				reason = fmt.Sprintf("matches, but %s comes first", matched[0])
+			}
+
+			if len(mismatches) == 0 {
+				matched = append(matched, d.DeviceName)
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				node,
+				d.DeviceName,
+				humanize.Bytes(d.Size),
+				placeholder(d.Serial),
+				placeholder(d.Wwid),
+				placeholder(d.BusPath),
+				selected,
+				reason,
+			)
+		}
+
+		switch {
+		case len(matched) == 0:
+			cli.Warning("node %s: no disk matches the selector", node)
+		case len(matched) > 1:
+			cli.Warning("node %s: %d disks match the selector (%s), the choice depends on the device names which might change across reboots; "+
+				"consider matching on wwid, serial or busPath", node, len(matched), strings.Join(matched, ", "))
+		}
+	}
+
+	return w.Flush()
+}
+
 func init() {
 	disksCmd.Flags().BoolVarP(&disksCmdFlags.insecure, "insecure", "i", false, "get disks using the insecure (encrypted with no auth) maintenance service")
+	disksCmd.Flags().StringVar(&disksCmdFlags.explainSelector, "explain-selector", "", "show which disk the install disk selector (YAML) picks and why")
 	addCommand(disksCmd)
 }
@@ -13,20 +13,40 @@ import (
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/codes"
 
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
 )
 
-var dmesgTail bool
+var (
+	dmesgTail     bool
+	dmesgPrevious bool
+)
 
 // dmesgCmd represents the dmesg command.
 var dmesgCmd = &cobra.Command{
 	Use:   "dmesg",
 	Short: "Retrieve kernel logs",
-	Long:  ``,
-	Args:  cobra.NoArgs,
+	Long: `Retrieve kernel logs.
+
+With --previous, kernel crash records (oops, panic) captured via pstore before the last reboot are printed instead.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if dmesgPrevious && (follow || dmesgTail) {
+			return fmt.Errorf("--previous can't be combined with --follow or --tail")
+		}
+
 		return WithClient(func(ctx context.Context, c *client.Client) error {
-			stream, err := c.Dmesg(ctx, follow, dmesgTail)
+			var (
+				stream machine.MachineService_DmesgClient
+				err    error
+			)
+
+			if dmesgPrevious {
+				stream, err = c.DmesgPrevious(ctx)
+			} else {
+				stream, err = c.Dmesg(ctx, follow, dmesgTail)
+			}
+
 			if err != nil {
 				return fmt.Errorf("error getting dmesg: %w", err)
 			}
@@ -66,4 +86,5 @@ func init() {
 	addCommand(dmesgCmd)
 	dmesgCmd.Flags().BoolVarP(&follow, "follow", "f", false, "specify if the kernel log should be streamed")
 	dmesgCmd.Flags().BoolVarP(&dmesgTail, "tail", "", false, "specify if only new messages should be sent (makes sense only when combined with --follow)")
+	dmesgCmd.Flags().BoolVarP(&dmesgPrevious, "previous", "", false, "show kernel crash records (oops, panic) captured before the last reboot")
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 	"time"
 
@@ -81,6 +82,24 @@ var eventsCmd = &cobra.Command{
 						args = []interface{}{msg.GetTask(), msg.GetAction().String()}
 					case *machine.ServiceStateEvent:
 						args = []interface{}{msg.GetService(), fmt.Sprintf("%s: %s", msg.GetAction(), msg.GetMessage())}
+					case *machine.DiskHealthEvent:
+						status := "healthy"
+						if !msg.GetHealthy() {
+							status = "unhealthy: " + strings.Join(msg.GetWarnings(), ", ")
+						}
+
+						args = []interface{}{msg.GetDevice(), status}
+					case *machine.FilesystemRepairEvent:
+						status := "mounted"
+						if !msg.GetMounted() {
+							status = "not mounted"
+						}
+
+						if msg.GetError() != "" {
+							status += ": " + msg.GetError()
+						}
+
+						args = []interface{}{msg.GetDevice(), fmt.Sprintf("%s %s (%s): %s", msg.GetFilesystem(), msg.GetMountPoint(), msg.GetPolicy(), status)}
 					default:
 						// We haven't implemented the handling of this event yet.
 						continue
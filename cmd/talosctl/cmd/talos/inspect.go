@@ -9,11 +9,16 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/emicklei/dot"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 
 	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/helpers"
 	"github.com/talos-systems/talos/pkg/cli"
@@ -190,9 +195,102 @@ to render the graph:
 	},
 }
 
+// inspectControllersCmd represents the inspect controllers command.
+var inspectControllersCmd = &cobra.Command{
+	Use:   "controllers [<controller>]",
+	Short: "Inspect recent reconcile runs of the controllers.",
+	Long: `Inspect recent reconcile runs of the controllers.
+
+Without arguments, the summary of the recorded reconcile runs is printed for each controller.
+If the controller name is specified, each recorded reconcile run is printed with the resources
+touched during the run.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			var remotePeer peer.Peer
+
+			resp, err := c.Inspect.ControllerRuntimeHistory(ctx, grpc.Peer(&remotePeer))
+			if err != nil {
+				if resp == nil {
+					return fmt.Errorf("error getting controller runtime history: %s", err)
+				}
+
+				cli.Warning("%s", err)
+			}
+
+			defaultNode := client.AddrFromPeer(&remotePeer)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+
+			if len(args) == 0 {
+				fmt.Fprintln(w, "NODE\tCONTROLLER\tRUNS\tERRORS\tLAST RUN\tLAST DURATION\tLAST ERROR")
+			} else {
+				fmt.Fprintln(w, "NODE\tSTART\tDURATION\tERROR\tTOUCHED")
+			}
+
+			for _, msg := range resp.GetMessages() {
+				node := defaultNode
+
+				if msg.Metadata != nil {
+					node = msg.Metadata.Hostname
+				}
+
+				for _, ctrl := range msg.GetControllers() {
+					runs := ctrl.GetRuns()
+
+					if len(args) == 0 {
+						if len(runs) == 0 {
+							continue
+						}
+
+						errors := 0
+						lastError := ""
+
+						for _, run := range runs {
+							if run.GetError() != "" {
+								errors++
+								lastError = run.GetError()
+							}
+						}
+
+						lastRun := runs[len(runs)-1]
+
+						fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s ago\t%s\t%s\n",
+							node, ctrl.GetControllerName(), len(runs), errors,
+							time.Since(lastRun.GetStart().AsTime()).Round(time.Second),
+							lastRun.GetDuration().AsDuration().Round(time.Microsecond),
+							lastError,
+						)
+
+						continue
+					}
+
+					if ctrl.GetControllerName() != args[0] {
+						continue
+					}
+
+					for _, run := range runs {
+						fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+							node,
+							run.GetStart().AsTime().Format(time.RFC3339),
+							run.GetDuration().AsDuration().Round(time.Microsecond),
+							run.GetError(),
+							strings.Join(run.GetTouchedResources(), ", "),
+						)
+					}
+				}
+			}
+
+			return w.Flush()
+		})
+	},
+}
+
 func init() {
 	addCommand(inspectCmd)
 
+	inspectCmd.AddCommand(inspectControllersCmd)
 	inspectCmd.AddCommand(inspectDependenciesCmd)
 	inspectDependenciesCmd.Flags().BoolVar(&inspectDependenciesCmdFlags.withResources, "with-resources", false, "display live resource information with dependencies")
 }
@@ -0,0 +1,80 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/pkg/cluster"
+	k8s "github.com/talos-systems/talos/pkg/cluster/kubernetes"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+)
+
+var renewCertsCmdFlags struct {
+	options              k8s.RenewOptions
+	controlPlaneEndpoint string
+}
+
+// renewCertsCmd represents the renew-certs command.
+var renewCertsCmd = &cobra.Command{
+	Use:   "renew-certs",
+	Short: "Renew certificates managed by Talos in the cluster.",
+	Long: `Command renews Kubernetes, etcd and Talos API certificates on all the nodes.
+Control plane nodes are processed one by one: etcd and control plane static pods are restarted to pick up the new certificates,
+and the next node is processed only when they are healthy again.
+
+Certificate expiry can be checked with 'talosctl get certificates'.
+If the nodes are not specified, they are discovered via Kubernetes API.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(renewCerts)
+	},
+}
+
+func init() {
+	renewCertsCmd.Flags().StringSliceVar(&renewCertsCmdFlags.options.ControlPlaneNodes, "control-plane-nodes", nil, "specify IPs of control plane nodes")
+	renewCertsCmd.Flags().StringSliceVar(&renewCertsCmdFlags.options.WorkerNodes, "worker-nodes", nil, "specify IPs of worker nodes")
+	renewCertsCmd.Flags().StringVar(&renewCertsCmdFlags.controlPlaneEndpoint, "endpoint", "", "the cluster control plane endpoint")
+	addCommand(renewCertsCmd)
+}
+
+func renewCerts(ctx context.Context, c *client.Client) error {
+	clientProvider := &cluster.ConfigClientProvider{
+		DefaultClient: c,
+	}
+	defer clientProvider.Close() //nolint:errcheck
+
+	state := struct {
+		cluster.ClientProvider
+		cluster.K8sProvider
+	}{
+		ClientProvider: clientProvider,
+		K8sProvider: &cluster.KubernetesClient{
+			ClientProvider: clientProvider,
+			ForceEndpoint:  renewCertsCmdFlags.controlPlaneEndpoint,
+		},
+	}
+
+	options := renewCertsCmdFlags.options
+
+	if len(options.ControlPlaneNodes) == 0 {
+		configContext := c.GetConfigContext()
+		if configContext == nil {
+			return fmt.Errorf("failed to resolve config context")
+		}
+
+		var err error
+
+		options.ControlPlaneNodes, options.WorkerNodes, err = discoverNodes(ctx, clientProvider, configContext)
+		if err != nil {
+			return err
+		}
+	}
+
+	return k8s.RenewCertificates(ctx, &state, options)
+}
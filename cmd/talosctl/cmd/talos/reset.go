@@ -6,27 +6,62 @@ package talos
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
+	"github.com/talos-systems/talos/pkg/wipe/report"
 )
 
 var resetCmdFlags struct {
 	graceful           bool
 	reboot             bool
 	systemLabelsToWipe []string
+	wipeMode           string
+	wipeReportDir      string
+}
+
+var wipeModes = map[string]machine.WipeRequest_Mode{
+	"fast":         machine.WipeRequest_FAST,
+	"zero":         machine.WipeRequest_ZERO,
+	"secure-erase": machine.WipeRequest_SECURE_ERASE,
 }
 
 // resetCmd represents the reset command.
 var resetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset a node",
-	Long:  ``,
-	Args:  cobra.NoArgs,
+	Long: `Reset a node.
+
+With --wipe-mode, the system disk and the user disks (including volume group devices) are wiped for decommissioning:
+"fast" wipes partition tables and filesystem signatures, "zero" overwrites the disks with zeroes, and
+"secure-erase" uses hardware secure erase (ATA Security Erase, NVMe Sanitize or Format) falling back to "zero".
+The progress is streamed back, and the report signed by the node certificate is verified against the talosconfig CA.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if resetCmdFlags.wipeMode != "" {
+			mode, ok := wipeModes[resetCmdFlags.wipeMode]
+			if !ok {
+				return fmt.Errorf("unsupported wipe mode %q, supported modes: fast, zero, secure-erase", resetCmdFlags.wipeMode)
+			}
+
+			if len(resetCmdFlags.systemLabelsToWipe) > 0 {
+				return fmt.Errorf("--wipe-mode can't be combined with --system-labels-to-wipe")
+			}
+
+			return WithClient(func(ctx context.Context, c *client.Client) error {
+				return resetWipe(ctx, c, mode)
+			})
+		}
+
 		return WithClient(func(ctx context.Context, c *client.Client) error {
 			var systemPartitionsToWipe []*machine.ResetPartitionSpec
 
@@ -50,9 +85,138 @@ var resetCmd = &cobra.Command{
 	},
 }
 
+//nolint:gocyclo
+func resetWipe(ctx context.Context, c *client.Client, mode machine.WipeRequest_Mode) error {
+	configContext := c.GetConfigContext()
+	if configContext == nil {
+		return fmt.Errorf("failed to resolve config context")
+	}
+
+	ca, err := base64.StdEncoding.DecodeString(configContext.CA)
+	if err != nil {
+		return fmt.Errorf("error decoding CA: %w", err)
+	}
+
+	stream, err := c.Wipe(ctx, &machine.WipeRequest{
+		Mode:     mode,
+		Graceful: resetCmdFlags.graceful,
+		Reboot:   resetCmdFlags.reboot,
+	})
+	if err != nil {
+		return fmt.Errorf("error executing wipe: %w", err)
+	}
+
+	defaultNode := client.RemotePeer(stream.Context())
+
+	lastPercent := map[string]uint64{}
+	failed := false
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			return fmt.Errorf("error reading from stream: %w", err)
+		}
+
+		node := defaultNode
+		if resp.Metadata != nil {
+			node = resp.Metadata.Hostname
+
+			if resp.Metadata.Error != "" {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", node, resp.Metadata.Error)
+
+				failed = true
+
+				continue
+			}
+		}
+
+		if resp.Report != nil {
+			if err = processWipeReport(node, resp.Report, ca); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", node, err)
+
+				failed = true
+			}
+
+			continue
+		}
+
+		if resp.BytesTotal == 0 {
+			continue
+		}
+
+		// print progress in 10% steps
+		percent := resp.BytesDone * 100 / resp.BytesTotal
+		key := node + ":" + resp.Device
+
+		if last, ok := lastPercent[key]; ok && percent < last+10 && (percent != 100 || last == 100) {
+			continue
+		}
+
+		lastPercent[key] = percent
+
+		fmt.Printf("%s: %s %d%%\n", node, resp.Device, percent)
+	}
+
+	if failed {
+		return fmt.Errorf("wipe failed")
+	}
+
+	return nil
+}
+
+func processWipeReport(node string, wipeReport *machine.WipeReport, ca []byte) error {
+	data, err := report.Verify(wipeReport, ca)
+	if err != nil {
+		return fmt.Errorf("error verifying wipe report: %w", err)
+	}
+
+	fmt.Printf("%s: wipe report for %q verified, completed at %s\n", node, data.Hostname, data.Completed.AsTime())
+
+	var failed bool
+
+	for _, disk := range data.Disks {
+		if disk.Error != "" {
+			fmt.Printf("%s: %s (model %q, serial %q): failed: %s\n", node, disk.Device, disk.Model, disk.Serial, disk.Error)
+
+			failed = true
+		} else {
+			fmt.Printf("%s: %s (model %q, serial %q): wiped using %s\n", node, disk.Device, disk.Model, disk.Serial, disk.Method)
+		}
+	}
+
+	if resetCmdFlags.wipeReportDir != "" {
+		var marshaled []byte
+
+		marshaled, err = proto.Marshal(wipeReport)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(resetCmdFlags.wipeReportDir, node+"-wipe-report.pb")
+
+		if err = ioutil.WriteFile(path, marshaled, 0o600); err != nil {
+			return fmt.Errorf("error saving wipe report: %w", err)
+		}
+
+		fmt.Printf("%s: wipe report saved to %q\n", node, path)
+	}
+
+	if failed {
+		return fmt.Errorf("some disks failed to wipe")
+	}
+
+	return nil
+}
+
 func init() {
 	resetCmd.Flags().BoolVar(&resetCmdFlags.graceful, "graceful", true, "if true, attempt to cordon/drain node and leave etcd (if applicable)")
 	resetCmd.Flags().BoolVar(&resetCmdFlags.reboot, "reboot", false, "if true, reboot the node after resetting instead of shutting down")
 	resetCmd.Flags().StringSliceVar(&resetCmdFlags.systemLabelsToWipe, "system-labels-to-wipe", nil, "if set, just wipe selected system disk partitions by label but keep other partitions intact")
+	resetCmd.Flags().StringVar(&resetCmdFlags.wipeMode, "wipe-mode", "", "if set, wipe the system and user disks for decommissioning (fast, zero, secure-erase)")
+	resetCmd.Flags().StringVar(&resetCmdFlags.wipeReportDir, "wipe-report-dir", "", "if set, save signed wipe reports to the directory")
 	addCommand(resetCmd)
 }
@@ -0,0 +1,134 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package talos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/talos-systems/talos/pkg/cluster"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+	clientconfig "github.com/talos-systems/talos/pkg/machinery/client/config"
+	machinetype "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
+)
+
+var rotateCACmdFlags struct {
+	options cluster.RotateCAOptions
+}
+
+// rotateCACmd represents the rotate-ca command.
+var rotateCACmd = &cobra.Command{
+	Use:   "rotate-ca",
+	Short: "Rotate Talos API certificate authority in the Talos cluster.",
+	Long: `Command generates new Talos API certificate authority and rolls it out to all the nodes without a reboot.
+New CA is accepted by all the nodes first, then it replaces the old CA, and the old CA is dropped once all the nodes trust the new one.
+Current talosconfig context is updated with the new CA and client certificate.
+
+If the nodes are not specified, they are discovered via Kubernetes API.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClientNoNodes(rotateCA)
+	},
+}
+
+func init() {
+	rotateCACmd.Flags().StringSliceVar(&rotateCACmdFlags.options.ControlPlaneNodes, "control-plane-nodes", nil, "specify IPs of control plane nodes")
+	rotateCACmd.Flags().StringSliceVar(&rotateCACmdFlags.options.WorkerNodes, "worker-nodes", nil, "specify IPs of worker nodes")
+	rotateCACmd.Flags().DurationVar(&rotateCACmdFlags.options.ClientCertTTL, "crt-ttl", 87600*time.Hour, "certificate TTL")
+	addCommand(rotateCACmd)
+}
+
+//nolint:gocyclo
+func rotateCA(ctx context.Context, c *client.Client) error {
+	cfg, err := clientconfig.Open(Talosconfig)
+	if err != nil {
+		return fmt.Errorf("failed to open config file %q: %w", Talosconfig, err)
+	}
+
+	contextName := cfg.Context
+
+	if Cmdcontext != "" {
+		contextName = Cmdcontext
+	}
+
+	configContext, ok := cfg.Contexts[contextName]
+	if !ok {
+		return fmt.Errorf("context %q is not defined in %q", contextName, Talosconfig)
+	}
+
+	clientProvider := &cluster.ConfigClientProvider{
+		DefaultClient: c,
+	}
+	defer clientProvider.Close() //nolint:errcheck
+
+	options := rotateCACmdFlags.options
+
+	if len(options.ControlPlaneNodes) == 0 {
+		options.ControlPlaneNodes, options.WorkerNodes, err = discoverNodes(ctx, clientProvider, configContext)
+		if err != nil {
+			return err
+		}
+	}
+
+	rotationContext := *configContext
+
+	if len(Endpoints) > 0 {
+		rotationContext.Endpoints = Endpoints
+	}
+
+	if err = cluster.RotateCA(ctx, clientProvider, &rotationContext, options); err != nil {
+		return err
+	}
+
+	configContext.CA = rotationContext.CA
+	configContext.Crt = rotationContext.Crt
+	configContext.Key = rotationContext.Key
+
+	if err = cfg.Save(Talosconfig); err != nil {
+		return fmt.Errorf("error saving config %q: %w", Talosconfig, err)
+	}
+
+	options.Log("updated context %q in %q", contextName, Talosconfig)
+
+	return nil
+}
+
+// discoverNodes returns control plane and worker node IPs via Kubernetes API.
+func discoverNodes(ctx context.Context, clientProvider cluster.ClientProvider, configContext *clientconfig.Context) (controlPlaneNodes, workerNodes []string, err error) {
+	nodes := Nodes
+
+	if len(nodes) == 0 {
+		nodes = configContext.Nodes
+	}
+
+	if len(nodes) == 0 {
+		return nil, nil, fmt.Errorf("nodes are not set for the command: please use `--control-plane-nodes` and `--worker-nodes` flags")
+	}
+
+	k8sProvider := &cluster.KubernetesClient{
+		ClientProvider: clientProvider,
+	}
+	defer k8sProvider.K8sClose() //nolint:errcheck
+
+	k8sClient, err := k8sProvider.K8sHelper(client.WithNodes(ctx, nodes[0]))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building kubernetes client: %w", err)
+	}
+
+	controlPlaneNodes, err = k8sClient.NodeIPs(ctx, machinetype.TypeControlPlane)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error discovering control plane nodes: %w", err)
+	}
+
+	workerNodes, err = k8sClient.NodeIPs(ctx, machinetype.TypeWorker)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error discovering worker nodes: %w", err)
+	}
+
+	return controlPlaneNodes, workerNodes, nil
+}
@@ -8,13 +8,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/emicklei/dot"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/peer"
 
+	"github.com/talos-systems/talos/cmd/talosctl/pkg/talos/helpers"
 	"github.com/talos-systems/talos/pkg/cli"
 	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
@@ -62,6 +65,30 @@ With actions 'start', 'stop', 'restart', service state is updated respectively.`
 	},
 }
 
+// serviceGraphCmd represents the service graph command.
+var serviceGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Show service dependencies with live state as graphviz graph.",
+	Long: `Show service dependencies with live state as graphviz graph.
+
+Services waiting to be started are linked to the conditions they are waiting for.
+Pipe the output of the command through the "dot" program (part of graphviz package)
+to render the graph:
+
+  talosctl service graph | dot -Tpng > services.png
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return WithClient(func(ctx context.Context, c *client.Client) error {
+			if err := helpers.FailIfMultiNodes(ctx, "service graph"); err != nil {
+				return err
+			}
+
+			return serviceGraph(ctx, c)
+		})
+	},
+}
+
 func serviceList(ctx context.Context, c *client.Client) error {
 	var remotePeer peer.Peer
 
@@ -125,12 +152,24 @@ func serviceInfo(ctx context.Context, c *client.Client, id string) error {
 		fmt.Fprintf(w, "ID\t%s\n", svc.Id)
 		fmt.Fprintf(w, "STATE\t%s\n", svc.State)
 		fmt.Fprintf(w, "HEALTH\t%s\n", svc.HealthStatus())
+		fmt.Fprintf(w, "RESTARTS\t%d\n", svc.RestartCount)
+
+		if len(svc.DependsOn) > 0 {
+			fmt.Fprintf(w, "DEPENDS ON\t%s\n", strings.Join(svc.DependsOn, ", "))
+		}
+
+		label := "WAITING FOR"
+
+		for _, condition := range svc.UnmetConditions {
+			fmt.Fprintf(w, "%s\t%s\n", label, condition)
+			label = ""
+		}
 
 		if svc.Health.LastMessage != "" {
 			fmt.Fprintf(w, "LAST HEALTH MESSAGE\t%s\n", svc.Health.LastMessage)
 		}
 
-		label := "EVENTS"
+		label = "EVENTS"
 
 		for i := range svc.Events.Events {
 			event := svc.Events.Events[len(svc.Events.Events)-1-i]
@@ -148,6 +187,84 @@ func serviceInfo(ctx context.Context, c *client.Client, id string) error {
 	return w.Flush()
 }
 
+func serviceGraph(ctx context.Context, c *client.Client) error {
+	resp, err := c.ServiceList(ctx)
+	if err != nil {
+		if resp == nil {
+			return fmt.Errorf("error listing services: %w", err)
+		}
+
+		cli.Warning("%s", err)
+	}
+
+	graph := dot.NewGraph(dot.Directed)
+
+	for _, msg := range resp.Messages {
+		for _, s := range msg.Services {
+			svc := serviceInfoWrapper{s}
+
+			graph.Node(svc.Id).
+				Label(fmt.Sprintf("%s\n%s (%s)", svc.Id, svc.State, svc.HealthStatus())).
+				Box().
+				Attr("fillcolor", svc.StateColor()).
+				Attr("style", "filled")
+		}
+	}
+
+	for _, msg := range resp.Messages {
+		for _, s := range msg.Services {
+			for _, dependency := range s.DependsOn {
+				edge := graph.Edge(graph.Node(dependency), graph.Node(s.Id))
+
+				if serviceWaitsFor(s, dependency) {
+					edge.Dashed().Attr("color", "red")
+				} else {
+					edge.Solid()
+				}
+			}
+
+			for _, condition := range s.UnmetConditions {
+				if isServiceCondition(s, condition) {
+					continue
+				}
+
+				graph.Edge(
+					graph.Node(condition).
+						Attr("shape", "note").
+						Attr("fillcolor", "azure2").
+						Attr("style", "filled"),
+					graph.Node(s.Id),
+				).Dashed().Attr("color", "red")
+			}
+		}
+	}
+
+	graph.Write(os.Stdout)
+
+	return nil
+}
+
+// serviceWaitsFor returns true if the service is still waiting for the dependency to be up.
+func serviceWaitsFor(s *machineapi.ServiceInfo, dependency string) bool {
+	for _, condition := range s.UnmetConditions {
+		if strings.HasPrefix(condition, fmt.Sprintf("service %q ", dependency)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isServiceCondition(s *machineapi.ServiceInfo, condition string) bool {
+	for _, dependency := range s.DependsOn {
+		if strings.HasPrefix(condition, fmt.Sprintf("service %q ", dependency)) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func serviceStart(ctx context.Context, c *client.Client, id string) error {
 	var remotePeer peer.Peer
 
@@ -272,6 +389,24 @@ func (svc serviceInfoWrapper) HealthStatus() string {
 	return "Fail"
 }
 
+func (svc serviceInfoWrapper) StateColor() string {
+	switch svc.State {
+	case "Running":
+		if svc.Health.Unknown || svc.Health.Healthy {
+			return "palegreen"
+		}
+
+		return "orange"
+	case "Finished", "Skipped":
+		return "lightgrey"
+	case "Failed":
+		return "tomato"
+	default:
+		return "lightyellow"
+	}
+}
+
 func init() {
+	serviceCmd.AddCommand(serviceGraphCmd)
 	addCommand(serviceCmd)
 }
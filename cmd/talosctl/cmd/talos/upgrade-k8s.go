@@ -6,14 +6,20 @@ package talos
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
-	"github.com/talos-systems/talos/pkg/cli"
 	"github.com/talos-systems/talos/pkg/cluster"
 	k8s "github.com/talos-systems/talos/pkg/cluster/kubernetes"
 	"github.com/talos-systems/talos/pkg/machinery/client"
+	clientconfig "github.com/talos-systems/talos/pkg/machinery/client/config"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
@@ -21,20 +27,40 @@ import (
 var upgradeK8sCmd = &cobra.Command{
 	Use:   "upgrade-k8s",
 	Short: "Upgrade Kubernetes control plane in the Talos cluster.",
-	Long:  `Command runs upgrade of Kubernetes control plane components between specified versions. Pod-checkpointer is handled in a special way to speed up kube-apisever upgrades.`,
-	Args:  cobra.NoArgs,
+	Long: `Command runs upgrade of Kubernetes control plane components between specified versions. Pod-checkpointer is handled in a special way to speed up kube-apisever upgrades.
+
+Before the upgrade, pre-flight checks scan the cluster for objects using API versions removed in the target release,
+and verify Talos and kubelet version skew on the nodes. Use '--dry-run' to run the checks only.
+
+For Talos-managed control plane, the upgrade stops on the first unhealthy component, and the previous component versions are recorded,
+so that the upgrade can be rolled back node by node with '--rollback'.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// target version is not used for the rollback, so it's only required for the upgrade
+		if !upgradeK8sCmdFlags.rollback && !cmd.Flags().Changed("to") {
+			return fmt.Errorf(`required flag(s) "to" not set`)
+		}
+
 		return WithClient(upgradeKubernetes)
 	},
 }
 
 var upgradeOptions k8s.UpgradeOptions
 
+var upgradeK8sCmdFlags struct {
+	dryRun        bool
+	rollback      bool
+	rollbackState string
+}
+
 func init() {
 	upgradeK8sCmd.Flags().StringVar(&upgradeOptions.FromVersion, "from", "", "the Kubernetes control plane version to upgrade from")
 	upgradeK8sCmd.Flags().StringVar(&upgradeOptions.ToVersion, "to", constants.DefaultKubernetesVersion, "the Kubernetes control plane version to upgrade to")
 	upgradeK8sCmd.Flags().StringVar(&upgradeOptions.ControlPlaneEndpoint, "endpoint", "", "the cluster control plane endpoint")
-	cli.Should(upgradeK8sCmd.MarkFlagRequired("to"))
+	upgradeK8sCmd.Flags().BoolVar(&upgradeK8sCmdFlags.dryRun, "dry-run", false, "run pre-flight checks only, don't upgrade the cluster")
+	upgradeK8sCmd.Flags().BoolVar(&upgradeK8sCmdFlags.rollback, "rollback", false, "roll back the last upgrade to the recorded versions")
+	upgradeK8sCmd.Flags().StringVar(&upgradeK8sCmdFlags.rollbackState, "rollback-state", "",
+		"the file path to record component versions for the rollback (defaults to a file in ~/.talos/upgrade-k8s-rollback/ named after the talosconfig context)")
 	addCommand(upgradeK8sCmd)
 }
 
@@ -55,6 +81,18 @@ func upgradeKubernetes(ctx context.Context, c *client.Client) error {
 		},
 	}
 
+	if upgradeK8sCmdFlags.rollbackState == "" {
+		var err error
+
+		if upgradeK8sCmdFlags.rollbackState, err = defaultRollbackStatePath(); err != nil {
+			return err
+		}
+	}
+
+	if upgradeK8sCmdFlags.rollback {
+		return rollbackKubernetes(ctx, &state)
+	}
+
 	var err error
 
 	if upgradeOptions.FromVersion == "" {
@@ -66,6 +104,23 @@ func upgradeKubernetes(ctx context.Context, c *client.Client) error {
 		upgradeOptions.Log("automatically detected the lowest Kubernetes version %s", upgradeOptions.FromVersion)
 	}
 
+	upgradeOptions.Log("running pre-flight checks for upgrade from %s to %s", upgradeOptions.FromVersion, upgradeOptions.ToVersion)
+
+	report, err := k8s.PreflightChecks(ctx, &state, upgradeOptions)
+	if err != nil {
+		return fmt.Errorf("error running pre-flight checks: %w", err)
+	}
+
+	k8s.LogPreflightReport(report, upgradeOptions)
+
+	if report.Blocking() {
+		return fmt.Errorf("pre-flight checks found issues blocking the upgrade")
+	}
+
+	if upgradeK8sCmdFlags.dryRun {
+		return nil
+	}
+
 	selfHosted, err := k8s.IsSelfHostedControlPlane(ctx, &state, Nodes[0])
 	if err != nil {
 		return fmt.Errorf("error checking self-hosted status: %w", err)
@@ -75,5 +130,123 @@ func upgradeKubernetes(ctx context.Context, c *client.Client) error {
 		return k8s.UpgradeSelfHosted(ctx, &state, upgradeOptions)
 	}
 
-	return k8s.UpgradeTalosManaged(ctx, &state, upgradeOptions)
+	rollbackState, err := loadRollbackState()
+	if err != nil {
+		return err
+	}
+
+	if rollbackState == nil || rollbackState.ToVersion != upgradeOptions.ToVersion {
+		rollbackState = &k8s.RollbackState{}
+	}
+
+	upgradeOptions.Rollback = rollbackState
+
+	upgradeErr := k8s.UpgradeTalosManaged(ctx, &state, upgradeOptions)
+
+	if len(rollbackState.Entries) > 0 {
+		if err = saveRollbackState(rollbackState); err != nil {
+			return err
+		}
+	}
+
+	if upgradeErr != nil && len(rollbackState.Entries) > 0 {
+		return fmt.Errorf("%w\nupgrade can be rolled back with 'talosctl upgrade-k8s --rollback'", upgradeErr)
+	}
+
+	return upgradeErr
+}
+
+func rollbackKubernetes(ctx context.Context, state k8s.UpgradeProvider) error {
+	selfHosted, err := k8s.IsSelfHostedControlPlane(ctx, state, Nodes[0])
+	if err != nil {
+		return fmt.Errorf("error checking self-hosted status: %w", err)
+	}
+
+	if selfHosted {
+		return fmt.Errorf("rollback is not supported for self-hosted control plane")
+	}
+
+	rollbackState, err := loadRollbackState()
+	if err != nil {
+		return err
+	}
+
+	if rollbackState == nil || len(rollbackState.Entries) == 0 {
+		return fmt.Errorf("no upgrade recorded in %q", upgradeK8sCmdFlags.rollbackState)
+	}
+
+	if err = k8s.RollbackTalosManaged(ctx, state, rollbackState, upgradeOptions); err != nil {
+		return err
+	}
+
+	return os.Remove(upgradeK8sCmdFlags.rollbackState)
+}
+
+// defaultRollbackStatePath returns the rollback state path for the current talosconfig context,
+// so that the upgrades of different clusters don't overwrite each other's state.
+func defaultRollbackStatePath() (string, error) {
+	contextName := Cmdcontext
+
+	if contextName == "" {
+		cfg, err := clientconfig.Open(Talosconfig)
+		if err != nil {
+			return "", fmt.Errorf("failed to open config file %q: %w", Talosconfig, err)
+		}
+
+		contextName = cfg.Context
+	}
+
+	if contextName == "" {
+		return "", fmt.Errorf("talosconfig context is not set, please use `--rollback-state` to set the rollback state path")
+	}
+
+	talosDir, err := clientconfig.GetTalosDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(talosDir, "upgrade-k8s-rollback", rollbackStateFilename(contextName)), nil
+}
+
+// rollbackStateFilename converts the context name (e.g. `admin@cluster`) into a file name.
+func rollbackStateFilename(contextName string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == filepath.Separator || r == 0 {
+			return '_'
+		}
+
+		return r
+	}, contextName) + ".yaml"
+}
+
+func loadRollbackState() (*k8s.RollbackState, error) {
+	data, err := ioutil.ReadFile(upgradeK8sCmdFlags.rollbackState)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("error reading rollback state: %w", err)
+	}
+
+	var rollbackState k8s.RollbackState
+
+	if err = yaml.Unmarshal(data, &rollbackState); err != nil {
+		return nil, fmt.Errorf("error parsing rollback state: %w", err)
+	}
+
+	return &rollbackState, nil
+}
+
+func saveRollbackState(rollbackState *k8s.RollbackState) error {
+	data, err := yaml.Marshal(rollbackState)
+	if err != nil {
+		return fmt.Errorf("error marshaling rollback state: %w", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(upgradeK8sCmdFlags.rollbackState), 0o700); err != nil {
+		return fmt.Errorf("error creating rollback state directory: %w", err)
+	}
+
+	return ioutil.WriteFile(upgradeK8sCmdFlags.rollbackState, data, 0o600)
 }
@@ -0,0 +1,37 @@
+#!/toolchain/bin/bash
+
+set -e
+
+export PATH=/toolchain/bin
+
+PREFIX="${1}"
+
+# Tools which are executed by machined to manage filesystems.
+BINARIES=(
+    e2fsck
+    mkfs.ext4
+    mkfs.vfat
+    mkfs.xfs
+    resize2fs
+    xfs_growfs
+    xfs_repair
+)
+
+missing=0
+
+for bin in "${BINARIES[@]}"; do
+    found=0
+
+    for dir in sbin bin usr/sbin usr/bin; do
+        if [ -x "${PREFIX}/${dir}/${bin}" ]; then
+            found=1
+        fi
+    done
+
+    if [ ${found} == 0 ]; then
+        echo "${bin} is missing in the rootfs"
+        missing=1
+    fi
+done
+
+exit ${missing}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"net/http"
 	"regexp"
 
 	"github.com/cosi-project/runtime/api/v1alpha1"
@@ -21,6 +22,7 @@ import (
 
 	apidbackend "github.com/talos-systems/talos/internal/app/apid/pkg/backend"
 	"github.com/talos-systems/talos/internal/app/apid/pkg/director"
+	"github.com/talos-systems/talos/internal/app/apid/pkg/metrics"
 	"github.com/talos-systems/talos/internal/app/apid/pkg/provider"
 	"github.com/talos-systems/talos/pkg/grpc/factory"
 	"github.com/talos-systems/talos/pkg/grpc/middleware/authz"
@@ -29,7 +31,10 @@ import (
 	"github.com/talos-systems/talos/pkg/startup"
 )
 
-var rbacEnabled *bool
+var (
+	rbacEnabled    *bool
+	metricsEnabled *bool
+)
 
 func runDebugServer(ctx context.Context) {
 	const debugAddr = ":9981"
@@ -48,6 +53,7 @@ func Main() {
 	log.SetFlags(log.Lshortfile | log.Ldate | log.Lmicroseconds | log.Ltime)
 
 	rbacEnabled = flag.Bool("enable-rbac", false, "enable RBAC for Talos API")
+	metricsEnabled = flag.Bool("enable-metrics", false, "enable Prometheus metrics endpoint")
 
 	flag.Parse()
 
@@ -96,6 +102,7 @@ func Main() {
 		"/machine.MachineService/List",
 		"/machine.MachineService/Logs",
 		"/machine.MachineService/Read",
+		"/machine.MachineService/Wipe",
 		"/resource.ResourceService/List",
 		"/resource.ResourceService/Watch",
 		"/os.OSService/Dmesg",
@@ -135,11 +142,29 @@ func Main() {
 						proxy.WithStreamedDetector(router.StreamedDetector),
 					)),
 			),
+			factory.WithUnaryInterceptor(metrics.UnaryInterceptor()),
+			factory.WithStreamInterceptor(metrics.StreamInterceptor()),
 			factory.WithUnaryInterceptor(injector.UnaryInterceptor()),
 			factory.WithStreamInterceptor(injector.StreamInterceptor()),
 		)
 	})
 
+	if *metricsEnabled {
+		errGroup.Go(func() error {
+			listener, err := factory.NewListener(factory.Port(constants.MetricsPort))
+			if err != nil {
+				return err
+			}
+
+			server := &http.Server{
+				Handler:   metrics.NewHandler(constants.MachineMetricsSocketPath, *rbacEnabled),
+				TLSConfig: serverTLSConfig.Clone(),
+			}
+
+			return server.ServeTLS(listener, "", "")
+		})
+	}
+
 	errGroup.Go(func() error {
 		injector := &authz.Injector{
 			Mode:   authz.MetadataOnly,
@@ -0,0 +1,107 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/talos-systems/talos/pkg/machinery/role"
+)
+
+// NewHandler returns http.Handler which serves apid metrics merged with the metrics of machined.
+//
+// If RBAC is enabled, client certificate should have either admin or reader role.
+func NewHandler(machinedSocketPath string, rbacEnabled bool) http.Handler {
+	handler := promhttp.HandlerFor(
+		prometheus.Gatherers{
+			Registry,
+			&socketGatherer{path: machinedSocketPath},
+		},
+		promhttp.HandlerOpts{
+			ErrorHandling: promhttp.ContinueOnError,
+		},
+	)
+
+	if !rbacEnabled {
+		return handler
+	}
+
+	allowedRoles := role.MakeSet(role.Admin, role.Reader)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) != 1 {
+			http.Error(w, "client certificate is required", http.StatusUnauthorized)
+
+			return
+		}
+
+		roles, _ := role.Parse(r.TLS.PeerCertificates[0].Subject.Organization)
+		if !roles.IncludesAny(allowedRoles) {
+			http.Error(w, "access denied", http.StatusForbidden)
+
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// socketGatherer fetches metrics in text format over the file socket.
+type socketGatherer struct {
+	path string
+}
+
+const socketGatherTimeout = 10 * time.Second
+
+// Gather implements prometheus.Gatherer interface.
+func (g *socketGatherer) Gather() ([]*dto.MetricFamily, error) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+
+				return d.DialContext(ctx, "unix", g.path)
+			},
+		},
+		Timeout: socketGatherTimeout,
+	}
+
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Get("http://localhost/metrics")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching metrics from %q: %w", g.path, err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching metrics from %q: %s", g.path, resp.Status)
+	}
+
+	var parser expfmt.TextParser
+
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics from %q: %w", g.path, err)
+	}
+
+	result := make([]*dto.MetricFamily, 0, len(families))
+
+	for _, family := range families {
+		result = append(result, family)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,64 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metrics_test
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/app/apid/pkg/metrics"
+)
+
+func serveSocket(t *testing.T) string {
+	socketPath := filepath.Join(t.TempDir(), "metrics.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "# HELP talos_test_metric Test metric.")
+			fmt.Fprintln(w, "# TYPE talos_test_metric gauge")
+			fmt.Fprintln(w, "talos_test_metric 42")
+		}),
+	}
+
+	go server.Serve(listener) //nolint:errcheck
+
+	t.Cleanup(func() { server.Close() }) //nolint:errcheck
+
+	return socketPath
+}
+
+func TestHandler(t *testing.T) {
+	handler := metrics.NewHandler(serveSocket(t), false)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), "talos_test_metric 42")
+}
+
+func TestHandlerRBAC(t *testing.T) {
+	handler := metrics.NewHandler(serveSocket(t), true)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
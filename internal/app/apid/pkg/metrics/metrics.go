@@ -0,0 +1,61 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package metrics implements Prometheus metrics endpoint of apid.
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Registry holds apid own metrics.
+//
+// Go runtime metrics are not registered, as the ones from machined are served.
+var Registry = prometheus.NewRegistry()
+
+var requestDurationMetric = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "talos",
+	Subsystem: "apid",
+	Name:      "request_duration_seconds",
+	Help:      "Duration of the API requests handled by apid.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "code"})
+
+func observe(method string, start time.Time, err error) {
+	requestDurationMetric.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+}
+
+// UnaryInterceptor returns grpc UnaryServerInterceptor which records request duration.
+func UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		observe(info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+// StreamInterceptor returns grpc StreamServerInterceptor which records request duration.
+//
+// Proxied requests are always handled as streams, so this covers both unary and streaming API methods.
+func StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		err := handler(srv, stream)
+
+		observe(info.FullMethod, start, err)
+
+		return err
+	}
+}
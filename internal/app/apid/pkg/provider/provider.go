@@ -8,6 +8,7 @@ package provider
 import (
 	"context"
 	stdlibtls "crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"log"
 	"sync"
@@ -71,31 +72,88 @@ func NewTLSConfig(resources state.State) (*TLSConfig, error) {
 }
 
 // ServerConfig generates server-side tls.Config.
+//
+// Client certificate is verified against the current CA bundle, as it might change while apid is running
+// (e.g. during CA rotation).
 func (tlsConfig *TLSConfig) ServerConfig() (*stdlibtls.Config, error) {
 	ca, err := tlsConfig.certificateProvider.GetCA()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get root CA: %w", err)
 	}
 
-	return tls.New(
+	serverConfig, err := tls.New(
 		tls.WithClientAuthType(tls.Mutual),
 		tls.WithCACertPEM(ca),
 		tls.WithServerCertificateProvider(tlsConfig.certificateProvider),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// default verification is replaced with VerifyConnection below
+	serverConfig.ClientAuth = stdlibtls.RequireAnyClientCert
+	serverConfig.VerifyConnection = func(cs stdlibtls.ConnectionState) error {
+		return tlsConfig.verifyConnection(cs, "", x509.ExtKeyUsageClientAuth)
+	}
+
+	return serverConfig, nil
 }
 
 // ClientConfig generates client-side tls.Config.
+//
+// Server certificate is verified against the current CA bundle, as it might change while apid is running.
 func (tlsConfig *TLSConfig) ClientConfig() (*stdlibtls.Config, error) {
 	ca, err := tlsConfig.certificateProvider.GetCA()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get root CA: %w", err)
 	}
 
-	return tls.New(
+	clientConfig, err := tls.New(
 		tls.WithClientAuthType(tls.Mutual),
 		tls.WithCACertPEM(ca),
 		tls.WithClientCertificateProvider(tlsConfig.certificateProvider),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// default verification is replaced with VerifyConnection below
+	clientConfig.InsecureSkipVerify = true
+	clientConfig.VerifyConnection = func(cs stdlibtls.ConnectionState) error {
+		return tlsConfig.verifyConnection(cs, cs.ServerName, x509.ExtKeyUsageServerAuth)
+	}
+
+	return clientConfig, nil
+}
+
+func (tlsConfig *TLSConfig) verifyConnection(cs stdlibtls.ConnectionState, dnsName string, keyUsage x509.ExtKeyUsage) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("no peer certificates")
+	}
+
+	ca, err := tlsConfig.certificateProvider.GetCA()
+	if err != nil {
+		return fmt.Errorf("failed to get root CA: %w", err)
+	}
+
+	opts := x509.VerifyOptions{
+		DNSName:       dnsName,
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{keyUsage},
+	}
+
+	if ok := opts.Roots.AppendCertsFromPEM(ca); !ok {
+		return fmt.Errorf("failed to append CA certificates")
+	}
+
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	_, err = cs.PeerCertificates[0].Verify(opts)
+
+	return err
 }
 
 type certificateProvider struct {
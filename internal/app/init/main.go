@@ -18,6 +18,7 @@ import (
 	"github.com/talos-systems/go-procfs/procfs"
 	"golang.org/x/sys/unix"
 
+	"github.com/talos-systems/talos/internal/pkg/extensions"
 	"github.com/talos-systems/talos/internal/pkg/mount"
 	"github.com/talos-systems/talos/internal/pkg/mount/switchroot"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
@@ -60,6 +61,11 @@ func run() (err error) {
 		return err
 	}
 
+	// Layer the system extensions over the rootfs.
+	if err = extensions.Mount(constants.ExtensionsConfigFile, constants.ExtensionKeysPath, constants.NewRoot); err != nil {
+		return err
+	}
+
 	// Switch into the new rootfs.
 	log.Println("entering the rootfs")
 
@@ -0,0 +1,98 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/resources/secrets"
+)
+
+// RenewCertificates implements the machine.MachineServer interface.
+//
+// Certificates are regenerated by the controllers when the renewal is requested.
+// Kubernetes static pods are restarted automatically as the rendered secrets change,
+// while etcd is restarted explicitly once new certificates are generated.
+func (s *Server) RenewCertificates(ctx context.Context, in *machine.RenewCertificatesRequest) (*machine.RenewCertificatesResponse, error) {
+	resources := s.Controller.Runtime().State().V1Alpha2().Resources()
+
+	etcdMetadata := resource.NewMetadata(secrets.NamespaceName, secrets.EtcdType, secrets.EtcdID, resource.VersionUndefined)
+
+	etcdSecrets, err := resources.Get(ctx, etcdMetadata)
+	if err != nil && !state.IsNotFoundError(err) {
+		return nil, fmt.Errorf("error getting etcd secrets: %w", err)
+	}
+
+	if err = requestCertificateRenewal(ctx, resources); err != nil {
+		return nil, fmt.Errorf("error requesting certificate renewal: %w", err)
+	}
+
+	log.Printf("certificate renewal requested")
+
+	reply := &machine.RenewCertificatesResponse{
+		Messages: []*machine.RenewCertificates{
+			{},
+		},
+	}
+
+	// etcd secrets are only present on control plane nodes
+	if etcdSecrets == nil {
+		return reply, nil
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, time.Minute)
+	defer waitCancel()
+
+	if _, err = resources.WatchFor(waitCtx, etcdMetadata, state.WithCondition(func(r resource.Resource) (bool, error) {
+		return !r.Metadata().Version().Equal(etcdSecrets.Metadata().Version()), nil
+	})); err != nil {
+		return nil, fmt.Errorf("error waiting for etcd certificates to be renewed: %w", err)
+	}
+
+	if _, running, _ := system.Services(s.Controller.Runtime()).IsRunning("etcd"); !running { //nolint:errcheck
+		return reply, nil
+	}
+
+	log.Printf("etcd certificates renewed, restarting etcd")
+
+	if err = system.Services(s.Controller.Runtime()).Stop(ctx, "etcd"); err != nil {
+		return nil, fmt.Errorf("error stopping etcd: %w", err)
+	}
+
+	if err = system.Services(s.Controller.Runtime()).Start("etcd"); err != nil {
+		return nil, fmt.Errorf("error starting etcd: %w", err)
+	}
+
+	reply.Messages[0].RestartedServices = append(reply.Messages[0].RestartedServices, "etcd")
+
+	return reply, nil
+}
+
+func requestCertificateRenewal(ctx context.Context, resources state.State) error {
+	renewal := secrets.NewCertificateRenewal()
+	renewal.TypedSpec().RequestedAt = time.Now()
+
+	existing, err := resources.Get(ctx, renewal.Metadata())
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return resources.Create(ctx, renewal)
+		}
+
+		return err
+	}
+
+	renewal.Metadata().SetVersion(existing.Metadata().Version())
+	renewal.Metadata().BumpVersion()
+
+	return resources.Update(ctx, existing.Metadata().Version(), renewal)
+}
@@ -7,9 +7,12 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/cosi-project/runtime/pkg/controller"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	inspectapi "github.com/talos-systems/talos/pkg/machinery/api/inspect"
 )
@@ -66,3 +69,40 @@ func (s *InspectServer) ControllerRuntimeDependencies(ctx context.Context, in *e
 		},
 	}, nil
 }
+
+// ControllerRuntimeHistory implements inspect.InspectService interface.
+func (s *InspectServer) ControllerRuntimeHistory(ctx context.Context, in *emptypb.Empty) (*inspectapi.ControllerRuntimeHistoryResponse, error) {
+	history := s.server.Controller.V1Alpha2().ReconcileHistory()
+
+	controllers := make([]*inspectapi.ControllerHistory, 0, len(history))
+
+	for name, runs := range history {
+		controllerHistory := &inspectapi.ControllerHistory{
+			ControllerName: name,
+			Runs:           make([]*inspectapi.ControllerReconcileRun, 0, len(runs)),
+		}
+
+		for _, run := range runs {
+			controllerHistory.Runs = append(controllerHistory.Runs, &inspectapi.ControllerReconcileRun{
+				Start:            timestamppb.New(run.Start),
+				Duration:         durationpb.New(run.Duration),
+				Error:            run.Error,
+				TouchedResources: run.TouchedResources,
+			})
+		}
+
+		controllers = append(controllers, controllerHistory)
+	}
+
+	sort.Slice(controllers, func(i, j int) bool {
+		return controllers[i].ControllerName < controllers[j].ControllerName
+	})
+
+	return &inspectapi.ControllerRuntimeHistoryResponse{
+		Messages: []*inspectapi.ControllerRuntimeHistory{
+			{
+				Controllers: controllers,
+			},
+		},
+	}, nil
+}
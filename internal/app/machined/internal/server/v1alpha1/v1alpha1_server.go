@@ -57,11 +57,14 @@ import (
 	"github.com/talos-systems/talos/internal/pkg/containers/cri"
 	"github.com/talos-systems/talos/internal/pkg/containers/image"
 	"github.com/talos-systems/talos/internal/pkg/etcd"
+	"github.com/talos-systems/talos/internal/pkg/kernel/kmod"
+	"github.com/talos-systems/talos/internal/pkg/kernel/pstore"
 	"github.com/talos-systems/talos/internal/pkg/kubeconfig"
 	"github.com/talos-systems/talos/internal/pkg/mount"
 	"github.com/talos-systems/talos/pkg/archiver"
 	"github.com/talos-systems/talos/pkg/chunker"
 	"github.com/talos-systems/talos/pkg/chunker/stream"
+	"github.com/talos-systems/talos/pkg/grpc/gen"
 	"github.com/talos-systems/talos/pkg/machinery/api/cluster"
 	"github.com/talos-systems/talos/pkg/machinery/api/common"
 	"github.com/talos-systems/talos/pkg/machinery/api/inspect"
@@ -129,6 +132,10 @@ func (s *Server) Register(obj *grpc.Server) {
 func (s *Server) ApplyConfiguration(ctx context.Context, in *machine.ApplyConfigurationRequest) (*machine.ApplyConfigurationResponse, error) {
 	log.Printf("apply config request: immediate %v, on reboot %v", in.Immediate, in.OnReboot)
 
+	if err := s.checkKernelModules(in.GetData()); err != nil {
+		return nil, err
+	}
+
 	applyDynamicConfig := func() ([]byte, error) {
 		cfg, err := s.Controller.Runtime().ValidateConfig(in.GetData())
 		if err != nil {
@@ -155,6 +162,8 @@ func (s *Server) ApplyConfiguration(ctx context.Context, in *machine.ApplyConfig
 			return nil, err
 		}
 
+		oldSecurity := s.Controller.Runtime().Config().Machine().Security()
+
 		if err := s.Controller.Runtime().SetConfig(cfg); err != nil {
 			return nil, err
 		}
@@ -162,6 +171,10 @@ func (s *Server) ApplyConfiguration(ctx context.Context, in *machine.ApplyConfig
 		if err := ioutil.WriteFile(constants.ConfigPath, in.GetData(), 0o600); err != nil {
 			return nil, err
 		}
+
+		if err := s.restartTrustdOnCAChange(ctx, oldSecurity); err != nil {
+			return nil, err
+		}
 	// default (no flags)
 	case !in.OnReboot:
 		if err := s.Controller.Runtime().SetConfig(in.GetData()); err != nil {
@@ -198,6 +211,78 @@ func (s *Server) ApplyConfiguration(ctx context.Context, in *machine.ApplyConfig
 	}, nil
 }
 
+// checkKernelModules verifies that the kernel modules listed in the machine config are shipped with the Talos image.
+//
+// The check is done only when the config is applied, so that the node still boots if the module is gone after an upgrade.
+func (s *Server) checkKernelModules(data []byte) error {
+	if s.Controller.Runtime().State().Platform().Mode() == runtime.ModeContainer {
+		return nil
+	}
+
+	cfg, err := s.Controller.Runtime().ValidateConfig(data)
+	if err != nil {
+		return err
+	}
+
+	modules := cfg.Machine().Kernel().Modules()
+	if len(modules) == 0 {
+		return nil
+	}
+
+	root, err := kmod.DefaultRoot()
+	if err != nil {
+		return err
+	}
+
+	idx, err := kmod.NewIndex(root)
+	if err != nil {
+		return fmt.Errorf("error reading kernel modules index: %w", err)
+	}
+
+	for _, module := range modules {
+		if !idx.Has(module.Name()) {
+			return fmt.Errorf("kernel module %q is not shipped with the Talos image", module.Name())
+		}
+	}
+
+	return nil
+}
+
+// restartTrustdOnCAChange restarts trustd if the machine CA or accepted CAs were changed.
+//
+// trustd reads the machine configuration on startup, so it needs to be restarted
+// to sign certificates with the new CA and to return the updated CA trust bundle.
+func (s *Server) restartTrustdOnCAChange(ctx context.Context, oldSecurity config.Security) error {
+	newSecurity := s.Controller.Runtime().Config().Machine().Security()
+
+	if bytes.Equal(caBundle(oldSecurity), caBundle(newSecurity)) {
+		return nil
+	}
+
+	if _, running, err := system.Services(s.Controller.Runtime()).IsRunning("trustd"); err != nil || !running {
+		// trustd is not running on worker nodes
+		return nil //nolint:nilerr
+	}
+
+	log.Printf("machine CA changed, restarting trustd")
+
+	if err := system.Services(s.Controller.Runtime()).Stop(ctx, "trustd"); err != nil {
+		return fmt.Errorf("error stopping trustd: %w", err)
+	}
+
+	return system.Services(s.Controller.Runtime()).Start("trustd")
+}
+
+func caBundle(security config.Security) []byte {
+	var ca []byte
+
+	if security.CA() != nil {
+		ca = security.CA().Crt
+	}
+
+	return gen.AcceptedCABundle(ca, security.AcceptedCAs())
+}
+
 // GenerateConfiguration implements the machine.MachineServer interface.
 func (s *Server) GenerateConfiguration(ctx context.Context, in *machine.GenerateConfigurationRequest) (reply *machine.GenerateConfigurationResponse, err error) {
 	if s.Controller.Runtime().Config().Machine().Type() == machinetype.TypeWorker {
@@ -504,6 +589,7 @@ type ResetOptions struct {
 	*machine.ResetRequest
 
 	systemDiskTargets []*installer.Target
+	wipeOptions       *wipeOptions
 }
 
 // GetSystemDiskTargets implements runtime.ResetOptions interface.
@@ -521,6 +607,51 @@ func (opt *ResetOptions) GetSystemDiskTargets() []runtime.PartitionTarget {
 	return result
 }
 
+// GetWipeOptions implements runtime.ResetOptions interface.
+func (opt *ResetOptions) GetWipeOptions() runtime.WipeOptions {
+	if opt.wipeOptions == nil {
+		return nil
+	}
+
+	return opt.wipeOptions
+}
+
+// wipeOptions implements runtime.WipeOptions interface.
+//
+// Progress is forwarded to the Wipe API stream until the request is done.
+type wipeOptions struct {
+	*machine.WipeRequest
+
+	progressCh chan<- *machine.WipeProgress
+	doneCh     <-chan struct{}
+}
+
+// Progress implements runtime.WipeOptions interface.
+//
+// Progress updates are dropped if the client doesn't keep up, so that the wipe is never blocked by the client.
+func (opt *wipeOptions) Progress(device string, done, total uint64) {
+	select {
+	case opt.progressCh <- &machine.WipeProgress{
+		Device:     device,
+		BytesDone:  done,
+		BytesTotal: total,
+	}:
+	default:
+	}
+}
+
+// Report implements runtime.WipeOptions interface.
+//
+// Report is never dropped, it's sent unless the request is done.
+func (opt *wipeOptions) Report(report *machine.WipeReport) {
+	select {
+	case opt.progressCh <- &machine.WipeProgress{
+		Report: report,
+	}:
+	case <-opt.doneCh:
+	}
+}
+
 // Reset resets the node.
 //
 //nolint:gocyclo
@@ -558,14 +689,24 @@ func (s *Server) Reset(ctx context.Context, in *machine.ResetRequest) (reply *ma
 			case constants.EphemeralPartitionLabel:
 				target = installer.EphemeralTarget(bd.Device().Name(), installer.NoFilesystem)
 			default:
-				return nil, fmt.Errorf("label %q is not supported", spec.Label)
+				if !s.isInstallExtraPartition(spec.Label) {
+					return nil, fmt.Errorf("label %q is not supported", spec.Label)
+				}
+
+				target = installer.ExtraPartitionTarget(bd.Device().Name(), installer.ExtraPartition{Label: spec.Label})
 			}
 
-			_, err = target.Locate(pt)
+			var part *gpt.Partition
+
+			part, err = target.Locate(pt)
 			if err != nil {
 				return nil, fmt.Errorf("failed location partition with label %q: %w", spec.Label, err)
 			}
 
+			if part == nil && target.PreserveExisting {
+				return nil, fmt.Errorf("partition with label %q doesn't exist", spec.Label)
+			}
+
 			if spec.Wipe {
 				opts.systemDiskTargets = append(opts.systemDiskTargets, target)
 			}
@@ -595,6 +736,89 @@ func (s *Server) Reset(ctx context.Context, in *machine.ResetRequest) (reply *ma
 	return reply, nil
 }
 
+// wipeProgressBuffer is the number of progress updates buffered for the Wipe API stream.
+const wipeProgressBuffer = 32
+
+// Wipe resets the node wiping the system and user disks.
+//
+// The progress is streamed back until the signed report is sent.
+func (s *Server) Wipe(in *machine.WipeRequest, srv machine.MachineService_WipeServer) error {
+	log.Printf("wipe request received")
+
+	if s.Controller.Runtime().State().Platform().Mode() == runtime.ModeContainer {
+		return status.Error(codes.FailedPrecondition, "wipe is not supported in container mode")
+	}
+
+	progressCh := make(chan *machine.WipeProgress, wipeProgressBuffer)
+	doneCh := make(chan struct{})
+
+	defer close(doneCh)
+
+	opts := ResetOptions{
+		ResetRequest: &machine.ResetRequest{
+			Graceful: in.GetGraceful(),
+			Reboot:   in.GetReboot(),
+		},
+		wipeOptions: &wipeOptions{
+			WipeRequest: in,
+			progressCh:  progressCh,
+			doneCh:      doneCh,
+		},
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		err := s.Controller.Run(context.Background(), runtime.SequenceReset, &opts)
+		errCh <- err
+
+		if err != nil {
+			if !runtime.IsRebootError(err) {
+				log.Println("wipe failed:", err)
+			}
+
+			if err != runtime.ErrLocked {
+				// NB: We stop the gRPC server since a failed sequence triggers a
+				// reboot.
+				s.server.GracefulStop()
+			}
+		}
+	}()
+
+	for {
+		select {
+		case msg := <-progressCh:
+			if err := srv.Send(msg); err != nil {
+				return err
+			}
+
+			if msg.Report != nil {
+				return nil
+			}
+		case err := <-errCh:
+			return err
+		case <-srv.Context().Done():
+			return srv.Context().Err()
+		}
+	}
+}
+
+// isInstallExtraPartition checks whether the label belongs to one of the extra partitions of the system disk.
+func (s *Server) isInstallExtraPartition(label string) bool {
+	cfg := s.Controller.Runtime().Config()
+	if cfg == nil {
+		return false
+	}
+
+	for _, part := range cfg.Machine().Install().ExtraPartitions() {
+		if part.Label() == label {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ServiceList returns list of the registered services and their status.
 func (s *Server) ServiceList(ctx context.Context, in *emptypb.Empty) (result *machine.ServiceListResponse, err error) {
 	services := system.Services(s.Controller.Runtime()).List()
@@ -1493,6 +1717,10 @@ func (s *Server) Restart(ctx context.Context, in *machine.RestartRequest) (*mach
 func (s *Server) Dmesg(req *machine.DmesgRequest, srv machine.MachineService_DmesgServer) error {
 	ctx := srv.Context()
 
+	if req.Previous {
+		return s.dmesgPrevious(srv)
+	}
+
 	var options []kmsg.Option
 
 	if req.Follow {
@@ -1542,6 +1770,32 @@ func (s *Server) Dmesg(req *machine.DmesgRequest, srv machine.MachineService_Dme
 	}
 }
 
+// dmesgPrevious sends kernel crash records saved from the previous boot line by line.
+func (s *Server) dmesgPrevious(srv machine.MachineService_DmesgServer) error {
+	records, err := pstore.Records(constants.PstoreArchivePath)
+	if err != nil {
+		return fmt.Errorf("error reading kernel crash records: %w", err)
+	}
+
+	for _, record := range records {
+		scanner := bufio.NewScanner(bytes.NewReader(record.Data))
+
+		for scanner.Scan() {
+			if err = srv.Send(&common.Data{
+				Bytes: []byte(fmt.Sprintf("%s: %s\n", record.Name, scanner.Text())),
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err = scanner.Err(); err != nil {
+			return fmt.Errorf("error reading kernel crash record %q: %w", record.Name, err)
+		}
+	}
+
+	return nil
+}
+
 // Processes implements the machine.MachineServer interface.
 func (s *Server) Processes(ctx context.Context, in *emptypb.Empty) (reply *machine.ProcessesResponse, err error) {
 	procs, err := procfs.AllProcs()
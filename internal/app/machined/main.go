@@ -29,6 +29,7 @@ import (
 	v1alpha1runtime "github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/v1alpha1/bootloader"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/process"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/services"
 	"github.com/talos-systems/talos/internal/app/trustd"
 	"github.com/talos-systems/talos/internal/pkg/mount"
@@ -214,6 +215,9 @@ func run() error {
 	// Schedule service shutdown on any return.
 	defer system.Services(c.Runtime()).Shutdown(ctx)
 
+	// Allow services to escalate to the reboot sequence.
+	system.Services(c.Runtime()).SetController(c)
+
 	// Start signal and ACPI listeners.
 	go func() {
 		if e := c.ListenForEvents(ctx); e != nil {
@@ -281,6 +285,10 @@ func main() {
 	case "/trustd":
 		trustd.Main()
 
+		return
+	case process.CgroupExecName:
+		process.CgroupExec()
+
 		return
 	default:
 	}
@@ -0,0 +1,189 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package block provides controllers which manage block device resources.
+package block
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"go.uber.org/zap"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/block/watch"
+	v1alpha1runtime "github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/sysblock"
+	"github.com/talos-systems/talos/pkg/resources/block"
+)
+
+// DefaultResyncInterval is the interval to rescan block devices even if there were no uevents.
+//
+// Mount state changes don't produce uevents, so they are picked up on resync.
+const DefaultResyncInterval = 30 * time.Second
+
+// DevicesController publishes BlockDevices and Partitions based on sysfs, udev database and mount table.
+type DevicesController struct {
+	V1Alpha1Mode v1alpha1runtime.Mode
+	// List returns block devices, defaults to the sysblock.List of the running system.
+	List           func() ([]*sysblock.Device, error)
+	ResyncInterval time.Duration
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *DevicesController) Name() string {
+	return "block.DevicesController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *DevicesController) Inputs() []controller.Input {
+	return nil
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *DevicesController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: block.BlockDeviceType,
+			Kind: controller.OutputExclusive,
+		},
+		{
+			Type: block.PartitionType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *DevicesController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	// host block devices are not managed in the container
+	if ctrl.V1Alpha1Mode == v1alpha1runtime.ModeContainer {
+		return nil
+	}
+
+	if ctrl.List == nil {
+		ctrl.List = func() ([]*sysblock.Device, error) {
+			return sysblock.List(sysblock.DefaultPaths())
+		}
+	}
+
+	if ctrl.ResyncInterval == 0 {
+		ctrl.ResyncInterval = DefaultResyncInterval
+	}
+
+	ueventWatcher, err := watch.NewUevent(r)
+	if err != nil {
+		logger.Warn("uevent watcher failed to start, falling back to periodic resync", zap.Error(err))
+	} else {
+		defer ueventWatcher.Done()
+	}
+
+	ticker := time.NewTicker(ctrl.ResyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-ticker.C:
+		}
+
+		if err = ctrl.reconcile(ctx, r); err != nil {
+			return err
+		}
+	}
+}
+
+//nolint:gocyclo
+func (ctrl *DevicesController) reconcile(ctx context.Context, r controller.Runtime) error {
+	devices, err := ctrl.List()
+	if err != nil {
+		return fmt.Errorf("error listing block devices: %w", err)
+	}
+
+	touchedDevices := map[resource.ID]struct{}{}
+	touchedPartitions := map[resource.ID]struct{}{}
+
+	for _, dev := range devices {
+		dev := dev
+
+		if dev.IsPartition() {
+			touchedPartitions[dev.Name] = struct{}{}
+
+			if err = r.Modify(ctx, block.NewPartition(block.NamespaceName, dev.Name), func(res resource.Resource) error {
+				*res.(*block.Partition).TypedSpec() = block.PartitionSpec{
+					DevPath:         dev.Path(),
+					Parent:          dev.Parent,
+					Number:          dev.PartitionNumber,
+					Size:            dev.Size,
+					Label:           dev.PartitionLabel,
+					UUID:            dev.PartitionUUID,
+					TypeUUID:        dev.PartitionType,
+					ReadOnly:        dev.ReadOnly,
+					Filesystem:      dev.Filesystem,
+					FilesystemLabel: dev.FilesystemLabel,
+					MountPoint:      dev.MountPoint,
+				}
+
+				return nil
+			}); err != nil {
+				return fmt.Errorf("error updating partition %q: %w", dev.Name, err)
+			}
+
+			continue
+		}
+
+		touchedDevices[dev.Name] = struct{}{}
+
+		if err = r.Modify(ctx, block.NewBlockDevice(block.NamespaceName, dev.Name), func(res resource.Resource) error {
+			*res.(*block.BlockDevice).TypedSpec() = block.BlockDeviceSpec{
+				DevPath:        dev.Path(),
+				Size:           dev.Size,
+				Model:          dev.Model,
+				Serial:         dev.Serial,
+				WWID:           dev.WWID,
+				Modalias:       dev.Modalias,
+				Transport:      dev.Transport,
+				Rotational:     dev.Rotational,
+				ReadOnly:       dev.ReadOnly,
+				Removable:      dev.Removable,
+				PartitionTable: dev.PartitionTable,
+				Filesystem:     dev.Filesystem,
+				MountPoint:     dev.MountPoint,
+			}
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error updating block device %q: %w", dev.Name, err)
+		}
+	}
+
+	for _, item := range []struct {
+		typ     resource.Type
+		touched map[resource.ID]struct{}
+	}{
+		{block.BlockDeviceType, touchedDevices},
+		{block.PartitionType, touchedPartitions},
+	} {
+		list, err := r.List(ctx, resource.NewMetadata(block.NamespaceName, item.typ, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing resources: %w", err)
+		}
+
+		for _, res := range list.Items {
+			if _, ok := item.touched[res.Metadata().ID()]; ok {
+				continue
+			}
+
+			if err = r.Destroy(ctx, res.Metadata()); err != nil {
+				return fmt.Errorf("error cleaning up %s: %w", res, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block_test
+
+import (
+	"context"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/stretchr/testify/suite"
+	"github.com/talos-systems/go-retry/retry"
+
+	blockctrl "github.com/talos-systems/talos/internal/app/machined/pkg/controllers/block"
+	"github.com/talos-systems/talos/internal/pkg/sysblock"
+	"github.com/talos-systems/talos/pkg/logging"
+	"github.com/talos-systems/talos/pkg/resources/block"
+)
+
+type mockDevices struct {
+	mu      sync.Mutex
+	devices []*sysblock.Device
+}
+
+func (m *mockDevices) list() ([]*sysblock.Device, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*sysblock.Device(nil), m.devices...), nil
+}
+
+func (m *mockDevices) set(devices ...*sysblock.Device) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.devices = devices
+}
+
+type DevicesSuite struct {
+	suite.Suite
+
+	state state.State
+
+	runtime *runtime.Runtime
+	wg      sync.WaitGroup
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+
+	devices *mockDevices
+}
+
+func (suite *DevicesSuite) SetupTest() {
+	suite.ctx, suite.ctxCancel = context.WithTimeout(context.Background(), 3*time.Minute)
+
+	suite.state = state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var err error
+
+	suite.runtime, err = runtime.NewRuntime(suite.state, logging.Wrap(log.Writer()))
+	suite.Require().NoError(err)
+
+	suite.devices = &mockDevices{}
+
+	suite.Require().NoError(suite.runtime.RegisterController(&blockctrl.DevicesController{
+		List:           suite.devices.list,
+		ResyncInterval: 100 * time.Millisecond,
+	}))
+
+	suite.wg.Add(1)
+
+	go func() {
+		defer suite.wg.Done()
+
+		suite.Assert().NoError(suite.runtime.Run(suite.ctx))
+	}()
+}
+
+func (suite *DevicesSuite) assertResource(typ resource.Type, id string, check func(resource.Resource) error) error {
+	res, err := suite.state.Get(suite.ctx, resource.NewMetadata(block.NamespaceName, typ, id, resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return retry.ExpectedError(err)
+		}
+
+		return err
+	}
+
+	return check(res)
+}
+
+func (suite *DevicesSuite) assertNoResource(typ resource.Type, id string) error {
+	_, err := suite.state.Get(suite.ctx, resource.NewMetadata(block.NamespaceName, typ, id, resource.VersionUndefined))
+	if err == nil {
+		return retry.ExpectedErrorf("resource %q still exists", id)
+	}
+
+	if state.IsNotFoundError(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (suite *DevicesSuite) retry(f func() error) {
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(f))
+}
+
+func (suite *DevicesSuite) TestReconcile() {
+	sda := &sysblock.Device{
+		Name:           "sda",
+		DevNum:         "8:0",
+		Size:           1 << 30,
+		Model:          "QEMU HARDDISK",
+		Serial:         "QM00001",
+		Transport:      "sata",
+		Rotational:     true,
+		PartitionTable: "gpt",
+	}
+	sda1 := &sysblock.Device{
+		Name:            "sda1",
+		DevNum:          "8:1",
+		Parent:          "sda",
+		PartitionNumber: 1,
+		Size:            1 << 20,
+		PartitionLabel:  "EPHEMERAL",
+		Filesystem:      "xfs",
+		MountPoint:      "/var",
+	}
+	sdb := &sysblock.Device{
+		Name:      "sdb",
+		DevNum:    "8:16",
+		Size:      1 << 31,
+		Transport: "usb",
+		Removable: true,
+	}
+
+	suite.devices.set(sda, sda1)
+
+	suite.retry(func() error {
+		if err := suite.assertResource(block.BlockDeviceType, "sda", func(res resource.Resource) error {
+			spec := res.(*block.BlockDevice).TypedSpec()
+
+			suite.Assert().Equal("/dev/sda", spec.DevPath)
+			suite.Assert().Equal(uint64(1<<30), spec.Size)
+			suite.Assert().Equal("QEMU HARDDISK", spec.Model)
+			suite.Assert().Equal("QM00001", spec.Serial)
+			suite.Assert().Equal("sata", spec.Transport)
+			suite.Assert().True(spec.Rotational)
+			suite.Assert().Equal("gpt", spec.PartitionTable)
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		return suite.assertResource(block.PartitionType, "sda1", func(res resource.Resource) error {
+			spec := res.(*block.Partition).TypedSpec()
+
+			suite.Assert().Equal("/dev/sda1", spec.DevPath)
+			suite.Assert().Equal("sda", spec.Parent)
+			suite.Assert().Equal(1, spec.Number)
+			suite.Assert().Equal("EPHEMERAL", spec.Label)
+			suite.Assert().Equal("xfs", spec.Filesystem)
+			suite.Assert().Equal("/var", spec.MountPoint)
+
+			return nil
+		})
+	})
+
+	// hotplug: sdb appears, partition of sda is removed
+	suite.devices.set(sda, sdb)
+
+	suite.retry(func() error {
+		if err := suite.assertResource(block.BlockDeviceType, "sdb", func(res resource.Resource) error {
+			spec := res.(*block.BlockDevice).TypedSpec()
+
+			if !spec.Removable || spec.Transport != "usb" {
+				return retry.ExpectedErrorf("unexpected spec %+v", spec)
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		return suite.assertNoResource(block.PartitionType, "sda1")
+	})
+
+	// unplug
+	suite.devices.set(sda)
+
+	suite.retry(func() error {
+		return suite.assertNoResource(block.BlockDeviceType, "sdb")
+	})
+}
+
+func (suite *DevicesSuite) TearDownTest() {
+	suite.T().Log("tear down")
+
+	suite.ctxCancel()
+
+	suite.wg.Wait()
+}
+
+func TestDevicesSuite(t *testing.T) {
+	suite.Run(t, new(DevicesSuite))
+}
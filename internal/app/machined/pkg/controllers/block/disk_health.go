@@ -0,0 +1,269 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"go.uber.org/zap"
+
+	v1alpha1runtime "github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/smart"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/resources/block"
+)
+
+// Disk health controller defaults.
+const (
+	DefaultDiskHealthPollInterval      = 5 * time.Minute
+	DefaultTemperatureThreshold        = 70
+	DefaultWearLevelThreshold          = 90
+	DefaultReallocatedSectorsThreshold = 8
+	DefaultMediaErrorsThreshold        = 1
+)
+
+// DiskHealthController reads SMART attributes and NVMe health logs of the block devices and publishes DiskHealth resources.
+//
+// DiskHealthEvent is published to the machine events when the disk crosses (or recovers from) the thresholds.
+type DiskHealthController struct {
+	V1Alpha1Mode   v1alpha1runtime.Mode
+	V1Alpha1Events v1alpha1runtime.Publisher
+	// Read defaults to reading the health from the device.
+	Read         func(devPath, transport string) (*smart.Health, error)
+	PollInterval time.Duration
+
+	// TemperatureThreshold is the temperature (Celsius) at which the disk is reported unhealthy.
+	TemperatureThreshold int
+	// WearLevelThreshold is the percentage of the rated life used at which the disk is reported unhealthy.
+	WearLevelThreshold int
+	// ReallocatedSectorsThreshold is the number of reallocated sectors at which the disk is reported unhealthy.
+	ReallocatedSectorsThreshold uint64
+	// MediaErrorsThreshold is the number of media errors at which the disk is reported unhealthy.
+	MediaErrorsThreshold uint64
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *DiskHealthController) Name() string {
+	return "block.DiskHealthController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *DiskHealthController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: block.NamespaceName,
+			Type:      block.BlockDeviceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *DiskHealthController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: block.DiskHealthType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+//
+//nolint:gocyclo,cyclop
+func (ctrl *DiskHealthController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	// disks are not accessible in the container
+	if ctrl.V1Alpha1Mode == v1alpha1runtime.ModeContainer {
+		return nil
+	}
+
+	ctrl.setDefaults()
+
+	ticker := time.NewTicker(ctrl.PollInterval)
+	defer ticker.Stop()
+
+	var (
+		// thresholds exceeded by unhealthy disks as of the last report
+		unhealthy = map[resource.ID]string{}
+		// devices which failed to report health, to avoid logging on every poll
+		failed = map[resource.ID]struct{}{}
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-ticker.C:
+		}
+
+		devices, err := r.List(ctx, resource.NewMetadata(block.NamespaceName, block.BlockDeviceType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing block devices: %w", err)
+		}
+
+		touchedIDs := make(map[resource.ID]struct{}, len(devices.Items))
+
+		for _, res := range devices.Items {
+			id := res.Metadata().ID()
+			device := res.(*block.BlockDevice).TypedSpec()
+
+			health, err := ctrl.Read(device.DevPath, device.Transport)
+			if err != nil {
+				if errors.Is(err, smart.ErrNotSupported) {
+					continue
+				}
+
+				if _, ok := failed[id]; !ok {
+					logger.Warn("error reading disk health", zap.String("device", id), zap.Error(err))
+				}
+
+				failed[id] = struct{}{}
+
+				// keep last known health, reads might fail transiently
+				touchedIDs[id] = struct{}{}
+
+				continue
+			}
+
+			delete(failed, id)
+
+			touchedIDs[id] = struct{}{}
+
+			warnings, exceeded := ctrl.checkThresholds(health)
+
+			ctrl.report(logger, id, warnings, exceeded, unhealthy)
+
+			if err = r.Modify(ctx, block.NewDiskHealth(block.NamespaceName, id), func(res resource.Resource) error {
+				*res.(*block.DiskHealth).TypedSpec() = block.DiskHealthSpec{
+					DevPath:            device.DevPath,
+					Transport:          device.Transport,
+					Temperature:        health.Temperature,
+					WearLevel:          health.WearLevel,
+					ReallocatedSectors: health.ReallocatedSectors,
+					PendingSectors:     health.PendingSectors,
+					MediaErrors:        health.MediaErrors,
+					PowerOnHours:       health.PowerOnHours,
+					CriticalWarning:    health.CriticalWarning,
+					Healthy:            len(warnings) == 0,
+					Warnings:           warnings,
+				}
+
+				return nil
+			}); err != nil {
+				return fmt.Errorf("error updating disk health %q: %w", id, err)
+			}
+		}
+
+		list, err := r.List(ctx, resource.NewMetadata(block.NamespaceName, block.DiskHealthType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing disk health: %w", err)
+		}
+
+		for _, res := range list.Items {
+			if _, ok := touchedIDs[res.Metadata().ID()]; ok {
+				continue
+			}
+
+			delete(unhealthy, res.Metadata().ID())
+
+			if err = r.Destroy(ctx, res.Metadata()); err != nil {
+				return fmt.Errorf("error cleaning up disk health %q: %w", res.Metadata().ID(), err)
+			}
+		}
+	}
+}
+
+func (ctrl *DiskHealthController) setDefaults() {
+	if ctrl.Read == nil {
+		ctrl.Read = smart.Read
+	}
+
+	if ctrl.PollInterval == 0 {
+		ctrl.PollInterval = DefaultDiskHealthPollInterval
+	}
+
+	if ctrl.TemperatureThreshold == 0 {
+		ctrl.TemperatureThreshold = DefaultTemperatureThreshold
+	}
+
+	if ctrl.WearLevelThreshold == 0 {
+		ctrl.WearLevelThreshold = DefaultWearLevelThreshold
+	}
+
+	if ctrl.ReallocatedSectorsThreshold == 0 {
+		ctrl.ReallocatedSectorsThreshold = DefaultReallocatedSectorsThreshold
+	}
+
+	if ctrl.MediaErrorsThreshold == 0 {
+		ctrl.MediaErrorsThreshold = DefaultMediaErrorsThreshold
+	}
+}
+
+// checkThresholds returns the warnings for the disk health and the names of the exceeded thresholds.
+func (ctrl *DiskHealthController) checkThresholds(health *smart.Health) (warnings, exceeded []string) {
+	if health.CriticalWarning != 0 {
+		warnings = append(warnings, fmt.Sprintf("critical warning 0x%02x", health.CriticalWarning))
+		exceeded = append(exceeded, "criticalWarning")
+	}
+
+	if health.Temperature >= ctrl.TemperatureThreshold {
+		warnings = append(warnings, fmt.Sprintf("temperature %dC >= %dC", health.Temperature, ctrl.TemperatureThreshold))
+		exceeded = append(exceeded, "temperature")
+	}
+
+	if health.WearLevel >= ctrl.WearLevelThreshold {
+		warnings = append(warnings, fmt.Sprintf("wear level %d%% >= %d%%", health.WearLevel, ctrl.WearLevelThreshold))
+		exceeded = append(exceeded, "wearLevel")
+	}
+
+	if health.ReallocatedSectors >= ctrl.ReallocatedSectorsThreshold {
+		warnings = append(warnings, fmt.Sprintf("reallocated sectors %d >= %d", health.ReallocatedSectors, ctrl.ReallocatedSectorsThreshold))
+		exceeded = append(exceeded, "reallocatedSectors")
+	}
+
+	if health.MediaErrors >= ctrl.MediaErrorsThreshold {
+		warnings = append(warnings, fmt.Sprintf("media errors %d >= %d", health.MediaErrors, ctrl.MediaErrorsThreshold))
+		exceeded = append(exceeded, "mediaErrors")
+	}
+
+	return warnings, exceeded
+}
+
+// report logs and publishes the event if the set of crossed thresholds has changed.
+//
+// Warnings include the current values (e.g. temperature), so only the names of the exceeded thresholds
+// are compared to avoid reporting the same condition again as the values change.
+func (ctrl *DiskHealthController) report(logger *zap.Logger, id resource.ID, warnings, exceeded []string, unhealthy map[resource.ID]string) {
+	current := strings.Join(exceeded, ",")
+	previous, wasUnhealthy := unhealthy[id]
+
+	switch {
+	case len(warnings) > 0 && (!wasUnhealthy || previous != current):
+		logger.Warn("disk is unhealthy", zap.String("device", id), zap.Strings("warnings", warnings))
+
+		unhealthy[id] = current
+	case len(warnings) == 0 && wasUnhealthy:
+		logger.Info("disk recovered", zap.String("device", id))
+
+		delete(unhealthy, id)
+	default:
+		return
+	}
+
+	if ctrl.V1Alpha1Events != nil {
+		ctrl.V1Alpha1Events.Publish(&machine.DiskHealthEvent{
+			Device:   id,
+			Healthy:  len(warnings) == 0,
+			Warnings: warnings,
+		})
+	}
+}
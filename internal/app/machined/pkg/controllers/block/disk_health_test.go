@@ -0,0 +1,262 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/stretchr/testify/suite"
+	"github.com/talos-systems/go-retry/retry"
+	"google.golang.org/protobuf/proto"
+
+	blockctrl "github.com/talos-systems/talos/internal/app/machined/pkg/controllers/block"
+	"github.com/talos-systems/talos/internal/pkg/smart"
+	"github.com/talos-systems/talos/pkg/logging"
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/resources/block"
+)
+
+type mockHealth struct {
+	mu     sync.Mutex
+	health map[string]smart.Health
+}
+
+func (m *mockHealth) read(devPath, transport string) (*smart.Health, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if transport == "virtio" {
+		return nil, smart.ErrNotSupported
+	}
+
+	health, ok := m.health[devPath]
+	if !ok {
+		return nil, fmt.Errorf("no health for %q", devPath)
+	}
+
+	return &health, nil
+}
+
+func (m *mockHealth) set(devPath string, health smart.Health) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.health[devPath] = health
+}
+
+type mockPublisher struct {
+	mu     sync.Mutex
+	events []*machine.DiskHealthEvent
+}
+
+func (m *mockPublisher) Publish(msg proto.Message) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, msg.(*machine.DiskHealthEvent))
+}
+
+func (m *mockPublisher) get() []*machine.DiskHealthEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*machine.DiskHealthEvent(nil), m.events...)
+}
+
+type DiskHealthSuite struct {
+	suite.Suite
+
+	state state.State
+
+	runtime *runtime.Runtime
+	wg      sync.WaitGroup
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+
+	health    *mockHealth
+	publisher *mockPublisher
+}
+
+func (suite *DiskHealthSuite) SetupTest() {
+	suite.ctx, suite.ctxCancel = context.WithTimeout(context.Background(), 3*time.Minute)
+
+	suite.state = state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var err error
+
+	suite.runtime, err = runtime.NewRuntime(suite.state, logging.Wrap(log.Writer()))
+	suite.Require().NoError(err)
+
+	suite.health = &mockHealth{health: map[string]smart.Health{}}
+	suite.publisher = &mockPublisher{}
+
+	suite.Require().NoError(suite.runtime.RegisterController(&blockctrl.DiskHealthController{
+		V1Alpha1Events: suite.publisher,
+		Read:           suite.health.read,
+		PollInterval:   100 * time.Millisecond,
+	}))
+
+	suite.wg.Add(1)
+
+	go func() {
+		defer suite.wg.Done()
+
+		suite.Assert().NoError(suite.runtime.Run(suite.ctx))
+	}()
+}
+
+func (suite *DiskHealthSuite) createDevice(id, transport string) {
+	device := block.NewBlockDevice(block.NamespaceName, id)
+	device.TypedSpec().DevPath = "/dev/" + id
+	device.TypedSpec().Transport = transport
+
+	suite.Require().NoError(suite.state.Create(suite.ctx, device))
+}
+
+func (suite *DiskHealthSuite) assertHealth(id string, check func(*block.DiskHealthSpec) error) error {
+	res, err := suite.state.Get(suite.ctx, resource.NewMetadata(block.NamespaceName, block.DiskHealthType, id, resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return retry.ExpectedError(err)
+		}
+
+		return err
+	}
+
+	return check(res.(*block.DiskHealth).TypedSpec())
+}
+
+func (suite *DiskHealthSuite) retry(f func() error) {
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(f))
+}
+
+func (suite *DiskHealthSuite) TestReconcile() {
+	suite.health.set("/dev/nvme0n1", smart.Health{Temperature: 37, WearLevel: 3, PowerOnHours: 1234})
+
+	suite.createDevice("nvme0n1", "nvme")
+	suite.createDevice("vda", "virtio")
+
+	suite.retry(func() error {
+		return suite.assertHealth("nvme0n1", func(spec *block.DiskHealthSpec) error {
+			if !spec.Healthy || spec.Temperature != 37 || spec.WearLevel != 3 || spec.Transport != "nvme" {
+				return retry.ExpectedErrorf("unexpected health %+v", spec)
+			}
+
+			return nil
+		})
+	})
+
+	// the disk wears out
+	suite.health.set("/dev/nvme0n1", smart.Health{Temperature: 78, WearLevel: 97, MediaErrors: 42, CriticalWarning: 0x04})
+
+	suite.retry(func() error {
+		return suite.assertHealth("nvme0n1", func(spec *block.DiskHealthSpec) error {
+			if spec.Healthy || len(spec.Warnings) != 4 {
+				return retry.ExpectedErrorf("unexpected health %+v", spec)
+			}
+
+			return nil
+		})
+	})
+
+	// the disk is cooled down, but it's still worn out
+	suite.health.set("/dev/nvme0n1", smart.Health{Temperature: 40, WearLevel: 97})
+
+	suite.retry(func() error {
+		return suite.assertHealth("nvme0n1", func(spec *block.DiskHealthSpec) error {
+			if spec.Healthy || len(spec.Warnings) != 1 || spec.Warnings[0] != "wear level 97% >= 90%" {
+				return retry.ExpectedErrorf("unexpected health %+v", spec)
+			}
+
+			return nil
+		})
+	})
+
+	// the disk is still worn out, the values change, but the same thresholds are exceeded
+	suite.health.set("/dev/nvme0n1", smart.Health{Temperature: 41, WearLevel: 98})
+
+	suite.retry(func() error {
+		return suite.assertHealth("nvme0n1", func(spec *block.DiskHealthSpec) error {
+			if spec.Healthy || spec.WearLevel != 98 {
+				return retry.ExpectedErrorf("unexpected health %+v", spec)
+			}
+
+			return nil
+		})
+	})
+
+	// the disk is replaced
+	suite.health.set("/dev/nvme0n1", smart.Health{Temperature: 35})
+
+	suite.retry(func() error {
+		return suite.assertHealth("nvme0n1", func(spec *block.DiskHealthSpec) error {
+			if !spec.Healthy {
+				return retry.ExpectedErrorf("unexpected health %+v", spec)
+			}
+
+			return nil
+		})
+	})
+
+	events := suite.publisher.get()
+	suite.Require().Len(events, 3)
+
+	suite.Assert().Equal("nvme0n1", events[0].Device)
+	suite.Assert().False(events[0].Healthy)
+	suite.Assert().Equal([]string{
+		"critical warning 0x04",
+		"temperature 78C >= 70C",
+		"wear level 97% >= 90%",
+		"media errors 42 >= 1",
+	}, events[0].Warnings)
+
+	suite.Assert().False(events[1].Healthy)
+	suite.Assert().Equal([]string{"wear level 97% >= 90%"}, events[1].Warnings)
+
+	suite.Assert().True(events[2].Healthy)
+
+	// health is not reported for virtio disks
+	_, err := suite.state.Get(suite.ctx, resource.NewMetadata(block.NamespaceName, block.DiskHealthType, "vda", resource.VersionUndefined))
+	suite.Assert().True(state.IsNotFoundError(err))
+
+	// the disk is removed
+	suite.Require().NoError(suite.state.Destroy(suite.ctx, block.NewBlockDevice(block.NamespaceName, "nvme0n1").Metadata()))
+
+	suite.retry(func() error {
+		_, err := suite.state.Get(suite.ctx, resource.NewMetadata(block.NamespaceName, block.DiskHealthType, "nvme0n1", resource.VersionUndefined))
+		if err == nil {
+			return retry.ExpectedErrorf("disk health still exists")
+		}
+
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	})
+}
+
+func (suite *DiskHealthSuite) TearDownTest() {
+	suite.T().Log("tear down")
+
+	suite.ctxCancel()
+
+	suite.wg.Wait()
+}
+
+func TestDiskHealthSuite(t *testing.T) {
+	suite.Run(t, new(DiskHealthSuite))
+}
@@ -0,0 +1,155 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"go.uber.org/zap"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/block/watch"
+	v1alpha1runtime "github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/lvm"
+	"github.com/talos-systems/talos/pkg/resources/block"
+)
+
+// DefaultLogicalVolumePollInterval is the interval to refresh logical volume status.
+//
+// RAID rebuild progress doesn't produce uevents, so it's picked up by polling.
+const DefaultLogicalVolumePollInterval = 10 * time.Second
+
+// LogicalVolumeLister lists logical volumes, interface for mocking.
+type LogicalVolumeLister interface {
+	LogicalVolumes() ([]lvm.LogicalVolume, error)
+}
+
+// LogicalVolumeStatusController publishes LogicalVolumeStatuses for LVM logical volumes.
+type LogicalVolumeStatusController struct {
+	V1Alpha1Mode v1alpha1runtime.Mode
+	// Lister defaults to the lvm tool shipped with Talos.
+	Lister       LogicalVolumeLister
+	PollInterval time.Duration
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *LogicalVolumeStatusController) Name() string {
+	return "block.LogicalVolumeStatusController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *LogicalVolumeStatusController) Inputs() []controller.Input {
+	return nil
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *LogicalVolumeStatusController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: block.LogicalVolumeStatusType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+//
+//nolint:gocyclo
+func (ctrl *LogicalVolumeStatusController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	// logical volumes are not managed in the container
+	if ctrl.V1Alpha1Mode == v1alpha1runtime.ModeContainer {
+		return nil
+	}
+
+	if ctrl.Lister == nil {
+		ctrl.Lister = lvm.New()
+	}
+
+	if ctrl.PollInterval == 0 {
+		ctrl.PollInterval = DefaultLogicalVolumePollInterval
+	}
+
+	ueventWatcher, err := watch.NewUevent(r)
+	if err != nil {
+		logger.Warn("uevent watcher failed to start", zap.Error(err))
+	} else {
+		defer ueventWatcher.Done()
+	}
+
+	ticker := time.NewTicker(ctrl.PollInterval)
+	defer ticker.Stop()
+
+	degraded := map[resource.ID]bool{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-ticker.C:
+		}
+
+		volumes, err := ctrl.Lister.LogicalVolumes()
+		if err != nil {
+			// lvm might fail transiently while devices are being changed, so don't fail the controller
+			logger.Warn("error listing logical volumes", zap.Error(err))
+
+			continue
+		}
+
+		touchedIDs := make(map[resource.ID]struct{}, len(volumes))
+
+		for _, lv := range volumes {
+			lv := lv
+			id := lv.VolumeGroup + "/" + lv.Name
+
+			touchedIDs[id] = struct{}{}
+
+			if lv.Degraded() && !degraded[id] {
+				logger.Warn("logical volume is degraded", zap.String("volume", id), zap.String("health", lv.Health))
+			}
+
+			degraded[id] = lv.Degraded()
+
+			if err = r.Modify(ctx, block.NewLogicalVolumeStatus(block.NamespaceName, id), func(res resource.Resource) error {
+				*res.(*block.LogicalVolumeStatus).TypedSpec() = block.LogicalVolumeStatusSpec{
+					VolumeGroup: lv.VolumeGroup,
+					Name:        lv.Name,
+					DevPath:     lv.Path,
+					Size:        lv.Size,
+					SegmentType: lv.SegmentType,
+					Degraded:    lv.Degraded(),
+					Health:      lv.Health,
+					SyncAction:  lv.SyncAction,
+					SyncPercent: lv.SyncPercent,
+				}
+
+				return nil
+			}); err != nil {
+				return fmt.Errorf("error updating logical volume status %q: %w", id, err)
+			}
+		}
+
+		list, err := r.List(ctx, resource.NewMetadata(block.NamespaceName, block.LogicalVolumeStatusType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing logical volume statuses: %w", err)
+		}
+
+		for _, res := range list.Items {
+			if _, ok := touchedIDs[res.Metadata().ID()]; ok {
+				continue
+			}
+
+			delete(degraded, res.Metadata().ID())
+
+			if err = r.Destroy(ctx, res.Metadata()); err != nil {
+				return fmt.Errorf("error cleaning up logical volume status %q: %w", res.Metadata().ID(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block_test
+
+import (
+	"context"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/stretchr/testify/suite"
+	"github.com/talos-systems/go-retry/retry"
+
+	blockctrl "github.com/talos-systems/talos/internal/app/machined/pkg/controllers/block"
+	"github.com/talos-systems/talos/internal/pkg/lvm"
+	"github.com/talos-systems/talos/pkg/logging"
+	"github.com/talos-systems/talos/pkg/resources/block"
+)
+
+type mockLogicalVolumes struct {
+	mu      sync.Mutex
+	volumes []lvm.LogicalVolume
+}
+
+func (m *mockLogicalVolumes) LogicalVolumes() ([]lvm.LogicalVolume, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]lvm.LogicalVolume(nil), m.volumes...), nil
+}
+
+func (m *mockLogicalVolumes) set(volumes ...lvm.LogicalVolume) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.volumes = volumes
+}
+
+type LogicalVolumeStatusSuite struct {
+	suite.Suite
+
+	state state.State
+
+	runtime *runtime.Runtime
+	wg      sync.WaitGroup
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+
+	volumes *mockLogicalVolumes
+}
+
+func (suite *LogicalVolumeStatusSuite) SetupTest() {
+	suite.ctx, suite.ctxCancel = context.WithTimeout(context.Background(), 3*time.Minute)
+
+	suite.state = state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var err error
+
+	suite.runtime, err = runtime.NewRuntime(suite.state, logging.Wrap(log.Writer()))
+	suite.Require().NoError(err)
+
+	suite.volumes = &mockLogicalVolumes{}
+
+	suite.Require().NoError(suite.runtime.RegisterController(&blockctrl.LogicalVolumeStatusController{
+		Lister:       suite.volumes,
+		PollInterval: 100 * time.Millisecond,
+	}))
+
+	suite.wg.Add(1)
+
+	go func() {
+		defer suite.wg.Done()
+
+		suite.Assert().NoError(suite.runtime.Run(suite.ctx))
+	}()
+}
+
+func (suite *LogicalVolumeStatusSuite) assertStatus(id string, check func(*block.LogicalVolumeStatusSpec) error) error {
+	res, err := suite.state.Get(suite.ctx, resource.NewMetadata(block.NamespaceName, block.LogicalVolumeStatusType, id, resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return retry.ExpectedError(err)
+		}
+
+		return err
+	}
+
+	return check(res.(*block.LogicalVolumeStatus).TypedSpec())
+}
+
+func (suite *LogicalVolumeStatusSuite) retry(f func() error) {
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(f))
+}
+
+func (suite *LogicalVolumeStatusSuite) TestReconcile() {
+	healthy := lvm.LogicalVolume{
+		VolumeGroup: "data",
+		Name:        "local",
+		Path:        "/dev/data/local",
+		Size:        1 << 30,
+		SegmentType: "raid1",
+		SyncAction:  "idle",
+		SyncPercent: 100,
+	}
+
+	suite.volumes.set(healthy)
+
+	suite.retry(func() error {
+		return suite.assertStatus("data/local", func(spec *block.LogicalVolumeStatusSpec) error {
+			if spec.Degraded || spec.SegmentType != "raid1" || spec.SyncPercent != 100 || spec.DevPath != "/dev/data/local" {
+				return retry.ExpectedErrorf("unexpected status %+v", spec)
+			}
+
+			return nil
+		})
+	})
+
+	// a disk fails, and the array is being rebuilt
+	rebuilding := healthy
+	rebuilding.Health = "partial"
+	rebuilding.SyncAction = "recover"
+	rebuilding.SyncPercent = 37.5
+
+	suite.volumes.set(rebuilding)
+
+	suite.retry(func() error {
+		return suite.assertStatus("data/local", func(spec *block.LogicalVolumeStatusSpec) error {
+			if !spec.Degraded || spec.Health != "partial" || spec.SyncAction != "recover" || spec.SyncPercent != 37.5 {
+				return retry.ExpectedErrorf("unexpected status %+v", spec)
+			}
+
+			return nil
+		})
+	})
+
+	// volume is removed
+	suite.volumes.set()
+
+	suite.retry(func() error {
+		_, err := suite.state.Get(suite.ctx, resource.NewMetadata(block.NamespaceName, block.LogicalVolumeStatusType, "data/local", resource.VersionUndefined))
+		if err == nil {
+			return retry.ExpectedErrorf("status still exists")
+		}
+
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	})
+}
+
+func (suite *LogicalVolumeStatusSuite) TearDownTest() {
+	suite.T().Log("tear down")
+
+	suite.ctxCancel()
+
+	suite.wg.Wait()
+}
+
+func TestLogicalVolumeStatusSuite(t *testing.T) {
+	suite.Run(t, new(LogicalVolumeStatusSuite))
+}
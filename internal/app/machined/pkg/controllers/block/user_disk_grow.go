@@ -0,0 +1,108 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"go.uber.org/zap"
+
+	v1alpha1runtime "github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/mount"
+	"github.com/talos-systems/talos/pkg/resources/block"
+)
+
+// UserDiskGrowController grows the mounted partitions of the user disks when the block devices are enlarged.
+type UserDiskGrowController struct {
+	V1Alpha1Mode v1alpha1runtime.Mode
+	// Grow defaults to growing the partitions mounted from the machine config disks.
+	Grow func() error
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *UserDiskGrowController) Name() string {
+	return "block.UserDiskGrowController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *UserDiskGrowController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: block.NamespaceName,
+			Type:      block.BlockDeviceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *UserDiskGrowController) Outputs() []controller.Output {
+	return nil
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *UserDiskGrowController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	// user disks are not mounted in the container
+	if ctrl.V1Alpha1Mode == v1alpha1runtime.ModeContainer {
+		return nil
+	}
+
+	if ctrl.Grow == nil {
+		ctrl.Grow = mount.UserDisksGrow
+	}
+
+	// last seen size of the block devices
+	sizes := map[resource.ID]uint64{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		devices, err := r.List(ctx, resource.NewMetadata(block.NamespaceName, block.BlockDeviceType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing block devices: %w", err)
+		}
+
+		var grown []string
+
+		touchedIDs := make(map[resource.ID]struct{}, len(devices.Items))
+
+		for _, res := range devices.Items {
+			id := res.Metadata().ID()
+			size := res.(*block.BlockDevice).TypedSpec().Size
+
+			touchedIDs[id] = struct{}{}
+
+			if lastSize, ok := sizes[id]; ok && size > lastSize {
+				grown = append(grown, id)
+			}
+
+			sizes[id] = size
+		}
+
+		for id := range sizes {
+			if _, ok := touchedIDs[id]; !ok {
+				delete(sizes, id)
+			}
+		}
+
+		if len(grown) == 0 {
+			continue
+		}
+
+		logger.Info("block devices were enlarged, growing user disk partitions", zap.Strings("devices", grown))
+
+		if err = ctrl.Grow(); err != nil {
+			// the partition might be grown on the next change or on reboot, so don't fail the controller
+			logger.Warn("error growing user disk partitions", zap.Error(err))
+		}
+	}
+}
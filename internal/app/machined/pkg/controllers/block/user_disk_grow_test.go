@@ -0,0 +1,121 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block_test
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/stretchr/testify/suite"
+	"github.com/talos-systems/go-retry/retry"
+
+	blockctrl "github.com/talos-systems/talos/internal/app/machined/pkg/controllers/block"
+	"github.com/talos-systems/talos/pkg/logging"
+	"github.com/talos-systems/talos/pkg/resources/block"
+)
+
+type UserDiskGrowSuite struct {
+	suite.Suite
+
+	state state.State
+
+	runtime *runtime.Runtime
+	wg      sync.WaitGroup
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+
+	grown int32
+}
+
+func (suite *UserDiskGrowSuite) SetupTest() {
+	suite.ctx, suite.ctxCancel = context.WithTimeout(context.Background(), 3*time.Minute)
+
+	suite.state = state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var err error
+
+	suite.runtime, err = runtime.NewRuntime(suite.state, logging.Wrap(log.Writer()))
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.runtime.RegisterController(&blockctrl.UserDiskGrowController{
+		Grow: func() error {
+			atomic.AddInt32(&suite.grown, 1)
+
+			return nil
+		},
+	}))
+
+	suite.wg.Add(1)
+
+	go func() {
+		defer suite.wg.Done()
+
+		suite.Assert().NoError(suite.runtime.Run(suite.ctx))
+	}()
+}
+
+func (suite *UserDiskGrowSuite) setSize(id string, size uint64) {
+	_, err := suite.state.UpdateWithConflicts(suite.ctx, resource.NewMetadata(block.NamespaceName, block.BlockDeviceType, id, resource.VersionUndefined), func(r resource.Resource) error {
+		r.(*block.BlockDevice).TypedSpec().Size = size
+
+		return nil
+	})
+	suite.Require().NoError(err)
+}
+
+func (suite *UserDiskGrowSuite) TestReconcile() {
+	device := block.NewBlockDevice(block.NamespaceName, "sdb")
+	device.TypedSpec().DevPath = "/dev/sdb"
+	device.TypedSpec().Size = 10 << 30
+
+	suite.Require().NoError(suite.state.Create(suite.ctx, device))
+
+	// the new device is not grown
+	time.Sleep(500 * time.Millisecond)
+	suite.Assert().EqualValues(0, atomic.LoadInt32(&suite.grown))
+
+	// the device is shrunk, which is not supported
+	suite.setSize("sdb", 5<<30)
+
+	time.Sleep(500 * time.Millisecond)
+	suite.Assert().EqualValues(0, atomic.LoadInt32(&suite.grown))
+
+	// the device is enlarged
+	size := uint64(20 << 30)
+
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(func() error {
+		if atomic.LoadInt32(&suite.grown) > 0 {
+			return nil
+		}
+
+		// enlarge the device again in case the controller didn't see the previous size
+		size += 1 << 30
+		suite.setSize("sdb", size)
+
+		return retry.ExpectedErrorf("partitions were not grown")
+	}))
+}
+
+func (suite *UserDiskGrowSuite) TearDownTest() {
+	suite.T().Log("tear down")
+
+	suite.ctxCancel()
+
+	suite.wg.Wait()
+}
+
+func TestUserDiskGrowSuite(t *testing.T) {
+	suite.Run(t, new(UserDiskGrowSuite))
+}
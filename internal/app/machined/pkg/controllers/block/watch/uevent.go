@@ -0,0 +1,112 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package watch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// kernelGroup receives uevents as sent by the kernel.
+	kernelGroup = 1 << iota
+	// udevGroup receives uevents after udev has processed them (and updated udev database).
+	udevGroup
+)
+
+// pollInterval is the receive timeout, it defines how fast the watcher notices Done.
+const pollInterval = time.Second
+
+var blockSubsystem = []byte("SUBSYSTEM=block\x00")
+
+type ueventWatcher struct {
+	wg   sync.WaitGroup
+	fd   int
+	done chan struct{}
+}
+
+// NewUevent starts watching block subsystem uevents.
+//
+// Both kernel and udev multicast groups are joined: kernel event is delivered as soon as device
+// appears or disappears, while udev event follows once the udev database is updated.
+func NewUevent(trigger Trigger) (Watcher, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, fmt.Errorf("error opening uevent socket: %w", err)
+	}
+
+	tv := unix.NsecToTimeval(pollInterval.Nanoseconds())
+
+	if err = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv); err != nil {
+		unix.Close(fd) //nolint:errcheck
+
+		return nil, fmt.Errorf("error setting uevent socket timeout: %w", err)
+	}
+
+	if err = unix.Bind(fd, &unix.SockaddrNetlink{
+		Family: unix.AF_NETLINK,
+		Groups: kernelGroup | udevGroup,
+	}); err != nil {
+		unix.Close(fd) //nolint:errcheck
+
+		return nil, fmt.Errorf("error binding uevent socket: %w", err)
+	}
+
+	watcher := &ueventWatcher{
+		fd:   fd,
+		done: make(chan struct{}),
+	}
+
+	watcher.wg.Add(1)
+
+	go func() {
+		defer watcher.wg.Done()
+
+		buf := make([]byte, 64*1024)
+
+		for {
+			select {
+			case <-watcher.done:
+				return
+			default:
+			}
+
+			n, _, recvErr := unix.Recvfrom(watcher.fd, buf, 0)
+			if recvErr != nil {
+				if errors.Is(recvErr, unix.EAGAIN) || errors.Is(recvErr, unix.EINTR) {
+					continue
+				}
+
+				if errors.Is(recvErr, unix.ENOBUFS) {
+					// some events were lost, resync anyways
+					trigger.QueueReconcile()
+
+					continue
+				}
+
+				return
+			}
+
+			if bytes.Contains(buf[:n], blockSubsystem) {
+				trigger.QueueReconcile()
+			}
+		}
+	}()
+
+	return watcher, nil
+}
+
+func (watcher *ueventWatcher) Done() {
+	close(watcher.done)
+
+	watcher.wg.Wait()
+
+	unix.Close(watcher.fd) //nolint:errcheck
+}
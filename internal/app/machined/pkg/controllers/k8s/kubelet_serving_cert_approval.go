@@ -0,0 +1,189 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"go.uber.org/zap"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/talos-systems/talos/pkg/kubernetes"
+	"github.com/talos-systems/talos/pkg/resources/secrets"
+)
+
+// KubeletServingCertApprovalController approves kubelet serving certificate signing requests.
+//
+// Each request is verified against the node addresses published by the node,
+// requests which fail verification are left pending and verified again later.
+type KubeletServingCertApprovalController struct {
+	// rejected tracks CSRs which failed verification to avoid repeating log messages.
+	rejected map[string]struct{}
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *KubeletServingCertApprovalController) Name() string {
+	return "k8s.KubeletServingCertApprovalController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *KubeletServingCertApprovalController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: secrets.NamespaceName,
+			Type:      secrets.KubernetesType,
+			ID:        pointer.ToString(secrets.KubernetesID),
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *KubeletServingCertApprovalController) Outputs() []controller.Output {
+	return nil
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *KubeletServingCertApprovalController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	// kubelet creates new CSRs on startup and when the serving certificate is close to expiry
+	pollTicker := time.NewTicker(30 * time.Second)
+	defer pollTicker.Stop()
+
+	ctrl.rejected = map[string]struct{}{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-pollTicker.C:
+		}
+
+		// Kubernetes secrets are only available on control plane nodes
+		secretsResources, err := r.Get(ctx, resource.NewMetadata(secrets.NamespaceName, secrets.KubernetesType, secrets.KubernetesID, resource.VersionUndefined))
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return err
+		}
+
+		if err = ctrl.approve(ctx, logger, secretsResources.(*secrets.Kubernetes).Certs()); err != nil {
+			// API server might not be up yet, retry on next poll
+			logger.Debug("failed processing kubelet serving certificate requests", zap.Error(err))
+		}
+	}
+}
+
+//nolint:gocyclo
+func (ctrl *KubeletServingCertApprovalController) approve(ctx context.Context, logger *zap.Logger, k8sSecrets *secrets.KubernetesCertsSpec) error {
+	config, err := clientcmd.BuildConfigFromKubeconfigGetter("", func() (*clientcmdapi.Config, error) {
+		return clientcmd.Load([]byte(k8sSecrets.AdminKubeconfig))
+	})
+	if err != nil {
+		return fmt.Errorf("error loading kubeconfig: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("error building Kubernetes client: %w", err)
+	}
+
+	//nolint:errcheck
+	defer client.Close()
+
+	csrs, err := client.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.signerName", certificatesv1.KubeletServingSignerName).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("error listing certificate signing requests: %w", err)
+	}
+
+	seen := map[string]struct{}{}
+
+	for i := range csrs.Items {
+		csr := &csrs.Items[i]
+
+		seen[csr.Name] = struct{}{}
+
+		if isCSRProcessed(csr) {
+			continue
+		}
+
+		var node *corev1.Node
+
+		node, err = client.CoreV1().Nodes().Get(ctx, strings.TrimPrefix(csr.Spec.Username, "system:node:"), metav1.GetOptions{})
+		if err == nil {
+			err = kubernetes.VerifyKubeletServingCSR(csr, node)
+		}
+
+		if err != nil {
+			// node addresses might not be published yet, so the request is verified again on next poll
+			if _, rejected := ctrl.rejected[csr.Name]; !rejected {
+				logger.Warn("kubelet serving certificate request not approved", zap.String("csr", csr.Name), zap.String("username", csr.Spec.Username), zap.Error(err))
+
+				ctrl.rejected[csr.Name] = struct{}{}
+			}
+
+			continue
+		}
+
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+			Type:           certificatesv1.CertificateApproved,
+			Status:         corev1.ConditionTrue,
+			Reason:         "TalosApprove",
+			Message:        "Approved by Talos after verifying node addresses",
+			LastUpdateTime: metav1.Now(),
+		})
+
+		if _, err = client.CertificatesV1().CertificateSigningRequests().UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+			if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+				// CSR was processed by another control plane node
+				continue
+			}
+
+			return fmt.Errorf("error approving certificate signing request %q: %w", csr.Name, err)
+		}
+
+		logger.Info("approved kubelet serving certificate request", zap.String("csr", csr.Name), zap.String("username", csr.Spec.Username))
+	}
+
+	for name := range ctrl.rejected {
+		if _, ok := seen[name]; !ok {
+			delete(ctrl.rejected, name)
+		}
+	}
+
+	return nil
+}
+
+func isCSRProcessed(csr *certificatesv1.CertificateSigningRequest) bool {
+	if len(csr.Status.Certificate) > 0 {
+		return true
+	}
+
+	for _, condition := range csr.Status.Conditions {
+		switch condition.Type { //nolint:exhaustive
+		case certificatesv1.CertificateApproved, certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,149 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"go.uber.org/zap"
+
+	"github.com/talos-systems/talos/pkg/kubernetes"
+	"github.com/talos-systems/talos/pkg/resources/k8s"
+	"github.com/talos-systems/talos/pkg/resources/network"
+	"github.com/talos-systems/talos/pkg/resources/v1alpha1"
+)
+
+// NodeAddressAnnotationController publishes node addresses as Kubernetes Node annotation.
+//
+// Published addresses are used to verify kubelet serving certificate signing requests.
+type NodeAddressAnnotationController struct{}
+
+// Name implements controller.Controller interface.
+func (ctrl *NodeAddressAnnotationController) Name() string {
+	return "k8s.NodeAddressAnnotationController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *NodeAddressAnnotationController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: network.NamespaceName,
+			Type:      network.NodeAddressType,
+			ID:        pointer.ToString(network.NodeAddressAccumulativeID),
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: k8s.ControlPlaneNamespaceName,
+			Type:      k8s.NodenameType,
+			ID:        pointer.ToString(k8s.NodenameID),
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: v1alpha1.NamespaceName,
+			Type:      v1alpha1.ServiceType,
+			ID:        pointer.ToString("kubelet"),
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *NodeAddressAnnotationController) Outputs() []controller.Output {
+	return nil
+}
+
+// Run implements controller.Controller interface.
+//
+//nolint:gocyclo
+func (ctrl *NodeAddressAnnotationController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	// retry publishing the annotation until the Node is registered by the kubelet
+	retryTicker := time.NewTicker(30 * time.Second)
+	defer retryTicker.Stop()
+
+	var published string
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-retryTicker.C:
+		}
+
+		kubeletResource, err := r.Get(ctx, resource.NewMetadata(v1alpha1.NamespaceName, v1alpha1.ServiceType, "kubelet", resource.VersionUndefined))
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				published = ""
+
+				continue
+			}
+
+			return err
+		}
+
+		if !kubeletResource.(*v1alpha1.Service).Running() {
+			published = ""
+
+			continue
+		}
+
+		nodenameResource, err := r.Get(ctx, resource.NewMetadata(k8s.ControlPlaneNamespaceName, k8s.NodenameType, k8s.NodenameID, resource.VersionUndefined))
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return err
+		}
+
+		nodename := nodenameResource.(*k8s.Nodename).TypedSpec().Nodename
+
+		addressesResource, err := r.Get(ctx, resource.NewMetadata(network.NamespaceName, network.NodeAddressType, network.NodeAddressAccumulativeID, resource.VersionUndefined))
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return err
+		}
+
+		addresses := make([]string, 0, len(addressesResource.(*network.NodeAddress).TypedSpec().Addresses))
+
+		for _, addr := range addressesResource.(*network.NodeAddress).TypedSpec().Addresses {
+			addresses = append(addresses, addr.String())
+		}
+
+		if key := nodename + "=" + strings.Join(addresses, ","); key != published {
+			if err = ctrl.annotate(ctx, nodename, addresses); err != nil {
+				logger.Debug("failed publishing node addresses", zap.String("node", nodename), zap.Error(err))
+
+				continue
+			}
+
+			logger.Info("published node addresses", zap.String("node", nodename), zap.Strings("addresses", addresses))
+
+			published = key
+		}
+	}
+}
+
+func (ctrl *NodeAddressAnnotationController) annotate(ctx context.Context, nodename string, addresses []string) error {
+	client, err := kubernetes.NewClientFromKubeletKubeconfig()
+	if err != nil {
+		return fmt.Errorf("error building Kubernetes client: %w", err)
+	}
+
+	//nolint:errcheck
+	defer client.Close()
+
+	return client.AnnotateNodeAddresses(ctx, nodename, addresses)
+}
@@ -0,0 +1,291 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+	"github.com/talos-systems/talos/pkg/machinery/nethelpers"
+	"github.com/talos-systems/talos/pkg/resources/k8s"
+	"github.com/talos-systems/talos/pkg/resources/network"
+	timeresource "github.com/talos-systems/talos/pkg/resources/time"
+	"github.com/talos-systems/talos/pkg/resources/v1alpha1"
+)
+
+// Node condition controller defaults.
+const (
+	DefaultDiskPressureThreshold = 90.0
+	DefaultDiskPollInterval      = 30 * time.Second
+)
+
+// NodeConditionController detects node-level problems and reports them as NodeCondition resources.
+type NodeConditionController struct {
+	// EphemeralPath is checked for the disk pressure, defaults to EPHEMERAL mount point.
+	EphemeralPath string
+	// DiskPressureThreshold is the disk usage (percent) above which disk pressure is reported.
+	DiskPressureThreshold float64
+	// DiskPollInterval defines how often disk usage is checked.
+	DiskPollInterval time.Duration
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *NodeConditionController) Name() string {
+	return "k8s.NodeConditionController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *NodeConditionController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: v1alpha1.NamespaceName,
+			Type:      v1alpha1.ServiceType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: v1alpha1.NamespaceName,
+			Type:      timeresource.StatusType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: network.NamespaceName,
+			Type:      network.LinkStatusType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *NodeConditionController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: k8s.NodeConditionType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *NodeConditionController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	if ctrl.EphemeralPath == "" {
+		ctrl.EphemeralPath = constants.EphemeralMountPoint
+	}
+
+	if ctrl.DiskPressureThreshold == 0 {
+		ctrl.DiskPressureThreshold = DefaultDiskPressureThreshold
+	}
+
+	if ctrl.DiskPollInterval == 0 {
+		ctrl.DiskPollInterval = DefaultDiskPollInterval
+	}
+
+	// disk usage is not backed by any resource, so it is polled
+	ticker := time.NewTicker(ctrl.DiskPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-ticker.C:
+		}
+
+		conditions := map[resource.ID]k8s.NodeConditionSpec{}
+
+		var err error
+
+		if conditions[k8s.NodeConditionServiceUnhealthy], err = ctrl.checkServices(ctx, r); err != nil {
+			return err
+		}
+
+		if conditions[k8s.NodeConditionTimeNotSynced], err = ctrl.checkTime(ctx, r); err != nil {
+			return err
+		}
+
+		if conditions[k8s.NodeConditionNetworkLinkDown], err = ctrl.checkLinks(ctx, r); err != nil {
+			return err
+		}
+
+		conditions[k8s.NodeConditionEphemeralDiskPressure] = ctrl.checkDisk(logger)
+
+		now := time.Now()
+
+		for id, condition := range conditions {
+			condition := condition
+
+			if err = r.Modify(ctx, k8s.NewNodeCondition(k8s.ControlPlaneNamespaceName, id), func(res resource.Resource) error {
+				spec := res.(*k8s.NodeCondition).TypedSpec()
+
+				if spec.LastTransitionTime.IsZero() || spec.Status != condition.Status {
+					spec.LastTransitionTime = now
+				}
+
+				spec.Status = condition.Status
+				spec.Reason = condition.Reason
+				spec.Message = condition.Message
+
+				return nil
+			}); err != nil {
+				return fmt.Errorf("error updating node condition %q: %w", id, err)
+			}
+		}
+	}
+}
+
+func (ctrl *NodeConditionController) checkServices(ctx context.Context, r controller.Runtime) (k8s.NodeConditionSpec, error) {
+	services, err := r.List(ctx, resource.NewMetadata(v1alpha1.NamespaceName, v1alpha1.ServiceType, "", resource.VersionUndefined))
+	if err != nil {
+		return k8s.NodeConditionSpec{}, fmt.Errorf("error listing services: %w", err)
+	}
+
+	var unhealthy []string
+
+	for _, res := range services.Items {
+		svc := res.(*v1alpha1.Service) //nolint:errcheck,forcetypeassert
+
+		switch {
+		case !svc.Running():
+			// only failed services are kept when not running
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (failed)", svc.Metadata().ID()))
+		case !svc.Unknown() && !svc.Healthy():
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (unhealthy)", svc.Metadata().ID()))
+		}
+	}
+
+	if len(unhealthy) == 0 {
+		return k8s.NodeConditionSpec{
+			Reason:  "TalosServicesHealthy",
+			Message: "All Talos services are healthy",
+		}, nil
+	}
+
+	sort.Strings(unhealthy)
+
+	return k8s.NodeConditionSpec{
+		Status:  true,
+		Reason:  "TalosServiceUnhealthy",
+		Message: fmt.Sprintf("Talos services are not healthy: %s", strings.Join(unhealthy, ", ")),
+	}, nil
+}
+
+func (ctrl *NodeConditionController) checkTime(ctx context.Context, r controller.Runtime) (k8s.NodeConditionSpec, error) {
+	res, err := r.Get(ctx, resource.NewMetadata(v1alpha1.NamespaceName, timeresource.StatusType, timeresource.StatusID, resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return k8s.NodeConditionSpec{
+				Status:  true,
+				Reason:  "TalosTimeNotSynced",
+				Message: "Time sync status is not available yet",
+			}, nil
+		}
+
+		return k8s.NodeConditionSpec{}, fmt.Errorf("error getting time status: %w", err)
+	}
+
+	status := res.(*timeresource.Status).Status()
+
+	switch {
+	case status.SyncDisabled:
+		return k8s.NodeConditionSpec{
+			Reason:  "TalosTimeSyncDisabled",
+			Message: "Time sync is disabled",
+		}, nil
+	case !status.Synced:
+		return k8s.NodeConditionSpec{
+			Status:  true,
+			Reason:  "TalosTimeNotSynced",
+			Message: "Time is not in sync",
+		}, nil
+	default:
+		return k8s.NodeConditionSpec{
+			Reason:  "TalosTimeSynced",
+			Message: "Time is in sync",
+		}, nil
+	}
+}
+
+func (ctrl *NodeConditionController) checkLinks(ctx context.Context, r controller.Runtime) (k8s.NodeConditionSpec, error) {
+	links, err := r.List(ctx, resource.NewMetadata(network.NamespaceName, network.LinkStatusType, "", resource.VersionUndefined))
+	if err != nil {
+		return k8s.NodeConditionSpec{}, fmt.Errorf("error listing links: %w", err)
+	}
+
+	var down []string
+
+	for _, res := range links.Items {
+		link := res.(*network.LinkStatus) //nolint:errcheck,forcetypeassert
+
+		// only physical links which were brought up are considered
+		if !link.Physical() || link.TypedSpec().Flags&nethelpers.LinkFlags(nethelpers.LinkUp) == 0 {
+			continue
+		}
+
+		if link.TypedSpec().OperationalState != nethelpers.OperStateUp {
+			down = append(down, link.Metadata().ID())
+		}
+	}
+
+	if len(down) == 0 {
+		return k8s.NodeConditionSpec{
+			Reason:  "TalosNetworkLinksUp",
+			Message: "All network links are up",
+		}, nil
+	}
+
+	sort.Strings(down)
+
+	return k8s.NodeConditionSpec{
+		Status:  true,
+		Reason:  "TalosNetworkLinkDown",
+		Message: fmt.Sprintf("Network links are down: %s", strings.Join(down, ", ")),
+	}, nil
+}
+
+func (ctrl *NodeConditionController) checkDisk(logger *zap.Logger) k8s.NodeConditionSpec {
+	var st unix.Statfs_t
+
+	if err := unix.Statfs(ctrl.EphemeralPath, &st); err != nil {
+		logger.Debug("failed checking disk usage", zap.String("path", ctrl.EphemeralPath), zap.Error(err))
+
+		return k8s.NodeConditionSpec{
+			Reason:  "TalosEphemeralDiskUnknown",
+			Message: fmt.Sprintf("Failed checking disk usage: %s", err),
+		}
+	}
+
+	used := st.Blocks - st.Bfree
+	total := used + st.Bavail
+
+	var usage float64
+
+	if total > 0 {
+		usage = float64(used) / float64(total) * 100
+	}
+
+	if usage > ctrl.DiskPressureThreshold {
+		return k8s.NodeConditionSpec{
+			Status:  true,
+			Reason:  "TalosEphemeralDiskPressure",
+			Message: fmt.Sprintf("EPHEMERAL disk usage is %.0f%%, above the threshold of %.0f%%", usage, ctrl.DiskPressureThreshold),
+		}
+	}
+
+	// usage is not reported in the message to avoid updates on every poll
+	return k8s.NodeConditionSpec{
+		Reason:  "TalosEphemeralDiskOK",
+		Message: fmt.Sprintf("EPHEMERAL disk usage is below the threshold of %.0f%%", ctrl.DiskPressureThreshold),
+	}
+}
@@ -0,0 +1,223 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/talos-systems/talos/pkg/kubernetes"
+	"github.com/talos-systems/talos/pkg/resources/k8s"
+	"github.com/talos-systems/talos/pkg/resources/v1alpha1"
+)
+
+// NodeConditionRefreshInterval defines how often node conditions are re-published even if they haven't changed.
+//
+// Re-publishing restores the conditions if the Node was re-registered.
+const NodeConditionRefreshInterval = 5 * time.Minute
+
+// NodeConditionApplyController mirrors NodeCondition resources to the Kubernetes Node conditions.
+//
+// Condition transitions are also recorded as Kubernetes Events for the Node.
+// Kubelet credentials are used to update the Node.
+type NodeConditionApplyController struct{}
+
+// Name implements controller.Controller interface.
+func (ctrl *NodeConditionApplyController) Name() string {
+	return "k8s.NodeConditionApplyController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *NodeConditionApplyController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: k8s.ControlPlaneNamespaceName,
+			Type:      k8s.NodeConditionType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: k8s.ControlPlaneNamespaceName,
+			Type:      k8s.NodenameType,
+			ID:        pointer.ToString(k8s.NodenameID),
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: v1alpha1.NamespaceName,
+			Type:      v1alpha1.ServiceType,
+			ID:        pointer.ToString("kubelet"),
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *NodeConditionApplyController) Outputs() []controller.Output {
+	return nil
+}
+
+// Run implements controller.Controller interface.
+//
+//nolint:gocyclo,cyclop
+func (ctrl *NodeConditionApplyController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	// retry publishing the conditions until the Node is registered by the kubelet
+	retryTicker := time.NewTicker(30 * time.Second)
+	defer retryTicker.Stop()
+
+	var (
+		publishedNode string
+		published     map[resource.ID]k8s.NodeConditionSpec
+		publishedAt   time.Time
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-retryTicker.C:
+		}
+
+		kubeletResource, err := r.Get(ctx, resource.NewMetadata(v1alpha1.NamespaceName, v1alpha1.ServiceType, "kubelet", resource.VersionUndefined))
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return err
+		}
+
+		if !kubeletResource.(*v1alpha1.Service).Running() {
+			continue
+		}
+
+		nodenameResource, err := r.Get(ctx, resource.NewMetadata(k8s.ControlPlaneNamespaceName, k8s.NodenameType, k8s.NodenameID, resource.VersionUndefined))
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return err
+		}
+
+		nodename := nodenameResource.(*k8s.Nodename).TypedSpec().Nodename
+
+		if nodename != publishedNode {
+			publishedNode = nodename
+			published = nil
+		}
+
+		conditions, err := r.List(ctx, resource.NewMetadata(k8s.ControlPlaneNamespaceName, k8s.NodeConditionType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing node conditions: %w", err)
+		}
+
+		current := make(map[resource.ID]k8s.NodeConditionSpec, len(conditions.Items))
+
+		for _, res := range conditions.Items {
+			current[res.Metadata().ID()] = *res.(*k8s.NodeCondition).TypedSpec()
+		}
+
+		if len(current) == 0 || (conditionsEqual(published, current) && time.Since(publishedAt) < NodeConditionRefreshInterval) {
+			continue
+		}
+
+		if err = ctrl.apply(ctx, logger, nodename, published, current); err != nil {
+			// Node might not be registered yet, retry on next tick
+			logger.Debug("failed publishing node conditions", zap.String("node", nodename), zap.Error(err))
+
+			continue
+		}
+
+		published = current
+		publishedAt = time.Now()
+	}
+}
+
+func (ctrl *NodeConditionApplyController) apply(ctx context.Context, logger *zap.Logger, nodename string, published, current map[resource.ID]k8s.NodeConditionSpec) error {
+	client, err := kubernetes.NewClientFromKubeletKubeconfig()
+	if err != nil {
+		return fmt.Errorf("error building Kubernetes client: %w", err)
+	}
+
+	//nolint:errcheck
+	defer client.Close()
+
+	now := metav1.Now()
+
+	nodeConditions := make([]corev1.NodeCondition, 0, len(current))
+
+	for id, condition := range current {
+		status := corev1.ConditionFalse
+
+		if condition.Status {
+			status = corev1.ConditionTrue
+		}
+
+		nodeConditions = append(nodeConditions, corev1.NodeCondition{
+			Type:               corev1.NodeConditionType(id),
+			Status:             status,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastHeartbeatTime:  now,
+			LastTransitionTime: metav1.NewTime(condition.LastTransitionTime),
+		})
+	}
+
+	if err = client.SetNodeConditions(ctx, nodename, nodeConditions); err != nil {
+		return err
+	}
+
+	for id, condition := range current {
+		previous, known := published[id]
+
+		// problems present at the first publish are reported, as well as all transitions afterwards
+		if (known && previous.Status == condition.Status) || (!known && !condition.Status) {
+			continue
+		}
+
+		eventType := corev1.EventTypeNormal
+
+		if condition.Status {
+			eventType = corev1.EventTypeWarning
+
+			logger.Warn("node problem detected", zap.String("condition", id), zap.String("message", condition.Message))
+		}
+
+		if err = client.RecordNodeEvent(ctx, nodename, eventType, condition.Reason, condition.Message); err != nil {
+			// events are best-effort
+			logger.Debug("failed recording node event", zap.String("node", nodename), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func conditionsEqual(a, b map[resource.ID]k8s.NodeConditionSpec) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for id, condition := range a {
+		other, ok := b[id]
+		if !ok {
+			return false
+		}
+
+		if condition.Status != other.Status || condition.Reason != other.Reason || condition.Message != other.Message || !condition.LastTransitionTime.Equal(other.LastTransitionTime) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,245 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package k8s_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/stretchr/testify/suite"
+	"github.com/talos-systems/go-retry/retry"
+
+	k8sctrl "github.com/talos-systems/talos/internal/app/machined/pkg/controllers/k8s"
+	"github.com/talos-systems/talos/pkg/logging"
+	"github.com/talos-systems/talos/pkg/machinery/nethelpers"
+	"github.com/talos-systems/talos/pkg/resources/k8s"
+	"github.com/talos-systems/talos/pkg/resources/network"
+	timeresource "github.com/talos-systems/talos/pkg/resources/time"
+	"github.com/talos-systems/talos/pkg/resources/v1alpha1"
+)
+
+type NodeConditionSuite struct {
+	suite.Suite
+
+	state state.State
+
+	runtime *runtime.Runtime
+	wg      sync.WaitGroup
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+}
+
+func (suite *NodeConditionSuite) SetupTest() {
+	suite.ctx, suite.ctxCancel = context.WithTimeout(context.Background(), 3*time.Minute)
+
+	suite.state = state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var err error
+
+	suite.runtime, err = runtime.NewRuntime(suite.state, logging.Wrap(log.Writer()))
+	suite.Require().NoError(err)
+}
+
+func (suite *NodeConditionSuite) startRuntime(ctrl *k8sctrl.NodeConditionController) {
+	suite.Require().NoError(suite.runtime.RegisterController(ctrl))
+
+	suite.wg.Add(1)
+
+	go func() {
+		defer suite.wg.Done()
+
+		suite.Assert().NoError(suite.runtime.Run(suite.ctx))
+	}()
+}
+
+func (suite *NodeConditionSuite) assertCondition(id resource.ID, status bool, reason string) error {
+	res, err := suite.state.Get(suite.ctx, resource.NewMetadata(k8s.ControlPlaneNamespaceName, k8s.NodeConditionType, id, resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return retry.ExpectedError(err)
+		}
+
+		return err
+	}
+
+	spec := res.(*k8s.NodeCondition).TypedSpec()
+
+	if spec.Status != status || spec.Reason != reason {
+		return retry.ExpectedErrorf("condition %q: expected %v/%q, got %v/%q", id, status, reason, spec.Status, spec.Reason)
+	}
+
+	if spec.LastTransitionTime.IsZero() {
+		return retry.ExpectedErrorf("condition %q: transition time is not set", id)
+	}
+
+	return nil
+}
+
+func (suite *NodeConditionSuite) TestConditions() {
+	suite.startRuntime(&k8sctrl.NodeConditionController{
+		EphemeralPath:         os.TempDir(),
+		DiskPressureThreshold: 100,
+	})
+
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(
+		func() error {
+			for _, check := range []struct {
+				id     resource.ID
+				status bool
+				reason string
+			}{
+				{k8s.NodeConditionServiceUnhealthy, false, "TalosServicesHealthy"},
+				{k8s.NodeConditionTimeNotSynced, true, "TalosTimeNotSynced"},
+				{k8s.NodeConditionNetworkLinkDown, false, "TalosNetworkLinksUp"},
+				{k8s.NodeConditionEphemeralDiskPressure, false, "TalosEphemeralDiskOK"},
+			} {
+				if err := suite.assertCondition(check.id, check.status, check.reason); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	))
+
+	apid := v1alpha1.NewService("apid")
+	apid.SetRunning(true)
+	apid.SetHealthy(false)
+	suite.Require().NoError(suite.state.Create(suite.ctx, apid))
+
+	timeStatus := timeresource.NewStatus()
+	timeStatus.SetStatus(timeresource.StatusSpec{Synced: true})
+	suite.Require().NoError(suite.state.Create(suite.ctx, timeStatus))
+
+	eth0 := network.NewLinkStatus(network.NamespaceName, "eth0")
+	eth0.TypedSpec().Type = nethelpers.LinkEther
+	eth0.TypedSpec().Flags = nethelpers.LinkFlags(nethelpers.LinkUp)
+	eth0.TypedSpec().OperationalState = nethelpers.OperStateDown
+	suite.Require().NoError(suite.state.Create(suite.ctx, eth0))
+
+	// links which are not brought up are ignored
+	eth1 := network.NewLinkStatus(network.NamespaceName, "eth1")
+	eth1.TypedSpec().Type = nethelpers.LinkEther
+	eth1.TypedSpec().OperationalState = nethelpers.OperStateDown
+	suite.Require().NoError(suite.state.Create(suite.ctx, eth1))
+
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(
+		func() error {
+			for _, check := range []struct {
+				id     resource.ID
+				status bool
+				reason string
+			}{
+				{k8s.NodeConditionServiceUnhealthy, true, "TalosServiceUnhealthy"},
+				{k8s.NodeConditionTimeNotSynced, false, "TalosTimeSynced"},
+				{k8s.NodeConditionNetworkLinkDown, true, "TalosNetworkLinkDown"},
+			} {
+				if err := suite.assertCondition(check.id, check.status, check.reason); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	))
+
+	res, err := suite.state.Get(suite.ctx, resource.NewMetadata(k8s.ControlPlaneNamespaceName, k8s.NodeConditionType, k8s.NodeConditionNetworkLinkDown, resource.VersionUndefined))
+	suite.Require().NoError(err)
+	suite.Assert().Equal("Network links are down: eth0", res.(*k8s.NodeCondition).TypedSpec().Message)
+}
+
+func (suite *NodeConditionSuite) TestFailedService() {
+	suite.startRuntime(&k8sctrl.NodeConditionController{
+		EphemeralPath:         os.TempDir(),
+		DiskPressureThreshold: 100,
+	})
+
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(
+		func() error {
+			return suite.assertCondition(k8s.NodeConditionServiceUnhealthy, false, "TalosServicesHealthy")
+		},
+	))
+
+	// crashed service is kept as not running
+	kubelet := v1alpha1.NewService("kubelet")
+	kubelet.SetRunning(false)
+	suite.Require().NoError(suite.state.Create(suite.ctx, kubelet))
+
+	apid := v1alpha1.NewService("apid")
+	apid.SetRunning(true)
+	apid.SetHealthy(false)
+	suite.Require().NoError(suite.state.Create(suite.ctx, apid))
+
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(
+		func() error {
+			if err := suite.assertCondition(k8s.NodeConditionServiceUnhealthy, true, "TalosServiceUnhealthy"); err != nil {
+				return err
+			}
+
+			res, err := suite.state.Get(suite.ctx, resource.NewMetadata(k8s.ControlPlaneNamespaceName, k8s.NodeConditionType, k8s.NodeConditionServiceUnhealthy, resource.VersionUndefined))
+			if err != nil {
+				return err
+			}
+
+			if message := res.(*k8s.NodeCondition).TypedSpec().Message; message != "Talos services are not healthy: apid (unhealthy), kubelet (failed)" {
+				return retry.ExpectedErrorf("unexpected message %q", message)
+			}
+
+			return nil
+		},
+	))
+
+	// service is restarted and becomes healthy
+	suite.Require().NoError(suite.state.Destroy(suite.ctx, kubelet.Metadata()))
+
+	_, err := suite.state.UpdateWithConflicts(suite.ctx, apid.Metadata(), func(r resource.Resource) error {
+		r.(*v1alpha1.Service).SetHealthy(true)
+
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(
+		func() error {
+			return suite.assertCondition(k8s.NodeConditionServiceUnhealthy, false, "TalosServicesHealthy")
+		},
+	))
+}
+
+func (suite *NodeConditionSuite) TestDiskPressure() {
+	suite.startRuntime(&k8sctrl.NodeConditionController{
+		EphemeralPath:         os.TempDir(),
+		DiskPressureThreshold: 0.0001,
+		DiskPollInterval:      100 * time.Millisecond,
+	})
+
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(
+		func() error {
+			return suite.assertCondition(k8s.NodeConditionEphemeralDiskPressure, true, "TalosEphemeralDiskPressure")
+		},
+	))
+}
+
+func (suite *NodeConditionSuite) TearDownTest() {
+	suite.T().Log("tear down")
+
+	suite.ctxCancel()
+
+	suite.wg.Wait()
+}
+
+func TestNodeConditionSuite(t *testing.T) {
+	suite.Run(t, new(NodeConditionSuite))
+}
@@ -272,6 +272,13 @@ observeLoop:
 					break observeLoop
 				}
 			}
+
+			// break the loop when etcd is not running or not healthy (failed service is not destroyed)
+			if event.Type == state.Created || event.Type == state.Updated {
+				if svc, ok := event.Resource.(*v1alpha1.Service); ok && svc.Metadata().ID() == "etcd" && (!svc.Running() || !svc.Healthy()) {
+					break observeLoop
+				}
+			}
 		}
 	}
 
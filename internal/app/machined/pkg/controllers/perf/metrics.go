@@ -0,0 +1,76 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package perf
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/talos-systems/talos/pkg/resources/perf"
+)
+
+var (
+	cpuSecondsMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "talos",
+		Subsystem: "perf",
+		Name:      "cpu_seconds",
+		Help:      "Total CPU time spent in each mode, as of the last perf snapshot.",
+	}, []string{"mode"})
+
+	processesMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "talos",
+		Subsystem: "perf",
+		Name:      "processes",
+		Help:      "Number of processes by state, as of the last perf snapshot.",
+	}, []string{"state"})
+
+	contextSwitchesMetric = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "talos",
+		Subsystem: "perf",
+		Name:      "context_switches",
+		Help:      "Total number of context switches, as of the last perf snapshot.",
+	})
+
+	memoryBytesMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "talos",
+		Subsystem: "perf",
+		Name:      "memory_bytes",
+		Help:      "Memory usage by kind, as of the last perf snapshot.",
+	}, []string{"kind"})
+)
+
+func recordCPUMetrics(spec *perf.CPUSpec) {
+	for mode, value := range map[string]float64{
+		"user":    spec.CPUTotal.User,
+		"nice":    spec.CPUTotal.Nice,
+		"system":  spec.CPUTotal.System,
+		"idle":    spec.CPUTotal.Idle,
+		"iowait":  spec.CPUTotal.Iowait,
+		"irq":     spec.CPUTotal.Irq,
+		"softirq": spec.CPUTotal.SoftIrq,
+		"steal":   spec.CPUTotal.Steal,
+	} {
+		cpuSecondsMetric.WithLabelValues(mode).Set(value)
+	}
+
+	processesMetric.WithLabelValues("running").Set(float64(spec.ProcessRunning))
+	processesMetric.WithLabelValues("blocked").Set(float64(spec.ProcessBlocked))
+	contextSwitchesMetric.Set(float64(spec.ContextSwitches))
+}
+
+func recordMemoryMetrics(spec *perf.MemorySpec) {
+	// procfs reports memory in kilobytes
+	for kind, value := range map[string]uint64{
+		"total":      spec.MemTotal,
+		"used":       spec.MemUsed,
+		"available":  spec.MemAvailable,
+		"buffers":    spec.Buffers,
+		"cached":     spec.Cached,
+		"swap_total": spec.SwapTotal,
+		"swap_free":  spec.SwapFree,
+	} {
+		memoryBytesMetric.WithLabelValues(kind).Set(float64(value) * 1024)
+	}
+}
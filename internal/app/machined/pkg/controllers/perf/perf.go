@@ -90,6 +90,8 @@ func (ctrl *StatsController) updateCPU(ctx context.Context, r controller.Runtime
 	return r.Modify(ctx, cpu, func(r resource.Resource) error {
 		r.(*perf.CPU).Update(&stat)
 
+		recordCPUMetrics(r.(*perf.CPU).Spec().(*perf.CPUSpec))
+
 		return nil
 	})
 }
@@ -105,6 +107,8 @@ func (ctrl *StatsController) updateMemory(ctx context.Context, r controller.Runt
 	return r.Modify(ctx, mem, func(r resource.Resource) error {
 		r.(*perf.Memory).Update(&info)
 
+		recordMemoryMetrics(r.(*perf.Memory).Spec().(*perf.MemorySpec))
+
 		return nil
 	})
 }
@@ -0,0 +1,90 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"go.uber.org/zap"
+
+	"github.com/talos-systems/talos/internal/pkg/extensions"
+	"github.com/talos-systems/talos/pkg/resources/runtime"
+)
+
+// ExtensionStatusController publishes the status of the system extensions layered over the rootfs on boot.
+type ExtensionStatusController struct {
+	// StatusPath is the path to the status file written by the initramfs.
+	StatusPath string
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *ExtensionStatusController) Name() string {
+	return "runtime.ExtensionStatusController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *ExtensionStatusController) Inputs() []controller.Input {
+	return nil
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *ExtensionStatusController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: runtime.ExtensionStatusType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *ExtensionStatusController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-r.EventCh():
+	}
+
+	// extensions are layered once on boot, so the status is published only once
+	status, err := extensions.LoadStatus(ctrl.StatusPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("error loading extensions status: %w", err)
+	}
+
+	for _, ext := range status.Extensions {
+		ext := ext
+
+		if err = r.Modify(ctx, runtime.NewExtensionStatus(runtime.NamespaceName, ext.Metadata.Name), func(res resource.Resource) error {
+			spec := res.(*runtime.ExtensionStatus).TypedSpec()
+
+			spec.Image = ext.Image
+			spec.Version = ext.Metadata.Version
+			spec.Author = ext.Metadata.Author
+			spec.Description = ext.Metadata.Description
+			spec.Digest = ext.Digest
+			spec.Loaded = ext.Loaded
+			spec.Error = ext.Error
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error updating extension status: %w", err)
+		}
+
+		if !ext.Loaded {
+			logger.Warn("system extension failed to load", zap.String("extension", ext.Metadata.Name), zap.String("error", ext.Error))
+		}
+	}
+
+	return nil
+}
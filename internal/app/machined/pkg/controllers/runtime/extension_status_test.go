@@ -0,0 +1,143 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime_test
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/stretchr/testify/suite"
+	"github.com/talos-systems/go-retry/retry"
+
+	runtimectrl "github.com/talos-systems/talos/internal/app/machined/pkg/controllers/runtime"
+	"github.com/talos-systems/talos/internal/pkg/extensions"
+	"github.com/talos-systems/talos/pkg/logging"
+	runtimeres "github.com/talos-systems/talos/pkg/resources/runtime"
+)
+
+type ExtensionStatusSuite struct {
+	suite.Suite
+
+	state state.State
+
+	runtime *runtime.Runtime
+	wg      sync.WaitGroup
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+}
+
+func (suite *ExtensionStatusSuite) SetupTest() {
+	suite.ctx, suite.ctxCancel = context.WithTimeout(context.Background(), 3*time.Minute)
+
+	suite.state = state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var err error
+
+	suite.runtime, err = runtime.NewRuntime(suite.state, logging.Wrap(log.Writer()))
+	suite.Require().NoError(err)
+}
+
+func (suite *ExtensionStatusSuite) startRuntime() {
+	suite.wg.Add(1)
+
+	go func() {
+		defer suite.wg.Done()
+
+		suite.Assert().NoError(suite.runtime.Run(suite.ctx))
+	}()
+}
+
+func (suite *ExtensionStatusSuite) TestReconcile() {
+	statusPath := filepath.Join(suite.T().TempDir(), "extensions.yaml")
+
+	suite.Require().NoError((&extensions.Status{
+		Extensions: []extensions.ExtensionStatus{
+			{
+				Extension: extensions.Extension{
+					Image:    "intel-ucode.sqsh",
+					Metadata: extensions.Metadata{Name: "intel-ucode", Version: "20210608", Author: "Talos"},
+					Digest:   "abcd",
+				},
+				Loaded: true,
+			},
+			{
+				Extension: extensions.Extension{
+					Image:    "drbd.sqsh",
+					Metadata: extensions.Metadata{Name: "drbd", Version: "9.0.29"},
+				},
+				Error: "extension \"drbd\": signature doesn't match any of the trusted keys",
+			},
+		},
+	}).Write(statusPath))
+
+	suite.Require().NoError(suite.runtime.RegisterController(&runtimectrl.ExtensionStatusController{
+		StatusPath: statusPath,
+	}))
+
+	suite.startRuntime()
+
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(func() error {
+		for id, check := range map[string]func(*runtimeres.ExtensionStatusSpec) bool{
+			"intel-ucode": func(spec *runtimeres.ExtensionStatusSpec) bool {
+				return spec.Loaded && spec.Version == "20210608" && spec.Author == "Talos" && spec.Digest == "abcd"
+			},
+			"drbd": func(spec *runtimeres.ExtensionStatusSpec) bool {
+				return !spec.Loaded && spec.Error != "" && spec.Image == "drbd.sqsh"
+			},
+		} {
+			res, err := suite.state.Get(suite.ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.ExtensionStatusType, id, resource.VersionUndefined))
+			if err != nil {
+				if state.IsNotFoundError(err) {
+					return retry.ExpectedError(err)
+				}
+
+				return err
+			}
+
+			if spec := res.(*runtimeres.ExtensionStatus).TypedSpec(); !check(spec) {
+				return retry.ExpectedErrorf("unexpected status %+v", spec)
+			}
+		}
+
+		return nil
+	}))
+}
+
+func (suite *ExtensionStatusSuite) TestNoExtensions() {
+	suite.Require().NoError(suite.runtime.RegisterController(&runtimectrl.ExtensionStatusController{
+		StatusPath: filepath.Join(suite.T().TempDir(), "extensions.yaml"),
+	}))
+
+	suite.startRuntime()
+
+	// give the controller a chance to run
+	time.Sleep(500 * time.Millisecond)
+
+	list, err := suite.state.List(suite.ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.ExtensionStatusType, "", resource.VersionUndefined))
+	suite.Require().NoError(err)
+	suite.Assert().Empty(list.Items)
+}
+
+func (suite *ExtensionStatusSuite) TearDownTest() {
+	suite.T().Log("tear down")
+
+	suite.ctxCancel()
+
+	suite.wg.Wait()
+}
+
+func TestExtensionStatusSuite(t *testing.T) {
+	suite.Run(t, new(ExtensionStatusSuite))
+}
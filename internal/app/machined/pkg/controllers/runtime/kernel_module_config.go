@@ -0,0 +1,106 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlekSi/pointer"
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"go.uber.org/zap"
+
+	"github.com/talos-systems/talos/internal/pkg/kernel/kmod"
+	"github.com/talos-systems/talos/pkg/resources/config"
+	runtimeres "github.com/talos-systems/talos/pkg/resources/runtime"
+)
+
+// KernelModuleConfigController generates KernelModuleSpecs from the machine configuration (.machine.kernel.modules).
+type KernelModuleConfigController struct{}
+
+// Name implements controller.Controller interface.
+func (ctrl *KernelModuleConfigController) Name() string {
+	return "runtime.KernelModuleConfigController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *KernelModuleConfigController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: config.NamespaceName,
+			Type:      config.MachineConfigType,
+			ID:        pointer.ToString(config.V1Alpha1ID),
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *KernelModuleConfigController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: runtimeres.KernelModuleSpecType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *KernelModuleConfigController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		touchedIDs := map[resource.ID]struct{}{}
+
+		cfg, err := r.Get(ctx, resource.NewMetadata(config.NamespaceName, config.MachineConfigType, config.V1Alpha1ID, resource.VersionUndefined))
+		if err != nil {
+			if !state.IsNotFoundError(err) {
+				return fmt.Errorf("error getting config: %w", err)
+			}
+		} else {
+			for _, module := range cfg.(*config.MachineConfig).Config().Machine().Kernel().Modules() {
+				module := module
+				id := kmod.ModuleName(module.Name())
+
+				if err = r.Modify(ctx, runtimeres.NewKernelModuleSpec(runtimeres.NamespaceName, id), func(res resource.Resource) error {
+					spec := res.(*runtimeres.KernelModuleSpec).TypedSpec()
+
+					spec.Name = module.Name()
+					spec.Parameters = append([]string(nil), module.Parameters()...)
+
+					return nil
+				}); err != nil {
+					return fmt.Errorf("error updating kernel module spec %q: %w", id, err)
+				}
+
+				touchedIDs[id] = struct{}{}
+			}
+		}
+
+		// clean up specs for the modules removed from the config
+		list, err := r.List(ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.KernelModuleSpecType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing kernel module specs: %w", err)
+		}
+
+		for _, res := range list.Items {
+			if res.Metadata().Owner() != ctrl.Name() {
+				continue
+			}
+
+			if _, ok := touchedIDs[res.Metadata().ID()]; !ok {
+				if err = r.Destroy(ctx, res.Metadata()); err != nil {
+					return fmt.Errorf("error cleaning up kernel module spec: %w", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"go.uber.org/zap"
+
+	v1alpha1runtime "github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/kernel/kmod"
+	runtimeres "github.com/talos-systems/talos/pkg/resources/runtime"
+)
+
+// ModuleLoader loads kernel modules, interface for mocking.
+type ModuleLoader interface {
+	Has(name string) bool
+	Load(name string, parameters []string) error
+}
+
+// KernelModuleSpecController loads kernel modules from KernelModuleSpecs and reports KernelModuleStatuses.
+//
+// Kernel modules are never unloaded: when the KernelModuleSpec is removed, only the status is cleaned up.
+type KernelModuleSpecController struct {
+	V1Alpha1Mode v1alpha1runtime.Mode
+	// Loader is built from the modules shipped with the running kernel by default, can be overridden for testing.
+	Loader ModuleLoader
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *KernelModuleSpecController) Name() string {
+	return "runtime.KernelModuleSpecController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *KernelModuleSpecController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: runtimeres.NamespaceName,
+			Type:      runtimeres.KernelModuleSpecType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *KernelModuleSpecController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: runtimeres.KernelModuleStatusType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+//
+//nolint:gocyclo
+func (ctrl *KernelModuleSpecController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	// kernel modules can't be loaded in the container
+	if ctrl.V1Alpha1Mode == v1alpha1runtime.ModeContainer {
+		return nil
+	}
+
+	var loaderErr error
+
+	if ctrl.Loader == nil {
+		ctrl.Loader, loaderErr = defaultModuleLoader()
+		if loaderErr != nil {
+			logger.Error("failed to build kernel modules index", zap.Error(loaderErr))
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		specs, err := r.List(ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.KernelModuleSpecType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing kernel module specs: %w", err)
+		}
+
+		statusList, err := r.List(ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.KernelModuleStatusType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing kernel module statuses: %w", err)
+		}
+
+		statuses := make(map[resource.ID]runtimeres.KernelModuleStatusSpec, len(statusList.Items))
+
+		for _, res := range statusList.Items {
+			statuses[res.Metadata().ID()] = *res.(*runtimeres.KernelModuleStatus).TypedSpec()
+		}
+
+		touchedIDs := make(map[resource.ID]struct{}, len(specs.Items))
+
+		for _, res := range specs.Items {
+			id := res.Metadata().ID()
+			spec := res.(*runtimeres.KernelModuleSpec).TypedSpec()
+
+			touchedIDs[id] = struct{}{}
+
+			status := statuses[id]
+
+			switch {
+			case status.Loaded && reflect.DeepEqual(status.Parameters, spec.Parameters):
+				// already loaded, parameters might have been reverted back
+				status.Error = ""
+			case status.Loaded:
+				status.Error = "module is already loaded, changing parameters requires a reboot"
+			case loaderErr != nil:
+				status.Error = fmt.Sprintf("failed to build kernel modules index: %s", loaderErr)
+			case !ctrl.Loader.Has(spec.Name):
+				status.Error = fmt.Sprintf("kernel module %q is not shipped with the Talos image", spec.Name)
+			default:
+				if err = ctrl.Loader.Load(spec.Name, spec.Parameters); err != nil {
+					logger.Error("failed loading kernel module", zap.String("module", spec.Name), zap.Error(err))
+
+					status.Error = err.Error()
+				} else {
+					logger.Info("loaded kernel module", zap.String("module", spec.Name), zap.Strings("parameters", spec.Parameters))
+
+					status.Loaded = true
+					status.Parameters = append([]string(nil), spec.Parameters...)
+					status.Error = ""
+				}
+			}
+
+			if err = r.Modify(ctx, runtimeres.NewKernelModuleStatus(runtimeres.NamespaceName, id), func(res resource.Resource) error {
+				*res.(*runtimeres.KernelModuleStatus).TypedSpec() = status
+
+				return nil
+			}); err != nil {
+				return fmt.Errorf("error updating kernel module status %q: %w", id, err)
+			}
+		}
+
+		for id := range statuses {
+			if _, ok := touchedIDs[id]; ok {
+				continue
+			}
+
+			if err = r.Destroy(ctx, runtimeres.NewKernelModuleStatus(runtimeres.NamespaceName, id).Metadata()); err != nil {
+				return fmt.Errorf("error cleaning up kernel module status %q: %w", id, err)
+			}
+		}
+	}
+}
+
+func defaultModuleLoader() (ModuleLoader, error) {
+	root, err := kmod.DefaultRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	return kmod.NewIndex(root)
+}
@@ -0,0 +1,243 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/stretchr/testify/suite"
+	"github.com/talos-systems/go-retry/retry"
+
+	runtimectrl "github.com/talos-systems/talos/internal/app/machined/pkg/controllers/runtime"
+	"github.com/talos-systems/talos/internal/pkg/kernel/kmod"
+	"github.com/talos-systems/talos/pkg/logging"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/resources/config"
+	runtimeres "github.com/talos-systems/talos/pkg/resources/runtime"
+)
+
+type mockModuleLoader struct {
+	mu sync.Mutex
+
+	shipped map[string]error
+	loaded  map[string][]string
+}
+
+func (loader *mockModuleLoader) Has(name string) bool {
+	_, ok := loader.shipped[kmod.ModuleName(name)]
+
+	return ok
+}
+
+func (loader *mockModuleLoader) Load(name string, parameters []string) error {
+	loader.mu.Lock()
+	defer loader.mu.Unlock()
+
+	name = kmod.ModuleName(name)
+
+	if err := loader.shipped[name]; err != nil {
+		return err
+	}
+
+	loader.loaded[name] = parameters
+
+	return nil
+}
+
+func (loader *mockModuleLoader) parameters(name string) ([]string, bool) {
+	loader.mu.Lock()
+	defer loader.mu.Unlock()
+
+	parameters, ok := loader.loaded[name]
+
+	return parameters, ok
+}
+
+type KernelModuleSuite struct {
+	suite.Suite
+
+	state state.State
+
+	runtime *runtime.Runtime
+	wg      sync.WaitGroup
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+
+	loader *mockModuleLoader
+}
+
+func (suite *KernelModuleSuite) SetupTest() {
+	suite.ctx, suite.ctxCancel = context.WithTimeout(context.Background(), 3*time.Minute)
+
+	suite.state = state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var err error
+
+	suite.runtime, err = runtime.NewRuntime(suite.state, logging.Wrap(log.Writer()))
+	suite.Require().NoError(err)
+
+	suite.loader = &mockModuleLoader{
+		shipped: map[string]error{
+			"nvme_tcp": nil,
+			"ip_vs":    nil,
+			"broken":   errors.New("exec format error"),
+		},
+		loaded: map[string][]string{},
+	}
+
+	suite.Require().NoError(suite.runtime.RegisterController(&runtimectrl.KernelModuleConfigController{}))
+	suite.Require().NoError(suite.runtime.RegisterController(&runtimectrl.KernelModuleSpecController{
+		Loader: suite.loader,
+	}))
+
+	suite.wg.Add(1)
+
+	go func() {
+		defer suite.wg.Done()
+
+		suite.Assert().NoError(suite.runtime.Run(suite.ctx))
+	}()
+}
+
+func (suite *KernelModuleSuite) assertStatus(id string, check func(*runtimeres.KernelModuleStatusSpec) error) error {
+	res, err := suite.state.Get(suite.ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.KernelModuleStatusType, id, resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return retry.ExpectedError(err)
+		}
+
+		return err
+	}
+
+	return check(res.(*runtimeres.KernelModuleStatus).TypedSpec())
+}
+
+func (suite *KernelModuleSuite) retry(f func() error) {
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(f))
+}
+
+func (suite *KernelModuleSuite) TestReconcile() {
+	cfg := config.NewMachineConfig(&v1alpha1.Config{
+		ConfigVersion: "v1alpha1",
+		MachineConfig: &v1alpha1.MachineConfig{
+			MachineKernel: &v1alpha1.KernelConfig{
+				KernelModules: []*v1alpha1.KernelModuleConfig{
+					{
+						ModuleName: "nvme-tcp",
+					},
+					{
+						ModuleName:       "ip_vs",
+						ModuleParameters: []string{"conn_tab_bits=15"},
+					},
+					{
+						ModuleName: "broken",
+					},
+					{
+						ModuleName: "missing",
+					},
+				},
+			},
+		},
+	})
+
+	suite.Require().NoError(suite.state.Create(suite.ctx, cfg))
+
+	suite.retry(func() error {
+		for _, id := range []string{"nvme_tcp", "ip_vs"} {
+			if err := suite.assertStatus(id, func(status *runtimeres.KernelModuleStatusSpec) error {
+				if !status.Loaded || status.Error != "" {
+					return retry.ExpectedErrorf("unexpected status %+v", status)
+				}
+
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		for id, expectedError := range map[string]string{
+			"broken":  "exec format error",
+			"missing": "kernel module \"missing\" is not shipped with the Talos image",
+		} {
+			expectedError := expectedError
+
+			if err := suite.assertStatus(id, func(status *runtimeres.KernelModuleStatusSpec) error {
+				if status.Loaded || status.Error != expectedError {
+					return retry.ExpectedErrorf("unexpected status %+v", status)
+				}
+
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	parameters, ok := suite.loader.parameters("ip_vs")
+	suite.Assert().True(ok)
+	suite.Assert().Equal([]string{"conn_tab_bits=15"}, parameters)
+
+	// changing parameters of the loaded module is not possible without a reboot
+	_, err := suite.state.UpdateWithConflicts(suite.ctx, cfg.Metadata(), func(r resource.Resource) error {
+		r.(*config.MachineConfig).Config().(*v1alpha1.Config).MachineConfig.MachineKernel.KernelModules = []*v1alpha1.KernelModuleConfig{
+			{
+				ModuleName:       "ip_vs",
+				ModuleParameters: []string{"conn_tab_bits=12"},
+			},
+		}
+
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	suite.retry(func() error {
+		for _, id := range []string{"nvme_tcp", "broken", "missing"} {
+			_, err := suite.state.Get(suite.ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.KernelModuleStatusType, id, resource.VersionUndefined))
+			if err == nil {
+				return retry.ExpectedErrorf("status %q still exists", id)
+			}
+
+			if !state.IsNotFoundError(err) {
+				return err
+			}
+		}
+
+		return suite.assertStatus("ip_vs", func(status *runtimeres.KernelModuleStatusSpec) error {
+			if !status.Loaded || status.Error == "" {
+				return retry.ExpectedErrorf("unexpected status %+v", status)
+			}
+
+			return nil
+		})
+	})
+
+	parameters, _ = suite.loader.parameters("ip_vs")
+	suite.Assert().Equal([]string{"conn_tab_bits=15"}, parameters)
+}
+
+func (suite *KernelModuleSuite) TearDownTest() {
+	suite.T().Log("tear down")
+
+	suite.ctxCancel()
+
+	suite.wg.Wait()
+}
+
+func TestKernelModuleSuite(t *testing.T) {
+	suite.Run(t, new(KernelModuleSuite))
+}
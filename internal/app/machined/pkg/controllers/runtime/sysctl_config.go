@@ -0,0 +1,101 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlekSi/pointer"
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"go.uber.org/zap"
+
+	"github.com/talos-systems/talos/pkg/resources/config"
+	runtimeres "github.com/talos-systems/talos/pkg/resources/runtime"
+)
+
+// SysctlConfigController generates SysctlSpecs from the machine configuration (.machine.sysctls).
+type SysctlConfigController struct{}
+
+// Name implements controller.Controller interface.
+func (ctrl *SysctlConfigController) Name() string {
+	return "runtime.SysctlConfigController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *SysctlConfigController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: config.NamespaceName,
+			Type:      config.MachineConfigType,
+			ID:        pointer.ToString(config.V1Alpha1ID),
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *SysctlConfigController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: runtimeres.SysctlSpecType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *SysctlConfigController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		touchedIDs := map[resource.ID]struct{}{}
+
+		cfg, err := r.Get(ctx, resource.NewMetadata(config.NamespaceName, config.MachineConfigType, config.V1Alpha1ID, resource.VersionUndefined))
+		if err != nil {
+			if !state.IsNotFoundError(err) {
+				return fmt.Errorf("error getting config: %w", err)
+			}
+		} else {
+			for key, value := range cfg.(*config.MachineConfig).Config().Machine().Sysctls() {
+				value := value
+
+				if err = r.Modify(ctx, runtimeres.NewSysctlSpec(runtimeres.NamespaceName, key), func(res resource.Resource) error {
+					res.(*runtimeres.SysctlSpec).TypedSpec().Value = value
+
+					return nil
+				}); err != nil {
+					return fmt.Errorf("error updating sysctl spec %q: %w", key, err)
+				}
+
+				touchedIDs[key] = struct{}{}
+			}
+		}
+
+		// clean up specs for the keys removed from the config
+		list, err := r.List(ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.SysctlSpecType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing sysctl specs: %w", err)
+		}
+
+		for _, res := range list.Items {
+			if res.Metadata().Owner() != ctrl.Name() {
+				continue
+			}
+
+			if _, ok := touchedIDs[res.Metadata().ID()]; !ok {
+				if err = r.Destroy(ctx, res.Metadata()); err != nil {
+					return fmt.Errorf("error cleaning up sysctl spec: %w", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"go.uber.org/zap"
+
+	runtimeres "github.com/talos-systems/talos/pkg/resources/runtime"
+)
+
+// DefaultSysctlCheckInterval defines how often kernel parameters are checked for changes made outside of Talos.
+const DefaultSysctlCheckInterval = time.Minute
+
+// SysctlSpecController applies SysctlSpecs to the kernel and reports SysctlStatuses.
+//
+// When the SysctlSpec is removed, the original value of the kernel parameter is restored.
+// Kernel parameters are periodically checked, and changes made outside of Talos are reported as mismatches.
+type SysctlSpecController struct {
+	// ProcSysPath is the path to /proc/sys, can be overridden for testing.
+	ProcSysPath string
+	// CheckInterval defines how often kernel parameters are checked for mismatches.
+	CheckInterval time.Duration
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *SysctlSpecController) Name() string {
+	return "runtime.SysctlSpecController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *SysctlSpecController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: runtimeres.NamespaceName,
+			Type:      runtimeres.SysctlSpecType,
+			Kind:      controller.InputWeak,
+		},
+		// kernel parameters might become available once the kernel module is loaded
+		{
+			Namespace: runtimeres.NamespaceName,
+			Type:      runtimeres.KernelModuleStatusType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *SysctlSpecController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: runtimeres.SysctlStatusType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+//
+//nolint:gocyclo,cyclop
+func (ctrl *SysctlSpecController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	if ctrl.ProcSysPath == "" {
+		ctrl.ProcSysPath = "/proc/sys"
+	}
+
+	if ctrl.CheckInterval == 0 {
+		ctrl.CheckInterval = DefaultSysctlCheckInterval
+	}
+
+	ticker := time.NewTicker(ctrl.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-ticker.C:
+		}
+
+		specs, err := r.List(ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.SysctlSpecType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing sysctl specs: %w", err)
+		}
+
+		statusList, err := r.List(ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.SysctlStatusType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing sysctl statuses: %w", err)
+		}
+
+		statuses := make(map[resource.ID]runtimeres.SysctlStatusSpec, len(statusList.Items))
+
+		for _, res := range statusList.Items {
+			statuses[res.Metadata().ID()] = *res.(*runtimeres.SysctlStatus).TypedSpec()
+		}
+
+		touchedIDs := make(map[resource.ID]struct{}, len(specs.Items))
+
+		for _, res := range specs.Items {
+			key := res.Metadata().ID()
+			desired := res.(*runtimeres.SysctlSpec).TypedSpec().Value
+
+			// status is kept even if the spec fails to be applied to remember the original value
+			touchedIDs[key] = struct{}{}
+
+			status := statuses[key]
+
+			if err = ctrl.reconcile(logger, key, desired, &status); err != nil {
+				// failure is reported in the status, and the parameter is applied again on the next check
+				if status.Error != err.Error() {
+					logger.Error("failed applying kernel parameter", zap.String("key", key), zap.String("value", desired), zap.Error(err))
+				}
+
+				status.Error = err.Error()
+			} else {
+				status.Error = ""
+			}
+
+			if err = r.Modify(ctx, runtimeres.NewSysctlStatus(runtimeres.NamespaceName, key), func(res resource.Resource) error {
+				*res.(*runtimeres.SysctlStatus).TypedSpec() = status
+
+				return nil
+			}); err != nil {
+				return fmt.Errorf("error updating sysctl status %q: %w", key, err)
+			}
+		}
+
+		// restore original values for the kernel parameters which are no longer managed
+		for key, status := range statuses {
+			if _, ok := touchedIDs[key]; ok {
+				continue
+			}
+
+			// the value which was never applied doesn't need to be restored
+			if status.Value != "" {
+				if err = ctrl.write(key, status.OriginalValue); err != nil {
+					logger.Error("failed restoring kernel parameter", zap.String("key", key), zap.String("value", status.OriginalValue), zap.Error(err))
+				} else {
+					logger.Info("restored kernel parameter", zap.String("key", key), zap.String("value", status.OriginalValue))
+				}
+			}
+
+			if err = r.Destroy(ctx, runtimeres.NewSysctlStatus(runtimeres.NamespaceName, key).Metadata()); err != nil {
+				return fmt.Errorf("error cleaning up sysctl status %q: %w", key, err)
+			}
+		}
+	}
+}
+
+// reconcile applies the kernel parameter and updates the status.
+func (ctrl *SysctlSpecController) reconcile(logger *zap.Logger, key, desired string, status *runtimeres.SysctlStatusSpec) error {
+	current, err := ctrl.read(key)
+	if err != nil {
+		return err
+	}
+
+	status.CurrentValue = current
+
+	// value is set only after a successful write, so the original value is captured before the first write
+	if status.Value == "" {
+		status.OriginalValue = current
+	}
+
+	switch {
+	case status.Value != desired:
+		// new or updated spec
+		if err = ctrl.write(key, desired); err != nil {
+			return err
+		}
+
+		logger.Info("applied kernel parameter", zap.String("key", key), zap.String("value", desired))
+
+		if current, err = ctrl.read(key); err != nil {
+			return err
+		}
+
+		status.Value = desired
+		status.CurrentValue = current
+		status.Mismatch = normalizeSysctlValue(current) != normalizeSysctlValue(desired)
+	case normalizeSysctlValue(current) != normalizeSysctlValue(desired):
+		if !status.Mismatch {
+			logger.Warn("kernel parameter was changed outside of Talos", zap.String("key", key), zap.String("expected", desired), zap.String("actual", current))
+		}
+
+		status.Mismatch = true
+	default:
+		status.Mismatch = false
+	}
+
+	return nil
+}
+
+func (ctrl *SysctlSpecController) path(key string) string {
+	return filepath.Join(ctrl.ProcSysPath, strings.ReplaceAll(key, ".", "/"))
+}
+
+func (ctrl *SysctlSpecController) read(key string) (string, error) {
+	contents, err := ioutil.ReadFile(ctrl.path(key))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
+
+func (ctrl *SysctlSpecController) write(key, value string) error {
+	return ioutil.WriteFile(ctrl.path(key), []byte(value), 0o644)
+}
+
+// normalizeSysctlValue handles multi-value kernel parameters which are read back with different whitespace (e.g. tabs).
+func normalizeSysctlValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,306 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/stretchr/testify/suite"
+	"github.com/talos-systems/go-retry/retry"
+
+	runtimectrl "github.com/talos-systems/talos/internal/app/machined/pkg/controllers/runtime"
+	"github.com/talos-systems/talos/pkg/logging"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/resources/config"
+	runtimeres "github.com/talos-systems/talos/pkg/resources/runtime"
+)
+
+type SysctlSuite struct {
+	suite.Suite
+
+	state state.State
+
+	runtime *runtime.Runtime
+	wg      sync.WaitGroup
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+
+	procSysPath string
+}
+
+func (suite *SysctlSuite) SetupTest() {
+	suite.ctx, suite.ctxCancel = context.WithTimeout(context.Background(), 3*time.Minute)
+
+	suite.state = state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var err error
+
+	suite.runtime, err = runtime.NewRuntime(suite.state, logging.Wrap(log.Writer()))
+	suite.Require().NoError(err)
+
+	suite.procSysPath, err = ioutil.TempDir("", "talos")
+	suite.Require().NoError(err)
+
+	for key, value := range map[string]string{
+		"net.ipv4.ip_forward":  "0",
+		"kernel.pid_max":       "32768",
+		"net.ipv4.tcp_rmem":    "4096\t131072\t6291456",
+		"vm.max_map_count":     "65530",
+		"kernel.panic_on_oops": "0",
+	} {
+		suite.writeSysctl(key, value)
+	}
+
+	suite.Require().NoError(suite.runtime.RegisterController(&runtimectrl.SysctlConfigController{}))
+	suite.Require().NoError(suite.runtime.RegisterController(&runtimectrl.SysctlSpecController{
+		ProcSysPath:   suite.procSysPath,
+		CheckInterval: 100 * time.Millisecond,
+	}))
+
+	suite.wg.Add(1)
+
+	go func() {
+		defer suite.wg.Done()
+
+		suite.Assert().NoError(suite.runtime.Run(suite.ctx))
+	}()
+}
+
+func (suite *SysctlSuite) sysctlPath(key string) string {
+	return filepath.Join(suite.procSysPath, strings.ReplaceAll(key, ".", "/"))
+}
+
+func (suite *SysctlSuite) writeSysctl(key, value string) {
+	suite.Require().NoError(os.MkdirAll(filepath.Dir(suite.sysctlPath(key)), 0o755))
+	suite.Require().NoError(ioutil.WriteFile(suite.sysctlPath(key), []byte(value+"\n"), 0o644))
+}
+
+func (suite *SysctlSuite) assertSysctl(key, expected string) error {
+	contents, err := ioutil.ReadFile(suite.sysctlPath(key))
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(string(contents)) != expected {
+		return retry.ExpectedErrorf("%s: expected %q, got %q", key, expected, strings.TrimSpace(string(contents)))
+	}
+
+	return nil
+}
+
+func (suite *SysctlSuite) assertStatus(key string, check func(*runtimeres.SysctlStatusSpec) error) error {
+	res, err := suite.state.Get(suite.ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.SysctlStatusType, key, resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return retry.ExpectedError(err)
+		}
+
+		return err
+	}
+
+	return check(res.(*runtimeres.SysctlStatus).TypedSpec())
+}
+
+func (suite *SysctlSuite) assertNoStatus(key string) error {
+	_, err := suite.state.Get(suite.ctx, resource.NewMetadata(runtimeres.NamespaceName, runtimeres.SysctlStatusType, key, resource.VersionUndefined))
+	if err == nil {
+		return retry.ExpectedErrorf("status %q still exists", key)
+	}
+
+	if state.IsNotFoundError(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (suite *SysctlSuite) retry(f func() error) {
+	suite.Assert().NoError(retry.Constant(3*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(f))
+}
+
+func (suite *SysctlSuite) TestReconcile() {
+	cfg := config.NewMachineConfig(&v1alpha1.Config{
+		ConfigVersion: "v1alpha1",
+		MachineConfig: &v1alpha1.MachineConfig{
+			MachineSysctls: map[string]string{
+				"net.ipv4.ip_forward": "1",
+				"kernel.pid_max":      "262144",
+				"net.ipv4.tcp_rmem":   "4096 131072 6291456",
+			},
+		},
+	})
+
+	suite.Require().NoError(suite.state.Create(suite.ctx, cfg))
+
+	suite.retry(func() error {
+		for key, value := range map[string]string{
+			"net.ipv4.ip_forward": "1",
+			"kernel.pid_max":      "262144",
+		} {
+			if err := suite.assertSysctl(key, value); err != nil {
+				return err
+			}
+		}
+
+		return suite.assertStatus("kernel.pid_max", func(status *runtimeres.SysctlStatusSpec) error {
+			if status.OriginalValue != "32768" || status.Value != "262144" || status.Mismatch {
+				return retry.ExpectedErrorf("unexpected status %+v", status)
+			}
+
+			return nil
+		})
+	})
+
+	// values read back with different whitespace are not a mismatch
+	suite.Require().NoError(suite.assertStatus("net.ipv4.tcp_rmem", func(status *runtimeres.SysctlStatusSpec) error {
+		suite.Assert().False(status.Mismatch)
+
+		return nil
+	}))
+
+	// change the value outside of Talos
+	suite.writeSysctl("kernel.pid_max", "4096")
+
+	suite.retry(func() error {
+		return suite.assertStatus("kernel.pid_max", func(status *runtimeres.SysctlStatusSpec) error {
+			if !status.Mismatch || status.CurrentValue != "4096" {
+				return retry.ExpectedErrorf("unexpected status %+v", status)
+			}
+
+			return nil
+		})
+	})
+
+	// update the config: change one key, remove another one, add a new one
+	_, err := suite.state.UpdateWithConflicts(suite.ctx, cfg.Metadata(), func(r resource.Resource) error {
+		r.(*config.MachineConfig).Config().(*v1alpha1.Config).MachineConfig.MachineSysctls = map[string]string{
+			"kernel.pid_max":   "131072",
+			"vm.max_map_count": "262144",
+		}
+
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	suite.retry(func() error {
+		for key, value := range map[string]string{
+			"net.ipv4.ip_forward": "0",
+			"kernel.pid_max":      "131072",
+			"vm.max_map_count":    "262144",
+			"net.ipv4.tcp_rmem":   "4096\t131072\t6291456",
+		} {
+			if err := suite.assertSysctl(key, value); err != nil {
+				return err
+			}
+		}
+
+		if err := suite.assertNoStatus("net.ipv4.ip_forward"); err != nil {
+			return err
+		}
+
+		return suite.assertStatus("kernel.pid_max", func(status *runtimeres.SysctlStatusSpec) error {
+			if status.OriginalValue != "32768" || status.Value != "131072" || status.Mismatch {
+				return retry.ExpectedErrorf("unexpected status %+v", status)
+			}
+
+			return nil
+		})
+	})
+
+	// removing the config restores the original values
+	suite.Require().NoError(suite.state.Destroy(suite.ctx, cfg.Metadata()))
+
+	suite.retry(func() error {
+		for key, value := range map[string]string{
+			"kernel.pid_max":   "32768",
+			"vm.max_map_count": "65530",
+		} {
+			if err := suite.assertSysctl(key, value); err != nil {
+				return err
+			}
+		}
+
+		return suite.assertNoStatus("kernel.pid_max")
+	})
+}
+
+func (suite *SysctlSuite) TestFailure() {
+	cfg := config.NewMachineConfig(&v1alpha1.Config{
+		ConfigVersion: "v1alpha1",
+		MachineConfig: &v1alpha1.MachineConfig{
+			MachineSysctls: map[string]string{
+				"kernel.pid_max":     "262144",
+				"net.ipv4.not_found": "1",
+			},
+		},
+	})
+
+	suite.Require().NoError(suite.state.Create(suite.ctx, cfg))
+
+	suite.retry(func() error {
+		if err := suite.assertSysctl("kernel.pid_max", "262144"); err != nil {
+			return err
+		}
+
+		return suite.assertStatus("net.ipv4.not_found", func(status *runtimeres.SysctlStatusSpec) error {
+			if status.Error == "" || status.Value != "" {
+				return retry.ExpectedErrorf("unexpected status %+v", status)
+			}
+
+			return nil
+		})
+	})
+
+	suite.Require().NoError(suite.assertStatus("kernel.pid_max", func(status *runtimeres.SysctlStatusSpec) error {
+		suite.Assert().Empty(status.Error)
+
+		return nil
+	}))
+
+	// the parameter is applied on the next check once it becomes available
+	suite.writeSysctl("net.ipv4.not_found", "0")
+
+	suite.retry(func() error {
+		if err := suite.assertSysctl("net.ipv4.not_found", "1"); err != nil {
+			return err
+		}
+
+		return suite.assertStatus("net.ipv4.not_found", func(status *runtimeres.SysctlStatusSpec) error {
+			if status.Error != "" || status.Value != "1" || status.OriginalValue != "0" {
+				return retry.ExpectedErrorf("unexpected status %+v", status)
+			}
+
+			return nil
+		})
+	})
+}
+
+func (suite *SysctlSuite) TearDownTest() {
+	suite.T().Log("tear down")
+
+	suite.ctxCancel()
+
+	suite.wg.Wait()
+
+	suite.Assert().NoError(os.RemoveAll(suite.procSysPath))
+}
+
+func TestSysctlSuite(t *testing.T) {
+	suite.Run(t, new(SysctlSuite))
+}
@@ -0,0 +1,230 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"go.uber.org/zap"
+
+	v1alpha1runtime "github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/watchdog"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+	"github.com/talos-systems/talos/pkg/resources/config"
+	"github.com/talos-systems/talos/pkg/resources/v1alpha1"
+)
+
+// CriticalServices is a list of services which should be healthy to keep petting the watchdog.
+//
+// Only the services required to manage the node are included: rebooting the node is not going to help
+// if the service is unhealthy because of the cluster-wide issue (e.g. etcd quorum loss).
+var CriticalServices = []string{"apid", "containerd", "cri"}
+
+// Watchdog interface is implemented by watchdog.Device, interface for mocking.
+type Watchdog interface {
+	SetTimeout(time.Duration) error
+	Pet() error
+	Close() error
+}
+
+// OpenWatchdogFunc function allows to replace watchdog.Device with the mock.
+type OpenWatchdogFunc func(device string) (Watchdog, error)
+
+// WatchdogTimerController pets the watchdog timer while the critical services are healthy.
+type WatchdogTimerController struct {
+	V1Alpha1Mode v1alpha1runtime.Mode
+	OpenWatchdog OpenWatchdogFunc
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *WatchdogTimerController) Name() string {
+	return "runtime.WatchdogTimerController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *WatchdogTimerController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: config.NamespaceName,
+			Type:      config.MachineConfigType,
+			ID:        pointer.ToString(config.V1Alpha1ID),
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: v1alpha1.NamespaceName,
+			Type:      v1alpha1.ServiceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *WatchdogTimerController) Outputs() []controller.Output {
+	return nil
+}
+
+// Run implements controller.Controller interface.
+//
+//nolint:gocyclo,cyclop
+func (ctrl *WatchdogTimerController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	if ctrl.OpenWatchdog == nil {
+		ctrl.OpenWatchdog = func(device string) (Watchdog, error) {
+			// fall back to the softdog only if the hardware watchdog is not available under the default path
+			return watchdog.Open(device, device == constants.DefaultWatchdogDevice)
+		}
+	}
+
+	var (
+		wd      Watchdog
+		device  string
+		timeout time.Duration
+		healthy bool
+
+		ticker   *time.Ticker
+		tickerCh <-chan time.Time
+	)
+
+	stop := func() error {
+		if wd == nil {
+			return nil
+		}
+
+		ticker.Stop()
+
+		ticker = nil
+		tickerCh = nil
+
+		err := wd.Close()
+		wd = nil
+
+		logger.Info("watchdog timer stopped", zap.String("device", device))
+
+		return err
+	}
+
+	defer stop() //nolint:errcheck
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-tickerCh:
+			if healthy {
+				if err := wd.Pet(); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		cfg, err := r.Get(ctx, resource.NewMetadata(config.NamespaceName, config.MachineConfigType, config.V1Alpha1ID, resource.VersionUndefined))
+		if err != nil && !state.IsNotFoundError(err) {
+			return fmt.Errorf("error getting config: %w", err)
+		}
+
+		enabled := ctrl.V1Alpha1Mode != v1alpha1runtime.ModeContainer &&
+			cfg != nil && cfg.(*config.MachineConfig).Config().Machine().Watchdog().Enabled()
+
+		if !enabled {
+			if err = stop(); err != nil {
+				return fmt.Errorf("error stopping watchdog: %w", err)
+			}
+
+			continue
+		}
+
+		watchdogConfig := cfg.(*config.MachineConfig).Config().Machine().Watchdog()
+
+		if wd != nil && device != watchdogConfig.Device() {
+			if err = stop(); err != nil {
+				return fmt.Errorf("error stopping watchdog: %w", err)
+			}
+		}
+
+		if wd == nil {
+			device = watchdogConfig.Device()
+
+			if wd, err = ctrl.OpenWatchdog(device); err != nil {
+				return err
+			}
+
+			timeout = 0
+			healthy = true
+		}
+
+		if timeout != watchdogConfig.Timeout() {
+			timeout = watchdogConfig.Timeout()
+
+			if err = wd.SetTimeout(timeout); err != nil {
+				return err
+			}
+
+			// pet the watchdog several times within the timeout, so that a single missed tick doesn't reset the node
+			if ticker == nil {
+				ticker = time.NewTicker(timeout / 4)
+				tickerCh = ticker.C
+			} else {
+				ticker.Reset(timeout / 4)
+			}
+
+			logger.Info("watchdog timer configured", zap.String("device", device), zap.Duration("timeout", timeout))
+		}
+
+		wasHealthy := healthy
+
+		if healthy, err = ctrl.servicesHealthy(ctx, r, logger, wasHealthy); err != nil {
+			return err
+		}
+
+		if healthy {
+			if !wasHealthy {
+				logger.Info("critical services are healthy, resuming petting the watchdog")
+			}
+
+			if err = wd.Pet(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (ctrl *WatchdogTimerController) servicesHealthy(ctx context.Context, r controller.Runtime, logger *zap.Logger, wasHealthy bool) (bool, error) {
+	list, err := r.List(ctx, resource.NewMetadata(v1alpha1.NamespaceName, v1alpha1.ServiceType, "", resource.VersionUndefined))
+	if err != nil {
+		return false, fmt.Errorf("error listing services: %w", err)
+	}
+
+	services := make(map[resource.ID]*v1alpha1.Service, len(list.Items))
+
+	for _, res := range list.Items {
+		services[res.Metadata().ID()] = res.(*v1alpha1.Service)
+	}
+
+	for _, id := range CriticalServices {
+		svc, ok := services[id]
+		if !ok {
+			// services which are not running yet (or restarting) are handled by the service restart policy
+			continue
+		}
+
+		if svc.Running() && !svc.Unknown() && !svc.Healthy() {
+			if wasHealthy {
+				logger.Warn("critical service is unhealthy, watchdog is not pet", zap.String("service", id))
+			}
+
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,251 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/stretchr/testify/suite"
+	"github.com/talos-systems/go-retry/retry"
+
+	runtimectrl "github.com/talos-systems/talos/internal/app/machined/pkg/controllers/runtime"
+	v1alpha1runtime "github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/pkg/logging"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/resources/config"
+	v1alpha1resource "github.com/talos-systems/talos/pkg/resources/v1alpha1"
+)
+
+type WatchdogSuite struct {
+	suite.Suite
+
+	state state.State
+
+	runtime *runtime.Runtime
+	wg      sync.WaitGroup
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+
+	watchdog *mockWatchdog
+}
+
+func (suite *WatchdogSuite) SetupTest() {
+	suite.ctx, suite.ctxCancel = context.WithTimeout(context.Background(), 3*time.Minute)
+
+	suite.state = state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var err error
+
+	logger := logging.Wrap(log.Writer())
+
+	suite.runtime, err = runtime.NewRuntime(suite.state, logger)
+	suite.Require().NoError(err)
+
+	suite.watchdog = &mockWatchdog{}
+}
+
+func (suite *WatchdogSuite) startRuntime() {
+	suite.wg.Add(1)
+
+	go func() {
+		defer suite.wg.Done()
+
+		suite.Assert().NoError(suite.runtime.Run(suite.ctx))
+	}()
+}
+
+func (suite *WatchdogSuite) openWatchdog(device string) (runtimectrl.Watchdog, error) {
+	suite.watchdog.mu.Lock()
+	defer suite.watchdog.mu.Unlock()
+
+	suite.watchdog.device = device
+	suite.watchdog.open = true
+
+	return suite.watchdog, nil
+}
+
+func (suite *WatchdogSuite) assertPetting() error {
+	pets := suite.watchdog.getPets()
+
+	time.Sleep(300 * time.Millisecond)
+
+	if suite.watchdog.getPets() == pets {
+		return retry.ExpectedError(fmt.Errorf("watchdog is not pet"))
+	}
+
+	return nil
+}
+
+func (suite *WatchdogSuite) assertNotPetting() error {
+	pets := suite.watchdog.getPets()
+
+	time.Sleep(300 * time.Millisecond)
+
+	if suite.watchdog.getPets() != pets {
+		return retry.ExpectedError(fmt.Errorf("watchdog is still pet"))
+	}
+
+	return nil
+}
+
+func (suite *WatchdogSuite) TestContainerMode() {
+	suite.Require().NoError(suite.runtime.RegisterController(&runtimectrl.WatchdogTimerController{
+		V1Alpha1Mode: v1alpha1runtime.ModeContainer,
+		OpenWatchdog: suite.openWatchdog,
+	}))
+
+	suite.startRuntime()
+
+	suite.Require().NoError(suite.state.Create(suite.ctx, config.NewMachineConfig(&v1alpha1.Config{
+		ConfigVersion: "v1alpha1",
+		MachineConfig: &v1alpha1.MachineConfig{
+			MachineWatchdog: &v1alpha1.WatchdogConfig{},
+		},
+	})))
+
+	time.Sleep(time.Second)
+
+	suite.Assert().False(suite.watchdog.isOpen())
+}
+
+func (suite *WatchdogSuite) TestReconcile() {
+	suite.Require().NoError(suite.runtime.RegisterController(&runtimectrl.WatchdogTimerController{
+		V1Alpha1Mode: v1alpha1runtime.ModeMetal,
+		OpenWatchdog: suite.openWatchdog,
+	}))
+
+	suite.startRuntime()
+
+	cfg := config.NewMachineConfig(&v1alpha1.Config{
+		ConfigVersion: "v1alpha1",
+		MachineConfig: &v1alpha1.MachineConfig{},
+	})
+	suite.Require().NoError(suite.state.Create(suite.ctx, cfg))
+
+	time.Sleep(time.Second)
+
+	suite.Assert().False(suite.watchdog.isOpen())
+
+	_, err := suite.state.UpdateWithConflicts(suite.ctx, cfg.Metadata(), func(r resource.Resource) error {
+		r.(*config.MachineConfig).Config().(*v1alpha1.Config).MachineConfig.MachineWatchdog = &v1alpha1.WatchdogConfig{
+			WatchdogTimeout: 400 * time.Millisecond,
+		}
+
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	suite.Assert().NoError(retry.Constant(10*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(suite.assertPetting))
+
+	suite.watchdog.mu.Lock()
+	suite.Assert().Equal("/dev/watchdog", suite.watchdog.device)
+	suite.Assert().Equal(400*time.Millisecond, suite.watchdog.timeout)
+	suite.watchdog.mu.Unlock()
+
+	// critical service becomes unhealthy
+	svc := v1alpha1resource.NewService("apid")
+	svc.SetRunning(true)
+	suite.Require().NoError(suite.state.Create(suite.ctx, svc))
+
+	suite.Assert().NoError(retry.Constant(10*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(suite.assertNotPetting))
+
+	_, err = suite.state.UpdateWithConflicts(suite.ctx, svc.Metadata(), func(r resource.Resource) error {
+		r.(*v1alpha1resource.Service).SetHealthy(true)
+
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	suite.Assert().NoError(retry.Constant(10*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(suite.assertPetting))
+
+	// disable watchdog
+	_, err = suite.state.UpdateWithConflicts(suite.ctx, cfg.Metadata(), func(r resource.Resource) error {
+		r.(*config.MachineConfig).Config().(*v1alpha1.Config).MachineConfig.MachineWatchdog = nil
+
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	suite.Assert().NoError(retry.Constant(10*time.Second, retry.WithUnits(100*time.Millisecond)).Retry(func() error {
+		if suite.watchdog.isOpen() {
+			return retry.ExpectedError(fmt.Errorf("watchdog is still open"))
+		}
+
+		return nil
+	}))
+}
+
+func (suite *WatchdogSuite) TearDownTest() {
+	suite.T().Log("tear down")
+
+	suite.ctxCancel()
+
+	suite.wg.Wait()
+}
+
+func TestWatchdogSuite(t *testing.T) {
+	suite.Run(t, new(WatchdogSuite))
+}
+
+type mockWatchdog struct {
+	mu sync.Mutex
+
+	device  string
+	timeout time.Duration
+	pets    int
+	open    bool
+}
+
+func (mock *mockWatchdog) SetTimeout(timeout time.Duration) error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	mock.timeout = timeout
+
+	return nil
+}
+
+func (mock *mockWatchdog) Pet() error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	mock.pets++
+
+	return nil
+}
+
+func (mock *mockWatchdog) Close() error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	mock.open = false
+
+	return nil
+}
+
+func (mock *mockWatchdog) getPets() int {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	return mock.pets
+}
+
+func (mock *mockWatchdog) isOpen() bool {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	return mock.open
+}
@@ -159,6 +159,13 @@ func (ctrl *APIController) reconcile(ctx context.Context, r controller.Runtime,
 			ID:        pointer.ToString(timeresource.StatusID),
 			Kind:      controller.InputWeak,
 		},
+		// renewal request isn't fetched, but it triggers certs regeneration the same way
+		{
+			Namespace: secrets.NamespaceName,
+			Type:      secrets.CertificateRenewalType,
+			ID:        pointer.ToString(secrets.CertificateRenewalID),
+			Kind:      controller.InputWeak,
+		},
 	}
 
 	if !isControlplane {
@@ -338,7 +345,7 @@ func (ctrl *APIController) generateControlPlane(ctx context.Context, r controlle
 			apiSecrets := r.(*secrets.API).TypedSpec()
 
 			apiSecrets.CA = &x509.PEMEncodedCertificateAndKey{
-				Crt: rootSpec.CA.Crt,
+				Crt: gen.AcceptedCABundle(rootSpec.CA.Crt, rootSpec.AcceptedCAs),
 			}
 			apiSecrets.Server = x509.NewCertificateAndKeyFromKeyPair(serverCert)
 			apiSecrets.Client = x509.NewCertificateAndKeyFromKeyPair(clientCert)
@@ -402,7 +409,7 @@ func (ctrl *APIController) generateJoin(ctx context.Context, r controller.Runtim
 			apiSecrets := r.(*secrets.API).TypedSpec()
 
 			apiSecrets.CA = &x509.PEMEncodedCertificateAndKey{
-				Crt: ca,
+				Crt: gen.AcceptedCABundle(ca, rootSpec.AcceptedCAs),
 			}
 			apiSecrets.Server = serverCert
 			apiSecrets.Client = clientCert
@@ -0,0 +1,297 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secrets
+
+import (
+	"context"
+	stdlibx509 "crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/talos-systems/crypto/x509"
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/talos-systems/talos/pkg/resources/secrets"
+)
+
+// Certificate components.
+const (
+	CertificateComponentKubernetes = "kubernetes"
+	CertificateComponentEtcd       = "etcd"
+	CertificateComponentTalos      = "talos"
+)
+
+// CertificateStatusController publishes secrets.CertificateStatus for every certificate managed by Talos.
+type CertificateStatusController struct{}
+
+// Name implements controller.Controller interface.
+func (ctrl *CertificateStatusController) Name() string {
+	return "secrets.CertificateStatusController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *CertificateStatusController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: secrets.NamespaceName,
+			Type:      secrets.RootType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: secrets.NamespaceName,
+			Type:      secrets.APIType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: secrets.NamespaceName,
+			Type:      secrets.EtcdType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: secrets.NamespaceName,
+			Type:      secrets.KubernetesType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *CertificateStatusController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: secrets.CertificateStatusType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+type managedCertificate struct {
+	id        resource.ID
+	component string
+	crt       []byte
+}
+
+// Run implements controller.Controller interface.
+//
+//nolint:gocyclo
+func (ctrl *CertificateStatusController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		certs, err := ctrl.gatherCertificates(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		touchedIDs := make(map[resource.ID]struct{}, len(certs))
+
+		for _, cert := range certs {
+			cert := cert
+
+			var parsed *stdlibx509.Certificate
+
+			parsed, err = parseCertificate(cert.crt)
+			if err != nil {
+				logger.Warn("failed to parse certificate", zap.String("id", cert.id), zap.Error(err))
+
+				continue
+			}
+
+			if err = r.Modify(ctx, secrets.NewCertificateStatus(cert.id), func(r resource.Resource) error {
+				spec := r.(*secrets.CertificateStatus).TypedSpec()
+
+				spec.Component = cert.component
+				spec.Subject = parsed.Subject.String()
+				spec.Issuer = parsed.Issuer.String()
+				spec.DNSNames = parsed.DNSNames
+				spec.IPAddresses = make([]string, 0, len(parsed.IPAddresses))
+
+				for _, ip := range parsed.IPAddresses {
+					spec.IPAddresses = append(spec.IPAddresses, ip.String())
+				}
+
+				spec.IsCA = parsed.IsCA
+				spec.NotBefore = parsed.NotBefore
+				spec.NotAfter = parsed.NotAfter
+
+				return nil
+			}); err != nil {
+				return fmt.Errorf("error updating certificate status: %w", err)
+			}
+
+			touchedIDs[cert.id] = struct{}{}
+		}
+
+		list, err := r.List(ctx, resource.NewMetadata(secrets.NamespaceName, secrets.CertificateStatusType, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing resources: %w", err)
+		}
+
+		for _, res := range list.Items {
+			if res.Metadata().Owner() != ctrl.Name() {
+				continue
+			}
+
+			if _, ok := touchedIDs[res.Metadata().ID()]; !ok {
+				if err = r.Destroy(ctx, res.Metadata()); err != nil {
+					return fmt.Errorf("error cleaning up certificate status: %w", err)
+				}
+			}
+		}
+	}
+}
+
+//nolint:gocyclo,cyclop
+func (ctrl *CertificateStatusController) gatherCertificates(ctx context.Context, r controller.Runtime) ([]managedCertificate, error) {
+	var certs []managedCertificate
+
+	add := func(id resource.ID, component string, certAndKey *x509.PEMEncodedCertificateAndKey) {
+		if certAndKey == nil || len(certAndKey.Crt) == 0 {
+			return
+		}
+
+		certs = append(certs, managedCertificate{
+			id:        id,
+			component: component,
+			crt:       certAndKey.Crt,
+		})
+	}
+
+	addKubeconfig := func(id resource.ID, kubeconfig string) error {
+		if kubeconfig == "" {
+			return nil
+		}
+
+		config, err := clientcmd.Load([]byte(kubeconfig))
+		if err != nil {
+			return fmt.Errorf("error parsing %q kubeconfig: %w", id, err)
+		}
+
+		kubeContext, ok := config.Contexts[config.CurrentContext]
+		if !ok {
+			return nil
+		}
+
+		if authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]; ok {
+			add(id, CertificateComponentKubernetes, &x509.PEMEncodedCertificateAndKey{Crt: authInfo.ClientCertificateData})
+		}
+
+		return nil
+	}
+
+	get := func(resourceType resource.Type, id resource.ID) (resource.Resource, error) {
+		res, err := r.Get(ctx, resource.NewMetadata(secrets.NamespaceName, resourceType, id, resource.VersionUndefined))
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				return nil, nil //nolint:nilnil
+			}
+
+			return nil, fmt.Errorf("error getting %s: %w", resourceType, err)
+		}
+
+		return res, nil
+	}
+
+	res, err := get(secrets.RootType, secrets.RootOSID)
+	if err != nil {
+		return nil, err
+	}
+
+	if res != nil {
+		add("talos-ca", CertificateComponentTalos, res.(*secrets.Root).OSSpec().CA)
+
+		for i, ca := range res.(*secrets.Root).OSSpec().AcceptedCAs {
+			add(fmt.Sprintf("talos-accepted-ca-%d", i), CertificateComponentTalos, ca)
+		}
+	}
+
+	res, err = get(secrets.APIType, secrets.APIID)
+	if err != nil {
+		return nil, err
+	}
+
+	if res != nil {
+		add("talos-api-server", CertificateComponentTalos, res.(*secrets.API).TypedSpec().Server)
+		add("talos-api-client", CertificateComponentTalos, res.(*secrets.API).TypedSpec().Client)
+	}
+
+	res, err = get(secrets.RootType, secrets.RootEtcdID)
+	if err != nil {
+		return nil, err
+	}
+
+	if res != nil {
+		add("etcd-ca", CertificateComponentEtcd, res.(*secrets.Root).EtcdSpec().EtcdCA)
+	}
+
+	res, err = get(secrets.EtcdType, secrets.EtcdID)
+	if err != nil {
+		return nil, err
+	}
+
+	if res != nil {
+		etcdCerts := res.(*secrets.Etcd).Certs()
+
+		add("etcd", CertificateComponentEtcd, etcdCerts.Etcd)
+		add("etcd-peer", CertificateComponentEtcd, etcdCerts.EtcdPeer)
+		add("etcd-admin", CertificateComponentEtcd, etcdCerts.EtcdAdmin)
+		add("etcd-kube-apiserver", CertificateComponentEtcd, etcdCerts.EtcdAPIServer)
+	}
+
+	res, err = get(secrets.RootType, secrets.RootKubernetesID)
+	if err != nil {
+		return nil, err
+	}
+
+	if res != nil {
+		add("kubernetes-ca", CertificateComponentKubernetes, res.(*secrets.Root).KubernetesSpec().CA)
+		add("kubernetes-aggregator-ca", CertificateComponentKubernetes, res.(*secrets.Root).KubernetesSpec().AggregatorCA)
+	}
+
+	res, err = get(secrets.KubernetesType, secrets.KubernetesID)
+	if err != nil {
+		return nil, err
+	}
+
+	if res != nil {
+		k8sCerts := res.(*secrets.Kubernetes).Certs()
+
+		add("kube-apiserver", CertificateComponentKubernetes, k8sCerts.APIServer)
+		add("kube-apiserver-kubelet-client", CertificateComponentKubernetes, k8sCerts.APIServerKubeletClient)
+		add("front-proxy-client", CertificateComponentKubernetes, k8sCerts.FrontProxy)
+
+		for _, kubeconfig := range []struct {
+			id         resource.ID
+			kubeconfig string
+		}{
+			{"kube-controller-manager", k8sCerts.ControllerManagerKubeconfig},
+			{"kube-scheduler", k8sCerts.SchedulerKubeconfig},
+			{"admin", k8sCerts.AdminKubeconfig},
+		} {
+			if err = addKubeconfig(kubeconfig.id, kubeconfig.kubeconfig); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return certs, nil
+}
+
+func parseCertificate(crt []byte) (*stdlibx509.Certificate, error) {
+	block, _ := pem.Decode(crt)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+
+	return stdlibx509.ParseCertificate(block.Bytes)
+}
@@ -50,6 +50,13 @@ func (ctrl *EtcdController) Inputs() []controller.Input {
 			ID:        pointer.ToString(time.StatusID),
 			Kind:      controller.InputWeak,
 		},
+		// renewal request isn't fetched, but it triggers certs regeneration
+		{
+			Namespace: secrets.NamespaceName,
+			Type:      secrets.CertificateRenewalType,
+			ID:        pointer.ToString(secrets.CertificateRenewalID),
+			Kind:      controller.InputWeak,
+		},
 	}
 }
 
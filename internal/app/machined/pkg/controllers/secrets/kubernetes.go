@@ -106,6 +106,13 @@ func (ctrl *KubernetesController) Run(ctx context.Context, r controller.Runtime,
 			ID:        pointer.ToString(timeresource.StatusID),
 			Kind:      controller.InputWeak,
 		},
+		// renewal request isn't fetched, but it triggers certs regeneration
+		{
+			Namespace: secrets.NamespaceName,
+			Type:      secrets.CertificateRenewalType,
+			ID:        pointer.ToString(secrets.CertificateRenewalID),
+			Kind:      controller.InputWeak,
+		},
 	}); err != nil {
 		return fmt.Errorf("error updating inputs: %w", err)
 	}
@@ -125,6 +125,7 @@ func (ctrl *RootController) Run(ctx context.Context, r controller.Runtime, logge
 
 func (ctrl *RootController) updateOSSecrets(cfgProvider talosconfig.Provider, osSecrets *secrets.RootOSSpec) error {
 	osSecrets.CA = cfgProvider.Machine().Security().CA()
+	osSecrets.AcceptedCAs = cfgProvider.Machine().Security().AcceptedCAs()
 
 	osSecrets.CertSANIPs = nil
 	osSecrets.CertSANDNSNames = nil
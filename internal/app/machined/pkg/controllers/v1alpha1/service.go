@@ -20,6 +20,9 @@ import (
 )
 
 // ServiceController manages v1alpha1.Service based on services subsystem state.
+//
+// Running services have the resource with the health status, failed services are kept as not running,
+// the resource is removed for any other service state.
 type ServiceController struct {
 	V1Alpha1Events runtime.Watcher
 }
@@ -86,6 +89,18 @@ func (ctrl *ServiceController) Run(ctx context.Context, r controller.Runtime, lo
 					}); err != nil {
 						logger.Info(fmt.Sprintf("failed creating service resource %s", service), zap.Error(err))
 					}
+				case machine.ServiceStateEvent_FAILED:
+					if err := r.Modify(ctx, service, func(r resource.Resource) error {
+						svc := r.(*v1alpha1.Service) //nolint:errcheck,forcetypeassert
+
+						svc.SetRunning(false)
+						svc.SetHealthy(false)
+						svc.SetUnknown(false)
+
+						return nil
+					}); err != nil {
+						logger.Info(fmt.Sprintf("failed updating service resource %s", service), zap.Error(err))
+					}
 				default:
 					if err := r.Destroy(ctx, service.Metadata()); err != nil && !state.IsNotFoundError(err) {
 						logger.Info(fmt.Sprintf("failed destroying service resource %s", service), zap.Error(err))
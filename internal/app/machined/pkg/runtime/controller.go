@@ -7,6 +7,7 @@ package runtime
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/cosi-project/runtime/pkg/controller"
 )
@@ -70,4 +71,15 @@ type Controller interface {
 type V1Alpha2Controller interface {
 	Run(context.Context) error
 	DependencyGraph() (*controller.DependencyGraph, error)
+	ReconcileHistory() map[string][]ControllerReconcileRun
+}
+
+// ControllerReconcileRun describes a single reconcile run of the controller.
+type ControllerReconcileRun struct {
+	Start    time.Time
+	Duration time.Duration
+	// Error is set if the controller failed during the run.
+	Error string
+	// TouchedResources is a list of resources created, updated or destroyed during the run.
+	TouchedResources []string
 }
@@ -96,6 +96,17 @@ type ResetOptions interface {
 	GetGraceful() bool
 	GetReboot() bool
 	GetSystemDiskTargets() []PartitionTarget
+	// GetWipeOptions returns nil unless the disks should be wiped.
+	GetWipeOptions() WipeOptions
+}
+
+// WipeOptions are parameters to wipe the disks in Reset sequence.
+type WipeOptions interface {
+	GetMode() machine.WipeRequest_Mode
+	// Progress is called periodically while the device is being wiped.
+	Progress(device string, done, total uint64)
+	// Report is called with the signed report once all the disks are wiped.
+	Report(*machine.WipeReport)
 }
 
 // PartitionTarget provides interface to the disk partition.
@@ -275,13 +275,21 @@ func (c *Controller) run(ctx context.Context, seq runtime.Sequence, phases []run
 	log.Printf("%s sequence: %d phase(s)", seq.String(), len(phases))
 
 	defer func() {
+		result := sequenceResultSuccess
+
 		if err != nil {
 			if !runtime.IsRebootError(err) {
 				log.Printf("%s sequence: failed", seq.String())
+
+				result = sequenceResultFailure
+			} else {
+				result = sequenceResultReboot
 			}
 		} else {
 			log.Printf("%s sequence: done: %s", seq.String(), time.Since(start))
 		}
+
+		sequenceDurationMetric.WithLabelValues(seq.String(), result).Observe(time.Since(start).Seconds())
 	}()
 
 	for number, phase = range phases {
@@ -304,6 +312,8 @@ func (c *Controller) run(ctx context.Context, seq runtime.Sequence, phases []run
 
 		log.Printf("phase %s (%s): done, %s", phase.Name, progress, time.Since(start))
 
+		phaseDurationMetric.WithLabelValues(seq.String(), phase.Name).Observe(time.Since(start).Seconds())
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
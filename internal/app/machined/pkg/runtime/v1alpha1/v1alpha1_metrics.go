@@ -0,0 +1,38 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// sequenceDurationBuckets cover sequences from a couple of seconds up to a slow install or upgrade.
+var sequenceDurationBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200}
+
+var (
+	sequenceDurationMetric = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "talos",
+		Subsystem: "sequence",
+		Name:      "duration_seconds",
+		Help:      "Duration of the machined sequences.",
+		Buckets:   sequenceDurationBuckets,
+	}, []string{"sequence", "result"})
+
+	phaseDurationMetric = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "talos",
+		Subsystem: "sequence",
+		Name:      "phase_duration_seconds",
+		Help:      "Duration of the successful machined sequence phases.",
+		Buckets:   sequenceDurationBuckets,
+	}, []string{"sequence", "phase"})
+)
+
+// Sequence results.
+const (
+	sequenceResultSuccess = "success"
+	sequenceResultFailure = "failure"
+	sequenceResultReboot  = "reboot"
+)
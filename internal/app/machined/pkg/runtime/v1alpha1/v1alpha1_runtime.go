@@ -103,6 +103,10 @@ func (r *Runtime) CanApplyImmediate(b []byte) error {
 	// * .machine.time
 	// * .machine.network
 	// * .machine.certCANs
+	// * .machine.ca
+	// * .machine.acceptedCAs
+	// * .machine.sysctls
+	// * .machine.kernel
 	newConfig.ClusterConfig = currentConfig.ClusterConfig
 	newConfig.ConfigDebug = currentConfig.ConfigDebug
 
@@ -110,6 +114,10 @@ func (r *Runtime) CanApplyImmediate(b []byte) error {
 		newConfig.MachineConfig.MachineTime = currentConfig.MachineConfig.MachineTime
 		newConfig.MachineConfig.MachineCertSANs = currentConfig.MachineConfig.MachineCertSANs
 		newConfig.MachineConfig.MachineNetwork = currentConfig.MachineConfig.MachineNetwork
+		newConfig.MachineConfig.MachineCA = currentConfig.MachineConfig.MachineCA
+		newConfig.MachineConfig.MachineAcceptedCAs = currentConfig.MachineConfig.MachineAcceptedCAs
+		newConfig.MachineConfig.MachineSysctls = currentConfig.MachineConfig.MachineSysctls
+		newConfig.MachineConfig.MachineKernel = currentConfig.MachineConfig.MachineKernel
 	}
 
 	if !reflect.DeepEqual(currentConfig, newConfig) {
@@ -108,6 +108,9 @@ func (*Sequencer) Initialize(r runtime.Runtime) []runtime.Phase {
 			MountCgroups,
 			MountPseudoFilesystems,
 			SetRLimit,
+		).Append(
+			"systemCgroups",
+			CreateSystemCgroups,
 		).Append(
 			"integrity",
 			WriteIMAPolicy,
@@ -218,6 +221,10 @@ func (*Sequencer) Boot(r runtime.Runtime) []runtime.Phase {
 	).Append(
 		"var",
 		SetupVarDirectory,
+	).AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer,
+		"pstore",
+		SaveKernelCrashRecords,
 	).AppendWhen(
 		r.State().Platform().Mode() != runtime.ModeContainer,
 		"overlay",
@@ -233,7 +240,13 @@ func (*Sequencer) Boot(r runtime.Runtime) []runtime.Phase {
 	).Append(
 		"userSetup",
 		WriteUserFiles,
-		WriteUserSysctls,
+	).AppendWhen(
+		r.State().Platform().Mode() != runtime.ModeContainer,
+		"kernelModules",
+		WaitForKernelModules,
+	).Append(
+		"sysctls",
+		WaitForUserSysctls,
 	).AppendWhen(
 		r.State().Platform().Mode() != runtime.ModeContainer,
 		"lvm",
@@ -315,17 +328,31 @@ func (*Sequencer) Reset(r runtime.Runtime, in runtime.ResetOptions) []runtime.Ph
 			in.GetGraceful() && (r.Config().Machine().Type() != machine.TypeWorker),
 			"leave",
 			LeaveEtcd,
-		).AppendList(
-			stopAllPhaselist(r),
-		).AppendWhen(
-			len(in.GetSystemDiskTargets()) == 0,
-			"reset",
-			ResetSystemDisk,
-		).AppendWhen(
-			len(in.GetSystemDiskTargets()) > 0,
-			"resetSpec",
-			ResetSystemDiskSpec,
-		).AppendWhen(
+		)
+
+		if in.GetWipeOptions() != nil {
+			// API services are kept running to stream the wipe progress back
+			phases = phases.AppendList(
+				stopPhaselist(r, StopServicesForWipe),
+			).Append(
+				"wipe",
+				WipeDisks,
+			)
+		} else {
+			phases = phases.AppendList(
+				stopAllPhaselist(r),
+			).AppendWhen(
+				len(in.GetSystemDiskTargets()) == 0,
+				"reset",
+				ResetSystemDisk,
+			).AppendWhen(
+				len(in.GetSystemDiskTargets()) > 0,
+				"resetSpec",
+				ResetSystemDiskSpec,
+			)
+		}
+
+		phases = phases.AppendWhen(
 			in.GetReboot(),
 			"reboot",
 			Reboot,
@@ -437,18 +464,22 @@ func (*Sequencer) Upgrade(r runtime.Runtime, in *machineapi.UpgradeRequest) []ru
 }
 
 func stopAllPhaselist(r runtime.Runtime) PhaseList {
+	return stopPhaselist(r, StopAllServices)
+}
+
+func stopPhaselist(r runtime.Runtime, stopServices runtime.TaskSetupFunc) PhaseList {
 	phases := PhaseList{}
 
 	switch r.State().Platform().Mode() { //nolint:exhaustive
 	case runtime.ModeContainer:
 		phases = phases.Append(
 			"stopEverything",
-			StopAllServices,
+			stopServices,
 		)
 	default:
 		phases = phases.Append(
 			"stopEverything",
-			StopAllServices,
+			stopServices,
 		).Append(
 			"unmountUser",
 			UnmountUserDisks,
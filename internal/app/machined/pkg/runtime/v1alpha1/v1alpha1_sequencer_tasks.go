@@ -25,16 +25,18 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/talos-systems/go-blockdevice/blockdevice"
 	"github.com/talos-systems/go-blockdevice/blockdevice/partition/gpt"
 	"github.com/talos-systems/go-blockdevice/blockdevice/util"
-	"github.com/talos-systems/go-cmd/pkg/cmd"
 	"github.com/talos-systems/go-kmsg"
 	"github.com/talos-systems/go-procfs/procfs"
 	"github.com/talos-systems/go-retry/retry"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
 
 	installer "github.com/talos-systems/talos/cmd/installer/pkg/install"
@@ -48,12 +50,18 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/services"
 	"github.com/talos-systems/talos/internal/app/maintenance"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/internal/pkg/containers/cri/containerd"
 	"github.com/talos-systems/talos/internal/pkg/cri"
 	"github.com/talos-systems/talos/internal/pkg/etcd"
+	"github.com/talos-systems/talos/internal/pkg/kernel/kmod"
 	"github.com/talos-systems/talos/internal/pkg/kernel/kspp"
+	"github.com/talos-systems/talos/internal/pkg/kernel/pstore"
+	"github.com/talos-systems/talos/internal/pkg/lvm"
 	"github.com/talos-systems/talos/internal/pkg/mount"
 	"github.com/talos-systems/talos/internal/pkg/partition"
+	"github.com/talos-systems/talos/internal/pkg/sysblock"
+	"github.com/talos-systems/talos/internal/pkg/wipe"
 	"github.com/talos-systems/talos/pkg/conditions"
 	"github.com/talos-systems/talos/pkg/images"
 	"github.com/talos-systems/talos/pkg/kubernetes"
@@ -63,9 +71,12 @@ import (
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
+	runtimeres "github.com/talos-systems/talos/pkg/resources/runtime"
+	"github.com/talos-systems/talos/pkg/resources/secrets"
 	resourcev1alpha1 "github.com/talos-systems/talos/pkg/resources/v1alpha1"
 	"github.com/talos-systems/talos/pkg/sysctl"
 	"github.com/talos-systems/talos/pkg/version"
+	wipereport "github.com/talos-systems/talos/pkg/wipe/report"
 )
 
 // SetupLogger represents the SetupLogger task.
@@ -175,6 +186,22 @@ func MountCgroups(seq runtime.Sequence, data interface{}) (runtime.TaskExecution
 	}, "mountCgroups"
 }
 
+// CreateSystemCgroups represents the CreateSystemCgroups task.
+//
+// Talos system services run in the /system cgroup, while pod runtime (CRI containerd and kubelet)
+// runs in the /podruntime cgroup, each service in a dedicated child cgroup.
+func CreateSystemCgroups(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		for _, path := range []string{constants.CgroupSystem, constants.CgroupPodRuntime} {
+			if _, err = cgroup.Create(path, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, "createSystemCgroups"
+}
+
 // MountPseudoFilesystems represents the MountPseudoFilesystems task.
 func MountPseudoFilesystems(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
@@ -629,6 +656,15 @@ func StopServicesForUpgrade(seq runtime.Sequence, data interface{}) (runtime.Tas
 	}, "stopServicesForUpgrade"
 }
 
+// StopServicesForWipe represents the StopServicesForWipe task.
+//
+// API services are kept running, as they don't use the disks.
+func StopServicesForWipe(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		return system.Services(nil).StopWithRevDepenencies(ctx, "cri", "etcd", "kubelet", "trustd", "udevd")
+	}, "stopServicesForWipe"
+}
+
 // StopAllServices represents the StopAllServices task.
 func StopAllServices(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
@@ -710,6 +746,27 @@ func SetupVarDirectory(seq runtime.Sequence, data interface{}) (runtime.TaskExec
 	}, "setupVarDirectory"
 }
 
+// SaveKernelCrashRecords represents the SaveKernelCrashRecords task.
+//
+// Kernel crash records (oops, panic) captured by pstore before the reboot are moved to the
+// EPHEMERAL partition, so that they are available via `talosctl dmesg --previous`.
+func SaveKernelCrashRecords(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		archived, err := pstore.Archive(constants.PstoreMountPoint, constants.PstoreArchivePath)
+
+		if archived > 0 {
+			logger.Printf("saved %d kernel crash record(s) from the previous boot to %q", archived, constants.PstoreArchivePath)
+		}
+
+		if err != nil {
+			// failing to save crash records shouldn't prevent the node from booting
+			logger.Printf("failed saving kernel crash records: %s", err)
+		}
+
+		return nil
+	}, "saveKernelCrashRecords"
+}
+
 // MountUserDisks represents the MountUserDisks task.
 func MountUserDisks(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
@@ -717,10 +774,38 @@ func MountUserDisks(seq runtime.Sequence, data interface{}) (runtime.TaskExecuti
 			return err
 		}
 
+		if err = setupVolumeGroups(logger, r); err != nil {
+			return err
+		}
+
 		return mountDisks(r)
 	}, "mountUserDisks"
 }
 
+func setupVolumeGroups(logger *log.Logger, r runtime.Runtime) error {
+	volumeGroups := r.Config().Machine().VolumeGroups()
+	if len(volumeGroups) == 0 {
+		return nil
+	}
+
+	l := lvm.New()
+
+	// activate existing volumes first, so that they are not recreated
+	if err := l.Activate(); err != nil {
+		return fmt.Errorf("failed to activate logical volumes: %w", err)
+	}
+
+	for _, vg := range volumeGroups {
+		logger.Printf("setting up volume group %q", vg.Name())
+
+		if err := l.Ensure(vg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // TODO(andrewrynhard): We shouldn't pull in the installer command package
 // here.
 func partitionAndFormatDisks(logger *log.Logger, r runtime.Runtime) error {
@@ -776,10 +861,16 @@ func partitionAndFormatDisks(logger *log.Logger, r runtime.Runtime) error {
 						Size:           part.Size(),
 						Force:          true,
 						PartitionType:  partition.LinuxFilesystemData,
-						FileSystemType: partition.FilesystemTypeXFS,
+						FileSystemType: part.Filesystem(),
 					},
 				}
 
+				// encrypted partitions are formatted on mount, after the encryption is set up
+				if part.Encryption() != nil {
+					extraTarget.FileSystemType = partition.FilesystemTypeNone
+					extraTarget.FastWipe = true
+				}
+
 				m.Targets[disk.Device()] = append(m.Targets[disk.Device()], extraTarget)
 			}
 
@@ -793,47 +884,93 @@ func partitionAndFormatDisks(logger *log.Logger, r runtime.Runtime) error {
 }
 
 func mountDisks(r runtime.Runtime) (err error) {
-	mountpoints := mount.NewMountPoints()
+	if err = mount.UserDisksMount(r.Config().Machine().Disks(), mount.WithRuntimeRepair(r)); err != nil {
+		return err
+	}
 
-	for _, disk := range r.Config().Machine().Disks() {
-		for i, part := range disk.Partitions() {
-			var partname string
+	return mount.UserVolumesMount(r.Config().Machine().VolumeGroups(), mount.WithRuntimeRepair(r))
+}
 
-			partname, err = util.PartPath(disk.Device(), i+1)
-			if err != nil {
-				return err
-			}
+func unmountDisks(r runtime.Runtime) (err error) {
+	return mount.UserDisksUnmount()
+}
 
-			if _, err = os.Stat(part.MountPoint()); errors.Is(err, os.ErrNotExist) {
-				if err = os.MkdirAll(part.MountPoint(), 0o700); err != nil {
-					return err
-				}
-			}
+// WaitForKernelModules represents the WaitForKernelModules task.
+//
+// Kernel modules are loaded by the controller, the task waits for them to be loaded before the services are started.
+func WaitForKernelModules(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		// missing module (e.g. after an upgrade) should not prevent the node from booting
+		for _, module := range r.Config().Machine().Kernel().Modules() {
+			if err = waitForStatus(ctx, r, runtimeres.NewKernelModuleStatus(runtimeres.NamespaceName, kmod.ModuleName(module.Name())).Metadata(),
+				func(r resource.Resource) (bool, string) {
+					status := r.(*runtimeres.KernelModuleStatus).TypedSpec()
 
-			mountpoints.Set(partname, mount.NewMountPoint(partname, part.MountPoint(), "xfs", unix.MS_NOATIME, ""))
+					return status.Loaded, status.Error
+				},
+			); err != nil {
+				logger.Printf("WARNING: error loading kernel module %q: %s", module.Name(), err)
+			}
 		}
-	}
 
-	return mount.Mount(mountpoints)
+		return nil
+	}, "waitForKernelModules"
 }
 
-func unmountDisks(r runtime.Runtime) (err error) {
-	mountpoints := mount.NewMountPoints()
+// WaitForUserSysctls represents the WaitForUserSysctls task.
+//
+// Kernel parameters are applied by the controller, the task waits for them to be applied before the services are started.
+func WaitForUserSysctls(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		var result *multierror.Error
 
-	for _, disk := range r.Config().Machine().Disks() {
-		for i, part := range disk.Partitions() {
-			var partname string
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
 
-			partname, err = util.PartPath(disk.Device(), i+1)
-			if err != nil {
-				return err
-			}
+		for key, value := range r.Config().Machine().Sysctls() {
+			value := value
+
+			if err = waitForStatus(ctx, r, runtimeres.NewSysctlStatus(runtimeres.NamespaceName, key).Metadata(),
+				func(r resource.Resource) (bool, string) {
+					status := r.(*runtimeres.SysctlStatus).TypedSpec()
 
-			mountpoints.Set(partname, mount.NewMountPoint(partname, part.MountPoint(), "xfs", unix.MS_NOATIME, ""))
+					return status.Value == value, status.Error
+				},
+			); err != nil {
+				result = multierror.Append(result, fmt.Errorf("error applying kernel parameter %q: %w", key, err))
+			}
 		}
+
+		return result.ErrorOrNil()
+	}, "waitForUserSysctls"
+}
+
+// waitForStatus waits for the status resource to report success.
+//
+// Errors reported in the status might be transient (e.g. a kernel parameter which requires a kernel module), so the
+// last reported error is returned only if the status doesn't report success before the context is canceled.
+func waitForStatus(ctx context.Context, r runtime.Runtime, md *resource.Metadata, check func(resource.Resource) (bool, string)) error {
+	var lastError string
+
+	_, err := r.State().V1Alpha2().Resources().WatchFor(ctx, md,
+		state.WithEventTypes(state.Created, state.Updated),
+		state.WithCondition(func(r resource.Resource) (bool, error) {
+			var ok bool
+
+			ok, lastError = check(r)
+
+			return ok, nil
+		}),
+	)
+
+	if err != nil && lastError != "" {
+		return errors.New(lastError)
 	}
 
-	return mount.Unmount(mountpoints)
+	return err
 }
 
 // WriteUserFiles represents the WriteUserFiles task.
@@ -988,21 +1125,6 @@ func existsAndIsFile(p string) (err error) {
 	return nil
 }
 
-// WriteUserSysctls represents the WriteUserSysctls task.
-func WriteUserSysctls(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
-	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
-		var result *multierror.Error
-
-		for k, v := range r.Config().Machine().Sysctls() {
-			if err = sysctl.WriteSystemProperty(&sysctl.SystemProperty{Key: k, Value: v}); err != nil {
-				return err
-			}
-		}
-
-		return result.ErrorOrNil()
-	}, "writeUserSysctls"
-}
-
 // UnmountOverlayFilesystems represents the UnmountOverlayFilesystems task.
 func UnmountOverlayFilesystems(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
@@ -1242,6 +1364,113 @@ func ResetSystemDiskSpec(seq runtime.Sequence, data interface{}) (runtime.TaskEx
 	}, "resetSystemDiskSpec"
 }
 
+// WipeDisks represents the task to wipe the system and user disks.
+//
+// Failure to wipe a disk doesn't fail the task, it is recorded in the report.
+//
+//nolint:gocyclo
+func WipeDisks(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
+	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
+		in, ok := data.(runtime.ResetOptions)
+		if !ok || in.GetWipeOptions() == nil {
+			return fmt.Errorf("unexpected runtime data")
+		}
+
+		opts := in.GetWipeOptions()
+
+		devices := []string{r.State().Machine().Disk().BlockDevice.Device().Name()}
+
+		for _, disk := range r.Config().Machine().Disks() {
+			devices = append(devices, disk.Device())
+		}
+
+		if volumeGroups := r.Config().Machine().VolumeGroups(); len(volumeGroups) > 0 {
+			// logical volumes keep the physical volumes busy
+			if err = lvm.New().Deactivate(); err != nil {
+				return fmt.Errorf("error deactivating volume groups: %w", err)
+			}
+
+			for _, vg := range volumeGroups {
+				devices = append(devices, vg.Devices()...)
+			}
+		}
+
+		blockDevices, err := sysblock.List(sysblock.DefaultPaths())
+		if err != nil {
+			return err
+		}
+
+		hostname, err := os.Hostname()
+		if err != nil {
+			return err
+		}
+
+		report := &machineapi.WipeReportData{
+			Hostname: hostname,
+			Mode:     opts.GetMode(),
+			Started:  timestamppb.Now(),
+		}
+
+		seen := map[string]struct{}{}
+
+		for _, device := range devices {
+			if _, ok = seen[device]; ok {
+				continue
+			}
+
+			seen[device] = struct{}{}
+
+			diskReport := &machineapi.WipeDiskReport{
+				Device: device,
+			}
+
+			var transport string
+
+			for _, dev := range blockDevices {
+				if dev.Path() == device {
+					diskReport.Model = dev.Model
+					diskReport.Serial = dev.Serial
+					diskReport.Size = dev.Size
+					transport = dev.Transport
+
+					break
+				}
+			}
+
+			logger.Printf("wiping %q", device)
+
+			diskReport.Method, err = wipe.Disk(ctx, device, transport, wipe.Mode(opts.GetMode()), func(done, total uint64) {
+				opts.Progress(device, done, total)
+			})
+			if err != nil {
+				logger.Printf("error wiping %q: %s", device, err)
+
+				diskReport.Error = err.Error()
+			} else {
+				logger.Printf("wiped %q using %s", device, diskReport.Method)
+			}
+
+			report.Disks = append(report.Disks, diskReport)
+		}
+
+		report.Completed = timestamppb.Now()
+
+		res, err := r.State().V1Alpha2().Resources().Get(ctx, secrets.NewAPI().Metadata())
+		if err != nil {
+			return fmt.Errorf("error getting API certificates: %w", err)
+		}
+
+		signed, err := wipereport.Sign(report, res.(*secrets.API).TypedSpec().Server)
+		if err != nil {
+			return err
+		}
+
+		opts.Report(signed)
+
+		return nil
+	}, "wipeDisks"
+}
+
 // VerifyDiskAvailability represents the task for verifying that the system
 // disk is not in use.
 func VerifyDiskAvailability(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
@@ -1721,7 +1950,7 @@ func BootstrapEtcd(seq runtime.Sequence, data interface{}) (runtime.TaskExecutio
 // ActivateLogicalVolumes represents the task for activating logical volumes.
 func ActivateLogicalVolumes(seq runtime.Sequence, data interface{}) (runtime.TaskExecutionFunc, string) {
 	return func(ctx context.Context, logger *log.Logger, r runtime.Runtime) (err error) {
-		if _, err = cmd.Run("/sbin/lvm", "vgchange", "-ay"); err != nil {
+		if err = lvm.New().Activate(); err != nil {
 			return fmt.Errorf("failed to activate logical volumes: %w", err)
 		}
 
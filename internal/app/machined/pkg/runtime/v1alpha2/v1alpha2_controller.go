@@ -15,11 +15,13 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/block"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/config"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/files"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/k8s"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/network"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/perf"
+	runtimecontrollers "github.com/talos-systems/talos/internal/app/machined/pkg/controllers/runtime"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/secrets"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/time"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/controllers/v1alpha1"
@@ -36,6 +38,9 @@ type Controller struct {
 	logger            *zap.Logger
 
 	v1alpha1Runtime runtime.Runtime
+
+	history reconcileHistory
+	tracer  *spanExporter
 }
 
 // NewController creates Controller.
@@ -43,6 +48,7 @@ func NewController(v1alpha1Runtime runtime.Runtime, loggingManager runtime.Loggi
 	ctrl := &Controller{
 		v1alpha1Runtime: v1alpha1Runtime,
 		consoleLogLevel: zap.NewAtomicLevel(),
+		tracer:          newSpanExporter(),
 	}
 
 	logWriter, err := loggingManager.ServiceLog("controller-runtime").Writer()
@@ -65,6 +71,9 @@ func (ctrl *Controller) Run(ctx context.Context) error {
 	// adjust the log level based on machine configuration
 	go ctrl.watchMachineConfig(ctx, ctrl.logger)
 
+	// export reconcile runs as trace spans if enabled in the machine configuration
+	go ctrl.tracer.run(ctx, ctrl.logger)
+
 	for _, c := range []controller.Controller{
 		&v1alpha1.BootstrapStatusController{},
 		&v1alpha1.ServiceController{
@@ -74,6 +83,19 @@ func (ctrl *Controller) Run(ctx context.Context) error {
 		&time.SyncController{
 			V1Alpha1Mode: ctrl.v1alpha1Runtime.State().Platform().Mode(),
 		},
+		&block.DevicesController{
+			V1Alpha1Mode: ctrl.v1alpha1Runtime.State().Platform().Mode(),
+		},
+		&block.DiskHealthController{
+			V1Alpha1Mode:   ctrl.v1alpha1Runtime.State().Platform().Mode(),
+			V1Alpha1Events: ctrl.v1alpha1Runtime.Events(),
+		},
+		&block.LogicalVolumeStatusController{
+			V1Alpha1Mode: ctrl.v1alpha1Runtime.State().Platform().Mode(),
+		},
+		&block.UserDiskGrowController{
+			V1Alpha1Mode: ctrl.v1alpha1Runtime.State().Platform().Mode(),
+		},
 		&config.MachineTypeController{},
 		&config.K8sControlPlaneController{},
 		&files.EtcFileController{
@@ -83,9 +105,13 @@ func (ctrl *Controller) Run(ctx context.Context) error {
 		&k8s.ControlPlaneStaticPodController{},
 		&k8s.EndpointController{},
 		&k8s.ExtraManifestController{},
+		&k8s.KubeletServingCertApprovalController{},
 		&k8s.KubeletStaticPodController{},
 		&k8s.ManifestController{},
 		&k8s.ManifestApplyController{},
+		&k8s.NodeAddressAnnotationController{},
+		&k8s.NodeConditionController{},
+		&k8s.NodeConditionApplyController{},
 		&k8s.NodenameController{},
 		&k8s.RenderSecretsStaticPodController{},
 		&network.AddressConfigController{
@@ -137,13 +163,26 @@ func (ctrl *Controller) Run(ctx context.Context) error {
 		},
 		&network.TimeServerMergeController{},
 		&perf.StatsController{},
+		&runtimecontrollers.ExtensionStatusController{
+			StatusPath: constants.ExtensionsRuntimeConfigFile,
+		},
+		&runtimecontrollers.KernelModuleConfigController{},
+		&runtimecontrollers.KernelModuleSpecController{
+			V1Alpha1Mode: ctrl.v1alpha1Runtime.State().Platform().Mode(),
+		},
+		&runtimecontrollers.SysctlConfigController{},
+		&runtimecontrollers.SysctlSpecController{},
+		&runtimecontrollers.WatchdogTimerController{
+			V1Alpha1Mode: ctrl.v1alpha1Runtime.State().Platform().Mode(),
+		},
 		&network.TimeServerSpecController{},
 		&secrets.APIController{},
+		&secrets.CertificateStatusController{},
 		&secrets.EtcdController{},
 		&secrets.KubernetesController{},
 		&secrets.RootController{},
 	} {
-		if err := ctrl.controllerRuntime.RegisterController(c); err != nil {
+		if err := ctrl.controllerRuntime.RegisterController(&instrumentedController{Controller: c, history: &ctrl.history, tracer: ctrl.tracer}); err != nil {
 			return err
 		}
 	}
@@ -156,6 +195,11 @@ func (ctrl *Controller) DependencyGraph() (*controller.DependencyGraph, error) {
 	return ctrl.controllerRuntime.GetDependencyGraph()
 }
 
+// ReconcileHistory returns last reconcile runs of each controller.
+func (ctrl *Controller) ReconcileHistory() map[string][]runtime.ControllerReconcileRun {
+	return ctrl.history.snapshot()
+}
+
 func (ctrl *Controller) watchMachineConfig(ctx context.Context, logger *zap.Logger) {
 	watchCh := make(chan state.Event)
 
@@ -171,18 +215,31 @@ func (ctrl *Controller) watchMachineConfig(ctx context.Context, logger *zap.Logg
 
 	for {
 		logLevel := zapcore.InfoLevel
+		tracingEndpoint := ""
 
 		select {
 		case event := <-watchCh:
 			if event.Type != state.Destroyed {
-				if event.Resource.(*configresource.MachineConfig).Config().Debug() {
+				cfg := event.Resource.(*configresource.MachineConfig).Config()
+
+				if cfg.Debug() {
 					logLevel = zapcore.DebugLevel
 				}
+
+				if cfg.Machine() != nil {
+					tracingEndpoint = cfg.Machine().Features().TracingEndpoint()
+				}
 			}
 		case <-ctx.Done():
 			return
 		}
 
+		if ctrl.tracer.getEndpoint() != tracingEndpoint {
+			ctrl.tracer.setEndpoint(tracingEndpoint)
+
+			ctrl.logger.Info("setting controller tracing endpoint", zap.String("endpoint", tracingEndpoint))
+		}
+
 		if ctrl.consoleLogLevel.Level() != logLevel {
 			ctrl.consoleLogLevel.SetLevel(logLevel)
 
@@ -0,0 +1,124 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+)
+
+// ReconcileHistorySize is the number of the last reconcile runs kept for each controller.
+const ReconcileHistorySize = 16
+
+// reconcileHistory keeps last reconcile runs for each controller.
+type reconcileHistory struct {
+	mu   sync.Mutex
+	runs map[string][]runtime.ControllerReconcileRun
+}
+
+func (h *reconcileHistory) record(name string, run runtime.ControllerReconcileRun) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.runs == nil {
+		h.runs = map[string][]runtime.ControllerReconcileRun{}
+	}
+
+	runs := append(h.runs[name], run)
+
+	if len(runs) > ReconcileHistorySize {
+		runs = append([]runtime.ControllerReconcileRun(nil), runs[len(runs)-ReconcileHistorySize:]...)
+	}
+
+	h.runs[name] = runs
+}
+
+func (h *reconcileHistory) snapshot() map[string][]runtime.ControllerReconcileRun {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	result := make(map[string][]runtime.ControllerReconcileRun, len(h.runs))
+
+	for name, runs := range h.runs {
+		result[name] = append([]runtime.ControllerReconcileRun(nil), runs...)
+	}
+
+	return result
+}
+
+// reconcileRun tracks the reconcile run in progress.
+//
+// Reconcile run starts when the controller receives an event, and it ends when the controller
+// goes back to waiting for the next event (or when the controller returns).
+type reconcileRun struct {
+	mu sync.Mutex
+
+	active  bool
+	start   time.Time
+	touched map[string]struct{}
+}
+
+func (run *reconcileRun) begin() {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	run.active = true
+	run.start = time.Now()
+	run.touched = map[string]struct{}{}
+}
+
+func (run *reconcileRun) isActive() bool {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	return run.active
+}
+
+func (run *reconcileRun) touch(md resource.Pointer) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	if !run.active {
+		return
+	}
+
+	run.touched[fmt.Sprintf("%s/%s/%s", md.Namespace(), md.Type(), md.ID())] = struct{}{}
+}
+
+// end finishes the run in progress, returns false if there's no active run.
+func (run *reconcileRun) end(err error) (runtime.ControllerReconcileRun, bool) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	if !run.active {
+		return runtime.ControllerReconcileRun{}, false
+	}
+
+	run.active = false
+
+	result := runtime.ControllerReconcileRun{
+		Start:            run.start,
+		Duration:         time.Since(run.start),
+		TouchedResources: make([]string, 0, len(run.touched)),
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	for id := range run.touched {
+		result.TouchedResources = append(result.TouchedResources, id)
+	}
+
+	sort.Strings(result.TouchedResources)
+
+	return result, true
+}
@@ -0,0 +1,110 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+)
+
+func TestReconcileHistoryEviction(t *testing.T) {
+	var history reconcileHistory
+
+	start := time.Now()
+
+	for i := 0; i < ReconcileHistorySize+5; i++ {
+		history.record("network.LinkSpecController", runtime.ControllerReconcileRun{
+			Start: start.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	history.record("time.SyncController", runtime.ControllerReconcileRun{
+		Start: start,
+		Error: "failed",
+	})
+
+	snapshot := history.snapshot()
+	require.Len(t, snapshot, 2)
+
+	runs := snapshot["network.LinkSpecController"]
+	require.Len(t, runs, ReconcileHistorySize)
+
+	// the oldest runs are evicted, the order is kept
+	for i, run := range runs {
+		assert.Equal(t, start.Add(time.Duration(i+5)*time.Second), run.Start)
+	}
+
+	assert.Equal(t, []runtime.ControllerReconcileRun{{Start: start, Error: "failed"}}, snapshot["time.SyncController"])
+}
+
+func TestReconcileHistorySnapshot(t *testing.T) {
+	var history reconcileHistory
+
+	assert.Empty(t, history.snapshot())
+
+	history.record("k8s.NodenameController", runtime.ControllerReconcileRun{Error: "first"})
+
+	snapshot := history.snapshot()
+
+	// snapshot is not affected by the new runs and changes to the snapshot don't affect the history
+	history.record("k8s.NodenameController", runtime.ControllerReconcileRun{Error: "second"})
+	snapshot["k8s.NodenameController"][0].Error = "changed"
+
+	assert.Len(t, snapshot["k8s.NodenameController"], 1)
+
+	runs := history.snapshot()["k8s.NodenameController"]
+	require.Len(t, runs, 2)
+	assert.Equal(t, "first", runs[0].Error)
+	assert.Equal(t, "second", runs[1].Error)
+}
+
+func TestReconcileRun(t *testing.T) {
+	var run reconcileRun
+
+	// touching resources outside of the run is ignored
+	run.touch(resource.NewMetadata("network", "LinkStatuses.net.talos.dev", "eth0", resource.VersionUndefined))
+
+	_, ok := run.end(nil)
+	assert.False(t, ok)
+	assert.False(t, run.isActive())
+
+	run.begin()
+	assert.True(t, run.isActive())
+
+	run.touch(resource.NewMetadata("network", "LinkStatuses.net.talos.dev", "eth1", resource.VersionUndefined))
+	run.touch(resource.NewMetadata("network", "LinkStatuses.net.talos.dev", "eth0", resource.VersionUndefined))
+	run.touch(resource.NewMetadata("network", "LinkStatuses.net.talos.dev", "eth1", resource.VersionUndefined))
+
+	result, ok := run.end(errors.New("link not found"))
+	require.True(t, ok)
+	assert.False(t, run.isActive())
+
+	assert.Equal(t, "link not found", result.Error)
+	assert.Equal(t, []string{
+		"network/LinkStatuses.net.talos.dev/eth0",
+		"network/LinkStatuses.net.talos.dev/eth1",
+	}, result.TouchedResources)
+	assert.False(t, result.Start.IsZero())
+	assert.GreaterOrEqual(t, int64(result.Duration), int64(0))
+
+	// the next run starts from scratch
+	run.begin()
+
+	result, ok = run.end(nil)
+	require.True(t, ok)
+
+	assert.Empty(t, result.Error)
+	assert.Empty(t, result.TouchedResources)
+
+	_, ok = run.end(nil)
+	assert.False(t, ok)
+}
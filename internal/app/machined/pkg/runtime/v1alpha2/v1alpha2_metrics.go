@@ -0,0 +1,180 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	controllerReconcilesMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "talos",
+		Subsystem: "controller",
+		Name:      "reconciles_total",
+		Help:      "Number of reconcile events processed by the controller.",
+	}, []string{"controller"})
+
+	controllerErrorsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "talos",
+		Subsystem: "controller",
+		Name:      "errors_total",
+		Help:      "Number of times the controller failed and had to be restarted.",
+	}, []string{"controller"})
+)
+
+// instrumentedController wraps the controller to collect reconcile metrics and history.
+type instrumentedController struct {
+	controller.Controller
+
+	history *reconcileHistory
+	tracer  *spanExporter
+}
+
+// Run implements controller.Controller interface.
+func (c *instrumentedController) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	// the controller is restarted on failure, so make sure event proxy goroutine is stopped
+	// with each run, otherwise it might steal events from the next run
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	runtime := &instrumentedRuntime{
+		Runtime: r,
+		ctx:     ctx,
+		name:    c.Name(),
+		history: c.history,
+		tracer:  c.tracer,
+	}
+
+	err := c.Controller.Run(ctx, runtime, logger)
+
+	if err != nil && !errors.Is(err, context.Canceled) {
+		controllerErrorsMetric.WithLabelValues(c.Name()).Inc()
+
+		runtime.failRun(err)
+	} else {
+		runtime.endRun(nil)
+	}
+
+	return err
+}
+
+// instrumentedRuntime counts reconcile events delivered to the controller and tracks reconcile runs.
+type instrumentedRuntime struct {
+	controller.Runtime
+
+	ctx     context.Context
+	name    string
+	history *reconcileHistory
+	tracer  *spanExporter
+	run     reconcileRun
+
+	eventChOnce sync.Once
+	eventCh     chan controller.ReconcileEvent
+}
+
+// EventCh implements controller.Runtime interface.
+func (r *instrumentedRuntime) EventCh() <-chan controller.ReconcileEvent {
+	// controller is going to wait for the next event, so the reconcile run is over
+	r.endRun(nil)
+
+	r.eventChOnce.Do(func() {
+		r.eventCh = make(chan controller.ReconcileEvent)
+
+		go r.proxyEvents(r.Runtime.EventCh())
+	})
+
+	return r.eventCh
+}
+
+func (r *instrumentedRuntime) proxyEvents(in <-chan controller.ReconcileEvent) {
+	for {
+		var event controller.ReconcileEvent
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case event = <-in:
+		}
+
+		// start the run before delivering the event, as the controller might start
+		// touching resources as soon as it receives the event
+		r.run.begin()
+
+		controllerReconcilesMetric.WithLabelValues(r.name).Inc()
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case r.eventCh <- event:
+		}
+	}
+}
+
+func (r *instrumentedRuntime) endRun(err error) {
+	run, ok := r.run.end(err)
+	if !ok {
+		return
+	}
+
+	if r.history != nil {
+		r.history.record(r.name, run)
+	}
+
+	if r.tracer != nil {
+		r.tracer.export(r.name, run)
+	}
+}
+
+// failRun records the controller failure, even if it happened outside of the reconcile run.
+func (r *instrumentedRuntime) failRun(err error) {
+	if !r.run.isActive() {
+		r.run.begin()
+	}
+
+	r.endRun(err)
+}
+
+// Create implements controller.Runtime interface.
+func (r *instrumentedRuntime) Create(ctx context.Context, res resource.Resource) error {
+	r.run.touch(res.Metadata())
+
+	return r.Runtime.Create(ctx, res)
+}
+
+// Update implements controller.Runtime interface.
+func (r *instrumentedRuntime) Update(ctx context.Context, currentVersion resource.Version, res resource.Resource) error {
+	r.run.touch(res.Metadata())
+
+	return r.Runtime.Update(ctx, currentVersion, res)
+}
+
+// Modify implements controller.Runtime interface.
+func (r *instrumentedRuntime) Modify(ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error) error {
+	r.run.touch(emptyResource.Metadata())
+
+	return r.Runtime.Modify(ctx, emptyResource, updateFunc)
+}
+
+// Teardown implements controller.Runtime interface.
+func (r *instrumentedRuntime) Teardown(ctx context.Context, resourcePointer resource.Pointer) (bool, error) {
+	r.run.touch(resourcePointer)
+
+	return r.Runtime.Teardown(ctx, resourcePointer)
+}
+
+// Destroy implements controller.Runtime interface.
+func (r *instrumentedRuntime) Destroy(ctx context.Context, resourcePointer resource.Pointer) error {
+	r.run.touch(resourcePointer)
+
+	return r.Runtime.Destroy(ctx, resourcePointer)
+}
@@ -14,11 +14,13 @@ import (
 	"github.com/cosi-project/runtime/pkg/state/registry"
 
 	talosconfig "github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/resources/block"
 	"github.com/talos-systems/talos/pkg/resources/config"
 	"github.com/talos-systems/talos/pkg/resources/files"
 	"github.com/talos-systems/talos/pkg/resources/k8s"
 	"github.com/talos-systems/talos/pkg/resources/network"
 	"github.com/talos-systems/talos/pkg/resources/perf"
+	talosruntime "github.com/talos-systems/talos/pkg/resources/runtime"
 	"github.com/talos-systems/talos/pkg/resources/secrets"
 	"github.com/talos-systems/talos/pkg/resources/time"
 	"github.com/talos-systems/talos/pkg/resources/v1alpha1"
@@ -57,12 +59,14 @@ func NewState() (*State, error) {
 	}{
 		{v1alpha1.NamespaceName, "Talos v1alpha1 subsystems glue resources."},
 		{config.NamespaceName, "Talos node configuration."},
+		{block.NamespaceName, "Block device resources."},
 		{files.NamespaceName, "Files and file-like resources."},
 		{k8s.ControlPlaneNamespaceName, "Kubernetes control plane resources."},
 		{network.NamespaceName, "Networking resources."},
 		{network.ConfigNamespaceName, "Networking configuration resources."},
 		{secrets.NamespaceName, "Resources with secret material."},
 		{perf.NamespaceName, "Stats resources."},
+		{talosruntime.NamespaceName, "Talos runtime resources."},
 	} {
 		if err := s.namespaceRegistry.Register(ctx, ns.name, ns.description); err != nil {
 			return nil, err
@@ -73,6 +77,10 @@ func NewState() (*State, error) {
 	for _, r := range []resource.Resource{
 		&v1alpha1.BootstrapStatus{},
 		&v1alpha1.Service{},
+		&block.BlockDevice{},
+		&block.DiskHealth{},
+		&block.LogicalVolumeStatus{},
+		&block.Partition{},
 		&config.MachineConfig{},
 		&config.MachineType{},
 		&config.K8sControlPlane{},
@@ -81,6 +89,7 @@ func NewState() (*State, error) {
 		&k8s.Endpoint{},
 		&k8s.Manifest{},
 		&k8s.ManifestStatus{},
+		&k8s.NodeCondition{},
 		&k8s.Nodename{},
 		&k8s.StaticPod{},
 		&k8s.StaticPodStatus{},
@@ -103,7 +112,14 @@ func NewState() (*State, error) {
 		&network.TimeServerSpec{},
 		&perf.CPU{},
 		&perf.Memory{},
+		&talosruntime.ExtensionStatus{},
+		&talosruntime.KernelModuleSpec{},
+		&talosruntime.KernelModuleStatus{},
+		&talosruntime.SysctlSpec{},
+		&talosruntime.SysctlStatus{},
 		&secrets.API{},
+		&secrets.CertificateRenewal{},
+		&secrets.CertificateStatus{},
 		&secrets.Etcd{},
 		&secrets.Kubernetes{},
 		&secrets.Root{},
@@ -0,0 +1,288 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+)
+
+const (
+	// spanBatchSize is the maximum number of spans sent in a single export request.
+	spanBatchSize = 64
+	// spanQueueSize is the maximum number of spans waiting for the export, newer spans are dropped when the queue is full.
+	spanQueueSize = 1024
+	// spanExportInterval is the interval between the exports.
+	spanExportInterval = 5 * time.Second
+	// spanExportTimeout is the timeout of a single export request.
+	spanExportTimeout = 10 * time.Second
+)
+
+// OTLP span status codes.
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+// OTLP span kind internal.
+const otlpSpanKindInternal = 1
+
+// spanExporter exports reconcile runs as OpenTelemetry spans with OTLP over HTTP (JSON encoding).
+//
+// Each reconcile run is exported as a separate trace with a single span named after the controller.
+type spanExporter struct {
+	mu       sync.Mutex
+	endpoint string
+
+	queue chan controllerSpan
+	// client is replaced in the tests.
+	client *http.Client
+}
+
+type controllerSpan struct {
+	name string
+	run  runtime.ControllerReconcileRun
+}
+
+func newSpanExporter() *spanExporter {
+	return &spanExporter{
+		queue:  make(chan controllerSpan, spanQueueSize),
+		client: &http.Client{Timeout: spanExportTimeout},
+	}
+}
+
+// setEndpoint sets the OTLP HTTP endpoint, e.g. `http://collector:4318`, empty endpoint disables the export.
+func (e *spanExporter) setEndpoint(endpoint string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.endpoint = strings.TrimRight(endpoint, "/")
+}
+
+func (e *spanExporter) getEndpoint() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.endpoint
+}
+
+// export queues the reconcile run for the export, it never blocks.
+func (e *spanExporter) export(name string, run runtime.ControllerReconcileRun) {
+	if e.getEndpoint() == "" {
+		return
+	}
+
+	select {
+	case e.queue <- controllerSpan{name: name, run: run}:
+	default:
+	}
+}
+
+// run sends the queued spans to the endpoint until the context is canceled.
+func (e *spanExporter) run(ctx context.Context, logger *zap.Logger) {
+	ticker := time.NewTicker(spanExportInterval)
+	defer ticker.Stop()
+
+	var batch []controllerSpan
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case span := <-e.queue:
+			batch = append(batch, span)
+
+			if len(batch) < spanBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		// spans are dropped on failure, as they are also kept in the reconcile history
+		if err := e.send(ctx, batch); err != nil {
+			logger.Warn("error exporting controller spans", zap.Int("spans", len(batch)), zap.Error(err))
+		}
+
+		batch = nil
+	}
+}
+
+func (e *spanExporter) send(ctx context.Context, batch []controllerSpan) error {
+	endpoint := e.getEndpoint()
+	if endpoint == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(otlpRequest(batch))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/v1/traces", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+
+	return nil
+}
+
+// OTLP JSON request types, see opentelemetry/proto/collector/trace/v1/trace_service.proto.
+type (
+	otlpExportRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		Name              string          `json:"name"`
+		Kind              int             `json:"kind"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		Status            otlpStatus      `json:"status"`
+	}
+
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+
+	otlpValue struct {
+		StringValue *string         `json:"stringValue,omitempty"`
+		ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+	}
+
+	otlpArrayValue struct {
+		Values []otlpValue `json:"values"`
+	}
+)
+
+func otlpString(s string) otlpValue {
+	return otlpValue{StringValue: &s}
+}
+
+func otlpRequest(batch []controllerSpan) *otlpExportRequest {
+	spans := make([]otlpSpan, 0, len(batch))
+
+	for _, item := range batch {
+		span := otlpSpan{
+			TraceID:           randomID(16),
+			SpanID:            randomID(8),
+			Name:              item.name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(item.run.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(item.run.Start.Add(item.run.Duration).UnixNano(), 10),
+			Status: otlpStatus{
+				Code: otlpStatusOK,
+			},
+		}
+
+		if item.run.Error != "" {
+			span.Status = otlpStatus{
+				Code:    otlpStatusError,
+				Message: item.run.Error,
+			}
+		}
+
+		if len(item.run.TouchedResources) > 0 {
+			values := make([]otlpValue, 0, len(item.run.TouchedResources))
+
+			for _, id := range item.run.TouchedResources {
+				values = append(values, otlpString(id))
+			}
+
+			span.Attributes = append(span.Attributes, otlpAttribute{
+				Key:   "talos.controller.touched_resources",
+				Value: otlpValue{ArrayValue: &otlpArrayValue{Values: values}},
+			})
+		}
+
+		spans = append(spans, span)
+	}
+
+	return &otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpAttribute{
+						{
+							Key:   "service.name",
+							Value: otlpString("machined"),
+						},
+					},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{
+							Name: "controller-runtime",
+						},
+						Spans: spans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func randomID(size int) string {
+	id := make([]byte, size)
+
+	rand.Read(id) //nolint:errcheck
+
+	return hex.EncodeToString(id)
+}
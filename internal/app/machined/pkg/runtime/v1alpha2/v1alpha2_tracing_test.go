@@ -0,0 +1,103 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+)
+
+func TestSpanExporter(t *testing.T) {
+	requests := make(chan *otlpExportRequest, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var req otlpExportRequest
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		requests <- &req
+	}))
+	defer srv.Close()
+
+	exporter := newSpanExporter()
+
+	start := time.Unix(1600000000, 0)
+
+	// export is disabled without the endpoint
+	exporter.export("network.LinkSpecController", runtime.ControllerReconcileRun{Start: start})
+	assert.Empty(t, exporter.queue)
+
+	exporter.setEndpoint(srv.URL + "/")
+
+	exporter.export("network.LinkSpecController", runtime.ControllerReconcileRun{
+		Start:            start,
+		Duration:         time.Second,
+		TouchedResources: []string{"network/LinkStatuses.net.talos.dev/eth0"},
+	})
+	exporter.export("time.SyncController", runtime.ControllerReconcileRun{
+		Start: start,
+		Error: "sync failed",
+	})
+
+	batch := []controllerSpan{<-exporter.queue, <-exporter.queue}
+
+	require.NoError(t, exporter.send(context.Background(), batch))
+
+	req := <-requests
+
+	require.Len(t, req.ResourceSpans, 1)
+	assert.Equal(t, "service.name", req.ResourceSpans[0].Resource.Attributes[0].Key)
+	assert.Equal(t, "machined", *req.ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+
+	require.Len(t, req.ResourceSpans[0].ScopeSpans, 1)
+
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "network.LinkSpecController", spans[0].Name)
+	assert.Len(t, spans[0].TraceID, 32)
+	assert.Len(t, spans[0].SpanID, 16)
+	assert.Equal(t, "1600000000000000000", spans[0].StartTimeUnixNano)
+	assert.Equal(t, "1600000001000000000", spans[0].EndTimeUnixNano)
+	assert.Equal(t, otlpStatus{Code: otlpStatusOK}, spans[0].Status)
+	require.Len(t, spans[0].Attributes, 1)
+	assert.Equal(t, "network/LinkStatuses.net.talos.dev/eth0", *spans[0].Attributes[0].Value.ArrayValue.Values[0].StringValue)
+
+	assert.Equal(t, "time.SyncController", spans[1].Name)
+	assert.NotEqual(t, spans[0].TraceID, spans[1].TraceID)
+	assert.Equal(t, otlpStatus{Code: otlpStatusError, Message: "sync failed"}, spans[1].Status)
+	assert.Empty(t, spans[1].Attributes)
+}
+
+func TestSpanExporterFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	exporter := newSpanExporter()
+	exporter.setEndpoint(srv.URL)
+
+	assert.EqualError(t, exporter.send(context.Background(), []controllerSpan{{name: "time.SyncController"}}), `unexpected response status "503 Service Unavailable"`)
+
+	// queue is bounded, spans are dropped when it's full
+	for i := 0; i < spanQueueSize+10; i++ {
+		exporter.export("time.SyncController", runtime.ControllerReconcileRun{})
+	}
+
+	assert.Len(t, exporter.queue, spanQueueSize)
+}
@@ -0,0 +1,78 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package system
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/health"
+)
+
+var (
+	serviceStateMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "talos",
+		Subsystem: "service",
+		Name:      "state",
+		Help:      "Current state of the service, set to 1 for the state the service is in.",
+	}, []string{"service", "state"})
+
+	serviceRestartsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "talos",
+		Subsystem: "service",
+		Name:      "restarts_total",
+		Help:      "Number of times the service went back to the running state.",
+	}, []string{"service"})
+
+	serviceHealthyMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "talos",
+		Subsystem: "service",
+		Name:      "healthy",
+		Help:      "Result of the last service health check (1 for healthy, 0 for unhealthy).",
+	}, []string{"service"})
+
+	serviceHealthCheckFailuresMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "talos",
+		Subsystem: "service",
+		Name:      "health_check_failures_total",
+		Help:      "Number of failed service health checks.",
+	}, []string{"service"})
+)
+
+var allServiceStates = []events.ServiceState{
+	events.StateInitialized,
+	events.StatePreparing,
+	events.StateWaiting,
+	events.StateRunning,
+	events.StateStopping,
+	events.StateFinished,
+	events.StateFailed,
+	events.StateSkipped,
+}
+
+func recordServiceState(id string, state events.ServiceState) {
+	for _, s := range allServiceStates {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+
+		serviceStateMetric.WithLabelValues(id, s.String()).Set(value)
+	}
+}
+
+func recordServiceHealth(id string, status health.Status) {
+	if status.Healthy == nil {
+		return
+	}
+
+	if *status.Healthy {
+		serviceHealthyMetric.WithLabelValues(id).Set(1)
+	} else {
+		serviceHealthyMetric.WithLabelValues(id).Set(0)
+		serviceHealthCheckFailuresMetric.WithLabelValues(id).Inc()
+	}
+}
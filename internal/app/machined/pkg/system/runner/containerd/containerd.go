@@ -261,6 +261,18 @@ func (c *containerdRunner) newOCISpecOpts(image oci.Image) []oci.SpecOpts {
 		oci.WithHostResolvconf,
 	)
 
+	if c.opts.CgroupPath != "" {
+		specOpts = append(specOpts,
+			oci.WithCgroup(c.opts.CgroupPath),
+		)
+	}
+
+	if c.opts.CgroupResources != nil {
+		specOpts = append(specOpts,
+			WithResources(c.opts.CgroupResources),
+		)
+	}
+
 	specOpts = append(specOpts,
 		c.opts.OCISpecOpts...,
 	)
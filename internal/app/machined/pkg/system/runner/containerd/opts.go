@@ -10,8 +10,27 @@ import (
 	"github.com/containerd/containerd/containers"
 	"github.com/containerd/containerd/oci"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 )
 
+// WithResources sets the linux resources from the cgroup resource settings.
+func WithResources(resources *cgroup.Resources) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
+		linuxResources := resources.LinuxResources()
+
+		if linuxResources.CPU != nil {
+			s.Linux.Resources.CPU = linuxResources.CPU
+		}
+
+		if linuxResources.Memory != nil {
+			s.Linux.Resources.Memory = linuxResources.Memory
+		}
+
+		return nil
+	}
+}
+
 // WithMemoryLimit sets the linux resource memory limit field.
 func WithMemoryLimit(limit int64) oci.SpecOpts {
 	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
@@ -0,0 +1,55 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package process
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
+)
+
+// CgroupExecName is the argv[0] machined is executed with to run the process in the cgroup.
+//
+// The wrapper moves itself into the cgroup and executes the process, so that
+// the process is in the cgroup from the very first instruction.
+const CgroupExecName = "/cgroup-exec"
+
+// cgroupExecPath is the machined executable.
+const cgroupExecPath = "/proc/self/exe"
+
+// wrapCgroup makes the command enter the cgroup before it is executed.
+func wrapCgroup(cmd *exec.Cmd, path string) {
+	cmd.Args = append([]string{CgroupExecName, path, cmd.Path}, cmd.Args...)
+	cmd.Path = cgroupExecPath
+}
+
+// CgroupExec is the entrypoint of the cgroup wrapper.
+//
+// Arguments are the cgroup path, the executable path and the process arguments.
+func CgroupExec() {
+	if err := cgroupExec(os.Args[1:]); err != nil {
+		log.Fatalf("cgroup-exec: %s", err)
+	}
+}
+
+func cgroupExec(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("expected cgroup path, executable and arguments, got %q", args)
+	}
+
+	if err := cgroup.Enter(args[0]); err != nil {
+		return err
+	}
+
+	if err := syscall.Exec(args[1], args[2:], os.Environ()); err != nil {
+		return fmt.Errorf("error executing %q: %w", args[1], err)
+	}
+
+	return nil
+}
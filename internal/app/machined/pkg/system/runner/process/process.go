@@ -17,6 +17,7 @@ import (
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
@@ -118,18 +119,27 @@ func (p *processRunner) run(eventSink events.Recorder) error {
 		defer reaper.Stop(notifyCh)
 	}
 
+	if p.opts.CgroupPath != "" {
+		if _, err = cgroup.Create(p.opts.CgroupPath, p.opts.CgroupResources); err != nil {
+			return err
+		}
+
+		// the process enters the cgroup before it is executed, so all its allocations are accounted
+		wrapCgroup(cmd, p.opts.CgroupPath)
+	}
+
 	if err = cmd.Start(); err != nil {
 		return fmt.Errorf("error starting process: %w", err)
 	}
 
-	eventSink(events.StateRunning, "Process %s started with PID %d", p, cmd.Process.Pid)
-
 	waitCh := make(chan error)
 
 	go func() {
 		waitCh <- reaper.WaitWrapper(usingReaper, notifyCh, cmd)
 	}()
 
+	eventSink(events.StateRunning, "Process %s started with PID %d", p, cmd.Process.Pid)
+
 	select {
 	case err = <-waitCh:
 		// process exited
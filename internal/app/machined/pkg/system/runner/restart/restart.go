@@ -6,6 +6,7 @@ package restart
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -43,8 +44,15 @@ type Options struct {
 	Type Type
 	// RestartInterval is the interval between restarts for failed runs.
 	RestartInterval time.Duration
+	// MaxRestarts is the maximum number of restarts within RestartWindow, zero means no limit.
+	MaxRestarts int
+	// RestartWindow is the period of time the restarts are counted over.
+	RestartWindow time.Duration
 }
 
+// ErrRestartBudgetExceeded is returned when the runner was restarted too many times.
+var ErrRestartBudgetExceeded = errors.New("restart budget exceeded")
+
 // Option is the functional option func.
 type Option func(*Options)
 
@@ -78,6 +86,7 @@ func DefaultOptions() *Options {
 	return &Options{
 		Type:            Forever,
 		RestartInterval: 5 * time.Second,
+		RestartWindow:   time.Hour,
 	}
 }
 
@@ -95,6 +104,34 @@ func WithRestartInterval(interval time.Duration) Option {
 	}
 }
 
+// WithMaxRestarts sets the maximum number of restarts.
+func WithMaxRestarts(maxRestarts int) Option {
+	return func(args *Options) {
+		args.MaxRestarts = maxRestarts
+	}
+}
+
+// WithRestartWindow sets the period of time the restarts are counted over.
+func WithRestartWindow(window time.Duration) Option {
+	return func(args *Options) {
+		args.RestartWindow = window
+	}
+}
+
+// Reconfigure applies options to the runner if it implements the restart policy.
+//
+// Other runners are not affected.
+func Reconfigure(r runner.Runner, opts ...Option) {
+	restarter, ok := r.(*restarter)
+	if !ok {
+		return
+	}
+
+	for _, opt := range opts {
+		opt(restarter.opts)
+	}
+}
+
 // Open implements the Runner interface.
 func (r *restarter) Open(ctx context.Context) error {
 	return r.wrappedRunner.Open(ctx)
@@ -105,6 +142,9 @@ func (r *restarter) Open(ctx context.Context) error {
 func (r *restarter) Run(eventSink events.Recorder) error {
 	defer close(r.stopped)
 
+	// restarts keeps the time of the restarts within the restart window
+	var restarts []time.Time
+
 	for {
 		errCh := make(chan error)
 
@@ -128,14 +168,26 @@ func (r *restarter) Run(eventSink events.Recorder) error {
 			return errStop
 		}
 
-		switch r.opts.Type {
-		case Once:
+		restarts = r.recentRestarts(restarts)
+
+		switch {
+		case r.opts.Type == Once:
 			return err
-		case UntilSuccess:
+		case r.opts.Type == UntilSuccess && err == nil:
+			return nil
+		case r.opts.MaxRestarts > 0 && len(restarts) >= r.opts.MaxRestarts:
 			if err == nil {
-				return nil
+				return fmt.Errorf("%w after %d restarts", ErrRestartBudgetExceeded, len(restarts))
 			}
 
+			return fmt.Errorf("%w after %d restarts: %v", ErrRestartBudgetExceeded, len(restarts), err)
+		}
+
+		restarts = append(restarts, time.Now())
+
+		switch r.opts.Type {
+		case Once:
+		case UntilSuccess:
 			eventSink(events.StateWaiting, "Error running %s, going to restart until it succeeds: %v", r.wrappedRunner, err)
 		case Forever:
 			if err == nil {
@@ -155,6 +207,17 @@ func (r *restarter) Run(eventSink events.Recorder) error {
 	}
 }
 
+// recentRestarts drops the restarts which happened before the restart window.
+func (r *restarter) recentRestarts(restarts []time.Time) []time.Time {
+	cutoff := time.Now().Add(-r.opts.RestartWindow)
+
+	for len(restarts) > 0 && restarts[0].Before(cutoff) {
+		restarts = restarts[1:]
+	}
+
+	return restarts
+}
+
 // Stop implements the Runner interface.
 func (r *restarter) Stop() error {
 	close(r.stop)
@@ -178,6 +178,79 @@ func (suite *RestartSuite) TestRunForever() {
 	suite.Assert().Equal(4, mock.times)
 }
 
+func (suite *RestartSuite) TestRunMaxRestarts() {
+	mock := MockRunner{
+		exitCh: make(chan error),
+	}
+
+	r := restart.New(&mock, restart.WithType(restart.Forever), restart.WithRestartInterval(time.Millisecond))
+	restart.Reconfigure(r, restart.WithMaxRestarts(2))
+
+	suite.Assert().NoError(r.Open(context.Background()))
+
+	defer func() { suite.Assert().NoError(r.Close()) }()
+
+	failed := errors.New("failed")
+	errCh := make(chan error)
+
+	go func() {
+		errCh <- r.Run(MockEventSink)
+	}()
+
+	mock.exitCh <- failed
+	mock.exitCh <- nil
+	mock.exitCh <- failed
+
+	err := <-errCh
+	suite.Assert().True(errors.Is(err, restart.ErrRestartBudgetExceeded))
+	suite.Assert().EqualError(err, "restart budget exceeded after 2 restarts: failed")
+	suite.Assert().NoError(r.Stop())
+	suite.Assert().Equal(3, mock.times)
+}
+
+func (suite *RestartSuite) TestRunRestartWindow() {
+	mock := MockRunner{
+		exitCh: make(chan error),
+	}
+
+	r := restart.New(&mock, restart.WithType(restart.Forever), restart.WithRestartInterval(time.Millisecond))
+	restart.Reconfigure(r, restart.WithMaxRestarts(2), restart.WithRestartWindow(500*time.Millisecond))
+
+	suite.Assert().NoError(r.Open(context.Background()))
+
+	defer func() { suite.Assert().NoError(r.Close()) }()
+
+	failed := errors.New("failed")
+	errCh := make(chan error)
+
+	go func() {
+		errCh <- r.Run(MockEventSink)
+	}()
+
+	mock.exitCh <- failed
+	mock.exitCh <- failed
+
+	// restarts are out of the window, so the budget is reset
+	time.Sleep(time.Second)
+
+	mock.exitCh <- failed
+
+	select {
+	case <-errCh:
+		suite.Assert().Fail("runner should be still running")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	mock.exitCh <- failed
+	mock.exitCh <- failed
+
+	err := <-errCh
+	suite.Assert().True(errors.Is(err, restart.ErrRestartBudgetExceeded))
+	suite.Assert().EqualError(err, "restart budget exceeded after 2 restarts: failed")
+	suite.Assert().NoError(r.Stop())
+	suite.Assert().Equal(5, mock.times)
+}
+
 func TestRestartSuite(t *testing.T) {
 	suite.Run(t, new(RestartSuite))
 }
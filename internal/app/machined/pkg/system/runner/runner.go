@@ -16,6 +16,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime/logging"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
@@ -56,6 +57,10 @@ type Options struct {
 	GracefulShutdownTimeout time.Duration
 	// Stdin is the process standard input.
 	Stdin io.ReadSeeker
+	// CgroupPath is the cgroup to run the process in, empty means the parent cgroup.
+	CgroupPath string
+	// CgroupResources describes resource settings of the cgroup.
+	CgroupResources *cgroup.Resources
 }
 
 // Option is the functional option func.
@@ -135,3 +140,11 @@ func WithStdin(stdin io.ReadSeeker) Option {
 		args.Stdin = stdin
 	}
 }
+
+// WithCgroup sets the cgroup and its resource settings.
+func WithCgroup(path string, resources *cgroup.Resources) Option {
+	return func(args *Options) {
+		args.CgroupPath = path
+		args.CgroupResources = resources
+	}
+}
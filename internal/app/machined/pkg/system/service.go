@@ -11,6 +11,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/health"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/pkg/conditions"
 )
 
@@ -53,3 +54,9 @@ type APIStoppableService interface {
 type APIRestartableService interface {
 	APIRestartAllowed(runtime.Runtime) bool
 }
+
+// CgroupService is a service which runs in a dedicated cgroup.
+type CgroupService interface {
+	// Cgroup returns the service cgroup path and its resource settings.
+	Cgroup(runtime.Runtime) (string, *cgroup.Resources)
+}
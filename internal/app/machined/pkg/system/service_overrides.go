@@ -0,0 +1,153 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/health"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/pkg/kubernetes"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// cordonTimeout is the timeout to cordon the node on escalation.
+const cordonTimeout = time.Minute
+
+// overrides returns machine configuration overrides for the service, if any.
+func (svcrunner *ServiceRunner) overrides() config.Service {
+	if svcrunner.runtime == nil || svcrunner.runtime.Config() == nil {
+		return nil
+	}
+
+	for _, service := range svcrunner.runtime.Config().Machine().Services() {
+		if service.Name() == svcrunner.id {
+			return service
+		}
+	}
+
+	return nil
+}
+
+// healthSettings applies overrides to the service health check settings.
+func (svcrunner *ServiceRunner) healthSettings(settings *health.Settings) *health.Settings {
+	override := svcrunner.overrides()
+	if override == nil {
+		return settings
+	}
+
+	// settings might be shared between services, so make a copy
+	result := *settings
+
+	if override.HealthCheckInterval() > 0 {
+		result.Period = override.HealthCheckInterval()
+	}
+
+	if override.HealthCheckTimeout() > 0 {
+		result.Timeout = override.HealthCheckTimeout()
+	}
+
+	return &result
+}
+
+// applyRestartOverrides applies overrides to the service restart policy.
+func (svcrunner *ServiceRunner) applyRestartOverrides(runnr runner.Runner) {
+	override := svcrunner.overrides()
+	if override == nil {
+		return
+	}
+
+	opts := []restart.Option{
+		restart.WithMaxRestarts(override.MaxRestarts()),
+	}
+
+	if override.RestartBackoff() > 0 {
+		opts = append(opts, restart.WithRestartInterval(override.RestartBackoff()))
+	}
+
+	if override.RestartWindow() > 0 {
+		opts = append(opts, restart.WithRestartWindow(override.RestartWindow()))
+	}
+
+	restart.Reconfigure(runnr, opts...)
+}
+
+// escalate runs the escalation action when the service exceeds the restart budget.
+func (svcrunner *ServiceRunner) escalate() {
+	override := svcrunner.overrides()
+	if override == nil {
+		return
+	}
+
+	action := override.Escalation()
+
+	switch action {
+	case config.ServiceEscalationNone:
+		return
+	case config.ServiceEscalationUnhealthy:
+		svcrunner.healthState.Update(false, "restart budget exceeded")
+
+		recordServiceHealth(svcrunner.id, svcrunner.healthState.Get())
+	case config.ServiceEscalationCordon:
+		if err := svcrunner.cordonNode(); err != nil {
+			log.Printf("service[%s]: failed to cordon the node: %s", svcrunner.id, err)
+
+			return
+		}
+	case config.ServiceEscalationReboot:
+		if err := svcrunner.rebootNode(); err != nil {
+			log.Printf("service[%s]: failed to reboot the node: %s", svcrunner.id, err)
+
+			return
+		}
+	}
+
+	log.Printf("service[%s]: restart budget exceeded, escalated to %q", svcrunner.id, action)
+}
+
+// rebootNode cordons the node and runs the reboot sequence, which stops the pods and services and unmounts the filesystems.
+func (svcrunner *ServiceRunner) rebootNode() error {
+	controller := Services(svcrunner.runtime).getController()
+	if controller == nil {
+		return fmt.Errorf("controller is not set")
+	}
+
+	// the reboot sequence stops this service, so it runs in the background
+	go func() {
+		if err := svcrunner.cordonNode(); err != nil {
+			log.Printf("service[%s]: failed to cordon the node before reboot: %s", svcrunner.id, err)
+		}
+
+		if err := controller.Run(context.Background(), runtime.SequenceReboot, nil, runtime.WithTakeover()); err != nil && !runtime.IsRebootError(err) {
+			log.Printf("service[%s]: reboot failed: %s", svcrunner.id, err)
+		}
+	}()
+
+	return nil
+}
+
+func (svcrunner *ServiceRunner) cordonNode() error {
+	ctx, cancel := context.WithTimeout(context.Background(), cordonTimeout)
+	defer cancel()
+
+	nodename, err := svcrunner.runtime.NodeName()
+	if err != nil {
+		return fmt.Errorf("error getting node name: %w", err)
+	}
+
+	kubeHelper, err := kubernetes.NewClientFromKubeletKubeconfig()
+	if err != nil {
+		return fmt.Errorf("error building kubernetes client: %w", err)
+	}
+
+	defer kubeHelper.Close() //nolint:errcheck
+
+	return kubeHelper.Cordon(ctx, nodename)
+}
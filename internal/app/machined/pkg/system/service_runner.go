@@ -6,6 +6,7 @@ package system
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -15,6 +16,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/health"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
 	"github.com/talos-systems/talos/pkg/conditions"
 	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
 )
@@ -36,6 +38,13 @@ type ServiceRunner struct {
 	state  events.ServiceState
 	events events.ServiceEvents
 
+	// started is set once the service reaches running state for the first time
+	started  bool
+	restarts int
+
+	// waitCondition is the condition service is waiting for before it starts
+	waitCondition conditions.Condition
+
 	healthState health.State
 
 	stateSubscribers map[StateEvent][]chan<- struct{}
@@ -78,9 +87,21 @@ func (svcrunner *ServiceRunner) UpdateState(newstate events.ServiceState, messag
 		Timestamp: time.Now(),
 	}
 
+	if newstate == events.StateRunning && svcrunner.state != events.StateRunning {
+		if svcrunner.started {
+			svcrunner.restarts++
+
+			serviceRestartsMetric.WithLabelValues(svcrunner.id).Inc()
+		}
+
+		svcrunner.started = true
+	}
+
 	svcrunner.state = newstate
 	svcrunner.events.Push(event)
 
+	recordServiceState(svcrunner.id, newstate)
+
 	log.Printf("service[%s](%s): %s", svcrunner.id, svcrunner.state, event.Message)
 
 	isUp := svcrunner.inStateLocked(StateEventUp)
@@ -130,6 +151,8 @@ func (svcrunner *ServiceRunner) healthUpdate(change health.StateChange) {
 	}
 	svcrunner.events.Push(event)
 
+	recordServiceHealth(svcrunner.id, change.New)
+
 	log.Printf("service[%s](%s): %s", svcrunner.id, svcrunner.state, event.Message)
 
 	isUp := svcrunner.inStateLocked(StateEventUp)
@@ -153,6 +176,16 @@ func (svcrunner *ServiceRunner) GetEventHistory(count int) []events.ServiceEvent
 }
 
 func (svcrunner *ServiceRunner) waitFor(ctx context.Context, condition conditions.Condition) error {
+	svcrunner.mu.Lock()
+	svcrunner.waitCondition = condition
+	svcrunner.mu.Unlock()
+
+	defer func() {
+		svcrunner.mu.Lock()
+		svcrunner.waitCondition = nil
+		svcrunner.mu.Unlock()
+	}()
+
 	description := condition.String()
 	svcrunner.UpdateState(events.StateWaiting, "Waiting for %s", description)
 
@@ -245,8 +278,14 @@ func (svcrunner *ServiceRunner) Start() {
 		return
 	}
 
+	svcrunner.applyRestartOverrides(runnr)
+
 	if err := svcrunner.run(ctx, runnr); err != nil {
 		svcrunner.UpdateState(events.StateFailed, "Failed running service: %v", err)
+
+		if errors.Is(err, restart.ErrRestartBudgetExceeded) {
+			svcrunner.escalate()
+		}
 	} else {
 		svcrunner.UpdateState(events.StateFinished, "Service finished successfully")
 	}
@@ -291,7 +330,7 @@ func (svcrunner *ServiceRunner) run(ctx context.Context, runnr runner.Runner) er
 			defer healthWg.Done()
 
 			//nolint:errcheck
-			health.Run(ctx, healthSvc.HealthSettings(svcrunner.runtime), &svcrunner.healthState, healthSvc.HealthFunc(svcrunner.runtime))
+			health.Run(ctx, svcrunner.healthSettings(healthSvc.HealthSettings(svcrunner.runtime)), &svcrunner.healthState, healthSvc.HealthFunc(svcrunner.runtime))
 		}()
 
 		notifyCh := make(chan health.StateChange, 2)
@@ -348,15 +387,29 @@ func (svcrunner *ServiceRunner) Shutdown() {
 
 // AsProto returns protobuf struct with the state of the service runner.
 func (svcrunner *ServiceRunner) AsProto() *machineapi.ServiceInfo {
+	dependsOn := svcrunner.service.DependsOn(svcrunner.runtime)
+
 	svcrunner.mu.Lock()
-	defer svcrunner.mu.Unlock()
 
-	return &machineapi.ServiceInfo{
-		Id:     svcrunner.id,
-		State:  svcrunner.state.String(),
-		Events: svcrunner.events.AsProto(events.MaxEventsToKeep),
-		Health: svcrunner.healthState.AsProto(),
+	info := &machineapi.ServiceInfo{
+		Id:           svcrunner.id,
+		State:        svcrunner.state.String(),
+		Events:       svcrunner.events.AsProto(events.MaxEventsToKeep),
+		Health:       svcrunner.healthState.AsProto(),
+		RestartCount: uint32(svcrunner.restarts),
+		DependsOn:    dependsOn,
 	}
+
+	waitCondition := svcrunner.waitCondition
+
+	svcrunner.mu.Unlock()
+
+	// condition descriptions are built outside of the lock, as service conditions might query other services
+	if waitCondition != nil {
+		info.UnmetConditions = conditions.Unmet(waitCondition)
+	}
+
+	return info
 }
 
 // Subscribe to a specific event for this service.
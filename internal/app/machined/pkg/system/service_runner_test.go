@@ -207,6 +207,8 @@ func (suite *ServiceRunnerSuite) TestWaitingDescriptionChange() {
 		return nil
 	}))
 
+	suite.Assert().Equal([]string{"cond1", "cond2"}, sr.AsProto().UnmetConditions)
+
 	select {
 	case <-finished:
 		suite.Require().Fail("service running should be still running")
@@ -231,6 +233,8 @@ func (suite *ServiceRunnerSuite) TestWaitingDescriptionChange() {
 	default:
 	}
 
+	suite.Assert().Equal([]string{"cond2"}, sr.AsProto().UnmetConditions)
+
 	close(cond2.done)
 
 	suite.Require().NoError(retry.Constant(time.Minute, retry.WithUnits(10*time.Millisecond)).Retry(func() error {
@@ -242,6 +246,8 @@ func (suite *ServiceRunnerSuite) TestWaitingDescriptionChange() {
 		return nil
 	}))
 
+	suite.Assert().Empty(sr.AsProto().UnmetConditions)
+
 	sr.Shutdown()
 
 	<-finished
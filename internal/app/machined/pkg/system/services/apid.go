@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/pkg/conditions"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 	"github.com/talos-systems/talos/pkg/resources/secrets"
@@ -96,6 +98,14 @@ func (o *APID) DependsOn(r runtime.Runtime) []string {
 	return []string{"containerd"}
 }
 
+// Cgroup implements the CgroupService interface.
+func (o *APID) Cgroup(r runtime.Runtime) (string, *cgroup.Resources) {
+	return path.Join(constants.CgroupSystem, o.ID(r)), &cgroup.Resources{
+		CPUShares:         128,
+		MemoryReservation: 64 * 1024 * 1024,
+	}
+}
+
 // Runner implements the Service interface.
 func (o *APID) Runner(r runtime.Runtime) (runner.Runner, error) {
 	// Ensure socket dir exists
@@ -115,6 +125,10 @@ func (o *APID) Runner(r runtime.Runtime) (runner.Runner, error) {
 		args.ProcessArgs = append(args.ProcessArgs, "--enable-rbac")
 	}
 
+	if r.Config().Machine().Features().MetricsEnabled() {
+		args.ProcessArgs = append(args.ProcessArgs, "--enable-metrics")
+	}
+
 	// Set the mounts.
 	mounts := []specs.Mount{
 		{Type: "bind", Destination: "/etc/ssl", Source: "/etc/ssl", Options: []string{"bind", "ro"}},
@@ -146,6 +160,7 @@ func (o *APID) Runner(r runtime.Runtime) (runner.Runner, error) {
 		r.Config().Debug(),
 		&args,
 		runner.WithLoggingManager(r.Logging()),
+		withCgroup(r, o),
 		runner.WithContainerdAddress(constants.SystemContainerdAddress),
 		runner.WithEnv(env),
 		runner.WithOCISpecOpts(
@@ -15,4 +15,5 @@ import (
 
 func TestAPIDInterfaces(t *testing.T) {
 	assert.Implements(t, (*system.HealthcheckedService)(nil), new(services.APID))
+	assert.Implements(t, (*system.CgroupService)(nil), new(services.APID))
 }
@@ -7,6 +7,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"path"
 	"path/filepath"
 
 	"github.com/containerd/containerd"
@@ -18,6 +19,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/process"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/pkg/conditions"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
@@ -51,6 +53,14 @@ func (c *Containerd) DependsOn(r runtime.Runtime) []string {
 	return nil
 }
 
+// Cgroup implements the CgroupService interface.
+func (c *Containerd) Cgroup(r runtime.Runtime) (string, *cgroup.Resources) {
+	return path.Join(constants.CgroupSystem, c.ID(r)), &cgroup.Resources{
+		CPUShares:         64,
+		MemoryReservation: 32 * 1024 * 1024,
+	}
+}
+
 // Runner implements the Service interface.
 func (c *Containerd) Runner(r runtime.Runtime) (runner.Runner, error) {
 	// Set the process arguments.
@@ -73,6 +83,7 @@ func (c *Containerd) Runner(r runtime.Runtime) (runner.Runner, error) {
 		r.Config().Debug(),
 		args,
 		runner.WithLoggingManager(r.Logging()),
+		withCgroup(r, c),
 		runner.WithEnv(env),
 	),
 		restart.WithType(restart.Forever),
@@ -15,4 +15,5 @@ import (
 
 func TestSystemContainerdInterfaces(t *testing.T) {
 	assert.Implements(t, (*system.HealthcheckedService)(nil), new(services.Containerd))
+	assert.Implements(t, (*system.CgroupService)(nil), new(services.Containerd))
 }
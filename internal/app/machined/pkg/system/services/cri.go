@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 
 	"github.com/containerd/containerd"
 	"github.com/containerd/containerd/defaults"
@@ -19,6 +20,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/process"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/pkg/conditions"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 	"github.com/talos-systems/talos/pkg/resources/network"
@@ -53,6 +55,14 @@ func (c *CRI) DependsOn(r runtime.Runtime) []string {
 	return nil
 }
 
+// Cgroup implements the CgroupService interface.
+func (c *CRI) Cgroup(r runtime.Runtime) (string, *cgroup.Resources) {
+	return path.Join(constants.CgroupPodRuntime, c.ID(r)), &cgroup.Resources{
+		CPUShares:         256,
+		MemoryReservation: 128 * 1024 * 1024,
+	}
+}
+
 // Runner implements the Service interface.
 func (c *CRI) Runner(r runtime.Runtime) (runner.Runner, error) {
 	// Set the process arguments.
@@ -76,6 +86,7 @@ func (c *CRI) Runner(r runtime.Runtime) (runner.Runner, error) {
 		r.Config().Debug(),
 		args,
 		runner.WithLoggingManager(r.Logging()),
+		withCgroup(r, c),
 		runner.WithEnv(env),
 	),
 		restart.WithType(restart.Forever),
@@ -15,4 +15,5 @@ import (
 
 func TestContainerdInterfaces(t *testing.T) {
 	assert.Implements(t, (*system.HealthcheckedService)(nil), new(services.CRI))
+	assert.Implements(t, (*system.CgroupService)(nil), new(services.CRI))
 }
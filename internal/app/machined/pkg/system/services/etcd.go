@@ -12,6 +12,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path"
 	goruntime "runtime"
 	"strings"
 	"time"
@@ -37,6 +38,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/internal/pkg/containers/image"
 	"github.com/talos-systems/talos/internal/pkg/etcd"
 	"github.com/talos-systems/talos/pkg/argsbuilder"
@@ -132,6 +134,14 @@ func (e *Etcd) DependsOn(r runtime.Runtime) []string {
 	return []string{"cri"}
 }
 
+// Cgroup implements the CgroupService interface.
+func (e *Etcd) Cgroup(r runtime.Runtime) (string, *cgroup.Resources) {
+	return path.Join(constants.CgroupSystem, e.ID(r)), &cgroup.Resources{
+		CPUShares:         512,
+		MemoryReservation: 256 * 1024 * 1024,
+	}
+}
+
 // Runner implements the Service interface.
 func (e *Etcd) Runner(r runtime.Runtime) (runner.Runner, error) {
 	// Set the process arguments.
@@ -160,6 +170,7 @@ func (e *Etcd) Runner(r runtime.Runtime) (runner.Runner, error) {
 		r.Config().Debug(),
 		&args,
 		runner.WithLoggingManager(r.Logging()),
+		withCgroup(r, e),
 		runner.WithNamespace(constants.SystemContainerdNamespace),
 		runner.WithContainerImage(r.Config().Machine().Kubelet().Image()),
 		runner.WithContainerImage(r.Config().Cluster().Etcd().Image()),
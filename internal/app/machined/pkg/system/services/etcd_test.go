@@ -15,4 +15,5 @@ import (
 
 func TestEtcdInterfaces(t *testing.T) {
 	assert.Implements(t, (*system.HealthcheckedService)(nil), new(services.Etcd))
+	assert.Implements(t, (*system.CgroupService)(nil), new(services.Etcd))
 }
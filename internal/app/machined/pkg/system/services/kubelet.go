@@ -12,7 +12,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"text/template"
 	"time"
 
@@ -26,11 +28,13 @@ import (
 	kubeletconfig "k8s.io/kubelet/config/v1beta1"
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/events"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/health"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/internal/pkg/containers/image"
 	"github.com/talos-systems/talos/pkg/argsbuilder"
 	"github.com/talos-systems/talos/pkg/conditions"
@@ -142,6 +146,14 @@ func (k *Kubelet) DependsOn(r runtime.Runtime) []string {
 	return []string{"cri"}
 }
 
+// Cgroup implements the CgroupService interface.
+func (k *Kubelet) Cgroup(r runtime.Runtime) (string, *cgroup.Resources) {
+	return path.Join(constants.CgroupPodRuntime, k.ID(r)), &cgroup.Resources{
+		CPUShares:         256,
+		MemoryReservation: 128 * 1024 * 1024,
+	}
+}
+
 // Runner implements the Service interface.
 func (k *Kubelet) Runner(r runtime.Runtime) (runner.Runner, error) {
 	a, err := k.args(r)
@@ -191,6 +203,7 @@ func (k *Kubelet) Runner(r runtime.Runtime) (runner.Runner, error) {
 		r.Config().Debug() && r.Config().Machine().Type() == machine.TypeWorker, // enable debug logs only for the worker nodes
 		&args,
 		runner.WithLoggingManager(r.Logging()),
+		withCgroup(r, k),
 		runner.WithNamespace(constants.SystemContainerdNamespace),
 		runner.WithContainerImage(r.Config().Machine().Kubelet().Image()),
 		runner.WithEnv(env),
@@ -241,7 +254,7 @@ func (k *Kubelet) HealthSettings(runtime.Runtime) *health.Settings {
 	return &settings
 }
 
-func newKubeletConfiguration(clusterDNS []string, dnsDomain string) *kubeletconfig.KubeletConfiguration {
+func newKubeletConfiguration(clusterDNS []string, dnsDomain string, serverTLSBootstrap bool, systemReserved *cgroup.Resources) *kubeletconfig.KubeletConfiguration {
 	f := false
 	t := true
 
@@ -254,6 +267,7 @@ func newKubeletConfiguration(clusterDNS []string, dnsDomain string) *kubeletconf
 		Address:            "0.0.0.0",
 		Port:               constants.KubeletPort,
 		RotateCertificates: true,
+		ServerTLSBootstrap: serverTLSBootstrap,
 		Authentication: kubeletconfig.KubeletAuthentication{
 			X509: kubeletconfig.KubeletX509Authentication{
 				ClientCAFile: constants.KubernetesCACert,
@@ -272,6 +286,10 @@ func newKubeletConfiguration(clusterDNS []string, dnsDomain string) *kubeletconf
 		ClusterDNS:          clusterDNS,
 		SerializeImagePulls: &f,
 		FailSwapOn:          &f,
+		SystemReserved: map[string]string{
+			"cpu":    fmt.Sprintf("%dm", systemReserved.MilliCPU()),
+			"memory": strconv.FormatInt(systemReserved.MemoryReservation, 10),
+		},
 	}
 }
 
@@ -329,7 +347,13 @@ func writeKubeletConfig(r runtime.Runtime) error {
 		dnsServiceIPsString = dnsServiceIPsCustom
 	}
 
-	kubeletConfiguration := newKubeletConfiguration(dnsServiceIPsString, r.Config().Cluster().Network().DNSDomain())
+	kubeletConfiguration := newKubeletConfiguration(
+		dnsServiceIPsString,
+		r.Config().Cluster().Network().DNSDomain(),
+		r.Config().Machine().Kubelet().ServerTLSBootstrap(),
+		// reserve resources for Talos system services
+		system.Services(r).CgroupResources(constants.CgroupSystem),
+	)
 
 	serializer := json.NewSerializerWithOptions(
 		json.DefaultMetaFactory,
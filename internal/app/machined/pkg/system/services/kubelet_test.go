@@ -15,4 +15,5 @@ import (
 
 func TestKubeletInterfaces(t *testing.T) {
 	assert.Implements(t, (*system.HealthcheckedService)(nil), new(services.Kubelet))
+	assert.Implements(t, (*system.CgroupService)(nil), new(services.Kubelet))
 }
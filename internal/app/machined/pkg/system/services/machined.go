@@ -8,6 +8,9 @@ import (
 	"context"
 	"io"
 	"log"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	v1alpha1server "github.com/talos-systems/talos/internal/app/machined/internal/server/v1alpha1"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
@@ -25,6 +28,7 @@ var rules = map[string]role.Set{
 	"/cluster.ClusterService/HealthCheck": role.MakeSet(role.Admin, role.Reader),
 
 	"/inspect.InspectService/ControllerRuntimeDependencies": role.MakeSet(role.Admin, role.Reader),
+	"/inspect.InspectService/ControllerRuntimeHistory":      role.MakeSet(role.Admin, role.Reader),
 
 	"/machine.MachineService/ApplyConfiguration":           role.MakeSet(role.Admin),
 	"/machine.MachineService/Bootstrap":                    role.MakeSet(role.Admin),
@@ -55,6 +59,7 @@ var rules = map[string]role.Set{
 	"/machine.MachineService/Read":                         role.MakeSet(role.Admin),
 	"/machine.MachineService/Reboot":                       role.MakeSet(role.Admin),
 	"/machine.MachineService/RemoveBootkubeInitializedKey": role.MakeSet(role.Admin),
+	"/machine.MachineService/RenewCertificates":            role.MakeSet(role.Admin),
 	"/machine.MachineService/Reset":                        role.MakeSet(role.Admin),
 	"/machine.MachineService/Restart":                      role.MakeSet(role.Admin),
 	"/machine.MachineService/Rollback":                     role.MakeSet(role.Admin),
@@ -67,6 +72,7 @@ var rules = map[string]role.Set{
 	"/machine.MachineService/SystemStat":                   role.MakeSet(role.Admin, role.Reader),
 	"/machine.MachineService/Upgrade":                      role.MakeSet(role.Admin),
 	"/machine.MachineService/Version":                      role.MakeSet(role.Admin, role.Reader),
+	"/machine.MachineService/Wipe":                         role.MakeSet(role.Admin),
 
 	"/network.NetworkService/Interfaces": role.MakeSet(role.Admin, role.Reader),
 	"/network.NetworkService/Routes":     role.MakeSet(role.Admin, role.Reader),
@@ -125,6 +131,23 @@ func (s *machinedService) Main(ctx context.Context, r runtime.Runtime, logWriter
 		server.Serve(listener)
 	}()
 
+	// metrics are collected by apid which serves them to the clients
+	metricsListener, err := factory.NewListener(factory.Network("unix"), factory.SocketPath(constants.MachineMetricsSocketPath))
+	if err != nil {
+		return err
+	}
+
+	metricsServer := &http.Server{
+		Handler: promhttp.Handler(),
+	}
+
+	defer metricsServer.Close() //nolint:errcheck
+
+	go func() {
+		//nolint:errcheck
+		metricsServer.Serve(metricsListener)
+	}()
+
 	<-ctx.Done()
 
 	return nil
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"path"
 	"path/filepath"
 
 	"github.com/containerd/containerd/oci"
@@ -22,6 +23,7 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/containerd"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/pkg/conditions"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 	"github.com/talos-systems/talos/pkg/resources/network"
@@ -60,6 +62,15 @@ func (t *Trustd) DependsOn(r runtime.Runtime) []string {
 	return []string{"containerd"}
 }
 
+// Cgroup implements the CgroupService interface.
+func (t *Trustd) Cgroup(r runtime.Runtime) (string, *cgroup.Resources) {
+	return path.Join(constants.CgroupSystem, t.ID(r)), &cgroup.Resources{
+		CPUShares:         32,
+		MemoryReservation: 32 * 1024 * 1024,
+		MemoryLimit:       512 * 1000 * 1000,
+	}
+}
+
 func (t *Trustd) Runner(r runtime.Runtime) (runner.Runner, error) {
 	// Set the process arguments.
 	args := runner.Args{
@@ -93,10 +104,10 @@ func (t *Trustd) Runner(r runtime.Runtime) (runner.Runner, error) {
 		&args,
 		runner.WithStdin(stdin),
 		runner.WithLoggingManager(r.Logging()),
+		withCgroup(r, t),
 		runner.WithContainerdAddress(constants.SystemContainerdAddress),
 		runner.WithEnv(env),
 		runner.WithOCISpecOpts(
-			containerd.WithMemoryLimit(int64(1000000*512)),
 			oci.WithHostNamespace(specs.NetworkNamespace),
 			oci.WithMounts(mounts),
 			oci.WithRootFSPath(filepath.Join(constants.SystemLibexecPath, t.ID(r))),
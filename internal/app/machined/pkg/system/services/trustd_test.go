@@ -15,4 +15,5 @@ import (
 
 func TestTrustdInterfaces(t *testing.T) {
 	assert.Implements(t, (*system.HealthcheckedService)(nil), new(services.Trustd))
+	assert.Implements(t, (*system.CgroupService)(nil), new(services.Trustd))
 }
@@ -7,6 +7,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"path"
 	"time"
 
 	"github.com/talos-systems/go-cmd/pkg/cmd"
@@ -17,7 +18,9 @@ import (
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/process"
 	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner/restart"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/pkg/conditions"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
 // Udevd implements the Service interface. It serves as the concrete type with
@@ -57,6 +60,14 @@ func (c *Udevd) DependsOn(r runtime.Runtime) []string {
 	return nil
 }
 
+// Cgroup implements the CgroupService interface.
+func (c *Udevd) Cgroup(r runtime.Runtime) (string, *cgroup.Resources) {
+	return path.Join(constants.CgroupSystem, c.ID(r)), &cgroup.Resources{
+		CPUShares:         32,
+		MemoryReservation: 16 * 1024 * 1024,
+	}
+}
+
 // Runner implements the Service interface.
 func (c *Udevd) Runner(r runtime.Runtime) (runner.Runner, error) {
 	// Set the process arguments.
@@ -77,6 +88,7 @@ func (c *Udevd) Runner(r runtime.Runtime) (runner.Runner, error) {
 		r.Config().Debug(),
 		args,
 		runner.WithLoggingManager(r.Logging()),
+		withCgroup(r, c),
 		runner.WithEnv(env),
 	),
 		restart.WithType(restart.Forever),
@@ -15,4 +15,5 @@ import (
 
 func TestUdevdInterfaces(t *testing.T) {
 	assert.Implements(t, (*system.HealthcheckedService)(nil), new(services.Udevd))
+	assert.Implements(t, (*system.CgroupService)(nil), new(services.Udevd))
 }
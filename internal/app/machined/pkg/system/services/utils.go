@@ -12,6 +12,9 @@ import (
 
 	"golang.org/x/sys/unix"
 
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system"
+	"github.com/talos-systems/talos/internal/app/machined/pkg/system/runner"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
@@ -35,3 +38,18 @@ func prepareRootfs(id string) error {
 
 	return nil
 }
+
+// withCgroup runs the service in its cgroup.
+//
+// Cgroups are not managed in container mode, as they are controlled by the container runtime:
+// services run by containerd keep their resource limits in the cgroup picked by containerd,
+// while the services run as processes (containerd, cri, udevd) are not limited.
+func withCgroup(r runtime.Runtime, svc system.CgroupService) runner.Option {
+	path, resources := svc.Cgroup(r)
+
+	if r.State().Platform().Mode() == runtime.ModeContainer {
+		return runner.WithCgroup("", resources)
+	}
+
+	return runner.WithCgroup(path, resources)
+}
@@ -9,12 +9,14 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
 
 	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
 	"github.com/talos-systems/talos/pkg/conditions"
 )
 
@@ -22,6 +24,9 @@ import (
 type singleton struct {
 	runtime runtime.Runtime
 
+	// controller runs the sequences on the service escalation
+	controller runtime.Controller
+
 	// State of running services by ID
 	state map[string]*ServiceRunner
 
@@ -56,6 +61,21 @@ func Services(runtime runtime.Runtime) *singleton {
 	return instance
 }
 
+// SetController sets the controller to run the reboot sequence when the service escalates to reboot.
+func (s *singleton) SetController(controller runtime.Controller) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.controller = controller
+}
+
+func (s *singleton) getController() runtime.Controller {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.controller
+}
+
 // Load adds service to the list of services managed by the runner.
 //
 // Load returns service IDs for each of the services.
@@ -330,6 +350,29 @@ func (s *singleton) List() (result []*ServiceRunner) {
 	return
 }
 
+// CgroupResources returns total resource settings of the loaded services running in the cgroup subtree.
+func (s *singleton) CgroupResources(parent string) *cgroup.Resources {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := &cgroup.Resources{}
+
+	for _, svcrunner := range s.state {
+		svc, ok := svcrunner.service.(CgroupService)
+		if !ok {
+			continue
+		}
+
+		path, resources := svc.Cgroup(s.runtime)
+
+		if path == parent || strings.HasPrefix(path, parent+"/") {
+			total.Add(resources)
+		}
+	}
+
+	return total
+}
+
 // IsRunning checks service status (started/stopped).
 //
 // It doesn't check if service runner was started or not, just pure
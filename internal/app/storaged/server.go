@@ -11,6 +11,7 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/talos-systems/talos/pkg/machinery/api/storage"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 )
 
 // Server implements storage.StorageService.
@@ -36,7 +37,10 @@ func (s *Server) Disks(ctx context.Context, in *emptypb.Empty) (reply *storage.D
 			Name:       disk.Name,
 			Serial:     disk.Serial,
 			Modalias:   disk.Modalias,
+			Uuid:       disk.UUID,
+			Wwid:       disk.WWID,
 			Type:       storage.Disk_DiskType(disk.Type),
+			BusPath:    v1alpha1.DiskBusPath(disk.DeviceName),
 		}
 	}
 
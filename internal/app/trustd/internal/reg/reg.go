@@ -10,6 +10,7 @@ import (
 	"github.com/talos-systems/crypto/x509"
 	"google.golang.org/grpc"
 
+	"github.com/talos-systems/talos/pkg/grpc/gen"
 	securityapi "github.com/talos-systems/talos/pkg/machinery/api/security"
 	"github.com/talos-systems/talos/pkg/machinery/config"
 )
@@ -38,8 +39,9 @@ func (r *Registrator) Certificate(ctx context.Context, in *securityapi.Certifica
 		return
 	}
 
+	// return the CA trust bundle, so that the accepted CAs are trusted during the CA rotation
 	resp = &securityapi.CertificateResponse{
-		Ca:  r.Config.Machine().Security().CA().Crt,
+		Ca:  gen.AcceptedCABundle(r.Config.Machine().Security().CA().Crt, r.Config.Machine().Security().AcceptedCAs()),
 		Crt: signed.X509CertificatePEM,
 	}
 
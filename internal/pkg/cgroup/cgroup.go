@@ -0,0 +1,102 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package cgroup provides helpers to manage cgroups of Talos system services.
+package cgroup
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containerd/cgroups"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// sharesPerCPU is the number of CPU shares equivalent to a single CPU.
+const sharesPerCPU = 1024
+
+// Resources describes resource settings of the cgroup.
+type Resources struct {
+	// CPUShares is the relative CPU weight, 1024 shares stand for a single CPU.
+	CPUShares uint64
+	// MemoryReservation is the memory soft limit in bytes.
+	MemoryReservation int64
+	// MemoryLimit is the memory hard limit in bytes, zero means no limit.
+	MemoryLimit int64
+}
+
+// Add sums up resource settings.
+func (r *Resources) Add(other *Resources) {
+	if other == nil {
+		return
+	}
+
+	r.CPUShares += other.CPUShares
+	r.MemoryReservation += other.MemoryReservation
+	r.MemoryLimit += other.MemoryLimit
+}
+
+// MilliCPU returns CPU shares as a number of millicores.
+func (r *Resources) MilliCPU() int64 {
+	return int64(r.CPUShares) * 1000 / sharesPerCPU
+}
+
+// LinuxResources converts resource settings to OCI runtime spec.
+func (r *Resources) LinuxResources() *specs.LinuxResources {
+	resources := &specs.LinuxResources{}
+
+	if r == nil {
+		return resources
+	}
+
+	if r.CPUShares > 0 {
+		shares := r.CPUShares
+
+		resources.CPU = &specs.LinuxCPU{
+			Shares: &shares,
+		}
+	}
+
+	if r.MemoryReservation > 0 || r.MemoryLimit > 0 {
+		resources.Memory = &specs.LinuxMemory{}
+
+		if r.MemoryReservation > 0 {
+			reservation := r.MemoryReservation
+
+			resources.Memory.Reservation = &reservation
+		}
+
+		if r.MemoryLimit > 0 {
+			limit := r.MemoryLimit
+
+			resources.Memory.Limit = &limit
+		}
+	}
+
+	return resources
+}
+
+// Create the cgroup (or update the existing one) with the resource settings.
+func Create(path string, resources *Resources) (cgroups.Cgroup, error) {
+	cg, err := cgroups.New(cgroups.V1, cgroups.StaticPath(path), resources.LinuxResources())
+	if err != nil {
+		return nil, fmt.Errorf("error creating cgroup %q: %w", path, err)
+	}
+
+	return cg, nil
+}
+
+// Enter moves the current process into the existing cgroup.
+func Enter(path string) error {
+	cg, err := cgroups.Load(cgroups.V1, cgroups.StaticPath(path))
+	if err != nil {
+		return fmt.Errorf("error loading cgroup %q: %w", path, err)
+	}
+
+	if err = cg.Add(cgroups.Process{Pid: os.Getpid()}); err != nil {
+		return fmt.Errorf("error moving process %d to cgroup %q: %w", os.Getpid(), path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cgroup_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/internal/pkg/cgroup"
+)
+
+func TestResources(t *testing.T) {
+	var total cgroup.Resources
+
+	total.Add(&cgroup.Resources{CPUShares: 512, MemoryReservation: 64 * 1024 * 1024})
+	total.Add(&cgroup.Resources{CPUShares: 256, MemoryLimit: 512 * 1024 * 1024})
+	total.Add(nil)
+
+	assert.Equal(t, uint64(768), total.CPUShares)
+	assert.Equal(t, int64(750), total.MilliCPU())
+	assert.Equal(t, int64(64*1024*1024), total.MemoryReservation)
+
+	resources := (&cgroup.Resources{CPUShares: 128, MemoryLimit: 1024}).LinuxResources()
+
+	assert.Equal(t, uint64(128), *resources.CPU.Shares)
+	assert.Equal(t, int64(1024), *resources.Memory.Limit)
+	assert.Nil(t, resources.Memory.Reservation)
+
+	resources = (*cgroup.Resources)(nil).LinuxResources()
+
+	assert.Nil(t, resources.CPU)
+	assert.Nil(t, resources.Memory)
+}
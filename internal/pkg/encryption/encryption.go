@@ -233,13 +233,20 @@ func (h *Handler) updateKey(existingKey, newKey *encryption.Key, path string) er
 func getKeys(encryptionConfig config.Encryption, partition *gpt.Partition) ([]*encryption.Key, error) {
 	encryptionKeys := make([]*encryption.Key, len(encryptionConfig.Keys()))
 
+	label := partition.Name
+
+	// partitions of the user disks have no label, so the partition UUID is used to derive a unique key
+	if label == "" {
+		label = partition.ID.String()
+	}
+
 	for i, cfg := range encryptionConfig.Keys() {
 		handler, err := keys.NewHandler(cfg)
 		if err != nil {
 			return nil, err
 		}
 
-		k, err := handler.GetKey(keys.WithPartitionLabel(partition.Name))
+		k, err := handler.GetKey(keys.WithPartitionLabel(label))
 		if err != nil {
 			return nil, err
 		}
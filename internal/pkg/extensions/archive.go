@@ -0,0 +1,179 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package extensions
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// WriteArchive writes the extension images, the trusted keys and the config as the uncompressed cpio archive.
+//
+// The archive is appended to the Talos initramfs, so that the kernel unpacks it on top of it:
+// images go to the constants.ExtensionsPath, the keys go to the constants.ExtensionKeysPath,
+// and the config goes to constants.ExtensionsConfigFile.
+// Sources map extension image names to the paths on the host.
+func WriteArchive(w io.Writer, config *Config, sources map[string]string, trustedKeys [][]byte) error {
+	cw := &cpioWriter{w: w}
+
+	keysDir := strings.TrimPrefix(constants.ExtensionKeysPath, "/")
+
+	if err := cw.writeHeader(keysDir, 0o40755, 0); err != nil {
+		return err
+	}
+
+	// keys are named by the digest, so that keys already present in the initramfs are not overwritten
+	for _, key := range trustedKeys {
+		if err := cw.writeData(filepath.Join(keysDir, fmt.Sprintf("%x.pem", sha256.Sum256(key))), key); err != nil {
+			return err
+		}
+	}
+
+	extensionsDir := strings.TrimPrefix(constants.ExtensionsPath, "/")
+
+	if err := cw.writeHeader(extensionsDir, 0o40755, 0); err != nil {
+		return err
+	}
+
+	for _, ext := range config.Extensions {
+		source, ok := sources[ext.Image]
+		if !ok {
+			return fmt.Errorf("source for the extension image %q is missing", ext.Image)
+		}
+
+		if err := cw.writeFile(filepath.Join(extensionsDir, ext.Image), source); err != nil {
+			return err
+		}
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	if err = cw.writeData(strings.TrimPrefix(constants.ExtensionsConfigFile, "/"), data); err != nil {
+		return err
+	}
+
+	return cw.writeHeader("TRAILER!!!", 0, 0)
+}
+
+// AppendArchive appends the archive with the extensions to the initramfs.
+//
+// Kernel expects the appended archive to start at 4-byte boundary, so the initramfs is padded with zeroes.
+func AppendArchive(initramfs string, config *Config, sources map[string]string, trustedKeys [][]byte) error {
+	f, err := os.OpenFile(initramfs, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if rem := st.Size() % 4; rem != 0 {
+		if _, err = f.Write(make([]byte, 4-rem)); err != nil {
+			return err
+		}
+	}
+
+	if err = WriteArchive(f, config, sources, trustedKeys); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// cpioWriter writes "newc" cpio archive format.
+type cpioWriter struct {
+	w       io.Writer
+	written int64
+	ino     int
+}
+
+func (cw *cpioWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.written += int64(n)
+
+	return n, err
+}
+
+func (cw *cpioWriter) pad() error {
+	if rem := cw.written % 4; rem != 0 {
+		_, err := cw.Write(make([]byte, 4-rem))
+
+		return err
+	}
+
+	return nil
+}
+
+func (cw *cpioWriter) writeHeader(name string, mode uint32, size int64) error {
+	cw.ino++
+
+	nlink := 1
+	if mode&0o40000 != 0 {
+		nlink = 2
+	}
+
+	// magic, ino, mode, uid, gid, nlink, mtime, filesize, devmajor, devminor, rdevmajor, rdevminor, namesize, check
+	if _, err := fmt.Fprintf(cw, "070701%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X",
+		cw.ino, mode, 0, 0, nlink, 0, size, 0, 0, 0, 0, len(name)+1, 0); err != nil {
+		return err
+	}
+
+	if _, err := cw.Write(append([]byte(name), 0)); err != nil {
+		return err
+	}
+
+	return cw.pad()
+}
+
+func (cw *cpioWriter) writeData(name string, data []byte) error {
+	if err := cw.writeHeader(name, 0o100644, int64(len(data))); err != nil {
+		return err
+	}
+
+	if _, err := cw.Write(data); err != nil {
+		return err
+	}
+
+	return cw.pad()
+}
+
+func (cw *cpioWriter) writeFile(name, source string) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err = cw.writeHeader(name, 0o100644, st.Size()); err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(cw, f); err != nil {
+		return fmt.Errorf("error copying %q: %w", source, err)
+	}
+
+	return cw.pad()
+}
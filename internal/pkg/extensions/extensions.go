@@ -0,0 +1,233 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package extensions implements system extensions layered on top of the Talos rootfs.
+//
+// System extension is a squashfs image with the manifest (manifest.yaml) and the rootfs/
+// directory which contents are layered over the Talos rootfs on boot.
+// Extension image is signed with Ed25519 key: the signature of the SHA-256 digest
+// of the image is stored next to it in the file with .sig suffix.
+package extensions
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ManifestFile is the path to the manifest in the extension image.
+	ManifestFile = "manifest.yaml"
+	// RootfsPath is the path to the files layered over the Talos rootfs in the extension image.
+	RootfsPath = "rootfs"
+	// SignatureSuffix is appended to the extension image path to find the signature.
+	SignatureSuffix = ".sig"
+
+	manifestVersion = "v1alpha1"
+)
+
+// AllowedPaths lists rootfs paths extensions can add files to.
+var AllowedPaths = []string{
+	"lib/firmware",
+	"lib/modules",
+	"usr/local",
+}
+
+var nameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// Manifest is the extension manifest.
+type Manifest struct {
+	Version  string   `yaml:"version"`
+	Metadata Metadata `yaml:"metadata"`
+}
+
+// Metadata describes the extension.
+type Metadata struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Author      string `yaml:"author,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// Config is the list of the extensions layered into the initramfs by the installer.
+type Config struct {
+	Extensions []Extension `yaml:"extensions"`
+}
+
+// Extension describes the extension image in the initramfs.
+type Extension struct {
+	// Image is the file name of the image in the extensions directory.
+	Image    string   `yaml:"image"`
+	Metadata Metadata `yaml:"metadata"`
+	// Digest is the hex-encoded SHA-256 digest of the image.
+	Digest string `yaml:"digest"`
+	// Signature is the base64-encoded Ed25519 signature of the digest.
+	Signature string `yaml:"signature"`
+	// PublicKey is the PEM-encoded trusted key which matched the signature on boot.
+	//
+	// It's only reported in the status, as the key from the config can't be trusted.
+	PublicKey string `yaml:"publicKey,omitempty"`
+}
+
+// Status is the status of the extensions written on boot.
+type Status struct {
+	Extensions []ExtensionStatus `yaml:"extensions"`
+}
+
+// ExtensionStatus describes the extension layered over the rootfs.
+type ExtensionStatus struct {
+	Extension `yaml:",inline"`
+
+	Loaded bool   `yaml:"loaded"`
+	Error  string `yaml:"error,omitempty"`
+}
+
+// ParseManifest parses and validates the extension manifest.
+func ParseManifest(data []byte) (*Manifest, error) {
+	var manifest Manifest
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("error parsing extension manifest: %w", err)
+	}
+
+	if manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported extension manifest version %q", manifest.Version)
+	}
+
+	if !nameRe.MatchString(manifest.Metadata.Name) {
+		return nil, fmt.Errorf("invalid extension name %q", manifest.Metadata.Name)
+	}
+
+	if manifest.Metadata.Version == "" {
+		return nil, fmt.Errorf("extension %q version is not set", manifest.Metadata.Name)
+	}
+
+	return &manifest, nil
+}
+
+// ValidateLayout checks that the extension image contains only the manifest and the allowed rootfs paths.
+//
+// Paths are relative to the root of the image.
+func ValidateLayout(paths []string) error {
+	hasManifest := false
+
+	for _, path := range paths {
+		path = strings.Trim(filepath.Clean(path), "/")
+
+		switch {
+		case path == "" || path == "." || path == RootfsPath:
+		case path == ManifestFile:
+			hasManifest = true
+		case strings.HasPrefix(path, RootfsPath+"/"):
+			if !allowedPath(strings.TrimPrefix(path, RootfsPath+"/")) {
+				return fmt.Errorf("path %q is not allowed in the extension, allowed paths: %s", path, strings.Join(AllowedPaths, ", "))
+			}
+		default:
+			return fmt.Errorf("unexpected path %q in the extension", path)
+		}
+	}
+
+	if !hasManifest {
+		return fmt.Errorf("extension manifest %q is missing", ManifestFile)
+	}
+
+	return nil
+}
+
+// allowedPath checks whether the path is one of the allowed paths, its parent or a child.
+func allowedPath(path string) bool {
+	for _, allowed := range AllowedPaths {
+		if path == allowed || strings.HasPrefix(allowed, path+"/") || strings.HasPrefix(path, allowed+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LoadConfig loads the list of the extensions.
+func LoadConfig(path string) (*Config, error) {
+	var config Config
+
+	if err := load(path, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// LoadTrustedKeys loads PEM-encoded public keys (*.pem) from the directory.
+func LoadTrustedKeys(dir string) ([][]byte, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+
+	keys := make([][]byte, 0, len(paths))
+
+	for _, path := range paths {
+		key, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = ParsePublicKey(key); err != nil {
+			return nil, fmt.Errorf("error loading %q: %w", path, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// LoadStatus loads the status of the extensions.
+func LoadStatus(path string) (*Status, error) {
+	var status Status
+
+	if err := load(path, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+func load(path string, out interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err = yaml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("error parsing %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// Write the status of the extensions.
+func (status *Status) Write(path string) error {
+	data, err := yaml.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0o644)
+}
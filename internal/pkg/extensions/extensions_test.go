@@ -0,0 +1,279 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package extensions_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/extensions"
+)
+
+func TestParseManifest(t *testing.T) {
+	manifest, err := extensions.ParseManifest([]byte(`version: v1alpha1
+metadata:
+  name: intel-ucode
+  version: 20210608
+  author: Talos
+  description: Intel microcode
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, extensions.Metadata{
+		Name:        "intel-ucode",
+		Version:     "20210608",
+		Author:      "Talos",
+		Description: "Intel microcode",
+	}, manifest.Metadata)
+
+	for _, invalid := range []string{
+		"version: v1alpha2\nmetadata:\n  name: foo\n  version: 1\n",
+		"version: v1alpha1\nmetadata:\n  name: Foo_Bar\n  version: 1\n",
+		"version: v1alpha1\nmetadata:\n  name: foo\n",
+		"version: v1alpha1\nmetadata:\n  name: foo\n  version: 1\n  unknown: bar\n",
+	} {
+		_, err = extensions.ParseManifest([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestValidateLayout(t *testing.T) {
+	assert.NoError(t, extensions.ValidateLayout([]string{
+		"/",
+		"/manifest.yaml",
+		"/rootfs",
+		"/rootfs/lib",
+		"/rootfs/lib/firmware",
+		"/rootfs/lib/firmware/intel-ucode/06-55-04",
+		"/rootfs/usr",
+		"/rootfs/usr/local/bin/tool",
+	}))
+
+	assert.EqualError(t, extensions.ValidateLayout([]string{
+		"/rootfs/lib/firmware/blob",
+	}), `extension manifest "manifest.yaml" is missing`)
+
+	assert.EqualError(t, extensions.ValidateLayout([]string{
+		"/manifest.yaml",
+		"/rootfs/usr/bin/tool",
+	}), `path "rootfs/usr/bin/tool" is not allowed in the extension, allowed paths: lib/firmware, lib/modules, usr/local`)
+
+	assert.EqualError(t, extensions.ValidateLayout([]string{
+		"/manifest.yaml",
+		"/README",
+	}), `unexpected path "README" in the extension`)
+}
+
+func TestVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	otherPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keyPEM := marshalPublicKey(t, publicKey)
+	otherKeyPEM := marshalPublicKey(t, otherPublicKey)
+
+	image := filepath.Join(t.TempDir(), "ext.sqsh")
+	require.NoError(t, ioutil.WriteFile(image, []byte("image"), 0o600))
+
+	digest, err := extensions.Digest(image)
+	require.NoError(t, err)
+
+	signature := ed25519.Sign(privateKey, digest)
+
+	matched, err := extensions.Verify(digest, signature, [][]byte{otherKeyPEM, keyPEM})
+	require.NoError(t, err)
+	assert.Equal(t, keyPEM, matched)
+
+	_, err = extensions.Verify(digest, signature, [][]byte{otherKeyPEM})
+	assert.Error(t, err)
+
+	ext := extensions.Extension{
+		Image:     "ext.sqsh",
+		Metadata:  extensions.Metadata{Name: "ext", Version: "1"},
+		Digest:    hex.EncodeToString(digest),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+		PublicKey: string(keyPEM),
+	}
+
+	matched, err = ext.Verify(image, [][]byte{otherKeyPEM, keyPEM})
+	require.NoError(t, err)
+	assert.Equal(t, keyPEM, matched)
+
+	// the key from the config is not trusted
+	_, err = ext.Verify(image, [][]byte{otherKeyPEM})
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(image, []byte("tampered"), 0o600))
+
+	_, err = ext.Verify(image, [][]byte{keyPEM})
+	assert.Error(t, err)
+}
+
+func TestLoadTrustedKeys(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keyPEM := marshalPublicKey(t, publicKey)
+
+	dir := t.TempDir()
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "vendor.pem"), keyPEM, 0o644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("not a key"), 0o644))
+
+	keys, err := extensions.LoadTrustedKeys(dir)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{keyPEM}, keys)
+
+	keys, err = extensions.LoadTrustedKeys(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0o644))
+
+	_, err = extensions.LoadTrustedKeys(dir)
+	assert.Error(t, err)
+}
+
+func marshalPublicKey(t *testing.T, key ed25519.PublicKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestWriteArchive(t *testing.T) {
+	image := filepath.Join(t.TempDir(), "image")
+	require.NoError(t, ioutil.WriteFile(image, []byte("squashfs"), 0o600))
+
+	config := &extensions.Config{
+		Extensions: []extensions.Extension{
+			{
+				Image:    "ext.sqsh",
+				Metadata: extensions.Metadata{Name: "ext", Version: "1"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	key := []byte("key")
+
+	require.NoError(t, extensions.WriteArchive(&buf, config, map[string]string{"ext.sqsh": image}, [][]byte{key}))
+
+	files := readArchive(t, buf.Bytes())
+
+	require.Len(t, files, 6)
+
+	assert.Equal(t, "extension-keys", files[0].name)
+	assert.EqualValues(t, 0o40755, files[0].mode)
+
+	assert.Equal(t, fmt.Sprintf("extension-keys/%x.pem", sha256.Sum256(key)), files[1].name)
+	assert.Equal(t, key, files[1].data)
+
+	assert.Equal(t, "extensions", files[2].name)
+	assert.EqualValues(t, 0o40755, files[2].mode)
+
+	assert.Equal(t, "extensions/ext.sqsh", files[3].name)
+	assert.Equal(t, "squashfs", string(files[3].data))
+
+	assert.Equal(t, "extensions.yaml", files[4].name)
+	assert.Contains(t, string(files[4].data), "image: ext.sqsh")
+
+	assert.Equal(t, "TRAILER!!!", files[5].name)
+
+	assert.Error(t, extensions.WriteArchive(&buf, config, nil, nil))
+}
+
+type cpioFile struct {
+	name string
+	mode uint64
+	data []byte
+}
+
+// readArchive parses "newc" cpio archive verifying the alignment.
+func readArchive(t *testing.T, data []byte) []cpioFile {
+	var files []cpioFile
+
+	align := func(n int) int {
+		return (n + 3) &^ 3
+	}
+
+	field := func(header []byte, i int) uint64 {
+		v, err := strconv.ParseUint(string(header[6+i*8:6+(i+1)*8]), 16, 32)
+		require.NoError(t, err)
+
+		return v
+	}
+
+	for offset := 0; offset < len(data); {
+		require.Zero(t, offset%4)
+
+		header := data[offset : offset+110]
+		require.Equal(t, "070701", string(header[:6]))
+
+		mode := field(header, 1)
+		size := int(field(header, 6))
+		nameSize := int(field(header, 11))
+
+		name := string(data[offset+110 : offset+110+nameSize-1])
+		offset = align(offset + 110 + nameSize)
+
+		files = append(files, cpioFile{
+			name: name,
+			mode: mode,
+			data: data[offset : offset+size],
+		})
+
+		offset = align(offset + size)
+	}
+
+	return files
+}
+
+func TestStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "system", "extensions.yaml")
+
+	status := &extensions.Status{
+		Extensions: []extensions.ExtensionStatus{
+			{
+				Extension: extensions.Extension{
+					Image:    "ext.sqsh",
+					Metadata: extensions.Metadata{Name: "ext", Version: "1"},
+				},
+				Loaded: true,
+			},
+			{
+				Extension: extensions.Extension{
+					Image:    "broken.sqsh",
+					Metadata: extensions.Metadata{Name: "broken", Version: "2"},
+				},
+				Error: "digest mismatch",
+			},
+		},
+	}
+
+	require.NoError(t, status.Write(path))
+
+	loaded, err := extensions.LoadStatus(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, status, loaded)
+}
@@ -0,0 +1,136 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package extensions
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+	"gopkg.in/freddierice/go-losetup.v1"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Mount verifies the extensions listed in the config and layers them over the rootfs mounted at root.
+//
+// Extension signatures are verified against the trusted keys from keysPath.
+// Extensions which fail verification or mounting are skipped, the result is recorded
+// to the status file, so that it can be inspected once the system is booted.
+// Missing config means that no extensions were installed.
+func Mount(configPath, keysPath, root string) error {
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	// extensions fail verification without the trusted keys, the boot is not interrupted
+	trustedKeys, err := LoadTrustedKeys(keysPath)
+	if err != nil {
+		log.Printf("error loading trusted extension keys: %s", err)
+	}
+
+	status := &Status{}
+
+	var mounted []string
+
+	for _, ext := range config.Extensions {
+		ext.PublicKey = ""
+
+		extStatus := ExtensionStatus{
+			Extension: ext,
+		}
+
+		target := filepath.Join(constants.ExtensionsRuntimePath, ext.Metadata.Name)
+
+		if extStatus.PublicKey, err = mountImage(filepath.Join(constants.ExtensionsPath, ext.Image), target, ext, trustedKeys); err != nil {
+			log.Printf("skipping extension %q: %s", ext.Metadata.Name, err)
+
+			extStatus.Error = err.Error()
+		} else {
+			log.Printf("loaded extension %q version %s", ext.Metadata.Name, ext.Metadata.Version)
+
+			extStatus.Loaded = true
+
+			mounted = append(mounted, target)
+		}
+
+		status.Extensions = append(status.Extensions, extStatus)
+	}
+
+	if err = overlay(root, mounted); err != nil {
+		return err
+	}
+
+	return status.Write(constants.ExtensionsRuntimeConfigFile)
+}
+
+// mountImage returns the trusted key which matched the extension signature.
+func mountImage(image, target string, ext Extension, trustedKeys [][]byte) (string, error) {
+	key, err := ext.Verify(image, trustedKeys)
+	if err != nil {
+		return "", err
+	}
+
+	dev, err := losetup.Attach(image, 0, true)
+	if err != nil {
+		return "", fmt.Errorf("error attaching loop device: %w", err)
+	}
+
+	if err = os.MkdirAll(target, 0o700); err != nil {
+		return "", err
+	}
+
+	if err = unix.Mount(dev.Path(), target, "squashfs", unix.MS_RDONLY, ""); err != nil {
+		dev.Detach() //nolint:errcheck
+
+		return "", fmt.Errorf("error mounting %q: %w", image, err)
+	}
+
+	return string(key), nil
+}
+
+// overlay mounts read-only overlay over each of the allowed paths, extensions loaded later take precedence.
+func overlay(root string, mounted []string) error {
+	for _, path := range AllowedPaths {
+		target := filepath.Join(root, path)
+
+		var lowerDirs []string
+
+		for i := len(mounted) - 1; i >= 0; i-- {
+			dir := filepath.Join(mounted[i], RootfsPath, path)
+
+			if _, err := os.Stat(dir); err == nil {
+				lowerDirs = append(lowerDirs, dir)
+			}
+		}
+
+		if len(lowerDirs) == 0 {
+			continue
+		}
+
+		if _, err := os.Stat(target); err != nil {
+			log.Printf("skipping %q provided by extensions: %s", path, err)
+
+			continue
+		}
+
+		lowerDirs = append(lowerDirs, target)
+
+		if err := unix.Mount("overlay", target, "overlay", unix.MS_RDONLY, "lowerdir="+strings.Join(lowerDirs, ":")); err != nil {
+			return fmt.Errorf("error mounting extensions overlay to %q: %w", target, err)
+		}
+	}
+
+	return nil
+}
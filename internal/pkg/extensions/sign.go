@@ -0,0 +1,102 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package extensions
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Digest calculates the SHA-256 digest of the extension image.
+func Digest(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	h := sha256.New()
+
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, fmt.Errorf("error reading %q: %w", path, err)
+	}
+
+	return h.Sum(nil), nil
+}
+
+// ParsePublicKey parses PEM-encoded PKIX Ed25519 public key.
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to parse public key PEM")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T, only Ed25519 keys are supported", key)
+	}
+
+	return publicKey, nil
+}
+
+// Verify checks the signature of the digest against the list of PEM-encoded public keys.
+//
+// Verify returns the PEM-encoded key which matched the signature.
+func Verify(digest, signature []byte, keys [][]byte) ([]byte, error) {
+	for _, keyPEM := range keys {
+		key, err := ParsePublicKey(keyPEM)
+		if err != nil {
+			return nil, err
+		}
+
+		if ed25519.Verify(key, digest, signature) {
+			return keyPEM, nil
+		}
+	}
+
+	return nil, errors.New("signature doesn't match any of the trusted keys")
+}
+
+// Verify the extension image: digest should match the recorded one, and the signature should be valid.
+//
+// Signature is verified only against the trusted keys, the key recorded in the config is ignored,
+// as anyone who can change the extension can change the config as well.
+// Verify returns the PEM-encoded trusted key which matched the signature.
+func (ext *Extension) Verify(path string, trustedKeys [][]byte) ([]byte, error) {
+	digest, err := Digest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if hex.EncodeToString(digest) != ext.Digest {
+		return nil, fmt.Errorf("extension %q digest mismatch: expected %s, got %x", ext.Metadata.Name, ext.Digest, digest)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(ext.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("extension %q signature is malformed: %w", ext.Metadata.Name, err)
+	}
+
+	key, err := Verify(digest, signature, trustedKeys)
+	if err != nil {
+		return nil, fmt.Errorf("extension %q: %w", ext.Metadata.Name, err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,157 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package fsck checks and repairs filesystems via xfs_repair and e2fsck.
+package fsck
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/talos-systems/talos/internal/pkg/partition"
+)
+
+// Mode of the filesystem check.
+type Mode int
+
+const (
+	// Check reports the problems without modifying the filesystem.
+	Check Mode = iota
+	// Repair fixes the problems which can be fixed without discarding data.
+	Repair
+	// Force fixes all the problems, discarding the data if required (e.g. zeroing the XFS log).
+	Force
+)
+
+// ErrDirtyLog is returned when the XFS log contains changes which should be replayed by mounting the filesystem.
+var ErrDirtyLog = errors.New("filesystem log contains changes which can't be replayed, repair in force mode zeroes the log")
+
+// Checker runs the filesystem tools.
+type Checker struct {
+	// Run runs the command and returns its combined output and the exit code.
+	//
+	// Error is returned only if the command can't be run.
+	Run func(name string, args ...string) (output string, exitCode int, err error)
+}
+
+// New returns Checker which runs the filesystem tools shipped with Talos.
+func New() *Checker {
+	return &Checker{
+		Run: run,
+	}
+}
+
+// Supported returns true if the filesystem can be checked.
+func Supported(fstype string) bool {
+	return fstype == partition.FilesystemTypeXFS || fstype == partition.FilesystemTypeExt4
+}
+
+// Check runs the filesystem check on the device.
+//
+// Error is returned if the filesystem has problems which were not fixed.
+func (c *Checker) Check(device, fstype string, mode Mode) error {
+	switch fstype {
+	case partition.FilesystemTypeXFS:
+		return c.xfs(device, mode)
+	case partition.FilesystemTypeExt4:
+		return c.ext4(device, mode)
+	default:
+		return fmt.Errorf("filesystem %q is not supported", fstype)
+	}
+}
+
+func (c *Checker) xfs(device string, mode Mode) error {
+	var args []string
+
+	switch mode {
+	case Check:
+		args = append(args, "-n")
+	case Repair:
+	case Force:
+		args = append(args, "-L")
+	}
+
+	output, exitCode, err := c.Run("xfs_repair", append(args, device)...)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case exitCode == 0:
+		return nil
+	case exitCode == 1 && mode == Check:
+		return fmt.Errorf("filesystem has errors: %s", lastLine(output))
+	case exitCode == 2:
+		return ErrDirtyLog
+	default:
+		return fmt.Errorf("xfs_repair exited with code %d: %s", exitCode, lastLine(output))
+	}
+}
+
+// e2fsck exit codes.
+const (
+	e2fsckCorrected       = 1
+	e2fsckReboot          = 2
+	e2fsckUncorrected     = 4
+	e2fsckOperationalMask = ^(e2fsckCorrected | e2fsckReboot | e2fsckUncorrected)
+)
+
+func (c *Checker) ext4(device string, mode Mode) error {
+	args := []string{"-f"}
+
+	switch mode {
+	case Check:
+		args = append(args, "-n")
+	case Repair:
+		args = append(args, "-p")
+	case Force:
+		args = append(args, "-y")
+	}
+
+	output, exitCode, err := c.Run("e2fsck", append(args, device)...)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case exitCode&e2fsckOperationalMask != 0:
+		return fmt.Errorf("e2fsck exited with code %d: %s", exitCode, lastLine(output))
+	case exitCode&e2fsckUncorrected != 0:
+		return fmt.Errorf("filesystem has errors: %s", lastLine(output))
+	default:
+		return nil
+	}
+}
+
+func run(name string, args ...string) (string, int, error) {
+	var output bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+
+	if errors.As(err, &exitErr) {
+		return output.String(), exitErr.ExitCode(), nil
+	}
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	return output.String(), 0, nil
+}
+
+// lastLine returns the last non-empty line of the tool output, which usually summarizes the result.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,114 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fsck_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/fsck"
+)
+
+type fakeTool struct {
+	output   string
+	exitCode int
+	commands []string
+}
+
+func (f *fakeTool) run(name string, args ...string) (string, int, error) {
+	f.commands = append(f.commands, strings.Join(append([]string{name}, args...), " "))
+
+	return f.output, f.exitCode, nil
+}
+
+func TestCheck(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		fstype   string
+		mode     fsck.Mode
+		exitCode int
+		output   string
+
+		expectedCommand string
+		expectedError   string
+	}{
+		{
+			name:            "xfs clean",
+			fstype:          "xfs",
+			mode:            fsck.Check,
+			expectedCommand: "xfs_repair -n /dev/sdb1",
+		},
+		{
+			name:            "xfs corrupted",
+			fstype:          "xfs",
+			mode:            fsck.Check,
+			exitCode:        1,
+			output:          "Phase 1 - find and verify superblock...\nbad magic number\n",
+			expectedCommand: "xfs_repair -n /dev/sdb1",
+			expectedError:   "filesystem has errors: bad magic number",
+		},
+		{
+			name:            "xfs dirty log",
+			fstype:          "xfs",
+			mode:            fsck.Repair,
+			exitCode:        2,
+			expectedCommand: "xfs_repair /dev/sdb1",
+			expectedError:   fsck.ErrDirtyLog.Error(),
+		},
+		{
+			name:            "xfs force",
+			fstype:          "xfs",
+			mode:            fsck.Force,
+			expectedCommand: "xfs_repair -L /dev/sdb1",
+		},
+		{
+			name:            "ext4 errors found",
+			fstype:          "ext4",
+			mode:            fsck.Check,
+			exitCode:        4,
+			output:          "/dev/sdb1: ********** WARNING: Filesystem still has errors **********\n",
+			expectedCommand: "e2fsck -f -n /dev/sdb1",
+			expectedError:   "filesystem has errors: /dev/sdb1: ********** WARNING: Filesystem still has errors **********",
+		},
+		{
+			name:            "ext4 corrected",
+			fstype:          "ext4",
+			mode:            fsck.Repair,
+			exitCode:        1,
+			expectedCommand: "e2fsck -f -p /dev/sdb1",
+		},
+		{
+			name:            "ext4 operational error",
+			fstype:          "ext4",
+			mode:            fsck.Force,
+			exitCode:        8,
+			output:          "e2fsck: Bad magic number in super-block while trying to open /dev/sdb1\n",
+			expectedCommand: "e2fsck -f -y /dev/sdb1",
+			expectedError:   "e2fsck exited with code 8: e2fsck: Bad magic number in super-block while trying to open /dev/sdb1",
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &fakeTool{output: tt.output, exitCode: tt.exitCode}
+
+			err := (&fsck.Checker{Run: tool.run}).Check("/dev/sdb1", tt.fstype, tt.mode)
+
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, []string{tt.expectedCommand}, tool.commands)
+		})
+	}
+
+	assert.Error(t, fsck.New().Check("/dev/sdb1", "vfat", fsck.Check))
+	assert.False(t, fsck.Supported("vfat"))
+}
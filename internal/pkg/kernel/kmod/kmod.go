@@ -0,0 +1,256 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package kmod implements loading kernel modules shipped with the Talos image.
+package kmod
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// ModulesPath is the path to the directory with kernel modules.
+const ModulesPath = "/lib/modules"
+
+// SysModulePath is the path to the sysfs directory with the loaded kernel modules.
+const SysModulePath = "/sys/module"
+
+// Index of the kernel modules shipped with the kernel.
+type Index struct {
+	root    string
+	deps    map[string][]string
+	builtin map[string]struct{}
+}
+
+// DefaultRoot returns the modules directory for the running kernel.
+func DefaultRoot() (string, error) {
+	var uname unix.Utsname
+
+	if err := unix.Uname(&uname); err != nil {
+		return "", fmt.Errorf("error getting kernel release: %w", err)
+	}
+
+	return filepath.Join(ModulesPath, unix.ByteSliceToString(uname.Release[:])), nil
+}
+
+// NewIndex builds the index of the kernel modules from modules.dep and modules.builtin in the root directory.
+//
+// Additional modules.dep.d/*.dep files (modules.dep format) are merged into the index.
+func NewIndex(root string) (*Index, error) {
+	idx := &Index{
+		root:    root,
+		deps:    map[string][]string{},
+		builtin: map[string]struct{}{},
+	}
+
+	readDeps := func(line string) error {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid modules.dep line %q", line)
+		}
+
+		idx.deps[ModuleName(parts[0])] = append([]string{parts[0]}, strings.Fields(parts[1])...)
+
+		return nil
+	}
+
+	if err := readLines(filepath.Join(root, "modules.dep"), readDeps); err != nil {
+		return nil, err
+	}
+
+	// modules shipped with system extensions are listed in modules.dep.d
+	extraDeps, err := filepath.Glob(filepath.Join(root, "modules.dep.d", "*.dep"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range extraDeps {
+		if err = readLines(path, readDeps); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = readLines(filepath.Join(root, "modules.builtin"), func(line string) error {
+		idx.builtin[ModuleName(line)] = struct{}{}
+
+		return nil
+	}); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+func readLines(path string, f func(line string) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" {
+			continue
+		}
+
+		if err = f(line); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// ModuleName returns normalized module name from the module path or name.
+//
+// Dashes and underscores are interchangeable in the module names.
+func ModuleName(path string) string {
+	name := filepath.Base(path)
+
+	if idx := strings.Index(name, ".ko"); idx != -1 {
+		name = name[:idx]
+	}
+
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// Has checks whether the module is shipped with the kernel (either as loadable module or built-in).
+func (idx *Index) Has(name string) bool {
+	name = ModuleName(name)
+
+	if _, ok := idx.builtin[name]; ok {
+		return true
+	}
+
+	_, ok := idx.deps[name]
+
+	return ok
+}
+
+// Builtin checks whether the module is built into the kernel.
+func (idx *Index) Builtin(name string) bool {
+	_, ok := idx.builtin[ModuleName(name)]
+
+	return ok
+}
+
+// Dependencies returns paths to the module files which should be loaded (in order) to load the module.
+//
+// The last path is the module itself.
+func (idx *Index) Dependencies(name string) ([]string, error) {
+	deps, ok := idx.deps[ModuleName(name)]
+	if !ok {
+		return nil, fmt.Errorf("module %q is not shipped with the kernel", name)
+	}
+
+	// modules.dep lists all (transitive) dependencies, the dependency which should be loaded first goes last
+	paths := make([]string, 0, len(deps))
+
+	for i := len(deps) - 1; i >= 1; i-- {
+		paths = append(paths, filepath.Join(idx.root, deps[i]))
+	}
+
+	return append(paths, filepath.Join(idx.root, deps[0])), nil
+}
+
+// Load loads the module with its dependencies, parameters are applied to the module itself.
+//
+// Dependencies which are already loaded are skipped. If the module itself is already loaded or built into the kernel,
+// the parameters are written via sysfs, and an error is returned if some parameter can't be changed.
+func (idx *Index) Load(name string, parameters []string) error {
+	if idx.Builtin(name) {
+		return ApplyParameters(SysModulePath, name, parameters)
+	}
+
+	paths, err := idx.Dependencies(name)
+	if err != nil {
+		return err
+	}
+
+	for i, path := range paths {
+		var params string
+
+		if i == len(paths)-1 {
+			params = strings.Join(parameters, " ")
+		}
+
+		var loaded bool
+
+		if loaded, err = load(path, params); err != nil {
+			return err
+		}
+
+		if !loaded && i == len(paths)-1 {
+			return ApplyParameters(SysModulePath, name, parameters)
+		}
+	}
+
+	return nil
+}
+
+// ApplyParameters writes the parameters of the loaded module to the sysfs directory root (usually SysModulePath).
+//
+// Parameters are in the `key=value` form, parameters without a value are set to `1`.
+func ApplyParameters(root, name string, parameters []string) error {
+	for _, parameter := range parameters {
+		key, value := parameter, "1"
+
+		if idx := strings.Index(parameter, "="); idx != -1 {
+			key, value = parameter[:idx], parameter[idx+1:]
+		}
+
+		if err := writeParameter(filepath.Join(root, ModuleName(name), "parameters", key), value); err != nil {
+			return fmt.Errorf("module %q is already loaded, failed to apply parameter %q: %w", ModuleName(name), key, err)
+		}
+	}
+
+	return nil
+}
+
+// writeParameter doesn't create the file, as sysfs only has files for the parameters supported by the module.
+func writeParameter(path, value string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.WriteString(value); err != nil {
+		f.Close() //nolint:errcheck
+
+		return err
+	}
+
+	return f.Close()
+}
+
+// load returns false if the module is already loaded.
+func load(path, params string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("error opening module: %w", err)
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	if err = unix.FinitModule(int(f.Fd()), params, 0); err != nil {
+		if errors.Is(err, unix.EEXIST) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("error loading module %q: %w", ModuleName(path), err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,117 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kmod_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/kernel/kmod"
+)
+
+const modulesDep = `kernel/drivers/md/dm-thin-pool.ko: kernel/drivers/md/persistent-data/dm-persistent-data.ko kernel/drivers/md/dm-bio-prison.ko kernel/drivers/md/dm-bufio.ko
+kernel/drivers/md/persistent-data/dm-persistent-data.ko: kernel/drivers/md/dm-bufio.ko
+kernel/drivers/md/dm-bio-prison.ko:
+kernel/drivers/md/dm-bufio.ko:
+kernel/drivers/nvme/host/nvme-tcp.ko: kernel/drivers/nvme/host/nvme-fabrics.ko
+kernel/drivers/nvme/host/nvme-fabrics.ko:
+`
+
+const modulesBuiltin = `kernel/net/bridge/br_netfilter.ko
+`
+
+const extensionModulesDep = `extra/drbd.ko: kernel/drivers/md/dm-bufio.ko
+`
+
+func TestIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "modules.dep"), []byte(modulesDep), 0o644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "modules.builtin"), []byte(modulesBuiltin), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "modules.dep.d"), 0o755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "modules.dep.d", "drbd.dep"), []byte(extensionModulesDep), 0o644))
+
+	idx, err := kmod.NewIndex(dir)
+	require.NoError(t, err)
+
+	assert.True(t, idx.Has("nvme_tcp"))
+	assert.True(t, idx.Has("nvme-tcp"))
+	assert.True(t, idx.Has("dm_thin_pool"))
+	assert.True(t, idx.Has("br_netfilter"))
+	assert.False(t, idx.Has("ip_vs"))
+
+	assert.True(t, idx.Builtin("br_netfilter"))
+	assert.False(t, idx.Builtin("nvme_tcp"))
+
+	paths, err := idx.Dependencies("dm_thin_pool")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "kernel/drivers/md/dm-bufio.ko"),
+		filepath.Join(dir, "kernel/drivers/md/dm-bio-prison.ko"),
+		filepath.Join(dir, "kernel/drivers/md/persistent-data/dm-persistent-data.ko"),
+		filepath.Join(dir, "kernel/drivers/md/dm-thin-pool.ko"),
+	}, paths)
+
+	paths, err = idx.Dependencies("drbd")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "kernel/drivers/md/dm-bufio.ko"),
+		filepath.Join(dir, "extra/drbd.ko"),
+	}, paths)
+
+	_, err = idx.Dependencies("ip_vs")
+	assert.Error(t, err)
+
+	// built-in modules are not loaded
+	assert.NoError(t, idx.Load("br_netfilter", nil))
+}
+
+func TestModuleName(t *testing.T) {
+	for path, expected := range map[string]string{
+		"kernel/drivers/nvme/host/nvme-tcp.ko":  "nvme_tcp",
+		"kernel/net/netfilter/ipvs/ip_vs.ko.xz": "ip_vs",
+		"dm-thin-pool":                          "dm_thin_pool",
+	} {
+		assert.Equal(t, expected, kmod.ModuleName(path))
+	}
+}
+
+func TestApplyParameters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nvme_core", "parameters"), 0o755))
+
+	for _, param := range []string{"multipath", "io_timeout"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "nvme_core", "parameters", param), []byte("0"), 0o644))
+	}
+
+	require.NoError(t, kmod.ApplyParameters(dir, "nvme-core", []string{"multipath", "io_timeout=4294967295"}))
+
+	for param, expected := range map[string]string{
+		"multipath":  "1",
+		"io_timeout": "4294967295",
+	} {
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "nvme_core", "parameters", param))
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, string(contents))
+	}
+
+	// parameters which are not exposed via sysfs can't be changed
+	err = kmod.ApplyParameters(dir, "nvme_core", []string{"admin_timeout=60"})
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "nvme_core", "parameters", "admin_timeout"))
+}
@@ -0,0 +1,115 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package pstore implements saving and reading kernel crash records (oops, panic) stored via pstore.
+package pstore
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Record is a single kernel crash record.
+type Record struct {
+	Name string
+	Data []byte
+}
+
+// Archive moves records from the pstore filesystem to the archive directory.
+//
+// Archive directory is cleaned up first, so that it contains only the records
+// which were captured before the last reboot.
+// Records are removed from the pstore to free up the backend storage.
+func Archive(pstorePath, archivePath string) (int, error) {
+	if err := os.RemoveAll(archivePath); err != nil {
+		return 0, fmt.Errorf("error cleaning up archive directory: %w", err)
+	}
+
+	if err := os.MkdirAll(archivePath, 0o700); err != nil {
+		return 0, fmt.Errorf("error creating archive directory: %w", err)
+	}
+
+	entries, err := ioutil.ReadDir(pstorePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("error reading pstore: %w", err)
+	}
+
+	var (
+		result   *multierror.Error
+		archived int
+	)
+
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+
+		src := filepath.Join(pstorePath, entry.Name())
+
+		data, err := ioutil.ReadFile(src)
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("error reading record %q: %w", entry.Name(), err))
+
+			continue
+		}
+
+		if err = ioutil.WriteFile(filepath.Join(archivePath, entry.Name()), data, 0o600); err != nil {
+			result = multierror.Append(result, fmt.Errorf("error saving record %q: %w", entry.Name(), err))
+
+			continue
+		}
+
+		archived++
+
+		if err = os.Remove(src); err != nil {
+			result = multierror.Append(result, fmt.Errorf("error removing record %q from pstore: %w", entry.Name(), err))
+		}
+	}
+
+	return archived, result.ErrorOrNil()
+}
+
+// Records returns the archived records sorted by name.
+func Records(archivePath string) ([]Record, error) {
+	entries, err := ioutil.ReadDir(archivePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(entries))
+
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(archivePath, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, Record{
+			Name: entry.Name(),
+			Data: data,
+		})
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+
+	return records, nil
+}
@@ -0,0 +1,73 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package pstore_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/kernel/pstore"
+)
+
+func TestArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	pstorePath := filepath.Join(dir, "pstore")
+	archivePath := filepath.Join(dir, "archive")
+
+	require.NoError(t, os.MkdirAll(pstorePath, 0o700))
+	require.NoError(t, os.MkdirAll(archivePath, 0o700))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pstorePath, "dmesg-efi-2"), []byte("Kernel panic"), 0o600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pstorePath, "dmesg-efi-1"), []byte("Oops"), 0o600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(archivePath, "dmesg-efi-0"), []byte("stale"), 0o600))
+
+	archived, err := pstore.Archive(pstorePath, archivePath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived)
+
+	entries, err := ioutil.ReadDir(pstorePath)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	records, err := pstore.Records(archivePath)
+	require.NoError(t, err)
+	assert.Equal(t, []pstore.Record{
+		{Name: "dmesg-efi-1", Data: []byte("Oops")},
+		{Name: "dmesg-efi-2", Data: []byte("Kernel panic")},
+	}, records)
+
+	// next boot without any crash records
+	archived, err = pstore.Archive(pstorePath, archivePath)
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+
+	records, err = pstore.Records(archivePath)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestArchiveNoPstore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	archived, err := pstore.Archive(filepath.Join(dir, "pstore"), filepath.Join(dir, "archive"))
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+
+	records, err := pstore.Records(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
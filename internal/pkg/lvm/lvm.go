@@ -0,0 +1,243 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package lvm manages LVM volume groups and logical volumes via the lvm tool.
+package lvm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/talos-systems/go-cmd/pkg/cmd"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// Path to the lvm tool.
+const Path = "/sbin/lvm"
+
+// LVM runs lvm commands.
+type LVM struct {
+	// Run runs lvm with the arguments and returns its stdout.
+	Run func(args ...string) (string, error)
+}
+
+// New returns LVM which runs the lvm tool shipped with Talos.
+func New() *LVM {
+	return &LVM{
+		Run: func(args ...string) (string, error) {
+			return cmd.Run(Path, args...)
+		},
+	}
+}
+
+// LogicalVolume describes the state of the logical volume as reported by lvm.
+type LogicalVolume struct {
+	VolumeGroup string
+	Name        string
+	Path        string
+	Size        uint64
+	SegmentType string
+	// Health is empty if the volume is healthy, e.g. `partial` or `refresh needed` otherwise.
+	Health string
+	// SyncAction is the current RAID sync action (e.g. `idle`, `recover`, `resync`).
+	SyncAction string
+	// SyncPercent is the RAID sync progress, 100 for in-sync volumes.
+	SyncPercent float64
+}
+
+// IsRAID returns true if the logical volume is a RAID volume.
+func (lv *LogicalVolume) IsRAID() bool {
+	return strings.HasPrefix(lv.SegmentType, "raid")
+}
+
+// Degraded returns true if the RAID volume lost some of the devices.
+func (lv *LogicalVolume) Degraded() bool {
+	return lv.Health != ""
+}
+
+type report struct {
+	Report []map[string][]map[string]string `json:"report"`
+}
+
+func (l *LVM) report(command, kind string, fields ...string) ([]map[string]string, error) {
+	out, err := l.Run(command, "--reportformat", "json", "--units", "b", "--nosuffix", "-o", strings.Join(fields, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	var r report
+
+	if err = json.Unmarshal([]byte(out), &r); err != nil {
+		return nil, fmt.Errorf("error parsing %s report: %w", command, err)
+	}
+
+	var items []map[string]string
+
+	for _, section := range r.Report {
+		items = append(items, section[kind]...)
+	}
+
+	return items, nil
+}
+
+// VolumeGroups returns names of the existing volume groups.
+func (l *LVM) VolumeGroups() ([]string, error) {
+	items, err := l.report("vgs", "vg", "vg_name")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(items))
+
+	for _, item := range items {
+		names = append(names, item["vg_name"])
+	}
+
+	return names, nil
+}
+
+// LogicalVolumes returns the existing logical volumes.
+func (l *LVM) LogicalVolumes() ([]LogicalVolume, error) {
+	items, err := l.report("lvs", "lv", "vg_name", "lv_name", "lv_path", "lv_size", "segtype", "lv_health_status", "raid_sync_action", "sync_percent")
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make([]LogicalVolume, 0, len(items))
+
+	for _, item := range items {
+		lv := LogicalVolume{
+			VolumeGroup: item["vg_name"],
+			Name:        item["lv_name"],
+			Path:        item["lv_path"],
+			SegmentType: item["segtype"],
+			Health:      item["lv_health_status"],
+			SyncAction:  item["raid_sync_action"],
+		}
+
+		if item["lv_size"] != "" {
+			if lv.Size, err = strconv.ParseUint(item["lv_size"], 10, 64); err != nil {
+				return nil, fmt.Errorf("error parsing size of %s/%s: %w", lv.VolumeGroup, lv.Name, err)
+			}
+		}
+
+		if item["sync_percent"] != "" {
+			if lv.SyncPercent, err = strconv.ParseFloat(item["sync_percent"], 64); err != nil {
+				return nil, fmt.Errorf("error parsing sync percent of %s/%s: %w", lv.VolumeGroup, lv.Name, err)
+			}
+		}
+
+		volumes = append(volumes, lv)
+	}
+
+	return volumes, nil
+}
+
+// CreateVolumeGroup creates a volume group on the devices.
+//
+// lvm refuses to use devices with existing partition tables or filesystem signatures.
+func (l *LVM) CreateVolumeGroup(name string, devices []string) error {
+	_, err := l.Run(append([]string{"vgcreate", name}, devices...)...)
+
+	return err
+}
+
+// CreateLogicalVolume creates a logical volume in the volume group.
+//
+// RAID volumes use all the physical volumes of the volume group.
+func (l *LVM) CreateLogicalVolume(vg string, devices int, lv config.LogicalVolume) error {
+	args := []string{"lvcreate", "--yes", "--wipesignatures", "y", "--name", lv.Name()}
+
+	if lv.Size() == 0 {
+		args = append(args, "--extents", "100%FREE")
+	} else {
+		args = append(args, "--size", fmt.Sprintf("%dB", lv.Size()))
+	}
+
+	switch lv.RAID() {
+	case config.RAID1:
+		args = append(args, "--type", config.RAID1, "--mirrors", strconv.Itoa(devices-1))
+	case config.RAID10:
+		args = append(args, "--type", config.RAID10, "--mirrors", "1", "--stripes", strconv.Itoa(devices/2))
+	}
+
+	args = append(args, vg)
+
+	_, err := l.Run(args...)
+
+	return err
+}
+
+// Activate activates all the logical volumes.
+func (l *LVM) Activate() error {
+	_, err := l.Run("vgchange", "-ay")
+
+	return err
+}
+
+// Deactivate deactivates all the logical volumes, releasing the physical volumes.
+func (l *LVM) Deactivate() error {
+	_, err := l.Run("vgchange", "-an")
+
+	return err
+}
+
+// Ensure creates the volume group and its logical volumes if they don't exist.
+//
+// Existing volume groups and logical volumes are never modified.
+func (l *LVM) Ensure(vg config.VolumeGroup) error {
+	volumeGroups, err := l.VolumeGroups()
+	if err != nil {
+		return fmt.Errorf("error listing volume groups: %w", err)
+	}
+
+	if !contains(volumeGroups, vg.Name()) {
+		if err = l.CreateVolumeGroup(vg.Name(), vg.Devices()); err != nil {
+			return fmt.Errorf("error creating volume group %q: %w", vg.Name(), err)
+		}
+	}
+
+	logicalVolumes, err := l.LogicalVolumes()
+	if err != nil {
+		return fmt.Errorf("error listing logical volumes: %w", err)
+	}
+
+	existing := map[string]struct{}{}
+
+	for _, lv := range logicalVolumes {
+		if lv.VolumeGroup == vg.Name() {
+			existing[lv.Name] = struct{}{}
+		}
+	}
+
+	for _, lv := range vg.LogicalVolumes() {
+		if _, ok := existing[lv.Name()]; ok {
+			continue
+		}
+
+		if err = l.CreateLogicalVolume(vg.Name(), len(vg.Devices()), lv); err != nil {
+			return fmt.Errorf("error creating logical volume %q in volume group %q: %w", lv.Name(), vg.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// DevicePath returns the path to the logical volume device.
+func DevicePath(vg, lv string) string {
+	return "/dev/" + vg + "/" + lv
+}
+
+func contains(list []string, item string) bool {
+	for _, s := range list {
+		if s == item {
+			return true
+		}
+	}
+
+	return false
+}
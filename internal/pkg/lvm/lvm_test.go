@@ -0,0 +1,120 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lvm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/lvm"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+const lvsReport = `{
+  "report": [
+    {
+      "lv": [
+        {"vg_name":"data", "lv_name":"local", "lv_path":"/dev/data/local", "lv_size":"107374182400", "segtype":"raid1", "lv_health_status":"partial", "raid_sync_action":"recover", "sync_percent":"42.50"},
+        {"vg_name":"data", "lv_name":"scratch", "lv_path":"/dev/data/scratch", "lv_size":"4194304", "segtype":"linear", "lv_health_status":"", "raid_sync_action":"", "sync_percent":""}
+      ]
+    }
+  ]
+}`
+
+type fakeLVM struct {
+	vgs      string
+	lvs      string
+	commands []string
+}
+
+func (f *fakeLVM) run(args ...string) (string, error) {
+	switch args[0] {
+	case "vgs":
+		return f.vgs, nil
+	case "lvs":
+		return f.lvs, nil
+	}
+
+	f.commands = append(f.commands, strings.Join(args, " "))
+
+	return "", nil
+}
+
+func TestLogicalVolumes(t *testing.T) {
+	fake := &fakeLVM{lvs: lvsReport}
+
+	volumes, err := (&lvm.LVM{Run: fake.run}).LogicalVolumes()
+	require.NoError(t, err)
+	require.Len(t, volumes, 2)
+
+	assert.Equal(t, lvm.LogicalVolume{
+		VolumeGroup: "data",
+		Name:        "local",
+		Path:        "/dev/data/local",
+		Size:        107374182400,
+		SegmentType: "raid1",
+		Health:      "partial",
+		SyncAction:  "recover",
+		SyncPercent: 42.5,
+	}, volumes[0])
+	assert.True(t, volumes[0].IsRAID())
+	assert.True(t, volumes[0].Degraded())
+
+	assert.False(t, volumes[1].IsRAID())
+	assert.False(t, volumes[1].Degraded())
+	assert.Equal(t, uint64(4194304), volumes[1].Size)
+}
+
+func TestEnsure(t *testing.T) {
+	vg := &v1alpha1.VolumeGroupConfig{
+		VolumeGroupName:    "data",
+		VolumeGroupDevices: []string{"/dev/sdb", "/dev/sdc", "/dev/sdd", "/dev/sde"},
+		VolumeGroupLogicalVolumes: []*v1alpha1.LogicalVolumeConfig{
+			{
+				LogicalVolumeName: "local",
+				LogicalVolumeSize: v1alpha1.DiskSize(100 * 1024 * 1024 * 1024),
+				LogicalVolumeRAID: "raid1",
+			},
+			{
+				LogicalVolumeName: "fast",
+				LogicalVolumeSize: v1alpha1.DiskSize(1024 * 1024 * 1024),
+				LogicalVolumeRAID: "raid10",
+			},
+			{
+				LogicalVolumeName: "scratch",
+			},
+		},
+	}
+
+	// nothing exists yet
+	fake := &fakeLVM{
+		vgs: `{"report": [{"vg": []}]}`,
+		lvs: `{"report": [{"lv": []}]}`,
+	}
+
+	require.NoError(t, (&lvm.LVM{Run: fake.run}).Ensure(vg))
+
+	assert.Equal(t, []string{
+		"vgcreate data /dev/sdb /dev/sdc /dev/sdd /dev/sde",
+		"lvcreate --yes --wipesignatures y --name local --size 107374182400B --type raid1 --mirrors 3 data",
+		"lvcreate --yes --wipesignatures y --name fast --size 1073741824B --type raid10 --mirrors 1 --stripes 2 data",
+		"lvcreate --yes --wipesignatures y --name scratch --extents 100%FREE data",
+	}, fake.commands)
+
+	// volume group and some volumes exist
+	fake = &fakeLVM{
+		vgs: `{"report": [{"vg": [{"vg_name": "data"}]}]}`,
+		lvs: lvsReport,
+	}
+
+	require.NoError(t, (&lvm.LVM{Run: fake.run}).Ensure(vg))
+
+	assert.Equal(t, []string{
+		"lvcreate --yes --wipesignatures y --name fast --size 1073741824B --type raid10 --mirrors 1 --stripes 2 data",
+	}, fake.commands)
+}
@@ -15,10 +15,12 @@ import (
 
 	"github.com/talos-systems/go-blockdevice/blockdevice"
 	"github.com/talos-systems/go-blockdevice/blockdevice/filesystem"
+	"github.com/talos-systems/go-blockdevice/blockdevice/partition/gpt"
 	"github.com/talos-systems/go-blockdevice/blockdevice/util"
 	"github.com/talos-systems/go-retry/retry"
 	"golang.org/x/sys/unix"
 
+	"github.com/talos-systems/talos/internal/pkg/partition"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
 	"github.com/talos-systems/talos/pkg/makefs"
 )
@@ -253,7 +255,9 @@ func (p *Point) Mount() (err error) {
 	case p.MountFlags.Check(Overlay):
 		err = mountRetry(overlay, p, false)
 	default:
-		err = mountRetry(mount, p, false)
+		if err = mountRetry(mount, p, false); err != nil {
+			err = p.repair(err)
+		}
 	}
 
 	if err != nil {
@@ -334,6 +338,9 @@ func (p *Point) Move(prefix string) (err error) {
 }
 
 // ResizePartition resizes a partition to the maximum size allowed.
+//
+// Only the last partition on the disk is resized, as other partitions can't grow
+// without moving the partitions which follow them.
 func (p *Point) ResizePartition() (resized bool, err error) {
 	var devname string
 
@@ -358,19 +365,30 @@ func (p *Point) ResizePartition() (resized bool, err error) {
 		return false, err
 	}
 
-	for _, partition := range pt.Partitions().Items() {
-		if partition.Name == constants.EphemeralPartitionLabel {
-			resized, err := pt.Resize(partition)
-			if err != nil {
-				return false, err
-			}
+	var part, last *gpt.Partition
 
-			if !resized {
-				return false, nil
-			}
+	for _, item := range pt.Partitions().Items() {
+		if path, err := item.Path(); err == nil && path == p.Source() {
+			part = item
+		}
+
+		if last == nil || item.LastLBA > last.LastLBA {
+			last = item
 		}
 	}
 
+	if part == nil {
+		return false, fmt.Errorf("partition %q is not found", p.Source())
+	}
+
+	if part != last {
+		return false, nil
+	}
+
+	if resized, err = pt.Resize(part); err != nil || !resized {
+		return false, err
+	}
+
 	if err := pt.Write(); err != nil {
 		return false, err
 	}
@@ -381,6 +399,14 @@ func (p *Point) ResizePartition() (resized bool, err error) {
 // GrowFilesystem grows a partition's filesystem to the maximum size allowed.
 // NB: An XFS partition MUST be mounted, or this will fail.
 func (p *Point) GrowFilesystem() (err error) {
+	if p.Fstype() == partition.FilesystemTypeExt4 {
+		if err = makefs.Ext4Resize(p.Source()); err != nil {
+			return fmt.Errorf("resize2fs: %w", err)
+		}
+
+		return nil
+	}
+
 	if err = makefs.XFSGrow(p.Target()); err != nil {
 		return fmt.Errorf("xfs_growfs: %w", err)
 	}
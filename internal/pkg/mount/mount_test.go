@@ -4,11 +4,45 @@
 
 package mount_test
 
-import "testing"
+import (
+	"testing"
 
-func TestEmpty(t *testing.T) {
-	// added for accurate coverage estimation
-	//
-	// please remove it once any unit-test is added
-	// for this package
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+
+	"github.com/talos-systems/talos/internal/pkg/mount"
+)
+
+func TestParseMountOptions(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		options       []string
+		expectedFlags uintptr
+		expectedData  string
+	}{
+		{
+			name:          "empty",
+			expectedFlags: unix.MS_NOATIME,
+		},
+		{
+			name:          "flags and data",
+			options:       []string{"nodev", "nosuid", "discard", "prjquota"},
+			expectedFlags: unix.MS_NOATIME | unix.MS_NODEV | unix.MS_NOSUID,
+			expectedData:  "discard,prjquota",
+		},
+		{
+			name:          "atime",
+			options:       []string{"relatime", "ro"},
+			expectedFlags: unix.MS_RELATIME | unix.MS_RDONLY,
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			flags, data := mount.ParseMountOptions(tt.options)
+
+			assert.Equal(t, tt.expectedFlags, flags)
+			assert.Equal(t, tt.expectedData, data)
+		})
+	}
 }
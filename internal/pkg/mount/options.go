@@ -34,6 +34,8 @@ type Options struct {
 	PreMountHooks    []Hook
 	PostUnmountHooks []Hook
 	Encryption       config.Encryption
+	RepairPolicy     string
+	RepairReport     func(*RepairResult)
 }
 
 // Option is the functional option func.
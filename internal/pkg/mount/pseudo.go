@@ -5,7 +5,15 @@
 package mount
 
 import (
+	"bufio"
+	"io"
+	"log"
+	"os"
+	"strings"
+
 	"golang.org/x/sys/unix"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
 )
 
 // PseudoMountPoints returns the mountpoints required to boot the system.
@@ -29,5 +37,42 @@ func PseudoSubMountPoints() (mountpoints *Points, err error) {
 	pseudo.Set("hugetlb", NewMountPoint("hugetlbfs", "/dev/hugepages", "hugetlbfs", 0, ""))
 	pseudo.Set("securityfs", NewMountPoint("securityfs", "/sys/kernel/security", "securityfs", unix.MS_NOSUID|unix.MS_NOEXEC|unix.MS_NODEV|unix.MS_RELATIME, ""))
 
+	// pstore is optional, as the kernel might be built without it
+	supported, err := isFilesystemSupported("pstore")
+	if err != nil {
+		log.Printf("failed to check pstore support, skipping pstore mount: %s", err)
+	}
+
+	if supported {
+		pseudo.Set("pstore", NewMountPoint("pstore", constants.PstoreMountPoint, "pstore", unix.MS_NOSUID|unix.MS_NOEXEC|unix.MS_NODEV|unix.MS_RELATIME, ""))
+	}
+
 	return pseudo, nil
 }
+
+// isFilesystemSupported checks if the filesystem is listed in /proc/filesystems.
+func isFilesystemSupported(fstype string) (bool, error) {
+	f, err := os.Open("/proc/filesystems")
+	if err != nil {
+		return false, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	return filesystemListed(f, fstype)
+}
+
+// filesystemListed checks if the filesystem is listed in /proc/filesystems format ("nodev\tsysfs", "\text4").
+func filesystemListed(r io.Reader, fstype string) (bool, error) {
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) > 0 && fields[len(fields)-1] == fstype {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
@@ -0,0 +1,32 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mount
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesystemListed(t *testing.T) {
+	const filesystems = "nodev\tsysfs\nnodev\ttmpfs\nnodev\tpstore\n\text4\n\txfs\n"
+
+	for _, tt := range []struct {
+		fstype   string
+		expected bool
+	}{
+		{fstype: "pstore", expected: true},
+		{fstype: "xfs", expected: true},
+		{fstype: "btrfs", expected: false},
+		{fstype: "nodev", expected: false},
+	} {
+		listed, err := filesystemListed(strings.NewReader(filesystems), tt.fstype)
+		require.NoError(t, err)
+
+		assert.Equal(t, tt.expected, listed, tt.fstype)
+	}
+}
@@ -0,0 +1,118 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mount
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/talos-systems/talos/internal/app/machined/pkg/runtime"
+	"github.com/talos-systems/talos/internal/pkg/fsck"
+	machineapi "github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// RepairResult describes the filesystem check run after the mount failure.
+type RepairResult struct {
+	Source     string
+	Target     string
+	Filesystem string
+	Policy     string
+	// Mounted is true if the filesystem was mounted after the repair.
+	Mounted bool
+	// Err is the error of the filesystem check or of the mount after the repair.
+	Err error
+}
+
+// repairModes maps the filesystem repair policy to the filesystem check mode.
+var repairModes = map[string]fsck.Mode{
+	config.FilesystemRepairCheck:  fsck.Check,
+	config.FilesystemRepairRepair: fsck.Repair,
+	config.FilesystemRepairForce:  fsck.Force,
+}
+
+// checker runs the filesystem tools, replaced in the tests.
+var checker = fsck.New()
+
+// WithRepair is a functional option to check the filesystem if the mount fails.
+//
+// The filesystem is mounted again if the policy allows to repair it, report is called with the result.
+func WithRepair(policy string, report func(*RepairResult)) Option {
+	return func(args *Options) {
+		args.RepairPolicy = policy
+		args.RepairReport = report
+	}
+}
+
+// WithRuntimeRepair is a functional option to repair the filesystem according to the machine config policy.
+//
+// The results are logged and published as machine events.
+func WithRuntimeRepair(r runtime.Runtime) Option {
+	policy := config.FilesystemRepairNone
+
+	if r.Config() != nil && r.Config().Machine() != nil {
+		policy = r.Config().Machine().FilesystemRepair().Policy()
+	}
+
+	return WithRepair(policy, func(result *RepairResult) {
+		event := &machineapi.FilesystemRepairEvent{
+			Device:     result.Source,
+			MountPoint: result.Target,
+			Filesystem: result.Filesystem,
+			Policy:     result.Policy,
+			Mounted:    result.Mounted,
+		}
+
+		if result.Err != nil {
+			event.Error = result.Err.Error()
+
+			log.Printf("filesystem %s check on %s (policy %q) failed: %s", result.Filesystem, result.Source, result.Policy, result.Err)
+		} else {
+			log.Printf("filesystem %s check on %s (policy %q) finished, mounted: %v", result.Filesystem, result.Source, result.Policy, result.Mounted)
+		}
+
+		r.Events().Publish(event)
+	})
+}
+
+// repair checks the filesystem after the mount failure and mounts it again if it was repaired.
+func (p *Point) repair(mountErr error) error {
+	mode, ok := repairModes[p.RepairPolicy]
+	if !ok || !fsck.Supported(p.fstype) {
+		return mountErr
+	}
+
+	log.Printf("mounting %s failed: %s, running filesystem check", p.source, mountErr)
+
+	result := &RepairResult{
+		Source:     p.source,
+		Target:     p.target,
+		Filesystem: p.fstype,
+		Policy:     p.RepairPolicy,
+	}
+
+	defer func() {
+		if p.RepairReport != nil {
+			p.RepairReport(result)
+		}
+	}()
+
+	if result.Err = checker.Check(p.source, p.fstype, mode); result.Err != nil {
+		return fmt.Errorf("%w, filesystem check failed: %s", mountErr, result.Err)
+	}
+
+	// the filesystem is not modified in the check mode, so mounting it again would fail the same way
+	if mode == fsck.Check {
+		return mountErr
+	}
+
+	if result.Err = mountRetry(mount, p, false); result.Err != nil {
+		return fmt.Errorf("error mounting after the filesystem repair: %w", result.Err)
+	}
+
+	result.Mounted = true
+
+	return nil
+}
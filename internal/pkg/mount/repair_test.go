@@ -0,0 +1,100 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mount
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/talos-systems/talos/internal/pkg/fsck"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+func TestRepair(t *testing.T) {
+	defer func(c *fsck.Checker) { checker = c }(checker)
+
+	mountErr := unix.EUCLEAN
+
+	for _, tt := range []struct {
+		name     string
+		policy   string
+		fstype   string
+		exitCode int
+
+		expectedCommands []string
+		expectedError    string
+		expectedResult   *RepairResult
+	}{
+		{
+			name:          "none",
+			policy:        config.FilesystemRepairNone,
+			fstype:        "xfs",
+			expectedError: mountErr.Error(),
+		},
+		{
+			name:          "unsupported filesystem",
+			policy:        config.FilesystemRepairRepair,
+			fstype:        "vfat",
+			expectedError: mountErr.Error(),
+		},
+		{
+			name:             "check clean",
+			policy:           config.FilesystemRepairCheck,
+			fstype:           "xfs",
+			expectedCommands: []string{"xfs_repair"},
+			expectedError:    mountErr.Error(),
+			expectedResult: &RepairResult{
+				Source:     "/dev/sdb1",
+				Target:     "/var/mnt/data",
+				Filesystem: "xfs",
+				Policy:     config.FilesystemRepairCheck,
+			},
+		},
+		{
+			name:             "check failed",
+			policy:           config.FilesystemRepairRepair,
+			fstype:           "ext4",
+			exitCode:         8,
+			expectedCommands: []string{"e2fsck"},
+			expectedError:    mountErr.Error() + ", filesystem check failed: e2fsck exited with code 8: bad superblock",
+			expectedResult: &RepairResult{
+				Source:     "/dev/sdb1",
+				Target:     "/var/mnt/data",
+				Filesystem: "ext4",
+				Policy:     config.FilesystemRepairRepair,
+				Err:        errors.New("e2fsck exited with code 8: bad superblock"),
+			},
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+
+			checker = &fsck.Checker{
+				Run: func(name string, args ...string) (string, int, error) {
+					commands = append(commands, name)
+
+					return "bad superblock\n", tt.exitCode, nil
+				},
+			}
+
+			var result *RepairResult
+
+			p := NewMountPoint("/dev/sdb1", "/var/mnt/data", tt.fstype, 0, "", WithRepair(tt.policy, func(r *RepairResult) { result = r }))
+
+			err := p.repair(mountErr)
+			require.EqualError(t, err, tt.expectedError)
+			assert.ErrorIs(t, err, mountErr)
+
+			assert.Equal(t, tt.expectedCommands, commands)
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
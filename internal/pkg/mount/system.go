@@ -174,6 +174,8 @@ func SystemMountPointForLabel(device *blockdevice.BlockDevice, label string, opt
 }
 
 // SystemPartitionMount mounts a system partition by the label.
+//
+// If the mount fails, the filesystem is repaired according to the machine config policy.
 func SystemPartitionMount(r runtime.Runtime, label string, opts ...Option) (err error) {
 	device := r.State().Machine().Disk(disk.WithPartitionLabel(label))
 	if device == nil {
@@ -190,6 +192,8 @@ func SystemPartitionMount(r runtime.Runtime, label string, opts ...Option) (err
 		opts = append(opts, WithEncryptionConfig(encryptionConfig))
 	}
 
+	opts = append([]Option{WithRuntimeRepair(r)}, opts...)
+
 	mountpoint, err := SystemMountPointForLabel(device.BlockDevice, label, opts...)
 	if err != nil {
 		return err
@@ -0,0 +1,381 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mount
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/talos-systems/go-blockdevice/blockdevice"
+	"github.com/talos-systems/go-blockdevice/blockdevice/filesystem"
+	"github.com/talos-systems/go-blockdevice/blockdevice/partition/gpt"
+	"golang.org/x/sys/unix"
+
+	"github.com/talos-systems/talos/internal/pkg/encryption"
+	"github.com/talos-systems/talos/internal/pkg/lvm"
+	"github.com/talos-systems/talos/internal/pkg/partition"
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+var (
+	userMountpoints      = map[string]*Point{}
+	userMountpointsMutex sync.Mutex
+)
+
+// mountFlags maps generic mount options to the mount flags, other options are passed to the filesystem.
+var mountFlags = map[string]uintptr{
+	"ro":          unix.MS_RDONLY,
+	"nodev":       unix.MS_NODEV,
+	"nosuid":      unix.MS_NOSUID,
+	"noexec":      unix.MS_NOEXEC,
+	"noatime":     unix.MS_NOATIME,
+	"nodiratime":  unix.MS_NODIRATIME,
+	"relatime":    unix.MS_RELATIME,
+	"strictatime": unix.MS_STRICTATIME,
+	"lazytime":    unix.MS_LAZYTIME,
+	"sync":        unix.MS_SYNCHRONOUS,
+	"dirsync":     unix.MS_DIRSYNC,
+}
+
+// ParseMountOptions splits mount options into the mount flags and filesystem-specific data.
+//
+// Partitions are mounted with `noatime` unless other atime option is specified.
+func ParseMountOptions(options []string) (flags uintptr, data string) {
+	flags = unix.MS_NOATIME
+
+	var fsOptions []string
+
+	for _, option := range options {
+		flag, ok := mountFlags[option]
+		if !ok {
+			fsOptions = append(fsOptions, option)
+
+			continue
+		}
+
+		if flag == unix.MS_RELATIME || flag == unix.MS_STRICTATIME {
+			flags &^= unix.MS_NOATIME
+		}
+
+		flags |= flag
+	}
+
+	return flags, strings.Join(fsOptions, ",")
+}
+
+// UserDiskMountPoint returns a mount point for the partition of the user disk.
+//
+// If the encryption is enabled, empty partition is encrypted and formatted on the first mount.
+func UserDiskMountPoint(device *blockdevice.BlockDevice, part *gpt.Partition, partitionConfig config.Partition, opts ...Option) (*Point, error) {
+	partPath, err := part.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	flags, data := ParseMountOptions(partitionConfig.MountOptions())
+
+	if partitionConfig.Encryption() != nil {
+		encryptionHandler, err := encryption.NewHandler(device, part, partitionConfig.Encryption())
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts,
+			WithPreMountHooks(
+				func(p *Point) error {
+					// ext4 is not detected by the encryption handler, so check it explicitly to avoid encrypting over the data
+					isExt4, err := hasExt4SuperBlock(p.source)
+					if err != nil {
+						return err
+					}
+
+					if isExt4 {
+						return fmt.Errorf("failed to encrypt the partition %s, because it is not empty", p.source)
+					}
+
+					path, err := encryptionHandler.Open()
+					if err != nil {
+						return err
+					}
+
+					p.source = path
+
+					return nil
+				},
+				func(p *Point) error {
+					fsType, err := probeFilesystem(p.source)
+					if err != nil {
+						return err
+					}
+
+					if fsType != "" {
+						p.fstype = fsType
+
+						return nil
+					}
+
+					p.fstype = partitionConfig.Filesystem()
+
+					return partition.Format(p.source, &partition.FormatOptions{
+						FileSystemType: partitionConfig.Filesystem(),
+						Force:          true,
+					})
+				},
+			),
+			WithPostUnmountHooks(
+				func(p *Point) error {
+					return encryptionHandler.Close()
+				},
+			),
+		)
+	}
+
+	return NewMountPoint(partPath, partitionConfig.MountPoint(), partitionConfig.Filesystem(), flags, data, opts...), nil
+}
+
+// UserDisksMount mounts partitions of the user disks.
+//
+// The last partition of the disk which takes the rest of the disk space is grown
+// to the size of the disk along with its filesystem.
+func UserDisksMount(disks []config.Disk, opts ...Option) error {
+	for _, disk := range disks {
+		if err := userDiskMount(disk, opts...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func userDiskMount(disk config.Disk, opts ...Option) error {
+	bd, err := blockdevice.Open(disk.Device())
+	if err != nil {
+		return err
+	}
+
+	defer bd.Close() //nolint:errcheck
+
+	pt, err := bd.PartitionTable()
+	if err != nil {
+		return fmt.Errorf("error reading partition table of %q: %w", disk.Device(), err)
+	}
+
+	for i, partitionConfig := range disk.Partitions() {
+		var part *gpt.Partition
+
+		for _, p := range pt.Partitions().Items() {
+			if int(p.Number) == i+1 {
+				part = p
+
+				break
+			}
+		}
+
+		if part == nil {
+			return fmt.Errorf("partition %d of %q is not found", i+1, disk.Device())
+		}
+
+		partitionOpts := append([]Option{}, opts...)
+
+		if i == len(disk.Partitions())-1 && canGrow(partitionConfig) {
+			partitionOpts = append(partitionOpts, WithFlags(Resize))
+		}
+
+		mountpoint, err := UserDiskMountPoint(bd, part, partitionConfig, partitionOpts...)
+		if err != nil {
+			return err
+		}
+
+		if err = os.MkdirAll(partitionConfig.MountPoint(), 0o700); err != nil {
+			return err
+		}
+
+		if err = mountMountpoint(mountpoint); err != nil {
+			return fmt.Errorf("error mounting %q: %w", mountpoint.Source(), err)
+		}
+
+		userMountpointsMutex.Lock()
+		userMountpoints[partitionConfig.MountPoint()] = mountpoint
+		userMountpointsMutex.Unlock()
+	}
+
+	return nil
+}
+
+// UserVolumesMount mounts logical volumes of the user volume groups.
+//
+// Empty logical volumes are formatted on the first mount.
+func UserVolumesMount(volumeGroups []config.VolumeGroup, opts ...Option) error {
+	for _, vg := range volumeGroups {
+		for _, lv := range vg.LogicalVolumes() {
+			if lv.MountPoint() == "" {
+				continue
+			}
+
+			if err := userVolumeMount(lvm.DevicePath(vg.Name(), lv.Name()), lv, opts...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func userVolumeMount(path string, lv config.LogicalVolume, opts ...Option) error {
+	fsType, err := probeFilesystem(path)
+	if err != nil {
+		return fmt.Errorf("error probing %q: %w", path, err)
+	}
+
+	if fsType == "" {
+		fsType = lv.Filesystem()
+
+		if err = partition.Format(path, &partition.FormatOptions{
+			FileSystemType: fsType,
+			Force:          true,
+		}); err != nil {
+			return err
+		}
+	}
+
+	flags, data := ParseMountOptions(lv.MountOptions())
+
+	mountpoint := NewMountPoint(path, lv.MountPoint(), fsType, flags, data, opts...)
+
+	if err = os.MkdirAll(lv.MountPoint(), 0o700); err != nil {
+		return err
+	}
+
+	if err = mountMountpoint(mountpoint); err != nil {
+		return fmt.Errorf("error mounting %q: %w", path, err)
+	}
+
+	userMountpointsMutex.Lock()
+	userMountpoints[lv.MountPoint()] = mountpoint
+	userMountpointsMutex.Unlock()
+
+	return nil
+}
+
+// UserDisksGrow grows the mounted partitions of the user disks to the size of the disk along with the filesystems.
+//
+// Partitions are grown online, only the partitions which were mounted with the resize flag by UserDisksMount are grown.
+func UserDisksGrow() error {
+	userMountpointsMutex.Lock()
+	defer userMountpointsMutex.Unlock()
+
+	for _, mountpoint := range userMountpoints {
+		if !mountpoint.MountFlags.Check(Resize) {
+			continue
+		}
+
+		resized, err := mountpoint.ResizePartition()
+		if err != nil {
+			return fmt.Errorf("error resizing %q: %w", mountpoint.Source(), err)
+		}
+
+		if !resized {
+			continue
+		}
+
+		if err = mountpoint.GrowFilesystem(); err != nil {
+			return fmt.Errorf("error resizing filesystem %q: %w", mountpoint.Source(), err)
+		}
+	}
+
+	return nil
+}
+
+// canGrow checks whether the user disk partition can be grown to the size of the disk.
+//
+// Partitions which have the size set are never grown, encrypted partitions are not grown
+// as the encrypted device is not resized along with the partition.
+func canGrow(partitionConfig config.Partition) bool {
+	return partitionConfig.Size() == 0 && partitionConfig.Encryption() == nil
+}
+
+// UserDisksUnmount unmounts partitions of the user disks and logical volumes mounted with UserDisksMount and UserVolumesMount.
+func UserDisksUnmount() error {
+	userMountpointsMutex.Lock()
+	defer userMountpointsMutex.Unlock()
+
+	targets := make([]string, 0, len(userMountpoints))
+
+	for target := range userMountpoints {
+		targets = append(targets, target)
+	}
+
+	// unmount nested mountpoints first
+	sort.Sort(sort.Reverse(sort.StringSlice(targets)))
+
+	for _, target := range targets {
+		if err := userMountpoints[target].Unmount(); err != nil {
+			return fmt.Errorf("unmount: %w", err)
+		}
+
+		delete(userMountpoints, target)
+	}
+
+	return nil
+}
+
+// probeFilesystem returns the type of the filesystem found on the device, or empty string if none is found.
+func probeFilesystem(path string) (string, error) {
+	sb, err := filesystem.Probe(path)
+	if err != nil {
+		return "", err
+	}
+
+	if sb != nil && sb.Type() != filesystem.Unknown {
+		return sb.Type(), nil
+	}
+
+	isExt4, err := hasExt4SuperBlock(path)
+	if err != nil {
+		return "", err
+	}
+
+	if isExt4 {
+		return partition.FilesystemTypeExt4, nil
+	}
+
+	return "", nil
+}
+
+const (
+	ext4SuperBlockMagicOffset = 1024 + 0x38
+	ext4SuperBlockMagic       = 0xef53
+)
+
+// hasExt4SuperBlock checks for the ext2/3/4 superblock magic, as filesystem.Probe doesn't support it.
+func hasExt4SuperBlock(path string) (bool, error) {
+	f, err := os.OpenFile(path, os.O_RDONLY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return false, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	var magic uint16
+
+	if _, err = f.Seek(ext4SuperBlockMagicOffset, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	if err = binary.Read(f, binary.LittleEndian, &magic); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return magic == ext4SuperBlockMagic, nil
+}
@@ -24,6 +24,7 @@ const (
 	FilesystemTypeNone FileSystemType = "none"
 	FilesystemTypeXFS  FileSystemType = "xfs"
 	FilesystemTypeVFAT FileSystemType = "vfat"
+	FilesystemTypeExt4 FileSystemType = "ext4"
 )
 
 // Partition default sizes.
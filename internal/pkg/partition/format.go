@@ -24,6 +24,9 @@ type FormatOptions struct {
 	FileSystemType FileSystemType
 	Size           uint64
 	Force          bool
+	// FastWipe wipes only the beginning of the partition instead of zeroing it out completely
+	// if the filesystem type is none.
+	FastWipe bool
 }
 
 // NewFormatOptions creates a new format options.
@@ -39,6 +42,10 @@ func NewFormatOptions(label string) *FormatOptions {
 // Format zeroes the device and formats it using filesystem type provided.
 func Format(devname string, t *FormatOptions) error {
 	if t.FileSystemType == FilesystemTypeNone {
+		if t.FastWipe {
+			return zeroPartition(devname, 0)
+		}
+
 		return zeroPartition(devname, int64(t.Size))
 	}
 
@@ -50,6 +57,8 @@ func Format(devname string, t *FormatOptions) error {
 		return makefs.VFAT(devname, opts...)
 	case FilesystemTypeXFS:
 		return makefs.XFS(devname, opts...)
+	case FilesystemTypeExt4:
+		return makefs.Ext4(devname, opts...)
 	default:
 		return fmt.Errorf("unsupported filesystem type: %q", t.FileSystemType)
 	}
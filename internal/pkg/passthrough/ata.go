@@ -0,0 +1,156 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package passthrough
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// ATA command opcodes.
+const (
+	ATAIdentifyDevice          = 0xec
+	ATASmart                   = 0xb0
+	ATASecuritySetPassword     = 0xf1
+	ATASecurityErasePrepare    = 0xf3
+	ATASecurityEraseUnit       = 0xf4
+	ATASecurityDisablePassword = 0xf6
+)
+
+// ATADirection is the direction of the ATA command data transfer.
+type ATADirection int
+
+// ATA data transfer directions.
+const (
+	ATANoData ATADirection = iota
+	ATAFromDevice
+	ATAToDevice
+)
+
+// ATACommand is the ATA command issued via SCSI ATA PASS-THROUGH(16).
+type ATACommand struct {
+	Command     uint8
+	Features    uint8
+	SectorCount uint8
+	LBALow      uint8
+	LBAMid      uint8
+	LBAHigh     uint8
+	Direction   ATADirection
+	// Timeout defaults to DefaultATATimeout.
+	Timeout time.Duration
+}
+
+// DefaultATATimeout is the default timeout of the ATA command.
+const DefaultATATimeout = 10 * time.Second
+
+const (
+	sgIO = 0x2285
+
+	sgDxferNone    = -1
+	sgDxferToDev   = -2
+	sgDxferFromDev = -3
+
+	ataPassThrough16 = 0x85
+
+	ataProtocolNonData = 3
+	ataProtocolPIOIn   = 4
+	ataProtocolPIOOut  = 5
+)
+
+// sgIOHdr is struct sg_io_hdr from scsi/sg.h.
+type sgIOHdr struct {
+	interfaceID    int32
+	dxferDirection int32
+	cmdLen         uint8
+	mxSbLen        uint8
+	iovecCount     uint16
+	dxferLen       uint32
+	dxferp         uintptr
+	cmdp           uintptr
+	sbp            uintptr
+	timeout        uint32
+	flags          uint32
+	packID         int32
+	usrPtr         uintptr
+	status         uint8
+	maskedStatus   uint8
+	msgStatus      uint8
+	sbLenWr        uint8
+	hostStatus     uint16
+	driverStatus   uint16
+	resid          int32
+	duration       uint32
+	info           uint32
+}
+
+// ATA issues the ATA command, data is the sector-sized buffer for PIO commands.
+func ATA(f *os.File, cmd ATACommand, data []byte) error {
+	sense := make([]byte, 32)
+
+	cdb := [16]byte{
+		0:  ataPassThrough16,
+		4:  cmd.Features,
+		6:  cmd.SectorCount,
+		8:  cmd.LBALow,
+		10: cmd.LBAMid,
+		12: cmd.LBAHigh,
+		14: cmd.Command,
+	}
+
+	hdr := sgIOHdr{
+		interfaceID: 'S',
+		cmdLen:      uint8(len(cdb)),
+		mxSbLen:     uint8(len(sense)),
+		cmdp:        uintptr(unsafe.Pointer(&cdb[0])),
+		sbp:         uintptr(unsafe.Pointer(&sense[0])),
+	}
+
+	switch cmd.Direction {
+	case ATANoData:
+		cdb[1] = ataProtocolNonData << 1
+		hdr.dxferDirection = sgDxferNone
+	case ATAFromDevice:
+		cdb[1] = ataProtocolPIOIn << 1
+		cdb[2] = 0x0e // T_DIR: from device, BYT_BLOK: blocks, T_LENGTH: sector count
+		hdr.dxferDirection = sgDxferFromDev
+	case ATAToDevice:
+		cdb[1] = ataProtocolPIOOut << 1
+		cdb[2] = 0x06 // T_DIR: to device, BYT_BLOK: blocks, T_LENGTH: sector count
+		hdr.dxferDirection = sgDxferToDev
+	}
+
+	if len(data) > 0 {
+		hdr.dxferLen = uint32(len(data))
+		hdr.dxferp = uintptr(unsafe.Pointer(&data[0]))
+	}
+
+	timeout := cmd.Timeout
+	if timeout == 0 {
+		timeout = DefaultATATimeout
+	}
+
+	hdr.timeout = uint32(timeout.Milliseconds())
+
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), sgIO, uintptr(unsafe.Pointer(&hdr)))
+
+	runtime.KeepAlive(data)
+	runtime.KeepAlive(sense)
+	runtime.KeepAlive(&cdb)
+
+	if errno != 0 {
+		return fmt.Errorf("ATA command 0x%02x failed: %w", cmd.Command, errno)
+	}
+
+	if hdr.status != 0 || hdr.hostStatus != 0 {
+		return fmt.Errorf("ATA command 0x%02x failed: status 0x%02x, host status 0x%02x", cmd.Command, hdr.status, hdr.hostStatus)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package passthrough issues raw NVMe admin and ATA commands to the block devices.
+package passthrough
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// NVMe admin command opcodes.
+const (
+	NVMeAdminGetLogPage = 0x02
+	NVMeAdminIdentify   = 0x06
+	NVMeAdminFormatNVM  = 0x80
+	NVMeAdminSanitize   = 0x84
+)
+
+// NVMeNSIDAll addresses all namespaces of the controller.
+const NVMeNSIDAll = 0xffffffff
+
+const (
+	// _IO('N', 0x40).
+	nvmeIoctlID = 0x4e40
+	// _IOWR('N', 0x41, struct nvme_admin_cmd).
+	nvmeIoctlAdminCmd = 0xc0484e41
+)
+
+// NVMeAdminCommand is struct nvme_admin_cmd from linux/nvme_ioctl.h.
+//
+// Addr and DataLen are filled in by NVMeAdmin.
+type NVMeAdminCommand struct {
+	Opcode      uint8
+	Flags       uint8
+	Rsvd1       uint16
+	NSID        uint32
+	CDW2        uint32
+	CDW3        uint32
+	Metadata    uint64
+	Addr        uint64
+	MetadataLen uint32
+	DataLen     uint32
+	CDW10       uint32
+	CDW11       uint32
+	CDW12       uint32
+	CDW13       uint32
+	CDW14       uint32
+	CDW15       uint32
+	TimeoutMs   uint32
+	Result      uint32
+}
+
+// NVMeAdmin issues the NVMe admin command, data is transferred to or from the controller depending on the command.
+func NVMeAdmin(f *os.File, cmd *NVMeAdminCommand, data []byte) error {
+	if len(data) > 0 {
+		cmd.Addr = uint64(uintptr(unsafe.Pointer(&data[0])))
+		cmd.DataLen = uint32(len(data))
+	}
+
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), nvmeIoctlAdminCmd, uintptr(unsafe.Pointer(cmd)))
+
+	runtime.KeepAlive(data)
+
+	if errno != 0 {
+		return fmt.Errorf("NVMe admin command 0x%02x failed: %w", cmd.Opcode, errno)
+	}
+
+	return nil
+}
+
+// NVMeGetLogPage reads the NVMe log page.
+func NVMeGetLogPage(f *os.File, logID uint8, data []byte) error {
+	return NVMeAdmin(f, &NVMeAdminCommand{
+		Opcode: NVMeAdminGetLogPage,
+		NSID:   NVMeNSIDAll,
+		// number of dwords to read (zero based) in the upper half, log page ID in the lower byte
+		CDW10: uint32(len(data)/4-1)<<16 | uint32(logID),
+	}, data)
+}
+
+// NVMeNamespaceID returns the namespace ID of the NVMe block device.
+func NVMeNamespaceID(f *os.File) (uint32, error) {
+	nsid, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), nvmeIoctlID, 0)
+	if errno != 0 {
+		return 0, fmt.Errorf("error getting NVMe namespace ID: %w", errno)
+	}
+
+	return uint32(nsid), nil
+}
@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package smart
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+
+	"github.com/talos-systems/talos/internal/pkg/passthrough"
+)
+
+// ATASmartDataSize is the size of the ATA SMART READ DATA response.
+const ATASmartDataSize = 512
+
+// ATA SMART attribute IDs.
+const (
+	ATAAttributePowerOnHours          = 9
+	ATAAttributeReallocatedSectors    = 5
+	ATAAttributeWearLevelingCount     = 177
+	ATAAttributeReportedUncorrectable = 187
+	ATAAttributeAirflowTemperature    = 190
+	ATAAttributeTemperature           = 194
+	ATAAttributePendingSectors        = 197
+	ATAAttributeOfflineUncorrectable  = 198
+	ATAAttributeSSDLifeLeft           = 231
+	ATAAttributeMediaWearoutIndicator = 233
+)
+
+const (
+	ataAttributesOffset = 2
+	ataAttributeSize    = 12
+	ataAttributeCount   = 30
+
+	ataSmartReadData = 0xd0
+)
+
+// ATAAttribute is a single ATA SMART attribute.
+type ATAAttribute struct {
+	ID uint8
+	// Current is the normalized value (usually 1-253, higher is better).
+	Current uint8
+	Worst   uint8
+	// Raw is the vendor-specific raw value.
+	Raw uint64
+}
+
+func readATASmartData(f *os.File) ([]byte, error) {
+	data := make([]byte, ATASmartDataSize)
+
+	if err := passthrough.ATA(f, passthrough.ATACommand{
+		Command:     passthrough.ATASmart,
+		Features:    ataSmartReadData,
+		SectorCount: 1,
+		// SMART signature
+		LBAMid:    0x4f,
+		LBAHigh:   0xc2,
+		Direction: passthrough.ATAFromDevice,
+	}, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ParseATAAttributes parses the attribute table of the ATA SMART READ DATA response.
+func ParseATAAttributes(data []byte) (map[uint8]ATAAttribute, error) {
+	if len(data) < ATASmartDataSize {
+		return nil, fmt.Errorf("ATA SMART data is too short: %d bytes", len(data))
+	}
+
+	attributes := map[uint8]ATAAttribute{}
+
+	for i := 0; i < ataAttributeCount; i++ {
+		entry := data[ataAttributesOffset+i*ataAttributeSize : ataAttributesOffset+(i+1)*ataAttributeSize]
+
+		// unused entries have zero ID
+		if entry[0] == 0 {
+			continue
+		}
+
+		raw := make([]byte, 8)
+		copy(raw, entry[5:11])
+
+		attributes[entry[0]] = ATAAttribute{
+			ID:      entry[0],
+			Current: entry[3],
+			Worst:   entry[4],
+			Raw:     binary.LittleEndian.Uint64(raw),
+		}
+	}
+
+	return attributes, nil
+}
+
+// ParseATASmartData parses the ATA SMART READ DATA response.
+func ParseATASmartData(data []byte) (*Health, error) {
+	attributes, err := ParseATAAttributes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &Health{
+		ReallocatedSectors: attributes[ATAAttributeReallocatedSectors].Raw,
+		PendingSectors:     attributes[ATAAttributePendingSectors].Raw,
+		MediaErrors:        attributes[ATAAttributeReportedUncorrectable].Raw + attributes[ATAAttributeOfflineUncorrectable].Raw,
+		// some vendors store minutes/seconds in the upper bytes
+		PowerOnHours: attributes[ATAAttributePowerOnHours].Raw & 0xffffffff,
+	}
+
+	// the lowest byte of the raw value is the current temperature, upper bytes are vendor-specific (min/max)
+	for _, id := range []uint8{ATAAttributeTemperature, ATAAttributeAirflowTemperature} {
+		if attr, ok := attributes[id]; ok {
+			health.Temperature = int(attr.Raw & 0xff)
+
+			break
+		}
+	}
+
+	// SSD wear is reported as the normalized value which counts down from 100 (remaining life)
+	for _, id := range []uint8{ATAAttributeWearLevelingCount, ATAAttributeSSDLifeLeft, ATAAttributeMediaWearoutIndicator} {
+		if attr, ok := attributes[id]; ok && attr.Current <= 100 {
+			health.WearLevel = 100 - int(attr.Current)
+
+			break
+		}
+	}
+
+	return health, nil
+}
@@ -0,0 +1,51 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package smart
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+
+	"github.com/talos-systems/talos/internal/pkg/passthrough"
+)
+
+// NVMeHealthLogSize is the size of the NVMe SMART / Health Information log page.
+const NVMeHealthLogSize = 512
+
+// nvmeLogSMARTHealth is the SMART / Health Information log page ID.
+const nvmeLogSMARTHealth = 0x02
+
+func readNVMeHealthLog(f *os.File) ([]byte, error) {
+	data := make([]byte, NVMeHealthLogSize)
+
+	if err := passthrough.NVMeGetLogPage(f, nvmeLogSMARTHealth, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ParseNVMeHealthLog parses the NVMe SMART / Health Information log page (log ID 02h).
+func ParseNVMeHealthLog(data []byte) (*Health, error) {
+	if len(data) < NVMeHealthLogSize {
+		return nil, fmt.Errorf("NVMe health log is too short: %d bytes", len(data))
+	}
+
+	health := &Health{
+		CriticalWarning: data[0],
+		WearLevel:       int(data[5]),
+		// 128-bit counters, the upper half is never reached in practice
+		PowerOnHours: binary.LittleEndian.Uint64(data[128:136]),
+		MediaErrors:  binary.LittleEndian.Uint64(data[160:168]),
+	}
+
+	// composite temperature is reported in Kelvin, zero if not implemented
+	if kelvin := int(binary.LittleEndian.Uint16(data[1:3])); kelvin > 0 {
+		health.Temperature = kelvin - 273
+	}
+
+	return health, nil
+}
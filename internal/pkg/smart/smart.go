@@ -0,0 +1,67 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package smart reads disk health from ATA SMART attributes and NVMe health logs.
+package smart
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNotSupported is returned for devices which don't report health (e.g. virtio disks).
+var ErrNotSupported = errors.New("health reporting is not supported for the device")
+
+// Health is the disk health normalized across ATA and NVMe devices.
+//
+// Counters which are not reported by the device are left zero.
+type Health struct {
+	// Temperature in degrees Celsius.
+	Temperature int
+	// WearLevel is the percentage of the rated device life used (might exceed 100).
+	WearLevel int
+	// ReallocatedSectors is the number of sectors remapped to the spare area (ATA only).
+	ReallocatedSectors uint64
+	// PendingSectors is the number of unstable sectors waiting to be remapped (ATA only).
+	PendingSectors uint64
+	// MediaErrors is the number of unrecovered data integrity errors.
+	MediaErrors  uint64
+	PowerOnHours uint64
+	// CriticalWarning is the NVMe critical warning bitmask.
+	CriticalWarning uint8
+}
+
+// Read reads the health of the device at devPath.
+//
+// Transport is the device transport as reported by sysblock, it selects the way
+// health is read.
+func Read(devPath, transport string) (*Health, error) {
+	var read func(*os.File) ([]byte, error)
+
+	var parse func([]byte) (*Health, error)
+
+	switch transport {
+	case "nvme":
+		read, parse = readNVMeHealthLog, ParseNVMeHealthLog
+	case "sata", "scsi":
+		read, parse = readATASmartData, ParseATASmartData
+	default:
+		return nil, ErrNotSupported
+	}
+
+	f, err := os.OpenFile(devPath, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	data, err := read(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading health of %q: %w", devPath, err)
+	}
+
+	return parse(data)
+}
@@ -0,0 +1,115 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package smart_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/smart"
+)
+
+func TestParseNVMeHealthLog(t *testing.T) {
+	for _, tt := range []struct {
+		fixture  string
+		expected smart.Health
+	}{
+		{
+			fixture: "nvme-healthy.bin",
+			expected: smart.Health{
+				Temperature:  37,
+				WearLevel:    3,
+				PowerOnHours: 1234,
+			},
+		},
+		{
+			fixture: "nvme-worn.bin",
+			expected: smart.Health{
+				Temperature:     78,
+				WearLevel:       97,
+				MediaErrors:     42,
+				PowerOnHours:    40210,
+				CriticalWarning: 0x04,
+			},
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join("testdata", tt.fixture))
+			require.NoError(t, err)
+
+			health, err := smart.ParseNVMeHealthLog(data)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, *health)
+		})
+	}
+
+	_, err := smart.ParseNVMeHealthLog(make([]byte, 64))
+	assert.Error(t, err)
+}
+
+func TestParseATASmartData(t *testing.T) {
+	for _, tt := range []struct {
+		fixture  string
+		expected smart.Health
+	}{
+		{
+			fixture: "ata-ssd.bin",
+			expected: smart.Health{
+				Temperature:  40,
+				WearLevel:    6,
+				PowerOnHours: 8760,
+			},
+		},
+		{
+			fixture: "ata-hdd-failing.bin",
+			expected: smart.Health{
+				Temperature:        45,
+				ReallocatedSectors: 16,
+				PendingSectors:     8,
+				MediaErrors:        3,
+				PowerOnHours:       30000,
+			},
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join("testdata", tt.fixture))
+			require.NoError(t, err)
+
+			health, err := smart.ParseATASmartData(data)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, *health)
+		})
+	}
+
+	_, err := smart.ParseATASmartData(make([]byte, 64))
+	assert.Error(t, err)
+}
+
+func TestParseATAAttributes(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "ata-ssd.bin"))
+	require.NoError(t, err)
+
+	attributes, err := smart.ParseATAAttributes(data)
+	require.NoError(t, err)
+
+	assert.Len(t, attributes, 10)
+	assert.Equal(t, smart.ATAAttribute{ID: 241, Current: 99, Worst: 99, Raw: 53873829}, attributes[241])
+	assert.Equal(t, smart.ATAAttribute{ID: 194, Current: 60, Worst: 45, Raw: 40 | 20<<16 | 55<<32}, attributes[smart.ATAAttributeTemperature])
+}
+
+func TestReadNotSupported(t *testing.T) {
+	_, err := smart.Read("/dev/vda", "virtio")
+	assert.ErrorIs(t, err, smart.ErrNotSupported)
+}
@@ -0,0 +1,341 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package sysblock gathers block device information from sysfs, udev database and mount table.
+package sysblock
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sectorSize is the unit of `size` in sysfs, it doesn't depend on the logical block size of the device.
+const sectorSize = 512
+
+// Paths to the sources of the block device information.
+type Paths struct {
+	// SysClassBlock is /sys/class/block.
+	SysClassBlock string
+	// UdevData is the udev database directory (/run/udev/data).
+	UdevData string
+	// MountInfo is /proc/self/mountinfo.
+	MountInfo string
+}
+
+// DefaultPaths returns paths for the running system.
+func DefaultPaths() Paths {
+	return Paths{
+		SysClassBlock: "/sys/class/block",
+		UdevData:      "/run/udev/data",
+		MountInfo:     "/proc/self/mountinfo",
+	}
+}
+
+// Device describes a block device or a partition.
+type Device struct {
+	// Name of the device (e.g. `sda` or `sda1`).
+	Name string
+	// DevNum is major:minor number of the device.
+	DevNum string
+	// Parent is the name of the whole disk for partitions.
+	Parent string
+	// PartitionNumber is set for partitions.
+	PartitionNumber int
+
+	Size       uint64
+	Model      string
+	Serial     string
+	WWID       string
+	Modalias   string
+	Transport  string
+	Rotational bool
+	ReadOnly   bool
+	Removable  bool
+
+	PartitionTable  string
+	Filesystem      string
+	FilesystemLabel string
+
+	PartitionLabel string
+	PartitionUUID  string
+	PartitionType  string
+
+	MountPoint string
+}
+
+// Path returns the path to the device node.
+func (dev *Device) Path() string {
+	return filepath.Join("/dev", dev.Name)
+}
+
+// IsPartition returns true if the device is a partition.
+func (dev *Device) IsPartition() bool {
+	return dev.Parent != ""
+}
+
+// skippedPrefixes lists devices which are not interesting for the inventory.
+var skippedPrefixes = []string{"loop", "ram", "zram"}
+
+// List returns block devices and partitions.
+//
+// Devices with zero size (e.g. empty card readers) are skipped.
+func List(paths Paths) ([]*Device, error) {
+	entries, err := ioutil.ReadDir(paths.SysClassBlock)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %w", paths.SysClassBlock, err)
+	}
+
+	mounts, err := readMountInfo(paths.MountInfo)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	devices := make([]*Device, 0, len(entries))
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		skip := false
+
+		for _, prefix := range skippedPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				skip = true
+
+				break
+			}
+		}
+
+		if skip {
+			continue
+		}
+
+		dev, err := get(paths, name)
+		if err != nil {
+			// device was removed while scanning
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if dev.Size == 0 {
+			continue
+		}
+
+		dev.MountPoint = mounts[dev.DevNum]
+
+		devices = append(devices, dev)
+	}
+
+	return devices, nil
+}
+
+//nolint:gocyclo
+func get(paths Paths, name string) (*Device, error) {
+	sysPath := filepath.Join(paths.SysClassBlock, name)
+
+	resolvedPath, err := filepath.EvalSymlinks(sysPath)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving %q: %w", sysPath, err)
+	}
+
+	dev := &Device{
+		Name:   name,
+		DevNum: readFile(resolvedPath, "dev"),
+	}
+
+	if partition := readFile(resolvedPath, "partition"); partition != "" {
+		dev.PartitionNumber, _ = strconv.Atoi(partition) //nolint:errcheck
+		dev.Parent = filepath.Base(filepath.Dir(resolvedPath))
+	}
+
+	if size, err := strconv.ParseUint(readFile(resolvedPath, "size"), 10, 64); err == nil {
+		dev.Size = size * sectorSize
+	}
+
+	dev.ReadOnly = readFile(resolvedPath, "ro") == "1"
+
+	// whole disk properties
+	diskPath := resolvedPath
+	if dev.IsPartition() {
+		diskPath = filepath.Dir(resolvedPath)
+	}
+
+	dev.Rotational = readFile(diskPath, "queue", "rotational") == "1"
+	dev.Removable = readFile(diskPath, "removable") == "1"
+	dev.Model = readFile(diskPath, "device", "model")
+	dev.Modalias = readFile(diskPath, "device", "modalias")
+	dev.Serial = firstNonEmpty(readFile(diskPath, "serial"), readFile(diskPath, "device", "serial"))
+	dev.WWID = firstNonEmpty(readFile(diskPath, "wwid"), readFile(diskPath, "device", "wwid"))
+	dev.Transport = transport(filepath.Base(diskPath), diskPath)
+
+	udev, err := readUdevData(paths.UdevData, dev.DevNum)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	dev.Serial = firstNonEmpty(dev.Serial, udev["ID_SERIAL_SHORT"])
+	dev.WWID = firstNonEmpty(dev.WWID, udev["ID_WWN"])
+	dev.Model = firstNonEmpty(dev.Model, strings.ReplaceAll(udev["ID_MODEL"], "_", " "))
+	dev.PartitionTable = udev["ID_PART_TABLE_TYPE"]
+	dev.Filesystem = udev["ID_FS_TYPE"]
+	dev.FilesystemLabel = udev["ID_FS_LABEL"]
+	dev.PartitionLabel = udev["ID_PART_ENTRY_NAME"]
+	dev.PartitionUUID = udev["ID_PART_ENTRY_UUID"]
+	dev.PartitionType = udev["ID_PART_ENTRY_TYPE"]
+
+	return dev, nil
+}
+
+// transport guesses the transport (bus) of the disk from the device name and the sysfs device path.
+func transport(name, sysPath string) string {
+	switch {
+	case strings.HasPrefix(name, "nvme"):
+		return "nvme"
+	case strings.HasPrefix(name, "mmcblk"):
+		return "mmc"
+	case strings.HasPrefix(name, "md"):
+		return "md"
+	case strings.HasPrefix(name, "dm-"):
+		return "dm"
+	case strings.Contains(sysPath, "/usb"):
+		return "usb"
+	case strings.Contains(sysPath, "/virtio"):
+		return "virtio"
+	case strings.Contains(sysPath, "/ata"):
+		return "sata"
+	case strings.Contains(sysPath, "/host"):
+		return "scsi"
+	default:
+		return ""
+	}
+}
+
+func readFile(parts ...string) string {
+	contents, err := ioutil.ReadFile(filepath.Join(parts...))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(contents))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// readUdevData reads udev properties (`E:` records) of the block device from the udev database.
+func readUdevData(udevDataPath, devNum string) (map[string]string, error) {
+	properties := map[string]string{}
+
+	if devNum == "" {
+		return properties, nil
+	}
+
+	f, err := os.Open(filepath.Join(udevDataPath, "b"+devNum))
+	if err != nil {
+		return properties, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if !strings.HasPrefix(line, "E:") {
+			continue
+		}
+
+		parts := strings.SplitN(line[2:], "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		properties[parts[0]] = parts[1]
+	}
+
+	return properties, scanner.Err()
+}
+
+// readMountInfo returns the map of device numbers (major:minor) to the first mountpoint.
+func readMountInfo(path string) (map[string]string, error) {
+	mounts := map[string]string{}
+	bindMounts := map[string]string{}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return mounts, err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		devNum, root, mountPoint := fields[2], fields[3], unescapeMountPath(fields[4])
+
+		// prefer mounts of the filesystem root over bind mounts of the subdirectories
+		if root == "/" {
+			if _, ok := mounts[devNum]; !ok {
+				mounts[devNum] = mountPoint
+			}
+		} else if _, ok := bindMounts[devNum]; !ok {
+			bindMounts[devNum] = mountPoint
+		}
+	}
+
+	for devNum, mountPoint := range bindMounts {
+		if _, ok := mounts[devNum]; !ok {
+			mounts[devNum] = mountPoint
+		}
+	}
+
+	return mounts, scanner.Err()
+}
+
+// unescapeMountPath decodes octal escapes (e.g. `\040` for space) in the mountinfo paths.
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, `\`) {
+		return path
+	}
+
+	var sb strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if c, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				sb.WriteByte(byte(c))
+
+				i += 3
+
+				continue
+			}
+		}
+
+		sb.WriteByte(path[i])
+	}
+
+	return sb.String()
+}
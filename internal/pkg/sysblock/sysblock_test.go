@@ -0,0 +1,142 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package sysblock_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/sysblock"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for path, contents := range files {
+		path = filepath.Join(root, path)
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents+"\n"), 0o644))
+	}
+}
+
+func TestList(t *testing.T) {
+	root, err := ioutil.TempDir("", "talos")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(root) //nolint:errcheck
+
+	sda := "devices/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0/block/sda"
+	nvme := "devices/pci0000:00/0000:00:1d.0/nvme/nvme0/nvme0n1"
+	loop := "devices/virtual/block/loop0"
+	sr := "devices/pci0000:00/0000:00:1f.2/ata2/host1/target1:0:0/1:0:0:0/block/sr0"
+
+	writeFiles(t, root, map[string]string{
+		sda + "/dev":               "8:0",
+		sda + "/size":              "2097152",
+		sda + "/ro":                "0",
+		sda + "/removable":         "0",
+		sda + "/queue/rotational":  "1",
+		sda + "/device/model":      "QEMU HARDDISK",
+		sda + "/sda1/dev":          "8:1",
+		sda + "/sda1/partition":    "1",
+		sda + "/sda1/size":         "1024",
+		sda + "/sda2/dev":          "8:2",
+		sda + "/sda2/partition":    "2",
+		sda + "/sda2/size":         "2048",
+		nvme + "/dev":              "259:0",
+		nvme + "/size":             "4096",
+		nvme + "/queue/rotational": "0",
+		nvme + "/wwid":             "eui.0025388b91b0b0c1",
+		nvme + "/device/model":     "Samsung SSD 970",
+		nvme + "/device/serial":    "S1234",
+		loop + "/dev":              "7:0",
+		loop + "/size":             "1024",
+		sr + "/dev":                "11:0",
+		sr + "/size":               "0",
+		"udev/b8:0":                "S:disk/by-id/ata-QEMU_HARDDISK_QM00001\nE:ID_SERIAL_SHORT=QM00001\nE:ID_WWN=0x5000c500a0b1c2d3\nE:ID_PART_TABLE_TYPE=gpt",
+		"udev/b8:1":                "E:ID_FS_TYPE=xfs\nE:ID_FS_LABEL=EPHEMERAL\nE:ID_PART_ENTRY_NAME=EPHEMERAL\nE:ID_PART_ENTRY_UUID=1ab2c3d4-0000-0000-0000-000000000001\nE:ID_PART_ENTRY_TYPE=0fc63daf-8483-4772-8e79-3d69d8477de4",
+		"mountinfo": "30 1 8:1 /kubelet /var/lib/kubelet rw - xfs /dev/sda1 rw\n" +
+			"31 1 8:1 / /var rw - xfs /dev/sda1 rw\n" +
+			"32 1 8:2 / /var/mnt/my\\040data rw - ext4 /dev/sda2 rw",
+	})
+
+	classBlock := filepath.Join(root, "class/block")
+	require.NoError(t, os.MkdirAll(classBlock, 0o755))
+
+	for name, target := range map[string]string{
+		"sda":     sda,
+		"sda1":    sda + "/sda1",
+		"sda2":    sda + "/sda2",
+		"nvme0n1": nvme,
+		"loop0":   loop,
+		"sr0":     sr,
+	} {
+		require.NoError(t, os.Symlink(filepath.Join("../..", target), filepath.Join(classBlock, name)))
+	}
+
+	// device which disappeared during the scan is skipped
+	require.NoError(t, os.Symlink("../../devices/pci0000:00/0000:00:1f.2/ata2/host1/target1:0:0/1:0:0:0/block/sdz", filepath.Join(classBlock, "sdz")))
+
+	devices, err := sysblock.List(sysblock.Paths{
+		SysClassBlock: classBlock,
+		UdevData:      filepath.Join(root, "udev"),
+		MountInfo:     filepath.Join(root, "mountinfo"),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, devices, 4)
+
+	for _, dev := range devices {
+		assert.NotEqual(t, "sdz", dev.Name)
+	}
+
+	assert.Equal(t, &sysblock.Device{
+		Name:      "nvme0n1",
+		DevNum:    "259:0",
+		Size:      4096 * 512,
+		Model:     "Samsung SSD 970",
+		Serial:    "S1234",
+		WWID:      "eui.0025388b91b0b0c1",
+		Transport: "nvme",
+	}, devices[0])
+
+	assert.Equal(t, &sysblock.Device{
+		Name:           "sda",
+		DevNum:         "8:0",
+		Size:           2097152 * 512,
+		Model:          "QEMU HARDDISK",
+		Serial:         "QM00001",
+		WWID:           "0x5000c500a0b1c2d3",
+		Transport:      "sata",
+		Rotational:     true,
+		PartitionTable: "gpt",
+	}, devices[1])
+
+	assert.Equal(t, &sysblock.Device{
+		Name:            "sda1",
+		DevNum:          "8:1",
+		Parent:          "sda",
+		PartitionNumber: 1,
+		Size:            1024 * 512,
+		Model:           "QEMU HARDDISK",
+		Transport:       "sata",
+		Rotational:      true,
+		Filesystem:      "xfs",
+		FilesystemLabel: "EPHEMERAL",
+		PartitionLabel:  "EPHEMERAL",
+		PartitionUUID:   "1ab2c3d4-0000-0000-0000-000000000001",
+		PartitionType:   "0fc63daf-8483-4772-8e79-3d69d8477de4",
+		MountPoint:      "/var",
+	}, devices[2])
+
+	assert.Equal(t, "sda2", devices[3].Name)
+	assert.Equal(t, "/var/mnt/my data", devices[3].MountPoint)
+	assert.True(t, devices[3].IsPartition())
+	assert.Equal(t, "/dev/sda2", devices[3].Path())
+}
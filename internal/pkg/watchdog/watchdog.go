@@ -0,0 +1,97 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package watchdog provides a simple wrapper around Linux watchdog timer API.
+package watchdog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/talos-systems/talos/internal/pkg/kernel/kmod"
+)
+
+// Device is an open watchdog timer device.
+//
+// Once the device is open, the node is reset if the watchdog is not pet within the timeout.
+type Device struct {
+	f *os.File
+}
+
+// Open the watchdog device.
+//
+// If the device doesn't exist and loadSoftdog is set, the softdog kernel module is loaded.
+func Open(path string, loadSoftdog bool) (*Device, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil && errors.Is(err, os.ErrNotExist) && loadSoftdog {
+		if err = LoadSoftdog(); err != nil {
+			return nil, err
+		}
+
+		f, err = os.OpenFile(path, os.O_WRONLY, 0)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error opening watchdog device: %w", err)
+	}
+
+	return &Device{f: f}, nil
+}
+
+// SetTimeout sets the watchdog timeout.
+//
+// Timeout is rounded to seconds, the device might adjust the timeout to the supported value.
+func (d *Device) SetTimeout(timeout time.Duration) error {
+	if err := unix.IoctlSetPointerInt(int(d.f.Fd()), unix.WDIOC_SETTIMEOUT, int(timeout.Seconds())); err != nil {
+		return fmt.Errorf("error setting watchdog timeout: %w", err)
+	}
+
+	return nil
+}
+
+// Pet resets the watchdog timer.
+func (d *Device) Pet() error {
+	if err := unix.IoctlWatchdogKeepalive(int(d.f.Fd())); err != nil {
+		return fmt.Errorf("error petting watchdog: %w", err)
+	}
+
+	return nil
+}
+
+// Close disarms the watchdog and closes the device.
+//
+// The magic character is written before closing the device, so that the watchdog is stopped
+// (unless the kernel is built with CONFIG_WATCHDOG_NOWAYOUT).
+func (d *Device) Close() error {
+	if _, err := d.f.Write([]byte("V")); err != nil {
+		d.f.Close() //nolint:errcheck
+
+		return fmt.Errorf("error disarming watchdog: %w", err)
+	}
+
+	return d.f.Close()
+}
+
+// LoadSoftdog loads the softdog kernel module which provides software watchdog timer.
+func LoadSoftdog() error {
+	root, err := kmod.DefaultRoot()
+	if err != nil {
+		return err
+	}
+
+	idx, err := kmod.NewIndex(root)
+	if err != nil {
+		return fmt.Errorf("error building kernel modules index: %w", err)
+	}
+
+	if err = idx.Load("softdog", nil); err != nil {
+		return fmt.Errorf("error loading softdog module: %w", err)
+	}
+
+	return nil
+}
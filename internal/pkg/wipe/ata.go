@@ -0,0 +1,205 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package wipe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/talos-systems/talos/internal/pkg/passthrough"
+)
+
+const (
+	ataIdentifySize = 512
+
+	// temporary user password which is required to issue the erase, it's cleared by the erase itself.
+	ataErasePassword = "talos"
+
+	// used if the drive doesn't report the erase time.
+	ataDefaultEraseTime   = 12 * time.Hour
+	ataEraseTimeout       = 30 * time.Minute
+	ataProgressInterval   = 10 * time.Second
+	ataEnhancedEraseFlag  = 1 << 1
+	ataPasswordDataOffset = 2
+)
+
+// ATASecurity is the state of the ATA security feature set.
+type ATASecurity struct {
+	Supported              bool
+	Enabled                bool
+	Locked                 bool
+	Frozen                 bool
+	EnhancedEraseSupported bool
+	// Erase times are estimated by the drive, zero if not reported.
+	EraseTime         time.Duration
+	EnhancedEraseTime time.Duration
+}
+
+// ParseATAIdentify parses the security feature set state from the IDENTIFY DEVICE data.
+func ParseATAIdentify(data []byte) (ATASecurity, error) {
+	if len(data) < ataIdentifySize {
+		return ATASecurity{}, fmt.Errorf("ATA identify data is too short: %d bytes", len(data))
+	}
+
+	word := func(n int) uint16 {
+		return binary.LittleEndian.Uint16(data[n*2 : n*2+2])
+	}
+
+	// erase time is reported in 2 minute units, bit 15 selects the extended format
+	eraseTime := func(w uint16) time.Duration {
+		if w&0x8000 != 0 {
+			w &= 0x7fff
+		} else {
+			w &= 0xff
+		}
+
+		return time.Duration(w) * 2 * time.Minute
+	}
+
+	security := word(128)
+
+	return ATASecurity{
+		Supported:              security&(1<<0) != 0,
+		Enabled:                security&(1<<1) != 0,
+		Locked:                 security&(1<<2) != 0,
+		Frozen:                 security&(1<<3) != 0,
+		EnhancedEraseSupported: security&(1<<5) != 0,
+		EraseTime:              eraseTime(word(89)),
+		EnhancedEraseTime:      eraseTime(word(90)),
+	}, nil
+}
+
+//nolint:gocyclo
+func ataSecureErase(f *os.File, size uint64, progress ProgressFunc) (string, error) {
+	identify := make([]byte, ataIdentifySize)
+
+	if err := passthrough.ATA(f, passthrough.ATACommand{
+		Command:     passthrough.ATAIdentifyDevice,
+		SectorCount: 1,
+		Direction:   passthrough.ATAFromDevice,
+	}, identify); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrSecureEraseNotSupported, err)
+	}
+
+	security, err := ParseATAIdentify(identify)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case !security.Supported:
+		return "", ErrSecureEraseNotSupported
+	case security.Frozen:
+		// BIOS usually freezes the security on boot, suspend/resume unfreezes it
+		return "", fmt.Errorf("%w: ATA security is frozen", ErrSecureEraseNotSupported)
+	case security.Enabled, security.Locked:
+		return "", errors.New("ATA security password is already set")
+	}
+
+	method, eraseTime := MethodATASecurityErase, security.EraseTime
+
+	if security.EnhancedEraseSupported {
+		method, eraseTime = MethodATASecurityEraseEnhanced, security.EnhancedEraseTime
+	}
+
+	if eraseTime == 0 {
+		eraseTime = ataDefaultEraseTime
+	}
+
+	password := make([]byte, ataIdentifySize)
+	copy(password[ataPasswordDataOffset:], ataErasePassword)
+
+	if err = passthrough.ATA(f, passthrough.ATACommand{
+		Command:     passthrough.ATASecuritySetPassword,
+		SectorCount: 1,
+		Direction:   passthrough.ATAToDevice,
+	}, password); err != nil {
+		return "", err
+	}
+
+	if err = ataErase(f, password, security.EnhancedEraseSupported, eraseTime, size, progress); err != nil {
+		// don't leave the password set, as the drive would be locked on the next power cycle
+		passthrough.ATA(f, passthrough.ATACommand{ //nolint:errcheck
+			Command:     passthrough.ATASecurityDisablePassword,
+			SectorCount: 1,
+			Direction:   passthrough.ATAToDevice,
+		}, password)
+
+		return "", err
+	}
+
+	return method, nil
+}
+
+func ataErase(f *os.File, password []byte, enhanced bool, eraseTime time.Duration, size uint64, progress ProgressFunc) error {
+	if err := passthrough.ATA(f, passthrough.ATACommand{
+		Command:   passthrough.ATASecurityErasePrepare,
+		Direction: passthrough.ATANoData,
+	}, nil); err != nil {
+		return err
+	}
+
+	erase := append([]byte(nil), password...)
+
+	if enhanced {
+		erase[0] |= ataEnhancedEraseFlag
+	}
+
+	// the drive doesn't report erase progress, so it's estimated from the erase time
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(ataProgressInterval)
+		defer ticker.Stop()
+
+		started := time.Now()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			estimate := float64(time.Since(started)) / float64(eraseTime)
+			if estimate > 0.99 {
+				estimate = 0.99
+			}
+
+			progress(uint64(estimate*float64(size)), size)
+		}
+	}()
+
+	err := passthrough.ATA(f, passthrough.ATACommand{
+		Command:     passthrough.ATASecurityEraseUnit,
+		SectorCount: 1,
+		Direction:   passthrough.ATAToDevice,
+		Timeout:     eraseTime + ataEraseTimeout,
+	}, erase)
+
+	close(done)
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	progress(size, size)
+
+	return nil
+}
@@ -0,0 +1,203 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package wipe
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/talos-systems/talos/internal/pkg/passthrough"
+)
+
+const (
+	nvmeIdentifySize       = 4096
+	nvmeIdentifyNamespace  = 0x00
+	nvmeIdentifyController = 0x01
+
+	nvmeLogSanitizeStatus  = 0x81
+	nvmeSanitizeStatusSize = 512
+
+	nvmeSanitizeBlockErase  = 0x02
+	nvmeSanitizeCryptoErase = 0x04
+	// allow unrestricted sanitize exit, so that the failed sanitize doesn't leave the controller unusable.
+	nvmeSanitizeAUSE = 1 << 3
+
+	nvmeFormatUserDataErase = 0x01
+	nvmeFormatCryptoErase   = 0x02
+
+	nvmeSanitizePollInterval = 5 * time.Second
+	nvmeFormatTimeout        = 2 * time.Hour
+)
+
+// NVMe sanitize operation status.
+const (
+	NVMeSanitizeNeverSanitized        = 0
+	NVMeSanitizeCompleted             = 1
+	NVMeSanitizeInProgress            = 2
+	NVMeSanitizeFailed                = 3
+	NVMeSanitizeCompletedNoDeallocate = 4
+)
+
+// NVMeCapabilities are the secure erase capabilities of the NVMe controller.
+type NVMeCapabilities struct {
+	Format              bool
+	FormatCryptoErase   bool
+	SanitizeCryptoErase bool
+	SanitizeBlockErase  bool
+}
+
+// NVMeSanitizeStatus is the state of the sanitize operation.
+type NVMeSanitizeStatus struct {
+	// Progress of the sanitize operation in progress (0..1).
+	Progress float64
+	Status   uint8
+}
+
+// ParseNVMeIdentifyController parses secure erase capabilities from the Identify Controller data structure.
+func ParseNVMeIdentifyController(data []byte) (NVMeCapabilities, error) {
+	if len(data) < nvmeIdentifySize {
+		return NVMeCapabilities{}, fmt.Errorf("NVMe identify data is too short: %d bytes", len(data))
+	}
+
+	oacs := binary.LittleEndian.Uint16(data[256:258])
+	sanicap := binary.LittleEndian.Uint32(data[328:332])
+	fna := data[524]
+
+	return NVMeCapabilities{
+		Format:              oacs&(1<<1) != 0,
+		FormatCryptoErase:   fna&(1<<2) != 0,
+		SanitizeCryptoErase: sanicap&(1<<0) != 0,
+		SanitizeBlockErase:  sanicap&(1<<1) != 0,
+	}, nil
+}
+
+// ParseNVMeSanitizeStatus parses the Sanitize Status log page (log ID 81h).
+func ParseNVMeSanitizeStatus(data []byte) (NVMeSanitizeStatus, error) {
+	if len(data) < 4 {
+		return NVMeSanitizeStatus{}, fmt.Errorf("NVMe sanitize status is too short: %d bytes", len(data))
+	}
+
+	return NVMeSanitizeStatus{
+		Progress: float64(binary.LittleEndian.Uint16(data[0:2])) / 65536,
+		Status:   data[2] & 0x07,
+	}, nil
+}
+
+func nvmeSecureErase(ctx context.Context, f *os.File, size uint64, progress ProgressFunc) (string, error) {
+	identify := make([]byte, nvmeIdentifySize)
+
+	if err := passthrough.NVMeAdmin(f, &passthrough.NVMeAdminCommand{
+		Opcode: passthrough.NVMeAdminIdentify,
+		CDW10:  nvmeIdentifyController,
+	}, identify); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrSecureEraseNotSupported, err)
+	}
+
+	caps, err := ParseNVMeIdentifyController(identify)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case caps.SanitizeCryptoErase:
+		return MethodNVMeSanitizeCryptoErase, nvmeSanitize(ctx, f, nvmeSanitizeCryptoErase, size, progress)
+	case caps.SanitizeBlockErase:
+		return MethodNVMeSanitizeBlockErase, nvmeSanitize(ctx, f, nvmeSanitizeBlockErase, size, progress)
+	case caps.Format && caps.FormatCryptoErase:
+		return MethodNVMeFormatCryptoErase, nvmeFormat(f, nvmeFormatCryptoErase, size, progress)
+	case caps.Format:
+		return MethodNVMeFormatUserDataErase, nvmeFormat(f, nvmeFormatUserDataErase, size, progress)
+	default:
+		return "", ErrSecureEraseNotSupported
+	}
+}
+
+// nvmeSanitize sanitizes the whole NVMe controller (all namespaces).
+//
+// Sanitize runs in the background on the controller, so the status is polled until it's done or ctx is canceled.
+func nvmeSanitize(ctx context.Context, f *os.File, action uint32, size uint64, progress ProgressFunc) error {
+	if err := passthrough.NVMeAdmin(f, &passthrough.NVMeAdminCommand{
+		Opcode: passthrough.NVMeAdminSanitize,
+		CDW10:  action | nvmeSanitizeAUSE,
+	}, nil); err != nil {
+		return err
+	}
+
+	progress(0, size)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("error waiting for NVMe sanitize to complete: %w", ctx.Err())
+		case <-time.After(nvmeSanitizePollInterval):
+		}
+
+		data := make([]byte, nvmeSanitizeStatusSize)
+
+		if err := passthrough.NVMeGetLogPage(f, nvmeLogSanitizeStatus, data); err != nil {
+			return err
+		}
+
+		status, err := ParseNVMeSanitizeStatus(data)
+		if err != nil {
+			return err
+		}
+
+		switch status.Status {
+		case NVMeSanitizeCompleted, NVMeSanitizeCompletedNoDeallocate:
+			progress(size, size)
+
+			return nil
+		case NVMeSanitizeFailed:
+			return errors.New("NVMe sanitize failed")
+		case NVMeSanitizeInProgress:
+			progress(uint64(status.Progress*float64(size)), size)
+		}
+	}
+}
+
+// nvmeFormat formats the NVMe namespace keeping the current LBA format.
+func nvmeFormat(f *os.File, ses uint32, size uint64, progress ProgressFunc) error {
+	nsid, err := passthrough.NVMeNamespaceID(f)
+	if err != nil {
+		return err
+	}
+
+	identify := make([]byte, nvmeIdentifySize)
+
+	if err = passthrough.NVMeAdmin(f, &passthrough.NVMeAdminCommand{
+		Opcode: passthrough.NVMeAdminIdentify,
+		NSID:   nsid,
+		CDW10:  nvmeIdentifyNamespace,
+	}, identify); err != nil {
+		return err
+	}
+
+	// FLBAS: index of the LBA format in use
+	lbaf := uint32(identify[26] & 0x0f)
+
+	progress(0, size)
+
+	// format NVM doesn't report progress, the command blocks until it's done
+	if err = passthrough.NVMeAdmin(f, &passthrough.NVMeAdminCommand{
+		Opcode:    passthrough.NVMeAdminFormatNVM,
+		NSID:      nsid,
+		CDW10:     lbaf | ses<<9,
+		TimeoutMs: uint32(nvmeFormatTimeout.Milliseconds()),
+	}, nil); err != nil {
+		return err
+	}
+
+	progress(size, size)
+
+	return nil
+}
@@ -0,0 +1,221 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+// Package wipe implements wiping the disks for decommissioning.
+package wipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/talos-systems/go-blockdevice/blockdevice"
+	"golang.org/x/sys/unix"
+)
+
+// Mode is the wipe mode.
+type Mode int
+
+// Wipe modes, the values match machine.WipeRequest_Mode.
+const (
+	// ModeFast wipes the partition table and filesystem signatures.
+	ModeFast Mode = iota
+	// ModeZero overwrites the whole disk with zeroes.
+	ModeZero
+	// ModeSecureErase uses the hardware secure erase, falling back to ModeZero if it's not supported.
+	ModeSecureErase
+)
+
+// Wipe methods reported back to the caller.
+const (
+	MethodSignatures               = "signatures"
+	MethodZeroFill                 = "zero-fill"
+	MethodATASecurityErase         = "ata-security-erase"
+	MethodATASecurityEraseEnhanced = "ata-security-erase-enhanced"
+	MethodNVMeSanitizeCryptoErase  = "nvme-sanitize-crypto-erase"
+	MethodNVMeSanitizeBlockErase   = "nvme-sanitize-block-erase"
+	MethodNVMeFormatCryptoErase    = "nvme-format-crypto-erase"
+	MethodNVMeFormatUserDataErase  = "nvme-format-user-data-erase"
+)
+
+const (
+	// signatureRange is wiped at the beginning (and the end) of the disk and each partition in fast mode.
+	signatureRange = 1 << 20
+	zeroFillChunk  = 64 << 20
+	sectorSize     = 512
+	sysfsBlockPath = "/sys/class/block"
+)
+
+// ErrSecureEraseNotSupported is returned if the device doesn't support hardware secure erase.
+var ErrSecureEraseNotSupported = errors.New("secure erase is not supported")
+
+// ProgressFunc is called periodically with the number of bytes wiped.
+type ProgressFunc func(done, total uint64)
+
+// Disk wipes the disk at path.
+//
+// Transport is the device transport as reported by sysblock, it selects the secure erase method.
+// The method which was used to wipe the disk is returned.
+func Disk(ctx context.Context, path, transport string, mode Mode, progress ProgressFunc) (string, error) {
+	if progress == nil {
+		progress = func(done, total uint64) {}
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", fmt.Errorf("error getting size of %q: %w", path, err)
+	}
+
+	switch mode {
+	case ModeFast:
+		return MethodSignatures, fast(f, uint64(size), progress)
+	case ModeZero:
+		return MethodZeroFill, zeroFill(f, uint64(size), progress)
+	case ModeSecureErase:
+		var method string
+
+		switch transport {
+		case "nvme":
+			method, err = nvmeSecureErase(ctx, f, uint64(size), progress)
+		case "sata", "scsi":
+			method, err = ataSecureErase(f, uint64(size), progress)
+		default:
+			err = ErrSecureEraseNotSupported
+		}
+
+		if errors.Is(err, ErrSecureEraseNotSupported) {
+			return MethodZeroFill, zeroFill(f, uint64(size), progress)
+		}
+
+		return method, err
+	default:
+		return "", fmt.Errorf("unsupported wipe mode %d", mode)
+	}
+}
+
+// fast discards the device contents (if supported) and zeroes the partition table and
+// the filesystem signatures at the beginning of each partition.
+func fast(f *os.File, size uint64, progress ProgressFunc) error {
+	discard := [2]uint64{0, size}
+
+	// ignoring the error here as DISCARD might be not supported by the device
+	unix.Syscall(unix.SYS_IOCTL, f.Fd(), blockdevice.BLKDISCARD, uintptr(unsafe.Pointer(&discard[0]))) //nolint:errcheck
+
+	// backup GPT is stored at the end of the disk
+	ranges := [][2]uint64{{0, signatureRange}, {size - signatureRange, signatureRange}}
+
+	if size < signatureRange {
+		ranges = [][2]uint64{{0, size}}
+	}
+
+	for _, offset := range partitionOffsets(sysfsBlockPath, f.Name()) {
+		ranges = append(ranges, [2]uint64{offset, signatureRange})
+	}
+
+	for _, r := range ranges {
+		if r[0] >= size {
+			continue
+		}
+
+		if r[0]+r[1] > size {
+			r[1] = size - r[0]
+		}
+
+		if err := zeroRange(f, r[0], r[1]); err != nil {
+			return err
+		}
+	}
+
+	progress(size, size)
+
+	return f.Sync()
+}
+
+// zeroFill overwrites the device with zeroes.
+func zeroFill(f *os.File, size uint64, progress ProgressFunc) error {
+	for offset := uint64(0); offset < size; offset += zeroFillChunk {
+		length := uint64(zeroFillChunk)
+
+		if offset+length > size {
+			length = size - offset
+		}
+
+		if err := zeroRange(f, offset, length); err != nil {
+			return err
+		}
+
+		progress(offset+length, size)
+	}
+
+	return f.Sync()
+}
+
+// zeroRange zeroes [offset, offset+length) with BLKZEROOUT falling back to writing zeroes.
+func zeroRange(f *os.File, offset, length uint64) error {
+	r := [2]uint64{offset, length}
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), blockdevice.BLKZEROOUT, uintptr(unsafe.Pointer(&r[0]))); errno == 0 {
+		return nil
+	}
+
+	buf := make([]byte, signatureRange)
+
+	for length > 0 {
+		n := uint64(len(buf))
+		if n > length {
+			n = length
+		}
+
+		if _, err := f.WriteAt(buf[:n], int64(offset)); err != nil {
+			return fmt.Errorf("error zeroing %q at %d: %w", f.Name(), offset, err)
+		}
+
+		offset += n
+		length -= n
+	}
+
+	return nil
+}
+
+// partitionOffsets returns byte offsets of the partitions of the device as seen by the kernel.
+func partitionOffsets(sysfsPath, devPath string) []uint64 {
+	name := filepath.Base(devPath)
+
+	matches, _ := filepath.Glob(filepath.Join(sysfsPath, name, name+"*", "start")) //nolint:errcheck
+
+	offsets := make([]uint64, 0, len(matches))
+
+	for _, match := range matches {
+		contents, err := ioutil.ReadFile(match)
+		if err != nil {
+			continue
+		}
+
+		start, err := strconv.ParseUint(strings.TrimSpace(string(contents)), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		offsets = append(offsets, start*sectorSize)
+	}
+
+	return offsets
+}
@@ -0,0 +1,146 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package wipe_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talos-systems/talos/internal/pkg/wipe"
+)
+
+const diskSize = 3 << 20
+
+func makeDisk(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "disk")
+
+	require.NoError(t, ioutil.WriteFile(path, bytes.Repeat([]byte{0xaa}, diskSize), 0o600))
+
+	return path
+}
+
+func TestDisk(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		mode      wipe.Mode
+		transport string
+		method    string
+		// zeroed is the list of [start, end) ranges expected to be zeroed, everything else is untouched
+		zeroed [][2]int
+	}{
+		{
+			name:   "fast",
+			mode:   wipe.ModeFast,
+			method: wipe.MethodSignatures,
+			zeroed: [][2]int{{0, 1 << 20}, {2 << 20, diskSize}},
+		},
+		{
+			name:   "zero",
+			mode:   wipe.ModeZero,
+			method: wipe.MethodZeroFill,
+			zeroed: [][2]int{{0, diskSize}},
+		},
+		{
+			name:      "secure erase fallback",
+			mode:      wipe.ModeSecureErase,
+			transport: "virtio",
+			method:    wipe.MethodZeroFill,
+			zeroed:    [][2]int{{0, diskSize}},
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			path := makeDisk(t)
+
+			var done, total uint64
+
+			method, err := wipe.Disk(context.Background(), path, tt.transport, tt.mode, func(d, t uint64) {
+				done, total = d, t
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.method, method)
+			assert.EqualValues(t, diskSize, done)
+			assert.EqualValues(t, diskSize, total)
+
+			contents, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+
+			expected := bytes.Repeat([]byte{0xaa}, diskSize)
+
+			for _, r := range tt.zeroed {
+				copy(expected[r[0]:r[1]], make([]byte, r[1]-r[0]))
+			}
+
+			assert.True(t, bytes.Equal(expected, contents))
+		})
+	}
+}
+
+func TestParseATAIdentify(t *testing.T) {
+	data := make([]byte, 512)
+
+	binary.LittleEndian.PutUint16(data[89*2:], 30)
+	binary.LittleEndian.PutUint16(data[90*2:], 0x8000|600)
+	binary.LittleEndian.PutUint16(data[128*2:], 1<<0|1<<3|1<<5)
+
+	security, err := wipe.ParseATAIdentify(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, wipe.ATASecurity{
+		Supported:              true,
+		Frozen:                 true,
+		EnhancedEraseSupported: true,
+		EraseTime:              time.Hour,
+		EnhancedEraseTime:      20 * time.Hour,
+	}, security)
+
+	_, err = wipe.ParseATAIdentify(data[:256])
+	assert.Error(t, err)
+}
+
+func TestParseNVMeIdentifyController(t *testing.T) {
+	data := make([]byte, 4096)
+
+	binary.LittleEndian.PutUint16(data[256:], 1<<1)
+	binary.LittleEndian.PutUint32(data[328:], 1<<1)
+	data[524] = 1 << 2
+
+	caps, err := wipe.ParseNVMeIdentifyController(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, wipe.NVMeCapabilities{
+		Format:             true,
+		FormatCryptoErase:  true,
+		SanitizeBlockErase: true,
+	}, caps)
+}
+
+func TestParseNVMeSanitizeStatus(t *testing.T) {
+	data := make([]byte, 512)
+
+	binary.LittleEndian.PutUint16(data[0:], 0x8000)
+	data[2] = 0x10 | wipe.NVMeSanitizeInProgress
+
+	status, err := wipe.ParseNVMeSanitizeStatus(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, wipe.NVMeSanitizeStatus{
+		Progress: 0.5,
+		Status:   wipe.NVMeSanitizeInProgress,
+	}, status)
+}
@@ -93,6 +93,40 @@ func (s *APICrashDumper) CrashDump(ctx context.Context, out io.Writer) {
 					r.Close() //nolint:errcheck
 				}
 			}
+
+			dumpKernelCrashRecords(nodeCtx, cli, out)
 		}(node)
 	}
 }
+
+func dumpKernelCrashRecords(ctx context.Context, cli *client.Client, out io.Writer) {
+	const title = "kernel crash records (previous boot)"
+
+	stream, err := cli.DmesgPrevious(ctx)
+	if err != nil {
+		fmt.Fprintf(out, "error getting kernel crash records: %s\n", err)
+
+		return
+	}
+
+	r, errCh, err := client.ReadStream(stream)
+	if err != nil {
+		fmt.Fprintf(out, "error getting kernel crash records: %s\n", err)
+
+		return
+	}
+
+	defer r.Close() //nolint:errcheck
+
+	fmt.Fprintf(out, "\n> %s\n%s\n\n", title, strings.Repeat("-", len(title)+2))
+
+	_, err = io.Copy(out, r)
+	if err != nil {
+		fmt.Fprintf(out, "error streaming kernel crash records: %s\n", err)
+	}
+
+	err = <-errCh
+	if err != nil {
+		fmt.Fprintf(out, "error streaming kernel crash records: %s\n", err)
+	}
+}
@@ -27,14 +27,11 @@ import (
 
 	"github.com/talos-systems/talos/pkg/cluster"
 	"github.com/talos-systems/talos/pkg/kubernetes"
-	"github.com/talos-systems/talos/pkg/machinery/api/machine"
 	"github.com/talos-systems/talos/pkg/machinery/client"
-	"github.com/talos-systems/talos/pkg/machinery/config/configloader"
 	v1alpha1config "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
 	machinetype "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/machine"
 	"github.com/talos-systems/talos/pkg/machinery/constants"
-	"github.com/talos-systems/talos/pkg/resources/config"
 	"github.com/talos-systems/talos/pkg/resources/k8s"
 	"github.com/talos-systems/talos/pkg/resources/v1alpha1"
 )
@@ -189,10 +186,10 @@ func IsSelfHostedControlPlane(ctx context.Context, cluster cluster.ClientProvide
 // updateNodeConfig reads self-hosted settings and secrets from K8s and stores them back to node configs.
 //
 //nolint:gocyclo
-func updateNodeConfig(ctx context.Context, cluster ConvertProvider, options *ConvertOptions) error {
+func updateNodeConfig(ctx context.Context, clusterProvider ConvertProvider, options *ConvertOptions) error {
 	fmt.Println("gathering control plane configuration")
 
-	k8sClient, err := cluster.K8sHelper(ctx)
+	k8sClient, err := clusterProvider.K8sHelper(ctx)
 	if err != nil {
 		return fmt.Errorf("error building kubernetes client: %w", err)
 	}
@@ -252,7 +249,7 @@ func updateNodeConfig(ctx context.Context, cluster ConvertProvider, options *Con
 	for _, node := range options.masterNodes {
 		fmt.Printf("patching master node %q configuration\n", node)
 
-		if err = patchNodeConfig(ctx, cluster, node, func(config *v1alpha1config.Config) error {
+		if err = cluster.PatchNodeConfig(ctx, clusterProvider, node, func(config *v1alpha1config.Config) error {
 			if config.ClusterConfig == nil {
 				config.ClusterConfig = &v1alpha1config.ClusterConfig{}
 			}
@@ -287,67 +284,6 @@ func updateNodeConfig(ctx context.Context, cluster ConvertProvider, options *Con
 	return nil
 }
 
-// patchNodeConfig updates node configuration by means of patch function.
-//
-//nolint:gocyclo
-func patchNodeConfig(ctx context.Context, cluster ConvertProvider, node string, patchFunc func(config *v1alpha1config.Config) error) error {
-	c, err := cluster.Client()
-	if err != nil {
-		return fmt.Errorf("error building Talos API client: %w", err)
-	}
-
-	ctx = client.WithNodes(ctx, node)
-
-	resources, err := c.Resources.Get(ctx, config.NamespaceName, config.MachineConfigType, config.V1Alpha1ID)
-	if err != nil {
-		return fmt.Errorf("error fetching config resource: %w", err)
-	}
-
-	if len(resources) != 1 {
-		return fmt.Errorf("expected 1 instance of config resource, got %d", len(resources))
-	}
-
-	r := resources[0]
-
-	yamlConfig, err := yaml.Marshal(r.Resource.Spec())
-	if err != nil {
-		return fmt.Errorf("error getting YAML config: %w", err)
-	}
-
-	config, err := configloader.NewFromBytes(yamlConfig)
-	if err != nil {
-		return fmt.Errorf("error loading config: %w", err)
-	}
-
-	cfg, ok := config.(*v1alpha1config.Config)
-	if !ok {
-		return fmt.Errorf("config is not v1alpha1 config")
-	}
-
-	if !cfg.Persist() {
-		return fmt.Errorf("config persistence is disabled, patching is not supported")
-	}
-
-	if err = patchFunc(cfg); err != nil {
-		return fmt.Errorf("error patching config: %w", err)
-	}
-
-	cfgBytes, err := cfg.Bytes()
-	if err != nil {
-		return fmt.Errorf("error serializing config: %w", err)
-	}
-
-	_, err = c.ApplyConfiguration(ctx, &machine.ApplyConfigurationRequest{
-		Data:      cfgBytes,
-		Immediate: true,
-	})
-	if err != nil {
-		return fmt.Errorf("error applying config: %w", err)
-	}
-
-	return nil
-}
-
 // waitResourcesReady waits for manifests and static pod definitions to be generated.
 //
 //nolint:gocyclo
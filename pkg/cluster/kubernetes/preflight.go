@@ -0,0 +1,274 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/talos-systems/talos/pkg/cluster"
+)
+
+// Pre-flight checks.
+const (
+	PreflightCheckRemovedAPIs        = "removed APIs"
+	PreflightCheckTalosVersionSkew   = "Talos version skew"
+	PreflightCheckKubeletVersionSkew = "kubelet version skew"
+)
+
+// PreflightIssue describes a problem found by the pre-flight checks.
+type PreflightIssue struct {
+	Check   string
+	Message string
+
+	// Blocking issues prevent the upgrade.
+	Blocking bool
+}
+
+// PreflightReport is a result of the pre-flight checks.
+type PreflightReport struct {
+	Issues []PreflightIssue
+}
+
+// Blocking returns true if any of the issues prevents the upgrade.
+func (report *PreflightReport) Blocking() bool {
+	for _, issue := range report.Issues {
+		if issue.Blocking {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (report *PreflightReport) add(check string, blocking bool, format string, args ...interface{}) {
+	report.Issues = append(report.Issues, PreflightIssue{
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+		Blocking: blocking,
+	})
+}
+
+// removedAPI is an API version of the resource which is no longer served since the Kubernetes release.
+type removedAPI struct {
+	schema.GroupVersionResource
+
+	removedIn string
+}
+
+var removedAPIs = []removedAPI{
+	{schema.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "ingresses"}, "1.22"},
+	{schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1beta1", Resource: "ingresses"}, "1.22"},
+	{schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1beta1", Resource: "ingressclasses"}, "1.22"},
+	{schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1beta1", Resource: "customresourcedefinitions"}, "1.22"},
+	{schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1beta1", Resource: "mutatingwebhookconfigurations"}, "1.22"},
+	{schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1beta1", Resource: "validatingwebhookconfigurations"}, "1.22"},
+	{schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1beta1", Resource: "apiservices"}, "1.22"},
+	{schema.GroupVersionResource{Group: "certificates.k8s.io", Version: "v1beta1", Resource: "certificatesigningrequests"}, "1.22"},
+	{schema.GroupVersionResource{Group: "coordination.k8s.io", Version: "v1beta1", Resource: "leases"}, "1.22"},
+	{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Resource: "clusterroles"}, "1.22"},
+	{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Resource: "clusterrolebindings"}, "1.22"},
+	{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Resource: "roles"}, "1.22"},
+	{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Resource: "rolebindings"}, "1.22"},
+	{schema.GroupVersionResource{Group: "scheduling.k8s.io", Version: "v1beta1", Resource: "priorityclasses"}, "1.22"},
+	{schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1beta1", Resource: "csidrivers"}, "1.22"},
+	{schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1beta1", Resource: "csinodes"}, "1.22"},
+	{schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1beta1", Resource: "storageclasses"}, "1.22"},
+	{schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1beta1", Resource: "volumeattachments"}, "1.22"},
+	{schema.GroupVersionResource{Group: "batch", Version: "v1beta1", Resource: "cronjobs"}, "1.25"},
+	{schema.GroupVersionResource{Group: "discovery.k8s.io", Version: "v1beta1", Resource: "endpointslices"}, "1.25"},
+	{schema.GroupVersionResource{Group: "events.k8s.io", Version: "v1beta1", Resource: "events"}, "1.25"},
+	{schema.GroupVersionResource{Group: "autoscaling", Version: "v2beta1", Resource: "horizontalpodautoscalers"}, "1.25"},
+	{schema.GroupVersionResource{Group: "policy", Version: "v1beta1", Resource: "poddisruptionbudgets"}, "1.25"},
+	{schema.GroupVersionResource{Group: "policy", Version: "v1beta1", Resource: "podsecuritypolicies"}, "1.25"},
+	{schema.GroupVersionResource{Group: "node.k8s.io", Version: "v1beta1", Resource: "runtimeclasses"}, "1.25"},
+}
+
+// talosKubernetesSupport is a range of Kubernetes minor versions supported by Talos minor version.
+var talosKubernetesSupport = map[string]struct {
+	min, max string
+}{
+	"0.10": {"1.19", "1.21"},
+	"0.11": {"1.19", "1.21"},
+	"0.12": {"1.20", "1.22"},
+}
+
+// kubeletVersionSkew is the maximum number of minor versions kubelet might be behind kube-apiserver.
+const kubeletVersionSkew = 2
+
+var talosOSImageRegexp = regexp.MustCompile(`^Talos \((v[^)]+)\)$`)
+
+// PreflightChecks verifies that the cluster can be upgraded to the target Kubernetes version.
+//
+// Checks don't modify the cluster, so they can be run standalone before the upgrade.
+func PreflightChecks(ctx context.Context, cluster cluster.K8sProvider, options UpgradeOptions) (*PreflightReport, error) {
+	from, err := semver.NewVersion(options.FromVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing version %q: %w", options.FromVersion, err)
+	}
+
+	to, err := semver.NewVersion(options.ToVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing version %q: %w", options.ToVersion, err)
+	}
+
+	restConfig, err := cluster.K8sRestConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client config: %w", err)
+	}
+
+	dyn, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error building dynamic client: %w", err)
+	}
+
+	k8sClient, err := cluster.K8sHelper(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client: %w", err)
+	}
+
+	return preflightChecks(ctx, dyn, k8sClient.Clientset, from, to)
+}
+
+func preflightChecks(ctx context.Context, dyn dynamic.Interface, clientset kubernetes.Interface, from, to *semver.Version) (*PreflightReport, error) {
+	report := &PreflightReport{}
+
+	if err := checkRemovedAPIs(ctx, dyn, from, to, report); err != nil {
+		return nil, fmt.Errorf("error checking removed APIs: %w", err)
+	}
+
+	if err := checkNodeVersionSkew(ctx, clientset, to, report); err != nil {
+		return nil, fmt.Errorf("error checking version skew: %w", err)
+	}
+
+	return report, nil
+}
+
+// LogPreflightReport prints the pre-flight checks report.
+func LogPreflightReport(report *PreflightReport, options UpgradeOptions) {
+	if len(report.Issues) == 0 {
+		options.Log("pre-flight checks passed")
+
+		return
+	}
+
+	for _, issue := range report.Issues {
+		severity := "warning"
+		if issue.Blocking {
+			severity = "blocking"
+		}
+
+		options.Log(" > %s (%s): %s", issue.Check, severity, issue.Message)
+	}
+}
+
+// checkRemovedAPIs finds objects which were last written using API versions removed in the target release.
+//
+// API server serves the objects in any supported version, so the version used by the clients is taken from the managed fields.
+func checkRemovedAPIs(ctx context.Context, dyn dynamic.Interface, from, to *semver.Version, report *PreflightReport) error {
+	for _, api := range removedAPIs {
+		removedIn := semver.New(api.removedIn + ".0")
+
+		if !minorVersionBefore(from, removedIn) || minorVersionBefore(to, removedIn) {
+			continue
+		}
+
+		list, err := dyn.Resource(api.GroupVersionResource).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// API version is not served
+				continue
+			}
+
+			return fmt.Errorf("error listing %s: %w", api.GroupVersionResource, err)
+		}
+
+		apiVersion := api.GroupVersion().String()
+
+		for _, item := range list.Items {
+			for _, managedFields := range item.GetManagedFields() {
+				if managedFields.APIVersion != apiVersion {
+					continue
+				}
+
+				name := item.GetName()
+				if item.GetNamespace() != "" {
+					name = item.GetNamespace() + "/" + name
+				}
+
+				report.add(PreflightCheckRemovedAPIs, true, "%s %s %q managed by %q uses API version removed in %s",
+					apiVersion, api.Resource, name, managedFields.Manager, api.removedIn)
+
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkNodeVersionSkew verifies that Talos and kubelet versions on the nodes support the target Kubernetes version.
+func checkNodeVersionSkew(ctx context.Context, clientset kubernetes.Interface, to *semver.Version, report *PreflightReport) error {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %w", err)
+	}
+
+	for _, node := range nodes.Items {
+		var kubeletVersion, talosVersion *semver.Version
+
+		kubeletVersion, err = semver.NewVersion(strings.TrimPrefix(node.Status.NodeInfo.KubeletVersion, "v"))
+		if err != nil {
+			report.add(PreflightCheckKubeletVersionSkew, false, "node %q: failed to parse kubelet version %q", node.Name, node.Status.NodeInfo.KubeletVersion)
+		} else if kubeletVersion.Major != to.Major || kubeletVersion.Minor > to.Minor || to.Minor-kubeletVersion.Minor > kubeletVersionSkew {
+			report.add(PreflightCheckKubeletVersionSkew, true, "node %q: kubelet version %s is not supported by Kubernetes %s control plane", node.Name, kubeletVersion, to)
+		}
+
+		matches := talosOSImageRegexp.FindStringSubmatch(node.Status.NodeInfo.OSImage)
+		if matches == nil {
+			report.add(PreflightCheckTalosVersionSkew, false, "node %q: not running Talos (%q)", node.Name, node.Status.NodeInfo.OSImage)
+
+			continue
+		}
+
+		talosVersion, err = semver.NewVersion(strings.TrimPrefix(matches[1], "v"))
+		if err != nil {
+			report.add(PreflightCheckTalosVersionSkew, false, "node %q: failed to parse Talos version %q", node.Name, matches[1])
+
+			continue
+		}
+
+		supported, ok := talosKubernetesSupport[fmt.Sprintf("%d.%d", talosVersion.Major, talosVersion.Minor)]
+		if !ok {
+			report.add(PreflightCheckTalosVersionSkew, false, "node %q: unknown Kubernetes support for Talos %s", node.Name, talosVersion)
+
+			continue
+		}
+
+		if minorVersionBefore(to, semver.New(supported.min+".0")) || minorVersionBefore(semver.New(supported.max+".0"), to) {
+			report.add(PreflightCheckTalosVersionSkew, true, "node %q: Talos %s supports Kubernetes %s-%s, upgrade Talos first", node.Name, talosVersion, supported.min, supported.max)
+		}
+	}
+
+	return nil
+}
+
+// minorVersionBefore returns true if a precedes b ignoring the patch version.
+func minorVersionBefore(a, b *semver.Version) bool {
+	if a.Major != b.Major {
+		return a.Major < b.Major
+	}
+
+	return a.Minor < b.Minor
+}
@@ -0,0 +1,271 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newFakeDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	scheme := runtime.NewScheme()
+	listKinds := map[schema.GroupVersionResource]string{}
+
+	for _, api := range removedAPIs {
+		listKind := api.Resource + "List"
+
+		listKinds[api.GroupVersionResource] = listKind
+		scheme.AddKnownTypeWithName(api.GroupVersion().WithKind(listKind), &unstructured.UnstructuredList{})
+	}
+
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds, objects...)
+}
+
+func ingress(name, managedAPIVersion string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "extensions/v1beta1",
+			"kind":       "Ingress",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+				"managedFields": []interface{}{
+					map[string]interface{}{
+						"manager":    "kubectl",
+						"operation":  "Update",
+						"apiVersion": managedAPIVersion,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCheckRemovedAPIs(t *testing.T) {
+	t.Parallel()
+
+	ingressesGVR := schema.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "ingresses"}
+
+	for _, tt := range []struct {
+		name     string
+		from, to string
+		objects  []runtime.Object
+		listErr  error
+
+		expectedIssues []PreflightIssue
+		expectedError  string
+	}{
+		{
+			name: "no objects",
+			from: "1.21.3",
+			to:   "1.22.0",
+		},
+		{
+			name:    "removed API",
+			from:    "1.21.3",
+			to:      "1.22.0",
+			objects: []runtime.Object{ingress("web", "extensions/v1beta1")},
+			expectedIssues: []PreflightIssue{
+				{
+					Check:    PreflightCheckRemovedAPIs,
+					Message:  `extensions/v1beta1 ingresses "default/web" managed by "kubectl" uses API version removed in 1.22`,
+					Blocking: true,
+				},
+			},
+		},
+		{
+			name:    "served API",
+			from:    "1.21.3",
+			to:      "1.22.0",
+			objects: []runtime.Object{ingress("web", "networking.k8s.io/v1")},
+		},
+		{
+			name:    "removal release is not crossed",
+			from:    "1.20.5",
+			to:      "1.21.3",
+			objects: []runtime.Object{ingress("web", "extensions/v1beta1")},
+		},
+		{
+			name:    "API is not served",
+			from:    "1.21.3",
+			to:      "1.22.0",
+			listErr: apierrors.NewNotFound(ingressesGVR.GroupResource(), ""),
+		},
+		{
+			name:          "list failed",
+			from:          "1.21.3",
+			to:            "1.22.0",
+			listErr:       errors.New("connection refused"),
+			expectedError: "error listing extensions/v1beta1, Resource=ingresses: connection refused",
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dyn := newFakeDynamicClient(tt.objects...)
+
+			if tt.listErr != nil {
+				dyn.PrependReactor("list", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					if action.GetResource() != ingressesGVR {
+						return false, nil, nil
+					}
+
+					return true, nil, tt.listErr
+				})
+			}
+
+			report := &PreflightReport{}
+
+			err := checkRemovedAPIs(context.Background(), dyn, semver.New(tt.from), semver.New(tt.to), report)
+
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedIssues, report.Issues)
+			assert.Equal(t, len(tt.expectedIssues) > 0, report.Blocking())
+		})
+	}
+}
+
+func node(name, kubeletVersion, osImage string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{
+				KubeletVersion: kubeletVersion,
+				OSImage:        osImage,
+			},
+		},
+	}
+}
+
+func TestCheckNodeVersionSkew(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name string
+		node *corev1.Node
+
+		expectedIssues []PreflightIssue
+	}{
+		{
+			name: "supported",
+			node: node("worker-1", "v1.21.3", "Talos (v0.12.0)"),
+		},
+		{
+			name: "kubelet too old",
+			node: node("worker-1", "v1.19.10", "Talos (v0.12.0)"),
+			expectedIssues: []PreflightIssue{
+				{
+					Check:    PreflightCheckKubeletVersionSkew,
+					Message:  `node "worker-1": kubelet version 1.19.10 is not supported by Kubernetes 1.22.0 control plane`,
+					Blocking: true,
+				},
+			},
+		},
+		{
+			name: "kubelet newer than control plane",
+			node: node("worker-1", "v1.23.0", "Talos (v0.12.0)"),
+			expectedIssues: []PreflightIssue{
+				{
+					Check:    PreflightCheckKubeletVersionSkew,
+					Message:  `node "worker-1": kubelet version 1.23.0 is not supported by Kubernetes 1.22.0 control plane`,
+					Blocking: true,
+				},
+			},
+		},
+		{
+			name: "kubelet version is not parsed",
+			node: node("worker-1", "custom", "Talos (v0.12.0)"),
+			expectedIssues: []PreflightIssue{
+				{
+					Check:   PreflightCheckKubeletVersionSkew,
+					Message: `node "worker-1": failed to parse kubelet version "custom"`,
+				},
+			},
+		},
+		{
+			name: "not Talos",
+			node: node("worker-1", "v1.21.3", "Ubuntu 20.04.2 LTS"),
+			expectedIssues: []PreflightIssue{
+				{
+					Check:   PreflightCheckTalosVersionSkew,
+					Message: `node "worker-1": not running Talos ("Ubuntu 20.04.2 LTS")`,
+				},
+			},
+		},
+		{
+			name: "Talos too old",
+			node: node("worker-1", "v1.21.3", "Talos (v0.11.5)"),
+			expectedIssues: []PreflightIssue{
+				{
+					Check:    PreflightCheckTalosVersionSkew,
+					Message:  `node "worker-1": Talos 0.11.5 supports Kubernetes 1.19-1.21, upgrade Talos first`,
+					Blocking: true,
+				},
+			},
+		},
+		{
+			name: "unknown Talos version",
+			node: node("worker-1", "v1.21.3", "Talos (v0.99.0)"),
+			expectedIssues: []PreflightIssue{
+				{
+					Check:   PreflightCheckTalosVersionSkew,
+					Message: `node "worker-1": unknown Kubernetes support for Talos 0.99.0`,
+				},
+			},
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := &PreflightReport{}
+
+			require.NoError(t, checkNodeVersionSkew(context.Background(), kubernetesfake.NewSimpleClientset(tt.node), semver.New("1.22.0"), report))
+
+			assert.Equal(t, tt.expectedIssues, report.Issues)
+		})
+	}
+}
+
+func TestPreflightChecks(t *testing.T) {
+	t.Parallel()
+
+	report, err := preflightChecks(context.Background(),
+		newFakeDynamicClient(ingress("web", "extensions/v1beta1")),
+		kubernetesfake.NewSimpleClientset(node("worker-1", "v1.21.3", "Talos (v0.12.0)")),
+		semver.New("1.21.3"), semver.New("1.22.0"),
+	)
+	require.NoError(t, err)
+
+	assert.True(t, report.Blocking())
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, PreflightCheckRemovedAPIs, report.Issues[0].Check)
+}
@@ -0,0 +1,169 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/talos-systems/go-retry/retry"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+	"github.com/talos-systems/talos/pkg/resources/k8s"
+)
+
+// RenewOptions represents certificate renewal settings.
+type RenewOptions struct {
+	ControlPlaneNodes []string
+	WorkerNodes       []string
+
+	LogOutput io.Writer
+}
+
+// Log writes the line to logger or to stdout if no logger was provided.
+func (options *RenewOptions) Log(line string, args ...interface{}) {
+	if options.LogOutput != nil {
+		options.LogOutput.Write([]byte(fmt.Sprintf(line+"\n", args...))) //nolint:errcheck
+
+		return
+	}
+
+	fmt.Printf(line+"\n", args...)
+}
+
+// RenewCertificates renews certificates managed by Talos on all the nodes.
+//
+// Control plane nodes are processed one by one: once the certificates are renewed,
+// etcd and control plane static pods are restarted, and the next node is processed only
+// after the static pods are ready again, so that the control plane stays available.
+func RenewCertificates(ctx context.Context, cluster UpgradeProvider, options RenewOptions) error {
+	for _, node := range options.ControlPlaneNodes {
+		if err := renewControlPlaneNode(ctx, cluster, options, node); err != nil {
+			return err
+		}
+	}
+
+	c, err := cluster.Client()
+	if err != nil {
+		return fmt.Errorf("error building Talos API client: %w", err)
+	}
+
+	for _, node := range options.WorkerNodes {
+		options.Log(" > %q: renewing certificates", node)
+
+		if _, err = c.RenewCertificates(client.WithNodes(ctx, node), &machine.RenewCertificatesRequest{}); err != nil {
+			return fmt.Errorf("error renewing certificates on node %q: %w", node, err)
+		}
+
+		options.Log(" < %q: certificates renewed", node)
+	}
+
+	return nil
+}
+
+//nolint:gocyclo
+func renewControlPlaneNode(ctx context.Context, cluster UpgradeProvider, options RenewOptions, node string) error {
+	c, err := cluster.Client()
+	if err != nil {
+		return fmt.Errorf("error building Talos API client: %w", err)
+	}
+
+	nodeCtx := client.WithNodes(ctx, node)
+
+	oldSecretsVersion, err := staticPodSecretsVersion(nodeCtx, c)
+	if err != nil {
+		return err
+	}
+
+	options.Log(" > %q: renewing certificates", node)
+
+	resp, err := c.RenewCertificates(nodeCtx, &machine.RenewCertificatesRequest{})
+	if err != nil {
+		return fmt.Errorf("error renewing certificates on node %q: %w", node, err)
+	}
+
+	for _, msg := range resp.GetMessages() {
+		for _, service := range msg.GetRestartedServices() {
+			options.Log(" > %q: waiting for service %q to be healthy", node, service)
+
+			if err = retry.Constant(3*time.Minute, retry.WithUnits(time.Second)).Retry(func() error {
+				return checkServiceHealthy(nodeCtx, c, service)
+			}); err != nil {
+				return fmt.Errorf("error waiting for service %q on node %q: %w", service, node, err)
+			}
+		}
+	}
+
+	var secretsVersion string
+
+	if err = retry.Constant(time.Minute, retry.WithUnits(time.Second)).Retry(func() error {
+		secretsVersion, err = staticPodSecretsVersion(nodeCtx, c)
+		if err != nil {
+			return retry.ExpectedError(err)
+		}
+
+		if secretsVersion == oldSecretsVersion {
+			return retry.ExpectedError(fmt.Errorf("static pod secrets are not rendered yet"))
+		}
+
+		return nil
+	}); err != nil {
+		return fmt.Errorf("error waiting for static pod secrets on node %q: %w", node, err)
+	}
+
+	for _, service := range []string{kubeAPIServer, kubeControllerManager, kubeScheduler} {
+		options.Log(" > %q: waiting for %s pod to be updated", node, service)
+
+		if err = retry.Constant(3*time.Minute, retry.WithUnits(10*time.Second)).Retry(func() error {
+			return checkPodStatus(ctx, cluster, service, node, constants.AnnotationStaticPodSecretsVersion, secretsVersion)
+		}); err != nil {
+			return err
+		}
+	}
+
+	options.Log(" < %q: certificates renewed", node)
+
+	return nil
+}
+
+func staticPodSecretsVersion(ctx context.Context, c *client.Client) (string, error) {
+	resources, err := c.Resources.Get(ctx, k8s.ControlPlaneNamespaceName, k8s.SecretsStatusType, k8s.StaticPodSecretsStaticPodID)
+	if err != nil {
+		return "", fmt.Errorf("error fetching secrets status: %w", err)
+	}
+
+	if len(resources) != 1 {
+		return "", fmt.Errorf("expected 1 instance of secrets status, got %d", len(resources))
+	}
+
+	spec, ok := resources[0].Resource.(*resource.Any).Value().(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("unexpected secrets status spec")
+	}
+
+	version, _ := spec["version"].(string) //nolint:errcheck
+
+	return version, nil
+}
+
+func checkServiceHealthy(ctx context.Context, c *client.Client, service string) error {
+	services, err := c.ServiceInfo(ctx, service)
+	if err != nil {
+		return retry.ExpectedError(err)
+	}
+
+	for _, svc := range services {
+		if svc.Service.GetState() != "Running" || !svc.Service.GetHealth().GetHealthy() {
+			return retry.ExpectedError(fmt.Errorf("service %q is not healthy", service))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1config "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+)
+
+// RollbackState records component images replaced during the upgrade, so that the upgrade can be rolled back.
+type RollbackState struct {
+	FromVersion string          `yaml:"fromVersion"`
+	ToVersion   string          `yaml:"toVersion"`
+	Entries     []RollbackEntry `yaml:"entries"`
+}
+
+// RollbackEntry records the image of a single component before the upgrade.
+type RollbackEntry struct {
+	// Node is empty for the components managed as Kubernetes DaemonSets.
+	Node    string `yaml:"node,omitempty"`
+	Service string `yaml:"service"`
+	// Image as set in the machine configuration (empty value stands for the default image)
+	// or in the DaemonSet spec.
+	Image string `yaml:"image"`
+}
+
+// Record the image of the component before the upgrade.
+//
+// If the component was already recorded, the first (oldest) record is kept.
+func (state *RollbackState) Record(node, service, image string) {
+	for _, entry := range state.Entries {
+		if entry.Node == node && entry.Service == service {
+			return
+		}
+	}
+
+	state.Entries = append(state.Entries, RollbackEntry{
+		Node:    node,
+		Service: service,
+		Image:   image,
+	})
+}
+
+// RollbackTalosManaged restores the component images recorded during the upgrade.
+//
+// Components are restored node by node in the reverse order of the upgrade.
+func RollbackTalosManaged(ctx context.Context, cluster UpgradeProvider, state *RollbackState, options UpgradeOptions) error {
+	options.Log("rolling back from version %q to version %q", state.ToVersion, state.FromVersion)
+
+	for i := len(state.Entries) - 1; i >= 0; i-- {
+		entry := state.Entries[i]
+
+		if entry.Node == "" {
+			if err := rollbackDaemonSet(ctx, cluster, options, entry); err != nil {
+				return fmt.Errorf("error rolling back %q: %w", entry.Service, err)
+			}
+
+			continue
+		}
+
+		options.Log("rolling back %q to image %q", entry.Service, imageOrDefault(entry.Image))
+
+		if err := upgradeNodeConfigPatch(ctx, cluster, options, entry.Service, entry.Node, rollbackConfigPatcher(entry)); err != nil {
+			return fmt.Errorf("error rolling back %q on node %q: %w", entry.Service, entry.Node, err)
+		}
+	}
+
+	return nil
+}
+
+func rollbackConfigPatcher(entry RollbackEntry) func(configResource resource.Resource) func(config *v1alpha1config.Config) error {
+	return func(configResource resource.Resource) func(config *v1alpha1config.Config) error {
+		return func(config *v1alpha1config.Config) error {
+			image, err := serviceContainerImage(config, entry.Service)
+			if err != nil {
+				return err
+			}
+
+			if *image == entry.Image {
+				return errUpdateSkipped
+			}
+
+			*image = entry.Image
+
+			return nil
+		}
+	}
+}
+
+func rollbackDaemonSet(ctx context.Context, cluster UpgradeProvider, options UpgradeOptions, entry RollbackEntry) error {
+	k8sClient, err := cluster.K8sHelper(ctx)
+	if err != nil {
+		return fmt.Errorf("error building kubernetes client: %w", err)
+	}
+
+	options.Log("rolling back daemonset %q to image %q", entry.Service, entry.Image)
+
+	err = updateDaemonset(ctx, k8sClient.Clientset, entry.Service, func(daemonset *appsv1.DaemonSet) error {
+		if len(daemonset.Spec.Template.Spec.Containers) != 1 {
+			return fmt.Errorf("unexpected number of containers: %d", len(daemonset.Spec.Template.Spec.Containers))
+		}
+
+		daemonset.Spec.Template.Spec.Containers[0].Image = entry.Image
+
+		return nil
+	})
+
+	if apierrors.IsNotFound(err) {
+		options.Log("%s skipped as DaemonSet was not found", entry.Service)
+
+		return nil
+	}
+
+	return err
+}
+
+func recordDaemonSetImage(ctx context.Context, cluster UpgradeProvider, options UpgradeOptions, ds string) error {
+	if options.Rollback == nil {
+		return nil
+	}
+
+	k8sClient, err := cluster.K8sHelper(ctx)
+	if err != nil {
+		return fmt.Errorf("error building kubernetes client: %w", err)
+	}
+
+	daemonset, err := k8sClient.Clientset.AppsV1().DaemonSets(namespace).Get(ctx, ds, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error fetching daemonset: %w", err)
+	}
+
+	if len(daemonset.Spec.Template.Spec.Containers) != 1 {
+		return fmt.Errorf("unexpected number of containers: %d", len(daemonset.Spec.Template.Spec.Containers))
+	}
+
+	options.Rollback.Record("", ds, daemonset.Spec.Template.Spec.Containers[0].Image)
+
+	return nil
+}
+
+func imageOrDefault(image string) string {
+	if image == "" {
+		return "default"
+	}
+
+	return image
+}
@@ -0,0 +1,30 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/pkg/cluster/kubernetes"
+)
+
+func TestRollbackStateRecord(t *testing.T) {
+	t.Parallel()
+
+	var state kubernetes.RollbackState
+
+	state.Record("10.5.0.2", "kube-apiserver", "")
+	state.Record("10.5.0.3", "kube-apiserver", "k8s.gcr.io/kube-apiserver:v1.20.5")
+	state.Record("10.5.0.2", "kube-apiserver", "k8s.gcr.io/kube-apiserver:v1.21.0")
+	state.Record("", "kube-proxy", "k8s.gcr.io/kube-proxy:v1.20.5")
+
+	assert.Equal(t, []kubernetes.RollbackEntry{
+		{Node: "10.5.0.2", Service: "kube-apiserver", Image: ""},
+		{Node: "10.5.0.3", Service: "kube-apiserver", Image: "k8s.gcr.io/kube-apiserver:v1.20.5"},
+		{Node: "", Service: "kube-proxy", Image: "k8s.gcr.io/kube-proxy:v1.20.5"},
+	}, state.Entries)
+}
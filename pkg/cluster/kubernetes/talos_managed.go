@@ -67,12 +67,21 @@ func UpgradeTalosManaged(ctx context.Context, cluster UpgradeProvider, options U
 
 	options.Log("discovered master nodes %q", options.masterNodes)
 
+	if options.Rollback != nil {
+		options.Rollback.FromVersion = options.FromVersion
+		options.Rollback.ToVersion = options.ToVersion
+	}
+
 	for _, service := range []string{kubeAPIServer, kubeControllerManager, kubeScheduler} {
 		if err = upgradeConfigPatch(ctx, cluster, options, service); err != nil {
 			return fmt.Errorf("failed updating service %q: %w", service, err)
 		}
 	}
 
+	if err = recordDaemonSetImage(ctx, cluster, options, kubeProxy); err != nil {
+		return fmt.Errorf("error recording kube-proxy image: %w", err)
+	}
+
 	if err = hyperkubeUpgradeDs(ctx, k8sClient.Clientset, kubeProxy, options); err != nil {
 		if apierrors.IsNotFound(err) {
 			options.Log("kube-proxy skipped as DaemonSet was not found")
@@ -88,7 +97,7 @@ func upgradeConfigPatch(ctx context.Context, cluster UpgradeProvider, options Up
 	options.Log("updating %q to version %q", service, options.ToVersion)
 
 	for _, node := range options.masterNodes {
-		if err := upgradeNodeConfigPatch(ctx, cluster, options, service, node); err != nil {
+		if err := upgradeNodeConfigPatch(ctx, cluster, options, service, node, upgradeConfigPatcher(options, service, node)); err != nil {
 			return fmt.Errorf("error updating node %q: %w", node, err)
 		}
 	}
@@ -97,11 +106,13 @@ func upgradeConfigPatch(ctx context.Context, cluster UpgradeProvider, options Up
 }
 
 //nolint:gocyclo
-func upgradeNodeConfigPatch(ctx context.Context, cluster UpgradeProvider, options UpgradeOptions, service, node string) error {
+func upgradeNodeConfigPatch(ctx context.Context, clusterProvider UpgradeProvider, options UpgradeOptions, service, node string,
+	patcher func(configResource resource.Resource) func(config *v1alpha1config.Config) error,
+) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	c, err := cluster.Client()
+	c, err := clusterProvider.Client()
 	if err != nil {
 		return fmt.Errorf("error building Talos API client: %w", err)
 	}
@@ -133,7 +144,7 @@ func upgradeNodeConfigPatch(ctx context.Context, cluster UpgradeProvider, option
 
 	skipConfigWait := false
 
-	err = patchNodeConfig(ctx, cluster, node, upgradeConfigPatcher(options, service, watchInitial.Resource))
+	err = cluster.PatchNodeConfig(ctx, clusterProvider, node, patcher(watchInitial.Resource))
 	if err != nil {
 		if errors.Is(err, errUpdateSkipped) {
 			skipConfigWait = true
@@ -165,7 +176,7 @@ func upgradeNodeConfigPatch(ctx context.Context, cluster UpgradeProvider, option
 	}
 
 	if err = retry.Constant(3*time.Minute, retry.WithUnits(10*time.Second)).Retry(func() error {
-		return checkPodStatus(ctx, cluster, service, node, expectedConfigVersion)
+		return checkPodStatus(ctx, clusterProvider, service, node, constants.AnnotationStaticPodConfigVersion, expectedConfigVersion)
 	}); err != nil {
 		return err
 	}
@@ -177,63 +188,75 @@ func upgradeNodeConfigPatch(ctx context.Context, cluster UpgradeProvider, option
 
 var errUpdateSkipped = fmt.Errorf("update skipped")
 
-//nolint:gocyclo
-func upgradeConfigPatcher(options UpgradeOptions, service string, configResource resource.Resource) func(config *v1alpha1config.Config) error {
-	return func(config *v1alpha1config.Config) error {
-		if config.ClusterConfig == nil {
-			config.ClusterConfig = &v1alpha1config.ClusterConfig{}
-		}
+func upgradeConfigPatcher(options UpgradeOptions, service, node string) func(configResource resource.Resource) func(config *v1alpha1config.Config) error {
+	return func(configResource resource.Resource) func(config *v1alpha1config.Config) error {
+		return func(config *v1alpha1config.Config) error {
+			configData := configResource.(*resource.Any).Value().(map[string]interface{}) //nolint:errcheck,forcetypeassert
+			configImage := configData["image"].(string)                                   //nolint:errcheck,forcetypeassert
 
-		configData := configResource.(*resource.Any).Value().(map[string]interface{}) //nolint:errcheck,forcetypeassert
-		configImage := configData["image"].(string)                                   //nolint:errcheck,forcetypeassert
-
-		switch service {
-		case kubeAPIServer:
-			if config.ClusterConfig.APIServerConfig == nil {
-				config.ClusterConfig.APIServerConfig = &v1alpha1config.APIServerConfig{}
+			containerImage, err := serviceContainerImage(config, service)
+			if err != nil {
+				return err
 			}
 
-			image := fmt.Sprintf("%s:v%s", constants.KubernetesAPIServerImage, options.ToVersion)
+			var image string
+
+			switch service {
+			case kubeAPIServer:
+				image = fmt.Sprintf("%s:v%s", constants.KubernetesAPIServerImage, options.ToVersion)
+			case kubeControllerManager:
+				image = fmt.Sprintf("%s:v%s", constants.KubernetesControllerManagerImage, options.ToVersion)
+			case kubeScheduler:
+				image = fmt.Sprintf("%s:v%s", constants.KubernetesSchedulerImage, options.ToVersion)
+			}
 
-			if config.ClusterConfig.APIServerConfig.ContainerImage == image || configImage == image {
+			if *containerImage == image || configImage == image {
 				return errUpdateSkipped
 			}
 
-			config.ClusterConfig.APIServerConfig.ContainerImage = image
-		case kubeControllerManager:
-			if config.ClusterConfig.ControllerManagerConfig == nil {
-				config.ClusterConfig.ControllerManagerConfig = &v1alpha1config.ControllerManagerConfig{}
+			if options.Rollback != nil {
+				options.Rollback.Record(node, service, *containerImage)
 			}
 
-			image := fmt.Sprintf("%s:v%s", constants.KubernetesControllerManagerImage, options.ToVersion)
+			*containerImage = image
 
-			if config.ClusterConfig.ControllerManagerConfig.ContainerImage == image || configImage == image {
-				return errUpdateSkipped
-			}
+			return nil
+		}
+	}
+}
 
-			config.ClusterConfig.ControllerManagerConfig.ContainerImage = image
-		case kubeScheduler:
-			if config.ClusterConfig.SchedulerConfig == nil {
-				config.ClusterConfig.SchedulerConfig = &v1alpha1config.SchedulerConfig{}
-			}
+// serviceContainerImage returns a pointer to the container image of the control plane service in the machine configuration.
+func serviceContainerImage(config *v1alpha1config.Config, service string) (*string, error) {
+	if config.ClusterConfig == nil {
+		config.ClusterConfig = &v1alpha1config.ClusterConfig{}
+	}
 
-			image := fmt.Sprintf("%s:v%s", constants.KubernetesSchedulerImage, options.ToVersion)
+	switch service {
+	case kubeAPIServer:
+		if config.ClusterConfig.APIServerConfig == nil {
+			config.ClusterConfig.APIServerConfig = &v1alpha1config.APIServerConfig{}
+		}
 
-			if config.ClusterConfig.SchedulerConfig.ContainerImage == image || configImage == image {
-				return errUpdateSkipped
-			}
+		return &config.ClusterConfig.APIServerConfig.ContainerImage, nil
+	case kubeControllerManager:
+		if config.ClusterConfig.ControllerManagerConfig == nil {
+			config.ClusterConfig.ControllerManagerConfig = &v1alpha1config.ControllerManagerConfig{}
+		}
 
-			config.ClusterConfig.SchedulerConfig.ContainerImage = image
-		default:
-			return fmt.Errorf("unsupported service %q", service)
+		return &config.ClusterConfig.ControllerManagerConfig.ContainerImage, nil
+	case kubeScheduler:
+		if config.ClusterConfig.SchedulerConfig == nil {
+			config.ClusterConfig.SchedulerConfig = &v1alpha1config.SchedulerConfig{}
 		}
 
-		return nil
+		return &config.ClusterConfig.SchedulerConfig.ContainerImage, nil
+	default:
+		return nil, fmt.Errorf("unsupported service %q", service)
 	}
 }
 
 //nolint:gocyclo
-func checkPodStatus(ctx context.Context, cluster UpgradeProvider, service, node, configVersion string) error {
+func checkPodStatus(ctx context.Context, cluster UpgradeProvider, service, node, versionAnnotation, version string) error {
 	k8sClient, err := cluster.K8sHelper(ctx)
 	if err != nil {
 		return fmt.Errorf("error building kubernetes client: %w", err)
@@ -259,8 +282,8 @@ func checkPodStatus(ctx context.Context, cluster UpgradeProvider, service, node,
 
 		podFound = true
 
-		if pod.Annotations[constants.AnnotationStaticPodConfigVersion] != configVersion {
-			return retry.ExpectedError(fmt.Errorf("config version mismatch: got %q, expected %q", pod.Annotations[constants.AnnotationStaticPodConfigVersion], configVersion))
+		if pod.Annotations[versionAnnotation] != version {
+			return retry.ExpectedError(fmt.Errorf("%s mismatch: got %q, expected %q", versionAnnotation, pod.Annotations[versionAnnotation], version))
 		}
 
 		ready := false
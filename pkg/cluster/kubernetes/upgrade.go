@@ -31,6 +31,9 @@ type UpgradeOptions struct {
 	ControlPlaneEndpoint string
 	LogOutput            io.Writer
 
+	// Rollback, if set, records the component images replaced during the upgrade.
+	Rollback *RollbackState
+
 	extraUpdaters                []daemonsetUpdater
 	podCheckpointerExtraUpdaters []daemonsetUpdater
 	masterNodes                  []string
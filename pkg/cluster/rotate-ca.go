@@ -0,0 +1,272 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/talos-systems/crypto/x509"
+	"github.com/talos-systems/go-retry/retry"
+
+	"github.com/talos-systems/talos/pkg/grpc/gen"
+	"github.com/talos-systems/talos/pkg/machinery/client"
+	clientconfig "github.com/talos-systems/talos/pkg/machinery/client/config"
+	v1alpha1config "github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1/generate"
+	"github.com/talos-systems/talos/pkg/machinery/role"
+)
+
+// RotateCAOptions represents Talos API CA rotation settings.
+type RotateCAOptions struct {
+	ControlPlaneNodes []string
+	WorkerNodes       []string
+
+	// NewCA is generated if not set.
+	NewCA *x509.PEMEncodedCertificateAndKey
+
+	// ClientCertTTL is the lifetime of the issued client certificate.
+	ClientCertTTL time.Duration
+
+	LogOutput io.Writer
+}
+
+// Log writes the line to logger or to stdout if no logger was provided.
+func (options *RotateCAOptions) Log(line string, args ...interface{}) {
+	if options.LogOutput != nil {
+		options.LogOutput.Write([]byte(fmt.Sprintf(line+"\n", args...))) //nolint:errcheck
+
+		return
+	}
+
+	fmt.Printf(line+"\n", args...)
+}
+
+func (options *RotateCAOptions) nodes() []string {
+	return append(append([]string(nil), options.ControlPlaneNodes...), options.WorkerNodes...)
+}
+
+// RotateCA replaces Talos API CA on all the nodes without a reboot.
+//
+// Rotation goes through the following phases:
+//   - new CA is added to the list of accepted CAs on all the nodes
+//   - new CA becomes machine CA, while old CA is moved to accepted CAs
+//   - old CA is removed from accepted CAs once all the nodes trust the new CA
+//
+// Client context is updated in place with the new CA and client certificate signed by the new CA.
+//
+//nolint:gocyclo,cyclop
+func RotateCA(ctx context.Context, cluster ClientProvider, talosContext *clientconfig.Context, options RotateCAOptions) error {
+	if len(options.ControlPlaneNodes) == 0 {
+		return fmt.Errorf("at least one control plane node is required")
+	}
+
+	if options.ClientCertTTL == 0 {
+		options.ClientCertTTL = 87600 * time.Hour
+	}
+
+	currentConfig, err := ReadNodeConfig(ctx, cluster, options.ControlPlaneNodes[0])
+	if err != nil {
+		return fmt.Errorf("error reading node %q config: %w", options.ControlPlaneNodes[0], err)
+	}
+
+	oldCA := currentConfig.Machine().Security().CA()
+	if oldCA == nil || len(oldCA.Crt) == 0 {
+		return fmt.Errorf("node %q doesn't have machine CA", options.ControlPlaneNodes[0])
+	}
+
+	newCA := options.NewCA
+
+	if newCA == nil {
+		var ca *x509.CertificateAuthority
+
+		ca, err = generate.NewTalosCA(time.Now())
+		if err != nil {
+			return fmt.Errorf("error generating new CA: %w", err)
+		}
+
+		newCA = x509.NewCertificateAndKeyFromCertificateAuthority(ca)
+	}
+
+	if len(newCA.Key) == 0 {
+		return fmt.Errorf("new CA key is required")
+	}
+
+	if bytes.Equal(oldCA.Crt, newCA.Crt) {
+		return fmt.Errorf("new CA is the same as the current CA")
+	}
+
+	options.Log("> accepting new CA on all the nodes")
+
+	for _, node := range options.nodes() {
+		options.Log(" > %q: adding new CA to accepted CAs", node)
+
+		if err = PatchNodeConfig(ctx, cluster, node, func(config *v1alpha1config.Config) error {
+			config.MachineConfig.MachineAcceptedCAs = appendAcceptedCA(config.MachineConfig.MachineAcceptedCAs, newCA.Crt)
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error patching node %q config: %w", node, err)
+		}
+	}
+
+	newClientCert, err := generate.NewAdminCertificateAndKey(time.Now(), newCA, role.MakeSet(role.Admin), options.ClientCertTTL)
+	if err != nil {
+		return fmt.Errorf("error generating client certificate: %w", err)
+	}
+
+	// during the rotation, nodes might present server certificates signed by either CA
+	transitionContext := clientContext(talosContext, gen.CABundle(oldCA.Crt, newCA.Crt), newClientCert)
+
+	options.Log("> verifying that all the nodes accept client certificate signed by the new CA")
+
+	if err = verifyNodes(ctx, transitionContext, options.nodes()); err != nil {
+		return err
+	}
+
+	transitionProvider := &ConfigClientProvider{TalosConfig: contextConfig(transitionContext)}
+
+	//nolint:errcheck
+	defer transitionProvider.Close()
+
+	options.Log("> switching machine CA on all the nodes")
+
+	// control plane nodes go first, as workers get their certificates issued by the control plane nodes
+	for _, node := range options.ControlPlaneNodes {
+		options.Log(" > %q: switching machine CA", node)
+
+		if err = PatchNodeConfig(ctx, transitionProvider, node, func(config *v1alpha1config.Config) error {
+			config.MachineConfig.MachineCA = newCA
+			config.MachineConfig.MachineAcceptedCAs = appendAcceptedCA(removeAcceptedCA(config.MachineConfig.MachineAcceptedCAs, newCA.Crt), oldCA.Crt)
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error patching node %q config: %w", node, err)
+		}
+	}
+
+	for _, node := range options.WorkerNodes {
+		options.Log(" > %q: switching machine CA", node)
+
+		if err = PatchNodeConfig(ctx, transitionProvider, node, func(config *v1alpha1config.Config) error {
+			config.MachineConfig.MachineCA = &x509.PEMEncodedCertificateAndKey{
+				Crt: newCA.Crt,
+			}
+			config.MachineConfig.MachineAcceptedCAs = appendAcceptedCA(removeAcceptedCA(config.MachineConfig.MachineAcceptedCAs, newCA.Crt), oldCA.Crt)
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error patching node %q config: %w", node, err)
+		}
+	}
+
+	newContext := clientContext(talosContext, newCA.Crt, newClientCert)
+
+	options.Log("> verifying that all the nodes present certificates signed by the new CA")
+
+	if err = verifyNodes(ctx, newContext, options.nodes()); err != nil {
+		return err
+	}
+
+	newProvider := &ConfigClientProvider{TalosConfig: contextConfig(newContext)}
+
+	//nolint:errcheck
+	defer newProvider.Close()
+
+	options.Log("> removing old CA from accepted CAs on all the nodes")
+
+	for _, node := range options.nodes() {
+		options.Log(" > %q: removing old CA", node)
+
+		if err = PatchNodeConfig(ctx, newProvider, node, func(config *v1alpha1config.Config) error {
+			config.MachineConfig.MachineAcceptedCAs = removeAcceptedCA(config.MachineConfig.MachineAcceptedCAs, oldCA.Crt)
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error patching node %q config: %w", node, err)
+		}
+	}
+
+	*talosContext = *newContext
+
+	options.Log("> CA rotation finished")
+
+	return nil
+}
+
+// verifyNodes checks that all the nodes can be accessed using the client context.
+//
+// Nodes are accessed directly, as endpoints might still be using old certificates.
+func verifyNodes(ctx context.Context, talosContext *clientconfig.Context, nodes []string) error {
+	for _, node := range nodes {
+		node := node
+
+		// apid might be restarted to pick up new certificates, so retry for a while
+		if err := retry.Constant(3*time.Minute, retry.WithUnits(time.Second)).Retry(func() error {
+			c, err := client.New(ctx, client.WithConfigContext(talosContext), client.WithEndpoints(node))
+			if err != nil {
+				return retry.ExpectedError(err)
+			}
+
+			//nolint:errcheck
+			defer c.Close()
+
+			if _, err = c.Version(ctx); err != nil {
+				return retry.ExpectedError(err)
+			}
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error verifying node %q: %w", node, err)
+		}
+	}
+
+	return nil
+}
+
+func clientContext(talosContext *clientconfig.Context, ca []byte, clientCert *x509.PEMEncodedCertificateAndKey) *clientconfig.Context {
+	return &clientconfig.Context{
+		Endpoints: talosContext.Endpoints,
+		Nodes:     talosContext.Nodes,
+		CA:        base64.StdEncoding.EncodeToString(ca),
+		Crt:       base64.StdEncoding.EncodeToString(clientCert.Crt),
+		Key:       base64.StdEncoding.EncodeToString(clientCert.Key),
+	}
+}
+
+func contextConfig(talosContext *clientconfig.Context) *clientconfig.Config {
+	return &clientconfig.Config{
+		Context: "rotate-ca",
+		Contexts: map[string]*clientconfig.Context{
+			"rotate-ca": talosContext,
+		},
+	}
+}
+
+func appendAcceptedCA(acceptedCAs []*x509.PEMEncodedCertificateAndKey, crt []byte) []*x509.PEMEncodedCertificateAndKey {
+	for _, ca := range acceptedCAs {
+		if bytes.Equal(ca.Crt, crt) {
+			return acceptedCAs
+		}
+	}
+
+	return append(acceptedCAs, &x509.PEMEncodedCertificateAndKey{Crt: crt})
+}
+
+func removeAcceptedCA(acceptedCAs []*x509.PEMEncodedCertificateAndKey, crt []byte) []*x509.PEMEncodedCertificateAndKey {
+	result := make([]*x509.PEMEncodedCertificateAndKey, 0, len(acceptedCAs))
+
+	for _, ca := range acceptedCAs {
+		if !bytes.Equal(ca.Crt, crt) {
+			result = append(result, ca)
+		}
+	}
+
+	return result
+}
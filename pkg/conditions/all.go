@@ -75,6 +75,21 @@ func (a *all) String() string {
 	return strings.Join(descriptions, ", ")
 }
 
+func (a *all) unmet() []string {
+	var result []string
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, c := range a.conditions {
+		if c != nil {
+			result = append(result, Unmet(c)...)
+		}
+	}
+
+	return result
+}
+
 // WaitForAll creates a condition which waits for all the conditions to be successful.
 func WaitForAll(conditions ...Condition) Condition {
 	res := &all{}
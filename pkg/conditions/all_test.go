@@ -104,6 +104,35 @@ func (suite *AllSuite) TestFlatten() {
 	suite.Require().Equal(context.Canceled, <-done)
 }
 
+func (suite *AllSuite) TestUnmet() {
+	conds := []conditions.Condition{
+		&MockCondition{description: "A", errCh: make(chan error)},
+		&MockCondition{description: "B", errCh: make(chan error)},
+		&MockCondition{description: "C", errCh: make(chan error)},
+	}
+
+	waiter := conditions.WaitForAll(conditions.WaitForAll(conds[:2]...), conds[2])
+	suite.Require().Equal([]string{"A", "B", "C"}, conditions.Unmet(waiter))
+
+	done := make(chan error)
+
+	go func() {
+		done <- waiter.Wait(context.Background())
+	}()
+
+	conds[1].(*MockCondition).errCh <- nil
+	time.Sleep(50 * time.Millisecond)
+
+	suite.Require().Equal([]string{"A", "C"}, conditions.Unmet(waiter))
+
+	conds[0].(*MockCondition).errCh <- nil
+	conds[2].(*MockCondition).errCh <- nil
+	suite.Require().NoError(<-done)
+
+	suite.Require().Empty(conditions.Unmet(waiter))
+	suite.Require().Equal([]string{"D"}, conditions.Unmet(&MockCondition{description: "D"}))
+}
+
 func TestAllSuite(t *testing.T) {
 	suite.Run(t, new(AllSuite))
 }
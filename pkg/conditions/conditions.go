@@ -19,3 +19,19 @@ type Condition interface {
 	fmt.Stringer
 	Wait(ctx context.Context) error
 }
+
+// Unmet returns descriptions of the conditions which are not met yet.
+//
+// Conditions combined with WaitForAll are reported one by one.
+func Unmet(condition Condition) []string {
+	if multi, ok := condition.(*all); ok {
+		return multi.unmet()
+	}
+
+	description := condition.String()
+	if description == "" || description == OK {
+		return nil
+	}
+
+	return []string{description}
+}
@@ -0,0 +1,51 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package gen
+
+import (
+	"bytes"
+
+	"github.com/talos-systems/crypto/x509"
+)
+
+// CABundle builds CA trust bundle from the PEM-encoded certificates.
+//
+// Each certificate is terminated with a newline, duplicate and empty certificates are skipped.
+func CABundle(certs ...[]byte) []byte {
+	var bundle []byte
+
+	seen := make(map[string]struct{}, len(certs))
+
+	for _, cert := range certs {
+		cert = bytes.TrimSpace(cert)
+
+		if len(cert) == 0 {
+			continue
+		}
+
+		if _, ok := seen[string(cert)]; ok {
+			continue
+		}
+
+		seen[string(cert)] = struct{}{}
+
+		bundle = append(bundle, cert...)
+		bundle = append(bundle, '\n')
+	}
+
+	return bundle
+}
+
+// AcceptedCABundle builds CA trust bundle from the CA and the accepted CAs.
+func AcceptedCABundle(ca []byte, acceptedCAs []*x509.PEMEncodedCertificateAndKey) []byte {
+	certs := make([][]byte, 0, len(acceptedCAs)+1)
+	certs = append(certs, ca)
+
+	for _, acceptedCA := range acceptedCAs {
+		certs = append(certs, acceptedCA.Crt)
+	}
+
+	return CABundle(certs...)
+}
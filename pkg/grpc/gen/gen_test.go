@@ -4,11 +4,23 @@
 
 package gen_test
 
-import "testing"
+import (
+	"testing"
 
-func TestEmpty(t *testing.T) {
-	// added for accurate coverage estimation
-	//
-	// please remove it once any unit-test is added
-	// for this package
+	"github.com/stretchr/testify/assert"
+
+	"github.com/talos-systems/talos/pkg/grpc/gen"
+)
+
+func TestCABundle(t *testing.T) {
+	ca1 := []byte("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----")
+	ca2 := []byte("-----BEGIN CERTIFICATE-----\nMIIC\n-----END CERTIFICATE-----\n")
+
+	assert.Nil(t, gen.CABundle())
+	assert.Nil(t, gen.CABundle(nil, []byte("\n")))
+
+	assert.Equal(t,
+		"-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n-----BEGIN CERTIFICATE-----\nMIIC\n-----END CERTIFICATE-----\n",
+		string(gen.CABundle(ca1, ca2, append(ca1, '\n'), ca2)),
+	)
 }
@@ -0,0 +1,164 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"strings"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+const (
+	nodeUserPrefix = "system:node:"
+	nodesGroup     = "system:nodes"
+)
+
+// VerifyKubeletServingCSR verifies kubelet serving certificate signing request against the node it was issued for.
+//
+// The request should come from the node itself, and the certificate should contain only
+// the node name and the node addresses published by Talos in the node annotations.
+//
+//nolint:gocyclo,cyclop
+func VerifyKubeletServingCSR(csr *certificatesv1.CertificateSigningRequest, node *corev1.Node) error {
+	if csr.Spec.SignerName != certificatesv1.KubeletServingSignerName {
+		return fmt.Errorf("unexpected signer name %q", csr.Spec.SignerName)
+	}
+
+	nodeUser := nodeUserPrefix + node.Name
+
+	if csr.Spec.Username != nodeUser {
+		return fmt.Errorf("request username %q doesn't match node %q", csr.Spec.Username, node.Name)
+	}
+
+	if !containsString(csr.Spec.Groups, nodesGroup) {
+		return fmt.Errorf("request user is not in %q group", nodesGroup)
+	}
+
+	serverAuth := false
+
+	for _, usage := range csr.Spec.Usages {
+		switch usage { //nolint:exhaustive
+		case certificatesv1.UsageServerAuth:
+			serverAuth = true
+		case certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment:
+		default:
+			return fmt.Errorf("unexpected key usage %q", usage)
+		}
+	}
+
+	if !serverAuth {
+		return fmt.Errorf("missing %q key usage", certificatesv1.UsageServerAuth)
+	}
+
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return fmt.Errorf("failed to decode PEM certificate request")
+	}
+
+	req, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("error parsing certificate request: %w", err)
+	}
+
+	if err = req.CheckSignature(); err != nil {
+		return fmt.Errorf("invalid certificate request signature: %w", err)
+	}
+
+	if req.Subject.CommonName != nodeUser {
+		return fmt.Errorf("certificate common name %q doesn't match node %q", req.Subject.CommonName, node.Name)
+	}
+
+	if len(req.Subject.Organization) != 1 || req.Subject.Organization[0] != nodesGroup {
+		return fmt.Errorf("certificate organization %q is not %q", req.Subject.Organization, nodesGroup)
+	}
+
+	if len(req.EmailAddresses) > 0 || len(req.URIs) > 0 {
+		return fmt.Errorf("certificate contains email or URI subject alternative names")
+	}
+
+	if len(req.DNSNames)+len(req.IPAddresses) == 0 {
+		return fmt.Errorf("certificate doesn't contain any subject alternative names")
+	}
+
+	allowedNames := []string{node.Name}
+
+	for _, address := range node.Status.Addresses {
+		switch address.Type { //nolint:exhaustive
+		case corev1.NodeHostName, corev1.NodeInternalDNS, corev1.NodeExternalDNS:
+			allowedNames = append(allowedNames, address.Address)
+		}
+	}
+
+	for _, name := range req.DNSNames {
+		if !containsString(allowedNames, name) {
+			return fmt.Errorf("DNS name %q doesn't belong to node %q", name, node.Name)
+		}
+	}
+
+	allowedIPs, err := NodeAddressesFromAnnotation(node)
+	if err != nil {
+		return err
+	}
+
+	for _, ip := range req.IPAddresses {
+		found := false
+
+		for _, allowedIP := range allowedIPs {
+			if allowedIP.Equal(ip) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("IP address %q doesn't belong to node %q", ip, node.Name)
+		}
+	}
+
+	return nil
+}
+
+// NodeAddressesFromAnnotation returns the node addresses published by Talos in the node annotations.
+func NodeAddressesFromAnnotation(node *corev1.Node) ([]net.IP, error) {
+	annotation, ok := node.Annotations[constants.AnnotationNodeAddresses]
+	if !ok {
+		return nil, fmt.Errorf("node %q doesn't have %q annotation", node.Name, constants.AnnotationNodeAddresses)
+	}
+
+	var addresses []net.IP
+
+	for _, addr := range strings.Split(annotation, ",") {
+		if addr == "" {
+			continue
+		}
+
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return nil, fmt.Errorf("failed to parse node address %q", addr)
+		}
+
+		addresses = append(addresses, ip)
+	}
+
+	return addresses, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
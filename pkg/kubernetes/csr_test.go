@@ -0,0 +1,173 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kubernetes_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/talos-systems/talos/pkg/kubernetes"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+func generateCSR(t *testing.T, commonName string, dnsNames []string, ips []net.IP) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{"system:nodes"},
+		},
+		DNSNames:    dnsNames,
+		IPAddresses: ips,
+	}, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func kubeletServingCSR(request []byte) *certificatesv1.CertificateSigningRequest {
+	return &certificatesv1.CertificateSigningRequest{
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    request,
+			SignerName: certificatesv1.KubeletServingSignerName,
+			Username:   "system:node:worker-1",
+			Groups:     []string{"system:nodes", "system:authenticated"},
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageServerAuth,
+			},
+		},
+	}
+}
+
+func TestVerifyKubeletServingCSR(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "worker-1",
+			Annotations: map[string]string{
+				constants.AnnotationNodeAddresses: "172.20.0.2,fd00::2",
+			},
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: "worker-1.example.com"},
+				{Type: corev1.NodeInternalIP, Address: "172.20.0.2"},
+			},
+		},
+	}
+
+	validRequest := generateCSR(t, "system:node:worker-1", []string{"worker-1", "worker-1.example.com"}, []net.IP{net.ParseIP("172.20.0.2"), net.ParseIP("fd00::2")})
+
+	for _, tt := range []struct {
+		name          string
+		csr           func() *certificatesv1.CertificateSigningRequest
+		node          func() *corev1.Node
+		expectedError string
+	}{
+		{
+			name: "valid",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				return kubeletServingCSR(validRequest)
+			},
+		},
+		{
+			name: "wrong signer",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := kubeletServingCSR(validRequest)
+				csr.Spec.SignerName = certificatesv1.KubeAPIServerClientKubeletSignerName
+
+				return csr
+			},
+			expectedError: "unexpected signer name \"kubernetes.io/kube-apiserver-client-kubelet\"",
+		},
+		{
+			name: "other node",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := kubeletServingCSR(validRequest)
+				csr.Spec.Username = "system:node:worker-2"
+
+				return csr
+			},
+			expectedError: "request username \"system:node:worker-2\" doesn't match node \"worker-1\"",
+		},
+		{
+			name: "client auth usage",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				csr := kubeletServingCSR(validRequest)
+				csr.Spec.Usages = append(csr.Spec.Usages, certificatesv1.UsageClientAuth)
+
+				return csr
+			},
+			expectedError: "unexpected key usage \"client auth\"",
+		},
+		{
+			name: "common name mismatch",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				return kubeletServingCSR(generateCSR(t, "system:node:worker-2", []string{"worker-1"}, nil))
+			},
+			expectedError: "certificate common name \"system:node:worker-2\" doesn't match node \"worker-1\"",
+		},
+		{
+			name: "foreign DNS name",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				return kubeletServingCSR(generateCSR(t, "system:node:worker-1", []string{"kubernetes.default"}, nil))
+			},
+			expectedError: "DNS name \"kubernetes.default\" doesn't belong to node \"worker-1\"",
+		},
+		{
+			name: "foreign IP",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				return kubeletServingCSR(generateCSR(t, "system:node:worker-1", nil, []net.IP{net.ParseIP("10.5.0.1")}))
+			},
+			expectedError: "IP address \"10.5.0.1\" doesn't belong to node \"worker-1\"",
+		},
+		{
+			name: "no addresses published",
+			csr: func() *certificatesv1.CertificateSigningRequest {
+				return kubeletServingCSR(validRequest)
+			},
+			node: func() *corev1.Node {
+				n := node.DeepCopy()
+				n.Annotations = nil
+
+				return n
+			},
+			expectedError: "node \"worker-1\" doesn't have \"talos.dev/node-addresses\" annotation",
+		},
+	} {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			n := node
+
+			if tt.node != nil {
+				n = tt.node()
+			}
+
+			err := kubernetes.VerifyKubeletServingCSR(tt.csr(), n)
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"log"
 	"net"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/talos-systems/crypto/x509"
@@ -296,6 +297,93 @@ func (h *Client) LabelNodeAsMaster(ctx context.Context, name string, taintNoSche
 	return nil
 }
 
+// AnnotateNodeAddresses publishes node addresses in the node annotations.
+//
+// Node is patched only if the addresses have changed.
+func (h *Client) AnnotateNodeAddresses(ctx context.Context, name string, addresses []string) error {
+	n, err := h.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	value := strings.Join(addresses, ",")
+
+	if current, ok := n.Annotations[constants.AnnotationNodeAddresses]; ok && current == value {
+		return nil
+	}
+
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				constants.AnnotationNodeAddresses: value,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal node %q patch: %w", name, err)
+	}
+
+	if _, err = h.CoreV1().Nodes().Patch(ctx, name, types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("error patching node %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// SetNodeConditions sets (adds or updates) node status conditions.
+//
+// Conditions not listed are left intact, so that conditions managed by the kubelet are not affected.
+func (h *Client) SetNodeConditions(ctx context.Context, name string, conditions []corev1.NodeCondition) error {
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": conditions,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal node %q status patch: %w", name, err)
+	}
+
+	if _, err = h.CoreV1().Nodes().Patch(ctx, name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{}, "status"); err != nil {
+		return fmt.Errorf("error patching node %q status: %w", name, err)
+	}
+
+	return nil
+}
+
+// RecordNodeEvent creates an event for the node.
+func (h *Client) RecordNodeEvent(ctx context.Context, name, eventType, reason, message string) error {
+	now := metav1.Now()
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", name, now.UnixNano()),
+			Namespace: metav1.NamespaceDefault,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind: "Node",
+			Name: name,
+			// kubelet uses node name as the UID for node events
+			UID: types.UID(name),
+		},
+		Reason:  reason,
+		Message: message,
+		Source: corev1.EventSource{
+			Component: "talos",
+			Host:      name,
+		},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           eventType,
+	}
+
+	if _, err := h.CoreV1().Events(metav1.NamespaceDefault).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("error creating event for node %q: %w", name, err)
+	}
+
+	return nil
+}
+
 // WaitUntilReady waits for a node to be ready.
 func (h *Client) WaitUntilReady(ctx context.Context, name string) error {
 	return retry.Exponential(10*time.Minute, retry.WithUnits(250*time.Millisecond), retry.WithJitter(50*time.Millisecond), retry.WithErrorLogging(true)).RetryWithContext(ctx,
@@ -77,6 +77,11 @@ type MachineServiceClient interface {
 	Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VersionResponse, error)
 	// GenerateClientConfiguration generates talosctl client configuration (talosconfig).
 	GenerateClientConfiguration(ctx context.Context, in *GenerateClientConfigurationRequest, opts ...grpc.CallOption) (*GenerateClientConfigurationResponse, error)
+	// RenewCertificates regenerates certificates managed by Talos on the node and restarts affected services.
+	RenewCertificates(ctx context.Context, in *RenewCertificatesRequest, opts ...grpc.CallOption) (*RenewCertificatesResponse, error)
+	// Wipe resets the node wiping the system and user disks, the progress is streamed back
+	// with the signed report as the last message.
+	Wipe(ctx context.Context, in *WipeRequest, opts ...grpc.CallOption) (MachineService_WipeClient, error)
 }
 
 type machineServiceClient struct {
@@ -689,6 +694,47 @@ func (c *machineServiceClient) GenerateClientConfiguration(ctx context.Context,
 	return out, nil
 }
 
+func (c *machineServiceClient) RenewCertificates(ctx context.Context, in *RenewCertificatesRequest, opts ...grpc.CallOption) (*RenewCertificatesResponse, error) {
+	out := new(RenewCertificatesResponse)
+	err := c.cc.Invoke(ctx, "/machine.MachineService/RenewCertificates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *machineServiceClient) Wipe(ctx context.Context, in *WipeRequest, opts ...grpc.CallOption) (MachineService_WipeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MachineService_ServiceDesc.Streams[10], "/machine.MachineService/Wipe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &machineServiceWipeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MachineService_WipeClient interface {
+	Recv() (*WipeProgress, error)
+	grpc.ClientStream
+}
+
+type machineServiceWipeClient struct {
+	grpc.ClientStream
+}
+
+func (x *machineServiceWipeClient) Recv() (*WipeProgress, error) {
+	m := new(WipeProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // MachineServiceServer is the server API for MachineService service.
 // All implementations must embed UnimplementedMachineServiceServer
 // for forward compatibility
@@ -744,6 +790,11 @@ type MachineServiceServer interface {
 	Version(context.Context, *emptypb.Empty) (*VersionResponse, error)
 	// GenerateClientConfiguration generates talosctl client configuration (talosconfig).
 	GenerateClientConfiguration(context.Context, *GenerateClientConfigurationRequest) (*GenerateClientConfigurationResponse, error)
+	// RenewCertificates regenerates certificates managed by Talos on the node and restarts affected services.
+	RenewCertificates(context.Context, *RenewCertificatesRequest) (*RenewCertificatesResponse, error)
+	// Wipe resets the node wiping the system and user disks, the progress is streamed back
+	// with the signed report as the last message.
+	Wipe(*WipeRequest, MachineService_WipeServer) error
 	mustEmbedUnimplementedMachineServiceServer()
 }
 
@@ -913,6 +964,14 @@ func (UnimplementedMachineServiceServer) Version(context.Context, *emptypb.Empty
 func (UnimplementedMachineServiceServer) GenerateClientConfiguration(context.Context, *GenerateClientConfigurationRequest) (*GenerateClientConfigurationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GenerateClientConfiguration not implemented")
 }
+
+func (UnimplementedMachineServiceServer) RenewCertificates(context.Context, *RenewCertificatesRequest) (*RenewCertificatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewCertificates not implemented")
+}
+
+func (UnimplementedMachineServiceServer) Wipe(*WipeRequest, MachineService_WipeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Wipe not implemented")
+}
 func (UnimplementedMachineServiceServer) mustEmbedUnimplementedMachineServiceServer() {}
 
 // UnsafeMachineServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -1699,6 +1758,45 @@ func _MachineService_GenerateClientConfiguration_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MachineService_RenewCertificates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewCertificatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MachineServiceServer).RenewCertificates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/machine.MachineService/RenewCertificates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MachineServiceServer).RenewCertificates(ctx, req.(*RenewCertificatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MachineService_Wipe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WipeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MachineServiceServer).Wipe(m, &machineServiceWipeServer{stream})
+}
+
+type MachineService_WipeServer interface {
+	Send(*WipeProgress) error
+	grpc.ServerStream
+}
+
+type machineServiceWipeServer struct {
+	grpc.ServerStream
+}
+
+func (x *machineServiceWipeServer) Send(m *WipeProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // MachineService_ServiceDesc is the grpc.ServiceDesc for MachineService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1830,6 +1928,10 @@ var MachineService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GenerateClientConfiguration",
 			Handler:    _MachineService_GenerateClientConfiguration_Handler,
 		},
+		{
+			MethodName: "RenewCertificates",
+			Handler:    _MachineService_RenewCertificates_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1882,6 +1984,11 @@ var MachineService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _MachineService_Read_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Wipe",
+			Handler:       _MachineService_Wipe_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "machine/machine.proto",
 }
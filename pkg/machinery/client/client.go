@@ -489,6 +489,11 @@ func (c *Client) ResetGeneric(ctx context.Context, req *machineapi.ResetRequest)
 	return
 }
 
+// Wipe resets the node wiping the disks, the progress and the signed report are streamed back.
+func (c *Client) Wipe(ctx context.Context, req *machineapi.WipeRequest, callOptions ...grpc.CallOption) (machineapi.MachineService_WipeClient, error) {
+	return c.MachineClient.Wipe(ctx, req, callOptions...)
+}
+
 // Reboot implements the proto.MachineServiceClient interface.
 func (c *Client) Reboot(ctx context.Context) (err error) {
 	resp, err := c.MachineClient.Reboot(ctx, &emptypb.Empty{})
@@ -541,6 +546,13 @@ func (c *Client) Dmesg(ctx context.Context, follow, tail bool) (machineapi.Machi
 	})
 }
 
+// DmesgPrevious returns kernel crash records (oops, panic) saved from the previous boot.
+func (c *Client) DmesgPrevious(ctx context.Context) (machineapi.MachineService_DmesgClient, error) {
+	return c.MachineClient.Dmesg(ctx, &machineapi.DmesgRequest{
+		Previous: true,
+	})
+}
+
 // Logs implements the proto.MachineServiceClient interface.
 func (c *Client) Logs(ctx context.Context, namespace string, driver common.ContainerDriver, id string, follow bool, tailLines int32) (stream machineapi.MachineService_LogsClient, err error) {
 	stream, err = c.MachineClient.Logs(ctx, &machineapi.LogsRequest{
@@ -942,6 +954,17 @@ func (c *Client) GenerateClientConfiguration(ctx context.Context, req *machineap
 	return
 }
 
+// RenewCertificates renews certificates managed by Talos on the node.
+func (c *Client) RenewCertificates(ctx context.Context, req *machineapi.RenewCertificatesRequest, callOptions ...grpc.CallOption) (resp *machineapi.RenewCertificatesResponse, err error) {
+	resp, err = c.MachineClient.RenewCertificates(ctx, req, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*machineapi.RenewCertificatesResponse) //nolint:errcheck
+
+	return
+}
+
 // MachineStream is a common interface for streams returned by streaming APIs.
 type MachineStream interface {
 	Recv() (*common.Data, error)
@@ -112,6 +112,8 @@ func (c *Client) EventsWatch(ctx context.Context, watchFunc func(<-chan Event),
 			&machineapi.PhaseEvent{},
 			&machineapi.TaskEvent{},
 			&machineapi.ServiceStateEvent{},
+			&machineapi.DiskHealthEvent{},
+			&machineapi.FilesystemRepairEvent{},
 		} {
 			if typeURL == "talos/runtime/"+string(eventType.ProtoReflect().Descriptor().FullName()) {
 				msg = eventType
@@ -28,3 +28,14 @@ func (c *InspectClient) ControllerRuntimeDependencies(ctx context.Context, callO
 
 	return resp, err
 }
+
+// ControllerRuntimeHistory returns last reconcile runs of each controller.
+func (c *InspectClient) ControllerRuntimeHistory(ctx context.Context, callOptions ...grpc.CallOption) (*inspectapi.ControllerRuntimeHistoryResponse, error) {
+	resp, err := c.client.ControllerRuntimeHistory(ctx, &emptypb.Empty{}, callOptions...)
+
+	var filtered interface{}
+	filtered, err = FilterMessages(resp, err)
+	resp, _ = filtered.(*inspectapi.ControllerRuntimeHistoryResponse) //nolint:errcheck
+
+	return resp, err
+}
@@ -40,6 +40,7 @@ type MachineConfig interface {
 	Security() Security
 	Network() MachineNetwork
 	Disks() []Disk
+	VolumeGroups() []VolumeGroup
 	Time() Time
 	Env() Env
 	Files() ([]File, error)
@@ -49,6 +50,10 @@ type MachineConfig interface {
 	Registries() Registries
 	SystemDiskEncryption() SystemDiskEncryption
 	Features() Features
+	Services() []Service
+	Watchdog() Watchdog
+	Kernel() Kernel
+	FilesystemRepair() FilesystemRepair
 }
 
 // Disk represents the options available for partitioning, formatting, and
@@ -62,8 +67,40 @@ type Disk interface {
 type Partition interface {
 	Size() uint64
 	MountPoint() string
+	Filesystem() string
+	MountOptions() []string
+	Encryption() Encryption
 }
 
+// VolumeGroup represents the options for an LVM volume group.
+type VolumeGroup interface {
+	Name() string
+	Devices() []string
+	LogicalVolumes() []LogicalVolume
+}
+
+// LogicalVolume represents the options for an LVM logical volume.
+type LogicalVolume interface {
+	Name() string
+	Size() uint64
+	RAID() string
+	MountPoint() string
+	Filesystem() string
+	MountOptions() []string
+}
+
+// Filesystems supported for the disk partitions.
+const (
+	FilesystemXFS  = "xfs"
+	FilesystemExt4 = "ext4"
+)
+
+// RAID levels supported for the logical volumes.
+const (
+	RAID1  = "raid1"
+	RAID10 = "raid10"
+)
+
 // Env represents a set of environment variables.
 type Env = map[string]string
 
@@ -84,12 +121,21 @@ type Install interface {
 	Zero() bool
 	LegacyBIOSSupport() bool
 	WithBootloader() bool
+	EphemeralSize() uint64
+	ExtraPartitions() []InstallPartition
+}
+
+// InstallPartition defines the requirements for an extra partition on the installation disk.
+type InstallPartition interface {
+	Label() string
+	Size() uint64
 }
 
 // Security defines the requirements for a config that pertains to security
 // related options.
 type Security interface {
 	CA() *x509.PEMEncodedCertificateAndKey
+	AcceptedCAs() []*x509.PEMEncodedCertificateAndKey
 	Token() string
 	CertSANs() []string
 }
@@ -207,6 +253,41 @@ type Time interface {
 	Servers() []string
 }
 
+// Watchdog defines the requirements for a config that pertains to the watchdog
+// timer options.
+type Watchdog interface {
+	Enabled() bool
+	Device() string
+	Timeout() time.Duration
+}
+
+// FilesystemRepair defines the requirements for a config that pertains to the
+// filesystem repair on the mount failure.
+type FilesystemRepair interface {
+	Policy() string
+}
+
+// Filesystem repair policies.
+const (
+	FilesystemRepairNone   = "none"
+	FilesystemRepairCheck  = "check"
+	FilesystemRepairRepair = "repair"
+	FilesystemRepairForce  = "force"
+)
+
+// Kernel defines the requirements for a config that pertains to the kernel
+// related options.
+type Kernel interface {
+	Modules() []KernelModule
+}
+
+// KernelModule defines the requirements for a config that pertains to the kernel
+// module options.
+type KernelModule interface {
+	Name() string
+	Parameters() []string
+}
+
 // Kubelet defines the requirements for a config that pertains to kubelet
 // related options.
 type Kubelet interface {
@@ -215,6 +296,7 @@ type Kubelet interface {
 	ExtraArgs() map[string]string
 	ExtraMounts() []specs.Mount
 	RegisterWithFQDN() bool
+	ServerTLSBootstrap() bool
 }
 
 // Registries defines the configuration for image fetching.
@@ -404,8 +486,32 @@ type SystemDiskEncryption interface {
 // Features describe individual Talos features that can be switched on or off.
 type Features interface {
 	RBACEnabled() bool
+	MetricsEnabled() bool
+	TracingEndpoint() string
 }
 
+// Service describes overrides for the built-in service health checks and restart policy.
+type Service interface {
+	Name() string
+	HealthCheckInterval() time.Duration
+	HealthCheckTimeout() time.Duration
+	RestartBackoff() time.Duration
+	MaxRestarts() int
+	RestartWindow() time.Duration
+	Escalation() ServiceEscalation
+}
+
+// ServiceEscalation is an action taken when the service exceeds its restart budget.
+type ServiceEscalation string
+
+// Service escalation actions.
+const (
+	ServiceEscalationNone      ServiceEscalation = "none"
+	ServiceEscalationUnhealthy ServiceEscalation = "unhealthy"
+	ServiceEscalationCordon    ServiceEscalation = "cordon"
+	ServiceEscalationReboot    ServiceEscalation = "reboot"
+)
+
 // VolumeMount describes extra volume mount for the static pods.
 type VolumeMount interface {
 	Name() string
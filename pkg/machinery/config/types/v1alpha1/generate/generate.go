@@ -77,6 +77,7 @@ type Input struct {
 	RegistryConfig             map[string]*v1alpha1.RegistryConfig
 	MachineDisks               []*v1alpha1.MachineDisk
 	SystemDiskEncryptionConfig *v1alpha1.SystemDiskEncryptionConfig
+	WatchdogConfig             *v1alpha1.WatchdogConfig
 
 	Debug                    bool
 	Persist                  bool
@@ -464,6 +465,7 @@ func NewInput(clustername, endpoint, kubernetesVersion string, secrets *SecretsB
 		AllowSchedulingOnMasters:   options.AllowSchedulingOnMasters,
 		MachineDisks:               options.MachineDisks,
 		SystemDiskEncryptionConfig: options.SystemDiskEncryptionConfig,
+		WatchdogConfig:             options.WatchdogConfig,
 	}
 
 	return input, nil
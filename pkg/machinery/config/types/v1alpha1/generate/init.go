@@ -51,6 +51,7 @@ func initUd(in *Input) (*v1alpha1.Config, error) {
 		},
 		MachineDisks:                in.MachineDisks,
 		MachineSystemDiskEncryption: in.SystemDiskEncryptionConfig,
+		MachineWatchdog:             in.WatchdogConfig,
 		MachineFeatures:             &v1alpha1.FeaturesConfig{},
 	}
 
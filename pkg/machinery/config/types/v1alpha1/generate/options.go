@@ -194,6 +194,15 @@ func WithSystemDiskEncryption(cfg *v1alpha1.SystemDiskEncryptionConfig) GenOptio
 	}
 }
 
+// WithWatchdog enables the watchdog timer with the specified settings.
+func WithWatchdog(cfg *v1alpha1.WatchdogConfig) GenOption {
+	return func(o *GenOptions) error {
+		o.WatchdogConfig = cfg
+
+		return nil
+	}
+}
+
 // WithRoles specifies user roles.
 func WithRoles(roles role.Set) GenOption {
 	return func(o *GenOptions) error {
@@ -221,6 +230,7 @@ type GenOptions struct {
 	MachineDisks               []*v1alpha1.MachineDisk
 	VersionContract            *config.VersionContract
 	SystemDiskEncryptionConfig *v1alpha1.SystemDiskEncryptionConfig
+	WatchdogConfig             *v1alpha1.WatchdogConfig
 	Roles                      role.Set
 }
 
@@ -12,3 +12,17 @@ func (f *FeaturesConfig) RBACEnabled() bool {
 
 	return *f.RBAC
 }
+
+// MetricsEnabled implements config.Features interface.
+func (f *FeaturesConfig) MetricsEnabled() bool {
+	if f.Metrics == nil {
+		return false
+	}
+
+	return *f.Metrics
+}
+
+// TracingEndpoint implements config.Features interface.
+func (f *FeaturesConfig) TracingEndpoint() string {
+	return f.TracingEndpointURL
+}
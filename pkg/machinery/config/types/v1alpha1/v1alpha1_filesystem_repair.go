@@ -0,0 +1,23 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// FilesystemRepair implements the config.MachineConfig interface.
+func (m *MachineConfig) FilesystemRepair() config.FilesystemRepair {
+	return m.MachineFilesystemRepair
+}
+
+// Policy implements the config.FilesystemRepair interface.
+func (f *FilesystemRepairConfig) Policy() string {
+	if f == nil || f.FilesystemRepairPolicy == "" {
+		return config.FilesystemRepairNone
+	}
+
+	return f.FilesystemRepairPolicy
+}
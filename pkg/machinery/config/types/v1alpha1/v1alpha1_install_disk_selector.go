@@ -0,0 +1,185 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	glob "github.com/ryanuber/go-glob"
+	"github.com/talos-systems/go-blockdevice/blockdevice/util/disk"
+)
+
+// InstallDiskSelectorMismatch describes the selector field which doesn't match the disk.
+type InstallDiskSelectorMismatch struct {
+	// Field is the name of the selector field as in the machine config.
+	Field string
+	// Expected is the selector value.
+	Expected string
+	// Actual is the value of the disk.
+	Actual string
+}
+
+func (m InstallDiskSelectorMismatch) String() string {
+	actual := fmt.Sprintf("%q", m.Actual)
+	if m.Actual == "" {
+		actual = "<empty>"
+	}
+
+	return fmt.Sprintf("%s %s doesn't match %q", m.Field, actual, m.Expected)
+}
+
+// Explain matches the disk against the selector and returns the fields which don't match.
+//
+// The disk matches the selector if no mismatches are returned.
+// Bus path is passed separately as it's not part of the disk information.
+func (s *InstallDiskSelector) Explain(d *disk.Disk, busPath string) []InstallDiskSelectorMismatch {
+	var mismatches []InstallDiskSelectorMismatch
+
+	for _, check := range s.checks(func(*disk.Disk) string { return busPath }) {
+		if !check.match(d) {
+			mismatches = append(mismatches, InstallDiskSelectorMismatch{
+				Field:    check.field,
+				Expected: check.expected,
+				Actual:   check.actual(d),
+			})
+		}
+	}
+
+	return mismatches
+}
+
+// DiskBusPath returns the bus path of the disk as matched by the `busPath` disk selector.
+//
+// Bus path is the sysfs path of the disk device relative to `/sys/devices`,
+// e.g. `/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0`.
+// Empty string is returned if the path can't be resolved.
+func DiskBusPath(deviceName string) string {
+	return diskBusPath("/sys", deviceName)
+}
+
+func diskBusPath(sysfs, deviceName string) string {
+	name := filepath.Base(deviceName)
+
+	path, err := filepath.EvalSymlinks(filepath.Join(sysfs, "block", name))
+	if err != nil {
+		return ""
+	}
+
+	devices := filepath.Join(sysfs, "devices")
+
+	if !strings.HasPrefix(path, devices+"/") {
+		return ""
+	}
+
+	// e.g. /pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0/block/sda or /pci0000:00/0000:00:1d.0/0000:3d:00.0/nvme/nvme0/nvme0n1
+	path = strings.TrimSuffix(strings.TrimPrefix(path, devices), "/"+name)
+
+	return strings.TrimSuffix(path, "/block")
+}
+
+// diskSelectorCheck is a condition of the disk selector.
+type diskSelectorCheck struct {
+	field    string
+	expected string
+	actual   func(*disk.Disk) string
+	match    disk.Matcher
+}
+
+// checks returns the conditions set in the selector, busPath resolves the bus path of the disk.
+//
+//nolint:gocyclo
+func (s *InstallDiskSelector) checks(busPath func(*disk.Disk) string) []diskSelectorCheck {
+	checks := []diskSelectorCheck{}
+
+	if s.Size != nil && s.Size.Matcher != nil {
+		checks = append(checks, diskSelectorCheck{
+			field:    "size",
+			expected: s.Size.condition,
+			actual:   func(d *disk.Disk) string { return humanize.Bytes(d.Size) },
+			match:    s.Size.Matcher,
+		})
+	}
+
+	if s.UUID != "" {
+		checks = append(checks, diskSelectorCheck{
+			field:    "uuid",
+			expected: s.UUID,
+			actual:   func(d *disk.Disk) string { return d.UUID },
+			match:    disk.WithUUID(s.UUID),
+		})
+	}
+
+	if s.WWID != "" {
+		checks = append(checks, diskSelectorCheck{
+			field:    "wwid",
+			expected: s.WWID,
+			actual:   func(d *disk.Disk) string { return d.WWID },
+			match:    disk.WithWWID(s.WWID),
+		})
+	}
+
+	if s.Model != "" {
+		checks = append(checks, diskSelectorCheck{
+			field:    "model",
+			expected: s.Model,
+			actual:   func(d *disk.Disk) string { return d.Model },
+			match:    disk.WithModel(s.Model),
+		})
+	}
+
+	if s.Name != "" {
+		checks = append(checks, diskSelectorCheck{
+			field:    "name",
+			expected: s.Name,
+			actual:   func(d *disk.Disk) string { return d.Name },
+			match:    disk.WithName(s.Name),
+		})
+	}
+
+	if s.Serial != "" {
+		checks = append(checks, diskSelectorCheck{
+			field:    "serial",
+			expected: s.Serial,
+			actual:   func(d *disk.Disk) string { return d.Serial },
+			match:    disk.WithSerial(s.Serial),
+		})
+	}
+
+	if s.Modalias != "" {
+		checks = append(checks, diskSelectorCheck{
+			field:    "modalias",
+			expected: s.Modalias,
+			actual:   func(d *disk.Disk) string { return d.Modalias },
+			match:    disk.WithModalias(s.Modalias),
+		})
+	}
+
+	if s.BusPath != "" {
+		pattern := s.BusPath
+
+		checks = append(checks, diskSelectorCheck{
+			field:    "busPath",
+			expected: pattern,
+			actual:   busPath,
+			match: func(d *disk.Disk) bool {
+				return glob.Glob(pattern, busPath(d))
+			},
+		})
+	}
+
+	if t := disk.Type(s.Type); t != disk.TypeUnknown {
+		checks = append(checks, diskSelectorCheck{
+			field:    "type",
+			expected: t.String(),
+			actual:   func(d *disk.Disk) string { return d.Type.String() },
+			match:    disk.WithType(t),
+		})
+	}
+
+	return checks
+}
@@ -0,0 +1,84 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talos-systems/go-blockdevice/blockdevice/util/disk"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDiskBusPath(t *testing.T) {
+	sysfs := t.TempDir()
+
+	for name, path := range map[string]string{
+		"sda":     "pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0/block/sda",
+		"nvme0n1": "pci0000:00/0000:00:1d.0/0000:3d:00.0/nvme/nvme0/nvme0n1",
+		"loop0":   "virtual/block/loop0",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(sysfs, "devices", path), 0o755))
+		require.NoError(t, os.MkdirAll(filepath.Join(sysfs, "block"), 0o755))
+		require.NoError(t, os.Symlink(filepath.Join("..", "devices", path), filepath.Join(sysfs, "block", name)))
+	}
+
+	assert.Equal(t, "/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0", diskBusPath(sysfs, "/dev/sda"))
+	assert.Equal(t, "/pci0000:00/0000:00:1d.0/0000:3d:00.0/nvme/nvme0", diskBusPath(sysfs, "/dev/nvme0n1"))
+	assert.Equal(t, "/virtual", diskBusPath(sysfs, "loop0"))
+	assert.Equal(t, "", diskBusPath(sysfs, "/dev/sdb"))
+}
+
+func TestInstallDiskSelectorExplain(t *testing.T) {
+	var selector InstallDiskSelector
+
+	require.NoError(t, yaml.Unmarshal([]byte(`
+size: ">= 100GB"
+serial: "S4EV*"
+wwid: "eui.*"
+busPath: "/pci0000:00/0000:00:1d.0/*"
+type: nvme
+`), &selector))
+
+	d := &disk.Disk{
+		DeviceName: "/dev/nvme0n1",
+		Size:       500 * 1000 * 1000 * 1000,
+		Serial:     "S4EVNX0N123456",
+		WWID:       "eui.0025385b71b0a0b5",
+		Type:       disk.TypeNVMe,
+	}
+
+	assert.Empty(t, selector.Explain(d, "/pci0000:00/0000:00:1d.0/0000:3d:00.0/nvme/nvme0"))
+
+	mismatches := selector.Explain(d, "/pci0000:00/0000:00:1c.0/0000:3c:00.0/nvme/nvme1")
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, "busPath", mismatches[0].Field)
+	assert.Equal(t, `busPath "/pci0000:00/0000:00:1c.0/0000:3c:00.0/nvme/nvme1" doesn't match "/pci0000:00/0000:00:1d.0/*"`, mismatches[0].String())
+
+	d = &disk.Disk{
+		DeviceName: "/dev/sda",
+		Size:       80 * 1000 * 1000 * 1000,
+		Type:       disk.TypeSSD,
+	}
+
+	mismatches = selector.Explain(d, "")
+
+	fields := make([]string, 0, len(mismatches))
+
+	for _, mismatch := range mismatches {
+		fields = append(fields, mismatch.Field)
+	}
+
+	assert.Equal(t, []string{"size", "wwid", "serial", "busPath", "type"}, fields)
+	assert.Equal(t, `size "80 GB" doesn't match ">= 100GB"`, mismatches[0].String())
+	assert.Equal(t, `wwid <empty> doesn't match "eui.*"`, mismatches[1].String())
+
+	// disk matchers use the same conditions
+	assert.Len(t, (&InstallConfig{InstallDiskSelector: &selector}).DiskMatchers(), 5)
+	assert.Empty(t, (&InstallDiskSelector{}).Explain(d, ""))
+}
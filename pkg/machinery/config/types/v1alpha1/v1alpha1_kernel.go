@@ -0,0 +1,39 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// Kernel implements the config.MachineConfig interface.
+func (m *MachineConfig) Kernel() config.Kernel {
+	if m.MachineKernel == nil {
+		return &KernelConfig{}
+	}
+
+	return m.MachineKernel
+}
+
+// Modules implements the config.Kernel interface.
+func (k *KernelConfig) Modules() []config.KernelModule {
+	modules := make([]config.KernelModule, len(k.KernelModules))
+
+	for i, module := range k.KernelModules {
+		modules[i] = module
+	}
+
+	return modules
+}
+
+// Name implements the config.KernelModule interface.
+func (m *KernelModuleConfig) Name() string {
+	return m.ModuleName
+}
+
+// Parameters implements the config.KernelModule interface.
+func (m *KernelModuleConfig) Parameters() []string {
+	return m.ModuleParameters
+}
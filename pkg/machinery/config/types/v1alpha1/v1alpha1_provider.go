@@ -174,6 +174,17 @@ func (m *MachineConfig) Disks() []config.Disk {
 	return disks
 }
 
+// VolumeGroups implements the config.Provider interface.
+func (m *MachineConfig) VolumeGroups() []config.VolumeGroup {
+	volumeGroups := make([]config.VolumeGroup, len(m.MachineVolumeGroups))
+
+	for i := 0; i < len(m.MachineVolumeGroups); i++ {
+		volumeGroups[i] = m.MachineVolumeGroups[i]
+	}
+
+	return volumeGroups
+}
+
 // Network implements the config.Provider interface.
 func (m *MachineConfig) Network() config.MachineNetwork {
 	if m.MachineNetwork == nil {
@@ -243,6 +254,11 @@ func (m *MachineConfig) CA() *x509.PEMEncodedCertificateAndKey {
 	return m.MachineCA
 }
 
+// AcceptedCAs implements the config.Provider interface.
+func (m *MachineConfig) AcceptedCAs() []*x509.PEMEncodedCertificateAndKey {
+	return m.MachineAcceptedCAs
+}
+
 // Token implements the config.Provider interface.
 func (m *MachineConfig) Token() string {
 	return m.MachineToken
@@ -325,6 +341,11 @@ func (k *KubeletConfig) RegisterWithFQDN() bool {
 	return k.KubeletRegisterWithFQDN
 }
 
+// ServerTLSBootstrap implements the config.Provider interface.
+func (k *KubeletConfig) ServerTLSBootstrap() bool {
+	return k.KubeletServerTLSBootstrap
+}
+
 // Mirrors implements the Registries interface.
 func (r *RegistriesConfig) Mirrors() map[string]config.RegistryMirrorConfig {
 	mirrors := make(map[string]config.RegistryMirrorConfig, len(r.RegistryMirrors))
@@ -876,39 +897,14 @@ func (i *InstallConfig) Disk() (string, error) {
 // DiskMatchers implements the config.Provider interface.
 func (i *InstallConfig) DiskMatchers() []disk.Matcher {
 	if i.InstallDiskSelector != nil {
-		selector := i.InstallDiskSelector
-
-		matchers := []disk.Matcher{}
-		if selector.Size != nil {
-			matchers = append(matchers, selector.Size.Matcher)
-		}
-
-		if selector.UUID != "" {
-			matchers = append(matchers, disk.WithUUID(selector.UUID))
-		}
-
-		if selector.WWID != "" {
-			matchers = append(matchers, disk.WithWWID(selector.WWID))
-		}
-
-		if selector.Model != "" {
-			matchers = append(matchers, disk.WithModel(selector.Model))
-		}
-
-		if selector.Name != "" {
-			matchers = append(matchers, disk.WithName(selector.Name))
-		}
+		checks := i.InstallDiskSelector.checks(func(d *disk.Disk) string {
+			return DiskBusPath(d.DeviceName)
+		})
 
-		if selector.Serial != "" {
-			matchers = append(matchers, disk.WithSerial(selector.Serial))
-		}
+		matchers := make([]disk.Matcher, 0, len(checks))
 
-		if selector.Modalias != "" {
-			matchers = append(matchers, disk.WithModalias(selector.Modalias))
-		}
-
-		if disk.Type(selector.Type) != disk.TypeUnknown {
-			matchers = append(matchers, disk.WithType(disk.Type(selector.Type)))
+		for _, check := range checks {
+			matchers = append(matchers, check.match)
 		}
 
 		return matchers
@@ -937,6 +933,32 @@ func (i *InstallConfig) WithBootloader() bool {
 	return i.InstallBootloader
 }
 
+// EphemeralSize implements the config.Provider interface.
+func (i *InstallConfig) EphemeralSize() uint64 {
+	return uint64(i.InstallEphemeralSize)
+}
+
+// ExtraPartitions implements the config.Provider interface.
+func (i *InstallConfig) ExtraPartitions() []config.InstallPartition {
+	partitions := make([]config.InstallPartition, len(i.InstallExtraPartitions))
+
+	for j, p := range i.InstallExtraPartitions {
+		partitions[j] = p
+	}
+
+	return partitions
+}
+
+// Label implements the config.Provider interface.
+func (p *InstallExtraPartition) Label() string {
+	return p.PartitionLabel
+}
+
+// Size implements the config.Provider interface.
+func (p *InstallExtraPartition) Size() uint64 {
+	return uint64(p.PartitionSize)
+}
+
 // Enabled implements the config.Provider interface.
 func (c *CoreDNS) Enabled() bool {
 	return !c.CoreDNSDisabled
@@ -1013,6 +1035,84 @@ func (p *DiskPartition) MountPoint() string {
 	return p.DiskMountPoint
 }
 
+// Filesystem implements the config.Provider interface.
+func (p *DiskPartition) Filesystem() string {
+	if p.DiskFilesystem == "" {
+		return config.FilesystemXFS
+	}
+
+	return p.DiskFilesystem
+}
+
+// MountOptions implements the config.Provider interface.
+func (p *DiskPartition) MountOptions() []string {
+	return p.DiskMountOptions
+}
+
+// Encryption implements the config.Provider interface.
+func (p *DiskPartition) Encryption() config.Encryption {
+	if p.DiskEncryption == nil {
+		return nil
+	}
+
+	return p.DiskEncryption
+}
+
+// Name implements the config.Provider interface.
+func (vg *VolumeGroupConfig) Name() string {
+	return vg.VolumeGroupName
+}
+
+// Devices implements the config.Provider interface.
+func (vg *VolumeGroupConfig) Devices() []string {
+	return vg.VolumeGroupDevices
+}
+
+// LogicalVolumes implements the config.Provider interface.
+func (vg *VolumeGroupConfig) LogicalVolumes() []config.LogicalVolume {
+	logicalVolumes := make([]config.LogicalVolume, len(vg.VolumeGroupLogicalVolumes))
+
+	for i := 0; i < len(vg.VolumeGroupLogicalVolumes); i++ {
+		logicalVolumes[i] = vg.VolumeGroupLogicalVolumes[i]
+	}
+
+	return logicalVolumes
+}
+
+// Name implements the config.Provider interface.
+func (lv *LogicalVolumeConfig) Name() string {
+	return lv.LogicalVolumeName
+}
+
+// Size implements the config.Provider interface.
+func (lv *LogicalVolumeConfig) Size() uint64 {
+	return uint64(lv.LogicalVolumeSize)
+}
+
+// RAID implements the config.Provider interface.
+func (lv *LogicalVolumeConfig) RAID() string {
+	return lv.LogicalVolumeRAID
+}
+
+// MountPoint implements the config.Provider interface.
+func (lv *LogicalVolumeConfig) MountPoint() string {
+	return lv.LogicalVolumeMountPoint
+}
+
+// Filesystem implements the config.Provider interface.
+func (lv *LogicalVolumeConfig) Filesystem() string {
+	if lv.LogicalVolumeFilesystem == "" {
+		return config.FilesystemXFS
+	}
+
+	return lv.LogicalVolumeFilesystem
+}
+
+// MountOptions implements the config.Provider interface.
+func (lv *LogicalVolumeConfig) MountOptions() []string {
+	return lv.LogicalVolumeMountOptions
+}
+
 // Kind implements the config.Provider interface.
 func (e *EncryptionConfig) Kind() string {
 	return e.EncryptionProvider
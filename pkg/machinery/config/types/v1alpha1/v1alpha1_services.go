@@ -0,0 +1,81 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+)
+
+// Services implements the config.MachineConfig interface.
+func (m *MachineConfig) Services() []config.Service {
+	services := make([]config.Service, len(m.MachineServices))
+
+	for i := range m.MachineServices {
+		services[i] = m.MachineServices[i]
+	}
+
+	return services
+}
+
+// Name implements the config.Service interface.
+func (s *ServiceConfig) Name() string {
+	return s.ServiceName
+}
+
+// HealthCheckInterval implements the config.Service interface.
+func (s *ServiceConfig) HealthCheckInterval() time.Duration {
+	if s.ServiceHealthCheck == nil {
+		return 0
+	}
+
+	return s.ServiceHealthCheck.HealthCheckInterval
+}
+
+// HealthCheckTimeout implements the config.Service interface.
+func (s *ServiceConfig) HealthCheckTimeout() time.Duration {
+	if s.ServiceHealthCheck == nil {
+		return 0
+	}
+
+	return s.ServiceHealthCheck.HealthCheckTimeout
+}
+
+// RestartBackoff implements the config.Service interface.
+func (s *ServiceConfig) RestartBackoff() time.Duration {
+	if s.ServiceRestart == nil {
+		return 0
+	}
+
+	return s.ServiceRestart.RestartBackoff
+}
+
+// MaxRestarts implements the config.Service interface.
+func (s *ServiceConfig) MaxRestarts() int {
+	if s.ServiceRestart == nil {
+		return 0
+	}
+
+	return s.ServiceRestart.RestartMaxRestarts
+}
+
+// RestartWindow implements the config.Service interface.
+func (s *ServiceConfig) RestartWindow() time.Duration {
+	if s.ServiceRestart == nil {
+		return 0
+	}
+
+	return s.ServiceRestart.RestartWindow
+}
+
+// Escalation implements the config.Service interface.
+func (s *ServiceConfig) Escalation() config.ServiceEscalation {
+	if s.ServiceRestart == nil || s.ServiceRestart.RestartEscalation == "" {
+		return config.ServiceEscalationNone
+	}
+
+	return config.ServiceEscalation(s.ServiceRestart.RestartEscalation)
+}
@@ -173,6 +173,31 @@ var (
 		},
 	}
 
+	machineVolumeGroupsExample = []*VolumeGroupConfig{
+		{
+			VolumeGroupName:    "data",
+			VolumeGroupDevices: []string{"/dev/sdb", "/dev/sdc"},
+			VolumeGroupLogicalVolumes: []*LogicalVolumeConfig{
+				{
+					LogicalVolumeName:       "local",
+					LogicalVolumeSize:       DiskSize(100 * 1000 * 1000 * 1000),
+					LogicalVolumeRAID:       "raid1",
+					LogicalVolumeMountPoint: "/var/mnt/local",
+				},
+			},
+		},
+	}
+
+	machineDiskPartitionEncryptionExample = &EncryptionConfig{
+		EncryptionProvider: "luks2",
+		EncryptionKeys: []*EncryptionKey{
+			{
+				KeyNodeID: &EncryptionKeyNodeID{},
+				KeySlot:   0,
+			},
+		},
+	}
+
 	machineInstallExample = &InstallConfig{
 		InstallDisk:            "/dev/sda",
 		InstallExtraKernelArgs: []string{"console=ttyS1", "panic=10"},
@@ -188,6 +213,16 @@ var (
 		},
 	}
 
+	machineInstallExtraPartitionsExample = []*InstallExtraPartition{
+		{
+			PartitionLabel: "DATA",
+			PartitionSize:  DiskSize(500 * 1000 * 1000 * 1000),
+		},
+		{
+			PartitionLabel: "CEPH",
+		},
+	}
+
 	machineInstallDiskSizeMatcherExamples = []*InstallDiskSizeMatcher{
 		{
 			condition: "4GB",
@@ -249,6 +284,42 @@ var (
 		RBAC: pointer.ToBool(true),
 	}
 
+	machineWatchdogExample = &WatchdogConfig{
+		WatchdogTimeout: 2 * time.Minute,
+	}
+
+	machineFilesystemRepairExample = &FilesystemRepairConfig{
+		FilesystemRepairPolicy: "repair",
+	}
+
+	machineKernelExample = &KernelConfig{
+		KernelModules: []*KernelModuleConfig{
+			{
+				ModuleName: "nvme_tcp",
+			},
+			{
+				ModuleName:       "ip_vs",
+				ModuleParameters: []string{"conn_tab_bits=15"},
+			},
+		},
+	}
+
+	machineServicesExample = []*ServiceConfig{
+		{
+			ServiceName: "etcd",
+			ServiceHealthCheck: &ServiceHealthCheckConfig{
+				HealthCheckInterval: 10 * time.Second,
+				HealthCheckTimeout:  time.Second,
+			},
+			ServiceRestart: &ServiceRestartConfig{
+				RestartBackoff:     15 * time.Second,
+				RestartMaxRestarts: 10,
+				RestartWindow:      30 * time.Minute,
+				RestartEscalation:  "cordon",
+			},
+		},
+	}
+
 	clusterConfigExample = struct {
 		ControlPlane *ControlPlaneConfig   `yaml:"controlPlane"`
 		ClusterName  string                `yaml:"clusterName"`
@@ -527,6 +598,11 @@ type MachineConfig struct {
 	//       name: machine CA example
 	MachineCA *x509.PEMEncodedCertificateAndKey `yaml:"ca,omitempty"`
 	//   description: |
+	//     The additional certificate authorities trusted by the machine API, only `crt` should be specified.
+	//     Client certificates signed by any of the accepted CAs are accepted in addition to the ones signed by the machine CA.
+	//     This is used to rotate the machine CA without downtime.
+	MachineAcceptedCAs []*x509.PEMEncodedCertificateAndKey `yaml:"acceptedCAs,omitempty"`
+	//   description: |
 	//     Extra certificate subject alternative names for the machine's certificate.
 	//     By default, all non-loopback interface IPs are automatically added to the certificate's SANs.
 	//   examples:
@@ -555,6 +631,15 @@ type MachineConfig struct {
 	//       value: machineDisksExample
 	MachineDisks []*MachineDisk `yaml:"disks,omitempty"` // Note: `size` is in units of bytes.
 	//   description: |
+	//     Used to create LVM volume groups and logical volumes on the additional disks.
+	//     Volume groups and logical volumes are created only if they don't exist yet, existing ones are never modified.
+	//     Logical volumes can be mirrored (`raid1`) or striped and mirrored (`raid10`) across the disks of the volume group,
+	//     so that a single disk failure doesn't cause data loss.
+	//     Disks used for the volume groups should not be listed in `.machine.disks`.
+	//   examples:
+	//     - value: machineVolumeGroupsExample
+	MachineVolumeGroups []*VolumeGroupConfig `yaml:"volumeGroups,omitempty"`
+	//   description: |
 	//     Used to provide instructions for installations.
 	//   examples:
 	//     - name: MachineInstall config usage example.
@@ -625,6 +710,34 @@ type MachineConfig struct {
 	//   examples:
 	//     - value: machineFeaturesExample
 	MachineFeatures *FeaturesConfig `yaml:"features,omitempty"`
+	//   description: |
+	//     Overrides for the health checks and restart policies of the built-in services.
+	//   examples:
+	//     - value: machineServicesExample
+	MachineServices []*ServiceConfig `yaml:"services,omitempty"`
+	//   description: |
+	//     Watchdog timer configuration.
+	//
+	//     When set, machined pets the watchdog timer while the critical services are healthy.
+	//     If the node hangs, or critical services stay unhealthy for longer than the timeout, the node is reset by the watchdog.
+	//     If the hardware watchdog is not available, `softdog` kernel module is used.
+	//   examples:
+	//     - value: machineWatchdogExample
+	MachineWatchdog *WatchdogConfig `yaml:"watchdog,omitempty"`
+	//   description: |
+	//     Configures the kernel.
+	//   examples:
+	//     - value: machineKernelExample
+	MachineKernel *KernelConfig `yaml:"kernel,omitempty"`
+	//   description: |
+	//     Filesystem check and repair settings for the partitions which fail to mount.
+	//
+	//     The policy applies to the system partitions, user disk partitions and logical volumes formatted with `xfs` or `ext4`.
+	//     `STATE` partition is mounted on boot before the machine config is loaded, so the policy doesn't apply to it on boot.
+	//     Results are published as the machine events (`talosctl events`).
+	//   examples:
+	//     - value: machineFilesystemRepairExample
+	MachineFilesystemRepair *FilesystemRepairConfig `yaml:"filesystemRepair,omitempty"`
 }
 
 // ClusterConfig represents the cluster-wide config values.
@@ -811,6 +924,16 @@ type KubeletConfig struct {
 	//     - false
 	//     - no
 	KubeletRegisterWithFQDN bool `yaml:"registerWithFQDN,omitempty"`
+	//   description: |
+	//     The `serverTLSBootstrap` field enables kubelet to request its serving certificate via CSR.
+	//     Certificate signing requests are verified against the node addresses and approved by Talos control plane nodes,
+	//     kubelet rotates the serving certificate before it expires.
+	//   values:
+	//     - true
+	//     - yes
+	//     - false
+	//     - no
+	KubeletServerTLSBootstrap bool `yaml:"serverTLSBootstrap,omitempty"`
 }
 
 // NetworkConfig represents the machine's networking config values.
@@ -885,6 +1008,34 @@ type InstallConfig struct {
 	//     Indicates if MBR partition should be marked as bootable (active).
 	//     Should be enabled only for the systems with legacy BIOS that doesn't support GPT partitioning scheme.
 	InstallLegacyBIOSSupport bool `yaml:"legacyBIOSSupport,omitempty"`
+	//   description: |
+	//     The size of the EPHEMERAL partition: either bytes or human readable representation.
+	//     If not set, the EPHEMERAL partition occupies the rest of the installation disk.
+	//     The size is applied when the EPHEMERAL partition is created, i.e. on install or on upgrade without `--preserve`.
+	//   examples:
+	//     - value: DiskSize(100 * 1000 * 1000 * 1000)
+	InstallEphemeralSize DiskSize `yaml:"ephemeralSize,omitempty"`
+	//   description: |
+	//     Extra partitions to create on the installation disk after the EPHEMERAL partition.
+	//     Partitions are created without a filesystem, and they are preserved across upgrades and resets
+	//     of the system disk with a partition spec.
+	//     Requires `ephemeralSize` to be set.
+	//   examples:
+	//     - value: machineInstallExtraPartitionsExample
+	InstallExtraPartitions []*InstallExtraPartition `yaml:"extraPartitions,omitempty"`
+}
+
+// InstallExtraPartition represents an extra partition on the installation disk.
+type InstallExtraPartition struct {
+	//   description: |
+	//     Partition label (GPT partition name), should be unique and shouldn't match any of the Talos system partition labels.
+	//   examples:
+	//     - value: '"DATA"'
+	PartitionLabel string `yaml:"label"`
+	//   description: >
+	//     The size of partition: either bytes or human readable representation. If `size:`
+	//     is omitted, the partition is sized to occupy the rest of the disk.
+	PartitionSize DiskSize `yaml:"size,omitempty"`
 }
 
 // InstallDiskSizeMatcher disk size condition parser.
@@ -990,6 +1141,9 @@ func (it *InstallDiskType) UnmarshalYAML(unmarshal func(interface{}) error) erro
 }
 
 // InstallDiskSelector represents a disk query parameters for the install disk lookup.
+//
+// All the conditions should match the disk, string values may contain `*` wildcards.
+// If several disks match, the first one in the order of `/sys/block` is used.
 type InstallDiskSelector struct {
 	//   description: Disk size.
 	//   examples:
@@ -1012,6 +1166,15 @@ type InstallDiskSelector struct {
 	UUID string `yaml:"uuid,omitempty"`
 	//   description: Disk WWID `/sys/block/<dev>/wwid`.
 	WWID string `yaml:"wwid,omitempty"`
+	//   description: |
+	//     Disk bus path: sysfs path of the disk device relative to `/sys/devices`.
+	//
+	//     Unlike the device name, the bus path is stable across reboots as long as the disk stays in the same slot.
+	//     Run `talosctl disks` to see the bus paths of the disks.
+	//   examples:
+	//     - value: '"/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0"'
+	//     - value: '"/pci0000:00/*"'
+	BusPath string `yaml:"busPath,omitempty"`
 	//   description: Disk Type.
 	//   values:
 	//     - ssd
@@ -1385,6 +1548,68 @@ type DiskPartition struct {
 	//   description:
 	//     Where to mount the partition.
 	DiskMountPoint string `yaml:"mountpoint,omitempty"`
+	//   description: |
+	//     Filesystem type to format the partition with.
+	//     Defaults to `xfs`.
+	//   values:
+	//     - xfs
+	//     - ext4
+	DiskFilesystem string `yaml:"filesystem,omitempty"`
+	//   description: |
+	//     Options to mount the partition with.
+	//     Options are passed as is to the filesystem, except for the generic mount flags (e.g. `nodev`, `nosuid`, `ro`).
+	//   examples:
+	//     - value: '[]string{"nodev", "nosuid", "discard"}'
+	DiskMountOptions []string `yaml:"mountOptions,omitempty"`
+	//   description: |
+	//     Partition encryption settings.
+	//     The partition is encrypted when it is set up for the first time, existing unencrypted partitions are not converted.
+	//   examples:
+	//     - value: machineDiskPartitionEncryptionExample
+	DiskEncryption *EncryptionConfig `yaml:"encryption,omitempty"`
+}
+
+// VolumeGroupConfig represents the options for an LVM volume group.
+type VolumeGroupConfig struct {
+	//   description: Volume group name.
+	VolumeGroupName string `yaml:"name"`
+	//   description: |
+	//     Disks to be used as physical volumes of the volume group.
+	//     Disks should be empty (without partition table or filesystem signatures) when the volume group is created.
+	VolumeGroupDevices []string `yaml:"devices"`
+	//   description: Logical volumes to create in the volume group.
+	VolumeGroupLogicalVolumes []*LogicalVolumeConfig `yaml:"logicalVolumes,omitempty"`
+}
+
+// LogicalVolumeConfig represents the options for an LVM logical volume.
+type LogicalVolumeConfig struct {
+	//   description: Logical volume name.
+	LogicalVolumeName string `yaml:"name"`
+	//   description: >
+	//     The size of the logical volume: either bytes or human readable representation. If `size:`
+	//     is omitted, the logical volume is sized to occupy the rest of the volume group.
+	LogicalVolumeSize DiskSize `yaml:"size,omitempty"`
+	//   description: |
+	//     RAID level of the logical volume.
+	//     `raid1` mirrors the data across all the disks of the volume group, `raid10` requires an even number of disks, at least four.
+	//     If not set, the logical volume is linear (no redundancy).
+	//   values:
+	//     - raid1
+	//     - raid10
+	LogicalVolumeRAID string `yaml:"raid,omitempty"`
+	//   description: |
+	//     Where to mount the logical volume.
+	//     If not set, the logical volume is created, but not formatted or mounted.
+	LogicalVolumeMountPoint string `yaml:"mountpoint,omitempty"`
+	//   description: |
+	//     Filesystem type to format the logical volume with.
+	//     Defaults to `xfs`.
+	//   values:
+	//     - xfs
+	//     - ext4
+	LogicalVolumeFilesystem string `yaml:"filesystem,omitempty"`
+	//   description: Options to mount the logical volume with.
+	LogicalVolumeMountOptions []string `yaml:"mountOptions,omitempty"`
 }
 
 // EncryptionConfig represents partition encryption settings.
@@ -1800,6 +2025,124 @@ type FeaturesConfig struct {
 	//   description: |
 	//     Enable role-based access control (RBAC).
 	RBAC *bool `yaml:"rbac,omitempty"`
+	//   description: |
+	//     Enable Prometheus metrics endpoint.
+	//
+	//     Metrics are served over HTTPS on port 50002 at `/metrics`, client certificate is required
+	//     as for the Talos API. If RBAC is enabled, client should have `os:admin` or `os:reader` role.
+	Metrics *bool `yaml:"metrics,omitempty"`
+	//   description: |
+	//     OpenTelemetry collector endpoint to export controller reconcile runs as trace spans.
+	//
+	//     Spans are sent with OTLP over HTTP (JSON encoding) to `<endpoint>/v1/traces`, export is disabled if not set.
+	//   examples:
+	//     - value: '"http://otel-collector:4318"'
+	TracingEndpointURL string `yaml:"tracingEndpoint,omitempty"`
+}
+
+// ServiceConfig describes overrides for the built-in service.
+type ServiceConfig struct {
+	//   description: |
+	//     Name of the service (e.g. `etcd` or `kubelet`).
+	ServiceName string `yaml:"name"`
+	//   description: |
+	//     Health check settings.
+	ServiceHealthCheck *ServiceHealthCheckConfig `yaml:"healthCheck,omitempty"`
+	//   description: |
+	//     Restart policy settings.
+	ServiceRestart *ServiceRestartConfig `yaml:"restart,omitempty"`
+}
+
+// ServiceHealthCheckConfig describes overrides for the service health check.
+type ServiceHealthCheckConfig struct {
+	//   description: |
+	//     Interval between the health checks.
+	//     Field format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes).
+	HealthCheckInterval time.Duration `yaml:"interval,omitempty"`
+	//   description: |
+	//     Health check timeout.
+	//     Field format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes).
+	HealthCheckTimeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// ServiceRestartConfig describes overrides for the service restart policy.
+type ServiceRestartConfig struct {
+	//   description: |
+	//     Interval between the service restarts.
+	//     Field format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes).
+	RestartBackoff time.Duration `yaml:"backoff,omitempty"`
+	//   description: |
+	//     Maximum number of the service restarts within the restart window, zero means no limit.
+	//     When the service exceeds the restart budget, it is stopped and the escalation action is run.
+	RestartMaxRestarts int `yaml:"maxRestarts,omitempty"`
+	//   description: |
+	//     Period of time the service restarts are counted over, defaults to one hour.
+	//     Restarts which happened before the window don't count towards the restart budget.
+	//     Field format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes).
+	RestartWindow time.Duration `yaml:"window,omitempty"`
+	//   description: |
+	//     Escalation action when the service exceeds the restart budget.
+	//
+	//     `unhealthy` marks the service health check as failed, so that the node is reported as unhealthy,
+	//     `cordon` cordons the Kubernetes node, `reboot` reboots the node.
+	//   values:
+	//     - none
+	//     - unhealthy
+	//     - cordon
+	//     - reboot
+	RestartEscalation string `yaml:"escalation,omitempty"`
+}
+
+// WatchdogConfig describes the watchdog timer settings.
+type WatchdogConfig struct {
+	//   description: |
+	//     Path to the watchdog device.
+	//     Defaults to `/dev/watchdog`.
+	WatchdogDevice string `yaml:"device,omitempty"`
+	//   description: |
+	//     Watchdog timeout, the node is reset if the watchdog is not pet for this long.
+	//     Should be at least 10 seconds, defaults to one minute.
+	//     Field format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes).
+	WatchdogTimeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// FilesystemRepairConfig describes the filesystem repair settings.
+type FilesystemRepairConfig struct {
+	//   description: |
+	//     What to do when a partition fails to mount:
+	//     `none` fails the mount, `check` runs the filesystem check without modifying the filesystem and reports the result,
+	//     `repair` fixes the problems which can be fixed safely and retries the mount,
+	//     `force` also fixes the problems which discard data (e.g. zeroes the dirty XFS log).
+	//     Defaults to `none`.
+	//   values:
+	//     - none
+	//     - check
+	//     - repair
+	//     - force
+	FilesystemRepairPolicy string `yaml:"policy,omitempty"`
+}
+
+// KernelConfig struct configures the kernel.
+type KernelConfig struct {
+	//   description: |
+	//     Kernel modules to load on boot.
+	//
+	//     Modules should be shipped with the Talos image, dependencies are loaded automatically.
+	KernelModules []*KernelModuleConfig `yaml:"modules,omitempty"`
+}
+
+// KernelModuleConfig struct configures the kernel module to load.
+type KernelModuleConfig struct {
+	//   description: |
+	//     Module name.
+	//   examples:
+	//     - value: '"nvme_tcp"'
+	ModuleName string `yaml:"name"`
+	//   description: |
+	//     Module parameters, in the `key=value` format.
+	//   examples:
+	//     - value: '[]string{"conn_tab_bits=15"}'
+	ModuleParameters []string `yaml:"parameters,omitempty"`
 }
 
 // VolumeMountConfig struct describes extra volume mount for the static pods.
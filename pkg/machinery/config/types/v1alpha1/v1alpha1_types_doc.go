@@ -18,6 +18,7 @@ var (
 	KubeletConfigDoc               encoder.Doc
 	NetworkConfigDoc               encoder.Doc
 	InstallConfigDoc               encoder.Doc
+	InstallExtraPartitionDoc       encoder.Doc
 	InstallDiskSizeMatcherDoc      encoder.Doc
 	InstallDiskSelectorDoc         encoder.Doc
 	TimeConfigDoc                  encoder.Doc
@@ -37,6 +38,8 @@ var (
 	AdminKubeconfigConfigDoc       encoder.Doc
 	MachineDiskDoc                 encoder.Doc
 	DiskPartitionDoc               encoder.Doc
+	VolumeGroupConfigDoc           encoder.Doc
+	LogicalVolumeConfigDoc         encoder.Doc
 	EncryptionConfigDoc            encoder.Doc
 	EncryptionKeyDoc               encoder.Doc
 	EncryptionKeyStaticDoc         encoder.Doc
@@ -57,6 +60,13 @@ var (
 	RegistryTLSConfigDoc           encoder.Doc
 	SystemDiskEncryptionConfigDoc  encoder.Doc
 	FeaturesConfigDoc              encoder.Doc
+	ServiceConfigDoc               encoder.Doc
+	ServiceHealthCheckConfigDoc    encoder.Doc
+	ServiceRestartConfigDoc        encoder.Doc
+	WatchdogConfigDoc              encoder.Doc
+	FilesystemRepairConfigDoc      encoder.Doc
+	KernelConfigDoc                encoder.Doc
+	KernelModuleConfigDoc          encoder.Doc
 	VolumeMountConfigDoc           encoder.Doc
 	ClusterInlineManifestDoc       encoder.Doc
 )
@@ -120,7 +130,7 @@ func init() {
 			FieldName: "machine",
 		},
 	}
-	MachineConfigDoc.Fields = make([]encoder.Doc, 15)
+	MachineConfigDoc.Fields = make([]encoder.Doc, 21)
 	MachineConfigDoc.Fields[0].Name = "type"
 	MachineConfigDoc.Fields[0].Type = "string"
 	MachineConfigDoc.Fields[0].Note = ""
@@ -145,101 +155,141 @@ func init() {
 	MachineConfigDoc.Fields[2].Comments[encoder.LineComment] = "The root certificate authority of the PKI."
 
 	MachineConfigDoc.Fields[2].AddExample("machine CA example", pemEncodedCertificateExample)
-	MachineConfigDoc.Fields[3].Name = "certSANs"
-	MachineConfigDoc.Fields[3].Type = "[]string"
+	MachineConfigDoc.Fields[3].Name = "acceptedCAs"
+	MachineConfigDoc.Fields[3].Type = "[]PEMEncodedCertificateAndKey"
 	MachineConfigDoc.Fields[3].Note = ""
-	MachineConfigDoc.Fields[3].Description = "Extra certificate subject alternative names for the machine's certificate.\nBy default, all non-loopback interface IPs are automatically added to the certificate's SANs."
-	MachineConfigDoc.Fields[3].Comments[encoder.LineComment] = "Extra certificate subject alternative names for the machine's certificate."
-
-	MachineConfigDoc.Fields[3].AddExample("Uncomment this to enable SANs.", []string{"10.0.0.10", "172.16.0.10", "192.168.0.10"})
-	MachineConfigDoc.Fields[4].Name = "kubelet"
-	MachineConfigDoc.Fields[4].Type = "KubeletConfig"
+	MachineConfigDoc.Fields[3].Description = "The additional certificate authorities trusted by the machine API, only `crt` should be specified.\nClient certificates signed by any of the accepted CAs are accepted in addition to the ones signed by the machine CA.\nThis is used to rotate the machine CA without downtime."
+	MachineConfigDoc.Fields[3].Comments[encoder.LineComment] = "The additional certificate authorities trusted by the machine API, only `crt` should be specified."
+	MachineConfigDoc.Fields[4].Name = "certSANs"
+	MachineConfigDoc.Fields[4].Type = "[]string"
 	MachineConfigDoc.Fields[4].Note = ""
-	MachineConfigDoc.Fields[4].Description = "Used to provide additional options to the kubelet."
-	MachineConfigDoc.Fields[4].Comments[encoder.LineComment] = "Used to provide additional options to the kubelet."
+	MachineConfigDoc.Fields[4].Description = "Extra certificate subject alternative names for the machine's certificate.\nBy default, all non-loopback interface IPs are automatically added to the certificate's SANs."
+	MachineConfigDoc.Fields[4].Comments[encoder.LineComment] = "Extra certificate subject alternative names for the machine's certificate."
 
-	MachineConfigDoc.Fields[4].AddExample("Kubelet definition example.", machineKubeletExample)
-	MachineConfigDoc.Fields[5].Name = "network"
-	MachineConfigDoc.Fields[5].Type = "NetworkConfig"
+	MachineConfigDoc.Fields[4].AddExample("Uncomment this to enable SANs.", []string{"10.0.0.10", "172.16.0.10", "192.168.0.10"})
+	MachineConfigDoc.Fields[5].Name = "kubelet"
+	MachineConfigDoc.Fields[5].Type = "KubeletConfig"
 	MachineConfigDoc.Fields[5].Note = ""
-	MachineConfigDoc.Fields[5].Description = "Provides machine specific network configuration options."
-	MachineConfigDoc.Fields[5].Comments[encoder.LineComment] = "Provides machine specific network configuration options."
-
-	MachineConfigDoc.Fields[5].AddExample("Network definition example.", machineNetworkConfigExample)
-	MachineConfigDoc.Fields[6].Name = "disks"
-	MachineConfigDoc.Fields[6].Type = "[]MachineDisk"
-	MachineConfigDoc.Fields[6].Note = "Note: `size` is in units of bytes.\n"
-	MachineConfigDoc.Fields[6].Description = "Used to partition, format and mount additional disks.\nSince the rootfs is read only with the exception of `/var`, mounts are only valid if they are under `/var`.\nNote that the partitioning and formating is done only once, if and only if no existing partitions are found.\nIf `size:` is omitted, the partition is sized to occupy the full disk."
-	MachineConfigDoc.Fields[6].Comments[encoder.LineComment] = "Used to partition, format and mount additional disks."
-
-	MachineConfigDoc.Fields[6].AddExample("MachineDisks list example.", machineDisksExample)
-	MachineConfigDoc.Fields[7].Name = "install"
-	MachineConfigDoc.Fields[7].Type = "InstallConfig"
-	MachineConfigDoc.Fields[7].Note = ""
-	MachineConfigDoc.Fields[7].Description = "Used to provide instructions for installations."
-	MachineConfigDoc.Fields[7].Comments[encoder.LineComment] = "Used to provide instructions for installations."
-
-	MachineConfigDoc.Fields[7].AddExample("MachineInstall config usage example.", machineInstallExample)
-	MachineConfigDoc.Fields[8].Name = "files"
-	MachineConfigDoc.Fields[8].Type = "[]MachineFile"
-	MachineConfigDoc.Fields[8].Note = "Note: The specified `path` is relative to `/var`.\n"
-	MachineConfigDoc.Fields[8].Description = "Allows the addition of user specified files.\nThe value of `op` can be `create`, `overwrite`, or `append`.\nIn the case of `create`, `path` must not exist.\nIn the case of `overwrite`, and `append`, `path` must be a valid file.\nIf an `op` value of `append` is used, the existing file will be appended.\nNote that the file contents are not required to be base64 encoded."
-	MachineConfigDoc.Fields[8].Comments[encoder.LineComment] = "Allows the addition of user specified files."
-
-	MachineConfigDoc.Fields[8].AddExample("MachineFiles usage example.", machineFilesExample)
-	MachineConfigDoc.Fields[9].Name = "env"
-	MachineConfigDoc.Fields[9].Type = "Env"
+	MachineConfigDoc.Fields[5].Description = "Used to provide additional options to the kubelet."
+	MachineConfigDoc.Fields[5].Comments[encoder.LineComment] = "Used to provide additional options to the kubelet."
+
+	MachineConfigDoc.Fields[5].AddExample("Kubelet definition example.", machineKubeletExample)
+	MachineConfigDoc.Fields[6].Name = "network"
+	MachineConfigDoc.Fields[6].Type = "NetworkConfig"
+	MachineConfigDoc.Fields[6].Note = ""
+	MachineConfigDoc.Fields[6].Description = "Provides machine specific network configuration options."
+	MachineConfigDoc.Fields[6].Comments[encoder.LineComment] = "Provides machine specific network configuration options."
+
+	MachineConfigDoc.Fields[6].AddExample("Network definition example.", machineNetworkConfigExample)
+	MachineConfigDoc.Fields[7].Name = "disks"
+	MachineConfigDoc.Fields[7].Type = "[]MachineDisk"
+	MachineConfigDoc.Fields[7].Note = "Note: `size` is in units of bytes.\n"
+	MachineConfigDoc.Fields[7].Description = "Used to partition, format and mount additional disks.\nSince the rootfs is read only with the exception of `/var`, mounts are only valid if they are under `/var`.\nNote that the partitioning and formating is done only once, if and only if no existing partitions are found.\nIf `size:` is omitted, the partition is sized to occupy the full disk."
+	MachineConfigDoc.Fields[7].Comments[encoder.LineComment] = "Used to partition, format and mount additional disks."
+
+	MachineConfigDoc.Fields[7].AddExample("MachineDisks list example.", machineDisksExample)
+	MachineConfigDoc.Fields[8].Name = "volumeGroups"
+	MachineConfigDoc.Fields[8].Type = "[]VolumeGroupConfig"
+	MachineConfigDoc.Fields[8].Note = ""
+	MachineConfigDoc.Fields[8].Description = "Used to create LVM volume groups and logical volumes on the additional disks.\nVolume groups and logical volumes are created only if they don't exist yet, existing ones are never modified.\nLogical volumes can be mirrored (`raid1`) or striped and mirrored (`raid10`) across the disks of the volume group,\nso that a single disk failure doesn't cause data loss.\nDisks used for the volume groups should not be listed in `.machine.disks`."
+	MachineConfigDoc.Fields[8].Comments[encoder.LineComment] = "Used to create LVM volume groups and logical volumes on the additional disks."
+
+	MachineConfigDoc.Fields[8].AddExample("", machineVolumeGroupsExample)
+	MachineConfigDoc.Fields[9].Name = "install"
+	MachineConfigDoc.Fields[9].Type = "InstallConfig"
 	MachineConfigDoc.Fields[9].Note = ""
-	MachineConfigDoc.Fields[9].Description = "The `env` field allows for the addition of environment variables.\nAll environment variables are set on PID 1 in addition to every service."
-	MachineConfigDoc.Fields[9].Comments[encoder.LineComment] = "The `env` field allows for the addition of environment variables."
+	MachineConfigDoc.Fields[9].Description = "Used to provide instructions for installations."
+	MachineConfigDoc.Fields[9].Comments[encoder.LineComment] = "Used to provide instructions for installations."
+
+	MachineConfigDoc.Fields[9].AddExample("MachineInstall config usage example.", machineInstallExample)
+	MachineConfigDoc.Fields[10].Name = "files"
+	MachineConfigDoc.Fields[10].Type = "[]MachineFile"
+	MachineConfigDoc.Fields[10].Note = "Note: The specified `path` is relative to `/var`.\n"
+	MachineConfigDoc.Fields[10].Description = "Allows the addition of user specified files.\nThe value of `op` can be `create`, `overwrite`, or `append`.\nIn the case of `create`, `path` must not exist.\nIn the case of `overwrite`, and `append`, `path` must be a valid file.\nIf an `op` value of `append` is used, the existing file will be appended.\nNote that the file contents are not required to be base64 encoded."
+	MachineConfigDoc.Fields[10].Comments[encoder.LineComment] = "Allows the addition of user specified files."
+
+	MachineConfigDoc.Fields[10].AddExample("MachineFiles usage example.", machineFilesExample)
+	MachineConfigDoc.Fields[11].Name = "env"
+	MachineConfigDoc.Fields[11].Type = "Env"
+	MachineConfigDoc.Fields[11].Note = ""
+	MachineConfigDoc.Fields[11].Description = "The `env` field allows for the addition of environment variables.\nAll environment variables are set on PID 1 in addition to every service."
+	MachineConfigDoc.Fields[11].Comments[encoder.LineComment] = "The `env` field allows for the addition of environment variables."
 
-	MachineConfigDoc.Fields[9].AddExample("Environment variables definition examples.", machineEnvExamples[0])
+	MachineConfigDoc.Fields[11].AddExample("Environment variables definition examples.", machineEnvExamples[0])
 
-	MachineConfigDoc.Fields[9].AddExample("", machineEnvExamples[1])
+	MachineConfigDoc.Fields[11].AddExample("", machineEnvExamples[1])
 
-	MachineConfigDoc.Fields[9].AddExample("", machineEnvExamples[2])
-	MachineConfigDoc.Fields[9].Values = []string{
+	MachineConfigDoc.Fields[11].AddExample("", machineEnvExamples[2])
+	MachineConfigDoc.Fields[11].Values = []string{
 		"`GRPC_GO_LOG_VERBOSITY_LEVEL`",
 		"`GRPC_GO_LOG_SEVERITY_LEVEL`",
 		"`http_proxy`",
 		"`https_proxy`",
 		"`no_proxy`",
 	}
-	MachineConfigDoc.Fields[10].Name = "time"
-	MachineConfigDoc.Fields[10].Type = "TimeConfig"
-	MachineConfigDoc.Fields[10].Note = ""
-	MachineConfigDoc.Fields[10].Description = "Used to configure the machine's time settings."
-	MachineConfigDoc.Fields[10].Comments[encoder.LineComment] = "Used to configure the machine's time settings."
-
-	MachineConfigDoc.Fields[10].AddExample("Example configuration for cloudflare ntp server.", machineTimeExample)
-	MachineConfigDoc.Fields[11].Name = "sysctls"
-	MachineConfigDoc.Fields[11].Type = "map[string]string"
-	MachineConfigDoc.Fields[11].Note = ""
-	MachineConfigDoc.Fields[11].Description = "Used to configure the machine's sysctls."
-	MachineConfigDoc.Fields[11].Comments[encoder.LineComment] = "Used to configure the machine's sysctls."
-
-	MachineConfigDoc.Fields[11].AddExample("MachineSysctls usage example.", machineSysctlsExample)
-	MachineConfigDoc.Fields[12].Name = "registries"
-	MachineConfigDoc.Fields[12].Type = "RegistriesConfig"
+	MachineConfigDoc.Fields[12].Name = "time"
+	MachineConfigDoc.Fields[12].Type = "TimeConfig"
 	MachineConfigDoc.Fields[12].Note = ""
-	MachineConfigDoc.Fields[12].Description = "Used to configure the machine's container image registry mirrors.\n\nAutomatically generates matching CRI configuration for registry mirrors.\n\nThe `mirrors` section allows to redirect requests for images to non-default registry,\nwhich might be local registry or caching mirror.\n\nThe `config` section provides a way to authenticate to the registry with TLS client\nidentity, provide registry CA, or authentication information.\nAuthentication information has same meaning with the corresponding field in `.docker/config.json`.\n\nSee also matching configuration for [CRI containerd plugin](https://github.com/containerd/cri/blob/master/docs/registry.md)."
-	MachineConfigDoc.Fields[12].Comments[encoder.LineComment] = "Used to configure the machine's container image registry mirrors."
+	MachineConfigDoc.Fields[12].Description = "Used to configure the machine's time settings."
+	MachineConfigDoc.Fields[12].Comments[encoder.LineComment] = "Used to configure the machine's time settings."
 
-	MachineConfigDoc.Fields[12].AddExample("", machineConfigRegistriesExample)
-	MachineConfigDoc.Fields[13].Name = "systemDiskEncryption"
-	MachineConfigDoc.Fields[13].Type = "SystemDiskEncryptionConfig"
+	MachineConfigDoc.Fields[12].AddExample("Example configuration for cloudflare ntp server.", machineTimeExample)
+	MachineConfigDoc.Fields[13].Name = "sysctls"
+	MachineConfigDoc.Fields[13].Type = "map[string]string"
 	MachineConfigDoc.Fields[13].Note = ""
-	MachineConfigDoc.Fields[13].Description = "Machine system disk encryption configuration.\nDefines each system partition encryption parameters."
-	MachineConfigDoc.Fields[13].Comments[encoder.LineComment] = "Machine system disk encryption configuration."
+	MachineConfigDoc.Fields[13].Description = "Used to configure the machine's sysctls."
+	MachineConfigDoc.Fields[13].Comments[encoder.LineComment] = "Used to configure the machine's sysctls."
 
-	MachineConfigDoc.Fields[13].AddExample("", machineSystemDiskEncryptionExample)
-	MachineConfigDoc.Fields[14].Name = "features"
-	MachineConfigDoc.Fields[14].Type = "FeaturesConfig"
+	MachineConfigDoc.Fields[13].AddExample("MachineSysctls usage example.", machineSysctlsExample)
+	MachineConfigDoc.Fields[14].Name = "registries"
+	MachineConfigDoc.Fields[14].Type = "RegistriesConfig"
 	MachineConfigDoc.Fields[14].Note = ""
-	MachineConfigDoc.Fields[14].Description = "Features describe individual Talos features that can be switched on or off."
-	MachineConfigDoc.Fields[14].Comments[encoder.LineComment] = "Features describe individual Talos features that can be switched on or off."
-
-	MachineConfigDoc.Fields[14].AddExample("", machineFeaturesExample)
+	MachineConfigDoc.Fields[14].Description = "Used to configure the machine's container image registry mirrors.\n\nAutomatically generates matching CRI configuration for registry mirrors.\n\nThe `mirrors` section allows to redirect requests for images to non-default registry,\nwhich might be local registry or caching mirror.\n\nThe `config` section provides a way to authenticate to the registry with TLS client\nidentity, provide registry CA, or authentication information.\nAuthentication information has same meaning with the corresponding field in `.docker/config.json`.\n\nSee also matching configuration for [CRI containerd plugin](https://github.com/containerd/cri/blob/master/docs/registry.md)."
+	MachineConfigDoc.Fields[14].Comments[encoder.LineComment] = "Used to configure the machine's container image registry mirrors."
+
+	MachineConfigDoc.Fields[14].AddExample("", machineConfigRegistriesExample)
+	MachineConfigDoc.Fields[15].Name = "systemDiskEncryption"
+	MachineConfigDoc.Fields[15].Type = "SystemDiskEncryptionConfig"
+	MachineConfigDoc.Fields[15].Note = ""
+	MachineConfigDoc.Fields[15].Description = "Machine system disk encryption configuration.\nDefines each system partition encryption parameters."
+	MachineConfigDoc.Fields[15].Comments[encoder.LineComment] = "Machine system disk encryption configuration."
+
+	MachineConfigDoc.Fields[15].AddExample("", machineSystemDiskEncryptionExample)
+	MachineConfigDoc.Fields[16].Name = "features"
+	MachineConfigDoc.Fields[16].Type = "FeaturesConfig"
+	MachineConfigDoc.Fields[16].Note = ""
+	MachineConfigDoc.Fields[16].Description = "Features describe individual Talos features that can be switched on or off."
+	MachineConfigDoc.Fields[16].Comments[encoder.LineComment] = "Features describe individual Talos features that can be switched on or off."
+
+	MachineConfigDoc.Fields[16].AddExample("", machineFeaturesExample)
+	MachineConfigDoc.Fields[17].Name = "services"
+	MachineConfigDoc.Fields[17].Type = "[]ServiceConfig"
+	MachineConfigDoc.Fields[17].Note = ""
+	MachineConfigDoc.Fields[17].Description = "Overrides for the health checks and restart policies of the built-in services."
+	MachineConfigDoc.Fields[17].Comments[encoder.LineComment] = "Overrides for the health checks and restart policies of the built-in services."
+
+	MachineConfigDoc.Fields[17].AddExample("", machineServicesExample)
+	MachineConfigDoc.Fields[18].Name = "watchdog"
+	MachineConfigDoc.Fields[18].Type = "WatchdogConfig"
+	MachineConfigDoc.Fields[18].Note = ""
+	MachineConfigDoc.Fields[18].Description = "Watchdog timer configuration.\n\nWhen set, machined pets the watchdog timer while the critical services are healthy.\nIf the node hangs, or critical services stay unhealthy for longer than the timeout, the node is reset by the watchdog.\nIf the hardware watchdog is not available, `softdog` kernel module is used."
+	MachineConfigDoc.Fields[18].Comments[encoder.LineComment] = "Watchdog timer configuration."
+
+	MachineConfigDoc.Fields[18].AddExample("", machineWatchdogExample)
+	MachineConfigDoc.Fields[19].Name = "kernel"
+	MachineConfigDoc.Fields[19].Type = "KernelConfig"
+	MachineConfigDoc.Fields[19].Note = ""
+	MachineConfigDoc.Fields[19].Description = "Configures the kernel."
+	MachineConfigDoc.Fields[19].Comments[encoder.LineComment] = "Configures the kernel."
+
+	MachineConfigDoc.Fields[19].AddExample("", machineKernelExample)
+	MachineConfigDoc.Fields[20].Name = "filesystemRepair"
+	MachineConfigDoc.Fields[20].Type = "FilesystemRepairConfig"
+	MachineConfigDoc.Fields[20].Note = ""
+	MachineConfigDoc.Fields[20].Description = "Filesystem check and repair settings for the partitions which fail to mount.\n\nThe policy applies to the system partitions, user disk partitions and logical volumes formatted with `xfs` or `ext4`.\n`STATE` partition is mounted on boot before the machine config is loaded, so the policy doesn't apply to it on boot.\nResults are published as the machine events (`talosctl events`)."
+	MachineConfigDoc.Fields[20].Comments[encoder.LineComment] = "Filesystem check and repair settings for the partitions which fail to mount."
+
+	MachineConfigDoc.Fields[20].AddExample("", machineFilesystemRepairExample)
 
 	ClusterConfigDoc.Type = "ClusterConfig"
 	ClusterConfigDoc.Comments[encoder.LineComment] = "ClusterConfig represents the cluster-wide config values."
@@ -426,7 +476,7 @@ func init() {
 			FieldName: "kubelet",
 		},
 	}
-	KubeletConfigDoc.Fields = make([]encoder.Doc, 5)
+	KubeletConfigDoc.Fields = make([]encoder.Doc, 6)
 	KubeletConfigDoc.Fields[0].Name = "image"
 	KubeletConfigDoc.Fields[0].Type = "string"
 	KubeletConfigDoc.Fields[0].Note = ""
@@ -468,6 +518,17 @@ func init() {
 		"false",
 		"no",
 	}
+	KubeletConfigDoc.Fields[5].Name = "serverTLSBootstrap"
+	KubeletConfigDoc.Fields[5].Type = "bool"
+	KubeletConfigDoc.Fields[5].Note = ""
+	KubeletConfigDoc.Fields[5].Description = "The `serverTLSBootstrap` field enables kubelet to request its serving certificate via CSR.\nCertificate signing requests are verified against the node addresses and approved by Talos control plane nodes,\nkubelet rotates the serving certificate before it expires."
+	KubeletConfigDoc.Fields[5].Comments[encoder.LineComment] = "The `serverTLSBootstrap` field enables kubelet to request its serving certificate via CSR."
+	KubeletConfigDoc.Fields[5].Values = []string{
+		"true",
+		"yes",
+		"false",
+		"no",
+	}
 
 	NetworkConfigDoc.Type = "NetworkConfig"
 	NetworkConfigDoc.Comments[encoder.LineComment] = "NetworkConfig represents the machine's networking config values."
@@ -519,7 +580,7 @@ func init() {
 			FieldName: "install",
 		},
 	}
-	InstallConfigDoc.Fields = make([]encoder.Doc, 7)
+	InstallConfigDoc.Fields = make([]encoder.Doc, 9)
 	InstallConfigDoc.Fields[0].Name = "disk"
 	InstallConfigDoc.Fields[0].Type = "string"
 	InstallConfigDoc.Fields[0].Note = ""
@@ -577,6 +638,45 @@ func init() {
 	InstallConfigDoc.Fields[6].Note = ""
 	InstallConfigDoc.Fields[6].Description = "Indicates if MBR partition should be marked as bootable (active).\nShould be enabled only for the systems with legacy BIOS that doesn't support GPT partitioning scheme."
 	InstallConfigDoc.Fields[6].Comments[encoder.LineComment] = "Indicates if MBR partition should be marked as bootable (active)."
+	InstallConfigDoc.Fields[7].Name = "ephemeralSize"
+	InstallConfigDoc.Fields[7].Type = "DiskSize"
+	InstallConfigDoc.Fields[7].Note = ""
+	InstallConfigDoc.Fields[7].Description = "The size of the EPHEMERAL partition: either bytes or human readable representation.\nIf not set, the EPHEMERAL partition occupies the rest of the installation disk.\nThe size is applied when the EPHEMERAL partition is created, i.e. on install or on upgrade without `--preserve`."
+	InstallConfigDoc.Fields[7].Comments[encoder.LineComment] = "The size of the EPHEMERAL partition: either bytes or human readable representation."
+
+	InstallConfigDoc.Fields[7].AddExample("", DiskSize(100*1000*1000*1000))
+	InstallConfigDoc.Fields[8].Name = "extraPartitions"
+	InstallConfigDoc.Fields[8].Type = "[]InstallExtraPartition"
+	InstallConfigDoc.Fields[8].Note = ""
+	InstallConfigDoc.Fields[8].Description = "Extra partitions to create on the installation disk after the EPHEMERAL partition.\nPartitions are created without a filesystem, and they are preserved across upgrades and resets\nof the system disk with a partition spec.\nRequires `ephemeralSize` to be set."
+	InstallConfigDoc.Fields[8].Comments[encoder.LineComment] = "Extra partitions to create on the installation disk after the EPHEMERAL partition."
+
+	InstallConfigDoc.Fields[8].AddExample("", machineInstallExtraPartitionsExample)
+
+	InstallExtraPartitionDoc.Type = "InstallExtraPartition"
+	InstallExtraPartitionDoc.Comments[encoder.LineComment] = "InstallExtraPartition represents an extra partition on the installation disk."
+	InstallExtraPartitionDoc.Description = "InstallExtraPartition represents an extra partition on the installation disk."
+
+	InstallExtraPartitionDoc.AddExample("", machineInstallExtraPartitionsExample)
+	InstallExtraPartitionDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "InstallConfig",
+			FieldName: "extraPartitions",
+		},
+	}
+	InstallExtraPartitionDoc.Fields = make([]encoder.Doc, 2)
+	InstallExtraPartitionDoc.Fields[0].Name = "label"
+	InstallExtraPartitionDoc.Fields[0].Type = "string"
+	InstallExtraPartitionDoc.Fields[0].Note = ""
+	InstallExtraPartitionDoc.Fields[0].Description = "Partition label (GPT partition name), should be unique and shouldn't match any of the Talos system partition labels."
+	InstallExtraPartitionDoc.Fields[0].Comments[encoder.LineComment] = "Partition label (GPT partition name), should be unique and shouldn't match any of the Talos system partition labels."
+
+	InstallExtraPartitionDoc.Fields[0].AddExample("", "DATA")
+	InstallExtraPartitionDoc.Fields[1].Name = "size"
+	InstallExtraPartitionDoc.Fields[1].Type = "DiskSize"
+	InstallExtraPartitionDoc.Fields[1].Note = ""
+	InstallExtraPartitionDoc.Fields[1].Description = "The size of partition: either bytes or human readable representation. If `size:` is omitted, the partition is sized to occupy the rest of the disk."
+	InstallExtraPartitionDoc.Fields[1].Comments[encoder.LineComment] = "The size of partition: either bytes or human readable representation. If `size:` is omitted, the partition is sized to occupy the rest of the disk."
 
 	InstallDiskSizeMatcherDoc.Type = "InstallDiskSizeMatcher"
 	InstallDiskSizeMatcherDoc.Comments[encoder.LineComment] = "InstallDiskSizeMatcher disk size condition parser."
@@ -597,7 +697,7 @@ func init() {
 
 	InstallDiskSelectorDoc.Type = "InstallDiskSelector"
 	InstallDiskSelectorDoc.Comments[encoder.LineComment] = "InstallDiskSelector represents a disk query parameters for the install disk lookup."
-	InstallDiskSelectorDoc.Description = "InstallDiskSelector represents a disk query parameters for the install disk lookup."
+	InstallDiskSelectorDoc.Description = "InstallDiskSelector represents a disk query parameters for the install disk lookup.\n\nAll the conditions should match the disk, string values may contain `*` wildcards.\nIf several disks match, the first one in the order of `/sys/block` is used.\n"
 
 	InstallDiskSelectorDoc.AddExample("", machineInstallDiskSelectorExample)
 	InstallDiskSelectorDoc.AppearsIn = []encoder.Appearance{
@@ -606,7 +706,7 @@ func init() {
 			FieldName: "diskSelector",
 		},
 	}
-	InstallDiskSelectorDoc.Fields = make([]encoder.Doc, 8)
+	InstallDiskSelectorDoc.Fields = make([]encoder.Doc, 9)
 	InstallDiskSelectorDoc.Fields[0].Name = "size"
 	InstallDiskSelectorDoc.Fields[0].Type = "InstallDiskSizeMatcher"
 	InstallDiskSelectorDoc.Fields[0].Note = ""
@@ -648,12 +748,21 @@ func init() {
 	InstallDiskSelectorDoc.Fields[6].Note = ""
 	InstallDiskSelectorDoc.Fields[6].Description = "Disk WWID `/sys/block/<dev>/wwid`."
 	InstallDiskSelectorDoc.Fields[6].Comments[encoder.LineComment] = "Disk WWID `/sys/block/<dev>/wwid`."
-	InstallDiskSelectorDoc.Fields[7].Name = "type"
-	InstallDiskSelectorDoc.Fields[7].Type = "InstallDiskType"
+	InstallDiskSelectorDoc.Fields[7].Name = "busPath"
+	InstallDiskSelectorDoc.Fields[7].Type = "string"
 	InstallDiskSelectorDoc.Fields[7].Note = ""
-	InstallDiskSelectorDoc.Fields[7].Description = "Disk Type."
-	InstallDiskSelectorDoc.Fields[7].Comments[encoder.LineComment] = "Disk Type."
-	InstallDiskSelectorDoc.Fields[7].Values = []string{
+	InstallDiskSelectorDoc.Fields[7].Description = "Disk bus path: sysfs path of the disk device relative to `/sys/devices`.\n\nUnlike the device name, the bus path is stable across reboots as long as the disk stays in the same slot.\nRun `talosctl disks` to see the bus paths of the disks."
+	InstallDiskSelectorDoc.Fields[7].Comments[encoder.LineComment] = "Disk bus path: sysfs path of the disk device relative to `/sys/devices`."
+
+	InstallDiskSelectorDoc.Fields[7].AddExample("", "/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0")
+
+	InstallDiskSelectorDoc.Fields[7].AddExample("", "/pci0000:00/*")
+	InstallDiskSelectorDoc.Fields[8].Name = "type"
+	InstallDiskSelectorDoc.Fields[8].Type = "InstallDiskType"
+	InstallDiskSelectorDoc.Fields[8].Note = ""
+	InstallDiskSelectorDoc.Fields[8].Description = "Disk Type."
+	InstallDiskSelectorDoc.Fields[8].Comments[encoder.LineComment] = "Disk Type."
+	InstallDiskSelectorDoc.Fields[8].Values = []string{
 		"ssd",
 		"hdd",
 		"nvme",
@@ -1102,7 +1211,7 @@ func init() {
 			FieldName: "partitions",
 		},
 	}
-	DiskPartitionDoc.Fields = make([]encoder.Doc, 2)
+	DiskPartitionDoc.Fields = make([]encoder.Doc, 5)
 	DiskPartitionDoc.Fields[0].Name = "size"
 	DiskPartitionDoc.Fields[0].Type = "DiskSize"
 	DiskPartitionDoc.Fields[0].Note = ""
@@ -1117,11 +1226,117 @@ func init() {
 	DiskPartitionDoc.Fields[1].Note = ""
 	DiskPartitionDoc.Fields[1].Description = "Where to mount the partition."
 	DiskPartitionDoc.Fields[1].Comments[encoder.LineComment] = "Where to mount the partition."
+	DiskPartitionDoc.Fields[2].Name = "filesystem"
+	DiskPartitionDoc.Fields[2].Type = "string"
+	DiskPartitionDoc.Fields[2].Note = ""
+	DiskPartitionDoc.Fields[2].Description = "Filesystem type to format the partition with.\nDefaults to `xfs`."
+	DiskPartitionDoc.Fields[2].Comments[encoder.LineComment] = "Filesystem type to format the partition with."
+	DiskPartitionDoc.Fields[2].Values = []string{
+		"xfs",
+		"ext4",
+	}
+	DiskPartitionDoc.Fields[3].Name = "mountOptions"
+	DiskPartitionDoc.Fields[3].Type = "[]string"
+	DiskPartitionDoc.Fields[3].Note = ""
+	DiskPartitionDoc.Fields[3].Description = "Options to mount the partition with.\nOptions are passed as is to the filesystem, except for the generic mount flags (e.g. `nodev`, `nosuid`, `ro`)."
+	DiskPartitionDoc.Fields[3].Comments[encoder.LineComment] = "Options to mount the partition with."
+
+	DiskPartitionDoc.Fields[3].AddExample("", []string{"nodev", "nosuid", "discard"})
+	DiskPartitionDoc.Fields[4].Name = "encryption"
+	DiskPartitionDoc.Fields[4].Type = "EncryptionConfig"
+	DiskPartitionDoc.Fields[4].Note = ""
+	DiskPartitionDoc.Fields[4].Description = "Partition encryption settings.\nThe partition is encrypted when it is set up for the first time, existing unencrypted partitions are not converted."
+	DiskPartitionDoc.Fields[4].Comments[encoder.LineComment] = "Partition encryption settings."
+
+	DiskPartitionDoc.Fields[4].AddExample("", machineDiskPartitionEncryptionExample)
+
+	VolumeGroupConfigDoc.Type = "VolumeGroupConfig"
+	VolumeGroupConfigDoc.Comments[encoder.LineComment] = "VolumeGroupConfig represents the options for an LVM volume group."
+	VolumeGroupConfigDoc.Description = "VolumeGroupConfig represents the options for an LVM volume group."
+
+	VolumeGroupConfigDoc.AddExample("", machineVolumeGroupsExample)
+	VolumeGroupConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "volumeGroups",
+		},
+	}
+	VolumeGroupConfigDoc.Fields = make([]encoder.Doc, 3)
+	VolumeGroupConfigDoc.Fields[0].Name = "name"
+	VolumeGroupConfigDoc.Fields[0].Type = "string"
+	VolumeGroupConfigDoc.Fields[0].Note = ""
+	VolumeGroupConfigDoc.Fields[0].Description = "Volume group name."
+	VolumeGroupConfigDoc.Fields[0].Comments[encoder.LineComment] = "Volume group name."
+	VolumeGroupConfigDoc.Fields[1].Name = "devices"
+	VolumeGroupConfigDoc.Fields[1].Type = "[]string"
+	VolumeGroupConfigDoc.Fields[1].Note = ""
+	VolumeGroupConfigDoc.Fields[1].Description = "Disks to be used as physical volumes of the volume group.\nDisks should be empty (without partition table or filesystem signatures) when the volume group is created."
+	VolumeGroupConfigDoc.Fields[1].Comments[encoder.LineComment] = "Disks to be used as physical volumes of the volume group."
+	VolumeGroupConfigDoc.Fields[2].Name = "logicalVolumes"
+	VolumeGroupConfigDoc.Fields[2].Type = "[]LogicalVolumeConfig"
+	VolumeGroupConfigDoc.Fields[2].Note = ""
+	VolumeGroupConfigDoc.Fields[2].Description = "Logical volumes to create in the volume group."
+	VolumeGroupConfigDoc.Fields[2].Comments[encoder.LineComment] = "Logical volumes to create in the volume group."
+
+	LogicalVolumeConfigDoc.Type = "LogicalVolumeConfig"
+	LogicalVolumeConfigDoc.Comments[encoder.LineComment] = "LogicalVolumeConfig represents the options for an LVM logical volume."
+	LogicalVolumeConfigDoc.Description = "LogicalVolumeConfig represents the options for an LVM logical volume."
+	LogicalVolumeConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "VolumeGroupConfig",
+			FieldName: "logicalVolumes",
+		},
+	}
+	LogicalVolumeConfigDoc.Fields = make([]encoder.Doc, 6)
+	LogicalVolumeConfigDoc.Fields[0].Name = "name"
+	LogicalVolumeConfigDoc.Fields[0].Type = "string"
+	LogicalVolumeConfigDoc.Fields[0].Note = ""
+	LogicalVolumeConfigDoc.Fields[0].Description = "Logical volume name."
+	LogicalVolumeConfigDoc.Fields[0].Comments[encoder.LineComment] = "Logical volume name."
+	LogicalVolumeConfigDoc.Fields[1].Name = "size"
+	LogicalVolumeConfigDoc.Fields[1].Type = "DiskSize"
+	LogicalVolumeConfigDoc.Fields[1].Note = ""
+	LogicalVolumeConfigDoc.Fields[1].Description = "The size of the logical volume: either bytes or human readable representation. If `size:` is omitted, the logical volume is sized to occupy the rest of the volume group."
+	LogicalVolumeConfigDoc.Fields[1].Comments[encoder.LineComment] = "The size of the logical volume: either bytes or human readable representation. If `size:` is omitted, the logical volume is sized to occupy the rest of the volume group."
+	LogicalVolumeConfigDoc.Fields[2].Name = "raid"
+	LogicalVolumeConfigDoc.Fields[2].Type = "string"
+	LogicalVolumeConfigDoc.Fields[2].Note = ""
+	LogicalVolumeConfigDoc.Fields[2].Description = "RAID level of the logical volume.\n`raid1` mirrors the data across all the disks of the volume group, `raid10` requires an even number of disks, at least four.\nIf not set, the logical volume is linear (no redundancy)."
+	LogicalVolumeConfigDoc.Fields[2].Comments[encoder.LineComment] = "RAID level of the logical volume."
+	LogicalVolumeConfigDoc.Fields[2].Values = []string{
+		"raid1",
+		"raid10",
+	}
+	LogicalVolumeConfigDoc.Fields[3].Name = "mountpoint"
+	LogicalVolumeConfigDoc.Fields[3].Type = "string"
+	LogicalVolumeConfigDoc.Fields[3].Note = ""
+	LogicalVolumeConfigDoc.Fields[3].Description = "Where to mount the logical volume.\nIf not set, the logical volume is created, but not formatted or mounted."
+	LogicalVolumeConfigDoc.Fields[3].Comments[encoder.LineComment] = "Where to mount the logical volume."
+	LogicalVolumeConfigDoc.Fields[4].Name = "filesystem"
+	LogicalVolumeConfigDoc.Fields[4].Type = "string"
+	LogicalVolumeConfigDoc.Fields[4].Note = ""
+	LogicalVolumeConfigDoc.Fields[4].Description = "Filesystem type to format the logical volume with.\nDefaults to `xfs`."
+	LogicalVolumeConfigDoc.Fields[4].Comments[encoder.LineComment] = "Filesystem type to format the logical volume with."
+	LogicalVolumeConfigDoc.Fields[4].Values = []string{
+		"xfs",
+		"ext4",
+	}
+	LogicalVolumeConfigDoc.Fields[5].Name = "mountOptions"
+	LogicalVolumeConfigDoc.Fields[5].Type = "[]string"
+	LogicalVolumeConfigDoc.Fields[5].Note = ""
+	LogicalVolumeConfigDoc.Fields[5].Description = "Options to mount the logical volume with."
+	LogicalVolumeConfigDoc.Fields[5].Comments[encoder.LineComment] = "Options to mount the logical volume with."
 
 	EncryptionConfigDoc.Type = "EncryptionConfig"
 	EncryptionConfigDoc.Comments[encoder.LineComment] = "EncryptionConfig represents partition encryption settings."
 	EncryptionConfigDoc.Description = "EncryptionConfig represents partition encryption settings."
+
+	EncryptionConfigDoc.AddExample("", machineDiskPartitionEncryptionExample)
 	EncryptionConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "DiskPartition",
+			FieldName: "encryption",
+		},
 		{
 			TypeName:  "SystemDiskEncryptionConfig",
 			FieldName: "state",
@@ -1824,12 +2039,200 @@ func init() {
 			FieldName: "features",
 		},
 	}
-	FeaturesConfigDoc.Fields = make([]encoder.Doc, 1)
+	FeaturesConfigDoc.Fields = make([]encoder.Doc, 3)
 	FeaturesConfigDoc.Fields[0].Name = "rbac"
 	FeaturesConfigDoc.Fields[0].Type = "bool"
 	FeaturesConfigDoc.Fields[0].Note = ""
 	FeaturesConfigDoc.Fields[0].Description = "Enable role-based access control (RBAC)."
 	FeaturesConfigDoc.Fields[0].Comments[encoder.LineComment] = "Enable role-based access control (RBAC)."
+	FeaturesConfigDoc.Fields[1].Name = "metrics"
+	FeaturesConfigDoc.Fields[1].Type = "bool"
+	FeaturesConfigDoc.Fields[1].Note = ""
+	FeaturesConfigDoc.Fields[1].Description = "Enable Prometheus metrics endpoint.\n\nMetrics are served over HTTPS on port 50002 at `/metrics`, client certificate is required\nas for the Talos API. If RBAC is enabled, client should have `os:admin` or `os:reader` role."
+	FeaturesConfigDoc.Fields[1].Comments[encoder.LineComment] = "Enable Prometheus metrics endpoint."
+	FeaturesConfigDoc.Fields[2].Name = "tracingEndpoint"
+	FeaturesConfigDoc.Fields[2].Type = "string"
+	FeaturesConfigDoc.Fields[2].Note = ""
+	FeaturesConfigDoc.Fields[2].Description = "OpenTelemetry collector endpoint to export controller reconcile runs as trace spans.\n\nSpans are sent with OTLP over HTTP (JSON encoding) to `<endpoint>/v1/traces`, export is disabled if not set."
+	FeaturesConfigDoc.Fields[2].Comments[encoder.LineComment] = "OpenTelemetry collector endpoint to export controller reconcile runs as trace spans."
+
+	FeaturesConfigDoc.Fields[2].AddExample("", "http://otel-collector:4318")
+
+	ServiceConfigDoc.Type = "ServiceConfig"
+	ServiceConfigDoc.Comments[encoder.LineComment] = "ServiceConfig describes overrides for the built-in service."
+	ServiceConfigDoc.Description = "ServiceConfig describes overrides for the built-in service."
+
+	ServiceConfigDoc.AddExample("", machineServicesExample)
+	ServiceConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "services",
+		},
+	}
+	ServiceConfigDoc.Fields = make([]encoder.Doc, 3)
+	ServiceConfigDoc.Fields[0].Name = "name"
+	ServiceConfigDoc.Fields[0].Type = "string"
+	ServiceConfigDoc.Fields[0].Note = ""
+	ServiceConfigDoc.Fields[0].Description = "Name of the service (e.g. `etcd` or `kubelet`)."
+	ServiceConfigDoc.Fields[0].Comments[encoder.LineComment] = "Name of the service (e.g. `etcd` or `kubelet`)."
+	ServiceConfigDoc.Fields[1].Name = "healthCheck"
+	ServiceConfigDoc.Fields[1].Type = "ServiceHealthCheckConfig"
+	ServiceConfigDoc.Fields[1].Note = ""
+	ServiceConfigDoc.Fields[1].Description = "Health check settings."
+	ServiceConfigDoc.Fields[1].Comments[encoder.LineComment] = "Health check settings."
+	ServiceConfigDoc.Fields[2].Name = "restart"
+	ServiceConfigDoc.Fields[2].Type = "ServiceRestartConfig"
+	ServiceConfigDoc.Fields[2].Note = ""
+	ServiceConfigDoc.Fields[2].Description = "Restart policy settings."
+	ServiceConfigDoc.Fields[2].Comments[encoder.LineComment] = "Restart policy settings."
+
+	ServiceHealthCheckConfigDoc.Type = "ServiceHealthCheckConfig"
+	ServiceHealthCheckConfigDoc.Comments[encoder.LineComment] = "ServiceHealthCheckConfig describes overrides for the service health check."
+	ServiceHealthCheckConfigDoc.Description = "ServiceHealthCheckConfig describes overrides for the service health check."
+	ServiceHealthCheckConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "ServiceConfig",
+			FieldName: "healthCheck",
+		},
+	}
+	ServiceHealthCheckConfigDoc.Fields = make([]encoder.Doc, 2)
+	ServiceHealthCheckConfigDoc.Fields[0].Name = "interval"
+	ServiceHealthCheckConfigDoc.Fields[0].Type = "Duration"
+	ServiceHealthCheckConfigDoc.Fields[0].Note = ""
+	ServiceHealthCheckConfigDoc.Fields[0].Description = "Interval between the health checks.\nField format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes)."
+	ServiceHealthCheckConfigDoc.Fields[0].Comments[encoder.LineComment] = "Interval between the health checks."
+	ServiceHealthCheckConfigDoc.Fields[1].Name = "timeout"
+	ServiceHealthCheckConfigDoc.Fields[1].Type = "Duration"
+	ServiceHealthCheckConfigDoc.Fields[1].Note = ""
+	ServiceHealthCheckConfigDoc.Fields[1].Description = "Health check timeout.\nField format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes)."
+	ServiceHealthCheckConfigDoc.Fields[1].Comments[encoder.LineComment] = "Health check timeout."
+
+	ServiceRestartConfigDoc.Type = "ServiceRestartConfig"
+	ServiceRestartConfigDoc.Comments[encoder.LineComment] = "ServiceRestartConfig describes overrides for the service restart policy."
+	ServiceRestartConfigDoc.Description = "ServiceRestartConfig describes overrides for the service restart policy."
+	ServiceRestartConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "ServiceConfig",
+			FieldName: "restart",
+		},
+	}
+	ServiceRestartConfigDoc.Fields = make([]encoder.Doc, 4)
+	ServiceRestartConfigDoc.Fields[0].Name = "backoff"
+	ServiceRestartConfigDoc.Fields[0].Type = "Duration"
+	ServiceRestartConfigDoc.Fields[0].Note = ""
+	ServiceRestartConfigDoc.Fields[0].Description = "Interval between the service restarts.\nField format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes)."
+	ServiceRestartConfigDoc.Fields[0].Comments[encoder.LineComment] = "Interval between the service restarts."
+	ServiceRestartConfigDoc.Fields[1].Name = "maxRestarts"
+	ServiceRestartConfigDoc.Fields[1].Type = "int"
+	ServiceRestartConfigDoc.Fields[1].Note = ""
+	ServiceRestartConfigDoc.Fields[1].Description = "Maximum number of the service restarts within the restart window, zero means no limit.\nWhen the service exceeds the restart budget, it is stopped and the escalation action is run."
+	ServiceRestartConfigDoc.Fields[1].Comments[encoder.LineComment] = "Maximum number of the service restarts within the restart window, zero means no limit."
+	ServiceRestartConfigDoc.Fields[2].Name = "window"
+	ServiceRestartConfigDoc.Fields[2].Type = "Duration"
+	ServiceRestartConfigDoc.Fields[2].Note = ""
+	ServiceRestartConfigDoc.Fields[2].Description = "Period of time the service restarts are counted over, defaults to one hour.\nRestarts which happened before the window don't count towards the restart budget.\nField format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes)."
+	ServiceRestartConfigDoc.Fields[2].Comments[encoder.LineComment] = "Period of time the service restarts are counted over, defaults to one hour."
+	ServiceRestartConfigDoc.Fields[3].Name = "escalation"
+	ServiceRestartConfigDoc.Fields[3].Type = "string"
+	ServiceRestartConfigDoc.Fields[3].Note = ""
+	ServiceRestartConfigDoc.Fields[3].Description = "Escalation action when the service exceeds the restart budget.\n\n`unhealthy` marks the service health check as failed, so that the node is reported as unhealthy,\n`cordon` cordons the Kubernetes node, `reboot` reboots the node."
+	ServiceRestartConfigDoc.Fields[3].Comments[encoder.LineComment] = "Escalation action when the service exceeds the restart budget."
+	ServiceRestartConfigDoc.Fields[3].Values = []string{
+		"none",
+		"unhealthy",
+		"cordon",
+		"reboot",
+	}
+
+	WatchdogConfigDoc.Type = "WatchdogConfig"
+	WatchdogConfigDoc.Comments[encoder.LineComment] = "WatchdogConfig describes the watchdog timer settings."
+	WatchdogConfigDoc.Description = "WatchdogConfig describes the watchdog timer settings."
+
+	WatchdogConfigDoc.AddExample("", machineWatchdogExample)
+	WatchdogConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "watchdog",
+		},
+	}
+	WatchdogConfigDoc.Fields = make([]encoder.Doc, 2)
+	WatchdogConfigDoc.Fields[0].Name = "device"
+	WatchdogConfigDoc.Fields[0].Type = "string"
+	WatchdogConfigDoc.Fields[0].Note = ""
+	WatchdogConfigDoc.Fields[0].Description = "Path to the watchdog device.\nDefaults to `/dev/watchdog`."
+	WatchdogConfigDoc.Fields[0].Comments[encoder.LineComment] = "Path to the watchdog device."
+	WatchdogConfigDoc.Fields[1].Name = "timeout"
+	WatchdogConfigDoc.Fields[1].Type = "Duration"
+	WatchdogConfigDoc.Fields[1].Note = ""
+	WatchdogConfigDoc.Fields[1].Description = "Watchdog timeout, the node is reset if the watchdog is not pet for this long.\nShould be at least 10 seconds, defaults to one minute.\nField format accepts any Go time.Duration format ('1h' for one hour, '10m' for ten minutes)."
+	WatchdogConfigDoc.Fields[1].Comments[encoder.LineComment] = "Watchdog timeout, the node is reset if the watchdog is not pet for this long."
+
+	FilesystemRepairConfigDoc.Type = "FilesystemRepairConfig"
+	FilesystemRepairConfigDoc.Comments[encoder.LineComment] = "FilesystemRepairConfig describes the filesystem repair settings."
+	FilesystemRepairConfigDoc.Description = "FilesystemRepairConfig describes the filesystem repair settings."
+
+	FilesystemRepairConfigDoc.AddExample("", machineFilesystemRepairExample)
+	FilesystemRepairConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "filesystemRepair",
+		},
+	}
+	FilesystemRepairConfigDoc.Fields = make([]encoder.Doc, 1)
+	FilesystemRepairConfigDoc.Fields[0].Name = "policy"
+	FilesystemRepairConfigDoc.Fields[0].Type = "string"
+	FilesystemRepairConfigDoc.Fields[0].Note = ""
+	FilesystemRepairConfigDoc.Fields[0].Description = "What to do when a partition fails to mount:\n`none` fails the mount, `check` runs the filesystem check without modifying the filesystem and reports the result,\n`repair` fixes the problems which can be fixed safely and retries the mount,\n`force` also fixes the problems which discard data (e.g. zeroes the dirty XFS log).\nDefaults to `none`."
+	FilesystemRepairConfigDoc.Fields[0].Comments[encoder.LineComment] = "What to do when a partition fails to mount:"
+	FilesystemRepairConfigDoc.Fields[0].Values = []string{
+		"none",
+		"check",
+		"repair",
+		"force",
+	}
+
+	KernelConfigDoc.Type = "KernelConfig"
+	KernelConfigDoc.Comments[encoder.LineComment] = "KernelConfig struct configures the kernel."
+	KernelConfigDoc.Description = "KernelConfig struct configures the kernel."
+
+	KernelConfigDoc.AddExample("", machineKernelExample)
+	KernelConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "MachineConfig",
+			FieldName: "kernel",
+		},
+	}
+	KernelConfigDoc.Fields = make([]encoder.Doc, 1)
+	KernelConfigDoc.Fields[0].Name = "modules"
+	KernelConfigDoc.Fields[0].Type = "[]KernelModuleConfig"
+	KernelConfigDoc.Fields[0].Note = ""
+	KernelConfigDoc.Fields[0].Description = "Kernel modules to load on boot.\n\nModules should be shipped with the Talos image, dependencies are loaded automatically."
+	KernelConfigDoc.Fields[0].Comments[encoder.LineComment] = "Kernel modules to load on boot."
+
+	KernelModuleConfigDoc.Type = "KernelModuleConfig"
+	KernelModuleConfigDoc.Comments[encoder.LineComment] = "KernelModuleConfig struct configures the kernel module to load."
+	KernelModuleConfigDoc.Description = "KernelModuleConfig struct configures the kernel module to load."
+	KernelModuleConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "KernelConfig",
+			FieldName: "modules",
+		},
+	}
+	KernelModuleConfigDoc.Fields = make([]encoder.Doc, 2)
+	KernelModuleConfigDoc.Fields[0].Name = "name"
+	KernelModuleConfigDoc.Fields[0].Type = "string"
+	KernelModuleConfigDoc.Fields[0].Note = ""
+	KernelModuleConfigDoc.Fields[0].Description = "Module name."
+	KernelModuleConfigDoc.Fields[0].Comments[encoder.LineComment] = "Module name."
+
+	KernelModuleConfigDoc.Fields[0].AddExample("", "nvme_tcp")
+	KernelModuleConfigDoc.Fields[1].Name = "parameters"
+	KernelModuleConfigDoc.Fields[1].Type = "[]string"
+	KernelModuleConfigDoc.Fields[1].Note = ""
+	KernelModuleConfigDoc.Fields[1].Description = "Module parameters, in the `key=value` format."
+	KernelModuleConfigDoc.Fields[1].Comments[encoder.LineComment] = "Module parameters, in the `key=value` format."
+
+	KernelModuleConfigDoc.Fields[1].AddExample("", []string{"conn_tab_bits=15"})
 
 	VolumeMountConfigDoc.Type = "VolumeMountConfig"
 	VolumeMountConfigDoc.Comments[encoder.LineComment] = "VolumeMountConfig struct describes extra volume mount for the static pods."
@@ -1919,6 +2322,10 @@ func (_ InstallConfig) Doc() *encoder.Doc {
 	return &InstallConfigDoc
 }
 
+func (_ InstallExtraPartition) Doc() *encoder.Doc {
+	return &InstallExtraPartitionDoc
+}
+
 func (_ InstallDiskSizeMatcher) Doc() *encoder.Doc {
 	return &InstallDiskSizeMatcherDoc
 }
@@ -1995,6 +2402,14 @@ func (_ DiskPartition) Doc() *encoder.Doc {
 	return &DiskPartitionDoc
 }
 
+func (_ VolumeGroupConfig) Doc() *encoder.Doc {
+	return &VolumeGroupConfigDoc
+}
+
+func (_ LogicalVolumeConfig) Doc() *encoder.Doc {
+	return &LogicalVolumeConfigDoc
+}
+
 func (_ EncryptionConfig) Doc() *encoder.Doc {
 	return &EncryptionConfigDoc
 }
@@ -2075,6 +2490,34 @@ func (_ FeaturesConfig) Doc() *encoder.Doc {
 	return &FeaturesConfigDoc
 }
 
+func (_ ServiceConfig) Doc() *encoder.Doc {
+	return &ServiceConfigDoc
+}
+
+func (_ ServiceHealthCheckConfig) Doc() *encoder.Doc {
+	return &ServiceHealthCheckConfigDoc
+}
+
+func (_ ServiceRestartConfig) Doc() *encoder.Doc {
+	return &ServiceRestartConfigDoc
+}
+
+func (_ WatchdogConfig) Doc() *encoder.Doc {
+	return &WatchdogConfigDoc
+}
+
+func (_ FilesystemRepairConfig) Doc() *encoder.Doc {
+	return &FilesystemRepairConfigDoc
+}
+
+func (_ KernelConfig) Doc() *encoder.Doc {
+	return &KernelConfigDoc
+}
+
+func (_ KernelModuleConfig) Doc() *encoder.Doc {
+	return &KernelModuleConfigDoc
+}
+
 func (_ VolumeMountConfig) Doc() *encoder.Doc {
 	return &VolumeMountConfigDoc
 }
@@ -2096,6 +2539,7 @@ func GetConfigurationDoc() *encoder.FileDoc {
 			&KubeletConfigDoc,
 			&NetworkConfigDoc,
 			&InstallConfigDoc,
+			&InstallExtraPartitionDoc,
 			&InstallDiskSizeMatcherDoc,
 			&InstallDiskSelectorDoc,
 			&TimeConfigDoc,
@@ -2115,6 +2559,8 @@ func GetConfigurationDoc() *encoder.FileDoc {
 			&AdminKubeconfigConfigDoc,
 			&MachineDiskDoc,
 			&DiskPartitionDoc,
+			&VolumeGroupConfigDoc,
+			&LogicalVolumeConfigDoc,
 			&EncryptionConfigDoc,
 			&EncryptionKeyDoc,
 			&EncryptionKeyStaticDoc,
@@ -2135,6 +2581,13 @@ func GetConfigurationDoc() *encoder.FileDoc {
 			&RegistryTLSConfigDoc,
 			&SystemDiskEncryptionConfigDoc,
 			&FeaturesConfigDoc,
+			&ServiceConfigDoc,
+			&ServiceHealthCheckConfigDoc,
+			&ServiceRestartConfigDoc,
+			&WatchdogConfigDoc,
+			&FilesystemRepairConfigDoc,
+			&KernelConfigDoc,
+			&KernelModuleConfigDoc,
 			&VolumeMountConfigDoc,
 			&ClusterInlineManifestDoc,
 		},
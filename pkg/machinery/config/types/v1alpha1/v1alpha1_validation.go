@@ -9,9 +9,13 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf16"
 
 	valid "github.com/asaskevich/govalidator"
 	"github.com/hashicorp/go-multierror"
@@ -103,6 +107,16 @@ func (c *Config) Validate(mode config.RuntimeMode, options ...config.ValidationO
 		}
 	}
 
+	for _, err := range c.MachineConfig.validateVolumeGroups() {
+		result = multierror.Append(result, err)
+	}
+
+	if c.MachineConfig.MachineInstall != nil {
+		for _, err := range c.MachineConfig.MachineInstall.validateExtraPartitions() {
+			result = multierror.Append(result, err)
+		}
+	}
+
 	if t := c.Machine().Type(); t != machine.TypeUnknown && t.String() != c.MachineConfig.MachineType {
 		warnings = append(warnings, fmt.Sprintf("use %q instead of %q for machine type", t.String(), c.MachineConfig.MachineType))
 	}
@@ -124,6 +138,18 @@ func (c *Config) Validate(mode config.RuntimeMode, options ...config.ValidationO
 		result = multierror.Append(result, fmt.Errorf("unknown machine type %q", c.MachineConfig.MachineType))
 	}
 
+	for i, ca := range c.MachineConfig.MachineAcceptedCAs {
+		if ca == nil || len(ca.Crt) == 0 {
+			result = multierror.Append(result, fmt.Errorf("accepted CA %d: certificate is required", i))
+
+			continue
+		}
+
+		if _, err := ca.GetCert(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("accepted CA %d: %w", i, err))
+		}
+	}
+
 	if c.MachineConfig.MachineNetwork != nil {
 		bondedInterfaces := map[string]string{}
 
@@ -152,6 +178,22 @@ func (c *Config) Validate(mode config.RuntimeMode, options ...config.ValidationO
 				if pt.DiskSize == 0 && i != len(disk.DiskPartitions)-1 {
 					result = multierror.Append(result, fmt.Errorf("partition for disk %q is set to occupy full disk, but it's not the last partition in the list", disk.Device()))
 				}
+
+				switch pt.Filesystem() {
+				case config.FilesystemXFS, config.FilesystemExt4:
+				default:
+					result = multierror.Append(result, fmt.Errorf("partition %d for disk %q: unsupported filesystem %q", i+1, disk.Device(), pt.Filesystem()))
+				}
+
+				if pt.DiskEncryption != nil {
+					if len(pt.DiskEncryption.EncryptionKeys) == 0 {
+						result = multierror.Append(result, fmt.Errorf("partition %d for disk %q: no encryption keys provided", i+1, disk.Device()))
+					}
+
+					for _, err := range validateEncryptionKeys(pt.Encryption()) {
+						result = multierror.Append(result, fmt.Errorf("partition %d for disk %q: %w", i+1, disk.Device(), err))
+					}
+				}
 			}
 		}
 	}
@@ -163,18 +205,85 @@ func (c *Config) Validate(mode config.RuntimeMode, options ...config.ValidationO
 				result = multierror.Append(result, fmt.Errorf("no encryption keys provided for the ephemeral partition encryption"))
 			}
 
-			slotsInUse := map[int]bool{}
-			for _, key := range encryptionConfig.Keys() {
-				if slotsInUse[key.Slot()] {
-					result = multierror.Append(result, fmt.Errorf("encryption key slot %d is already in use", key.Slot()))
-				}
+			result = multierror.Append(result, validateEncryptionKeys(encryptionConfig)...)
+		}
+	}
 
-				slotsInUse[key.Slot()] = true
+	serviceNames := map[string]struct{}{}
 
-				if key.NodeID() == nil && key.Static() == nil {
-					result = multierror.Append(result, fmt.Errorf("encryption key at slot %d doesn't have any settings", key.Slot()))
-				}
+	for i, service := range c.MachineConfig.MachineServices {
+		if service == nil || service.ServiceName == "" {
+			result = multierror.Append(result, fmt.Errorf("service override %d: name is required", i))
+
+			continue
+		}
+
+		if _, exists := serviceNames[service.ServiceName]; exists {
+			result = multierror.Append(result, fmt.Errorf("service %q: duplicate override", service.ServiceName))
+		}
+
+		serviceNames[service.ServiceName] = struct{}{}
+
+		if service.HealthCheckInterval() < 0 || service.HealthCheckTimeout() < 0 || service.RestartBackoff() < 0 || service.RestartWindow() < 0 {
+			result = multierror.Append(result, fmt.Errorf("service %q: durations can't be negative", service.ServiceName))
+		}
+
+		if service.MaxRestarts() < 0 {
+			result = multierror.Append(result, fmt.Errorf("service %q: maximum restarts can't be negative", service.ServiceName))
+		}
+
+		switch service.Escalation() {
+		case config.ServiceEscalationNone, config.ServiceEscalationUnhealthy, config.ServiceEscalationCordon, config.ServiceEscalationReboot:
+		default:
+			result = multierror.Append(result, fmt.Errorf("service %q: unknown escalation action %q", service.ServiceName, service.Escalation()))
+		}
+
+		if service.Escalation() != config.ServiceEscalationNone && service.MaxRestarts() == 0 {
+			warnings = append(warnings, fmt.Sprintf("service %q: escalation action is ignored without the maximum restarts", service.ServiceName))
+		}
+	}
+
+	if c.MachineConfig.MachineFeatures != nil && c.MachineConfig.MachineFeatures.TracingEndpoint() != "" {
+		endpoint := c.MachineConfig.MachineFeatures.TracingEndpoint()
+
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			result = multierror.Append(result, fmt.Errorf("tracing endpoint %q should be an http or https URL", endpoint))
+		}
+	}
+
+	if c.MachineConfig.MachineWatchdog != nil {
+		watchdog := c.MachineConfig.MachineWatchdog
+
+		if !filepath.IsAbs(watchdog.Device()) {
+			result = multierror.Append(result, fmt.Errorf("watchdog device %q should be an absolute path", watchdog.Device()))
+		}
+
+		if watchdog.Timeout() < constants.MinWatchdogTimeout {
+			result = multierror.Append(result, fmt.Errorf("watchdog timeout %s should be at least %s", watchdog.Timeout(), constants.MinWatchdogTimeout))
+		}
+	}
+
+	switch c.MachineConfig.FilesystemRepair().Policy() {
+	case config.FilesystemRepairNone, config.FilesystemRepairCheck, config.FilesystemRepairRepair, config.FilesystemRepairForce:
+	default:
+		result = multierror.Append(result, fmt.Errorf("unknown filesystem repair policy %q", c.MachineConfig.FilesystemRepair().Policy()))
+	}
+
+	if c.MachineConfig.MachineKernel != nil {
+		seen := map[string]struct{}{}
+
+		for i, module := range c.MachineConfig.MachineKernel.KernelModules {
+			if module.ModuleName == "" {
+				result = multierror.Append(result, fmt.Errorf("kernel module %d: name is required", i+1))
+
+				continue
 			}
+
+			if _, ok := seen[module.ModuleName]; ok {
+				result = multierror.Append(result, fmt.Errorf("kernel module %q is specified more than once", module.ModuleName))
+			}
+
+			seen[module.ModuleName] = struct{}{}
 		}
 	}
 
@@ -189,6 +298,141 @@ func (c *Config) Validate(mode config.RuntimeMode, options ...config.ValidationO
 	return warnings, result.ErrorOrNil()
 }
 
+// lvmNameRegexp matches valid LVM volume group and logical volume names.
+var lvmNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9+_.][a-zA-Z0-9+_.-]*$`)
+
+//nolint:gocyclo,cyclop
+func (m *MachineConfig) validateVolumeGroups() []error {
+	var errs []error
+
+	usedDevices := map[string]string{}
+
+	for _, disk := range m.MachineDisks {
+		usedDevices[disk.Device()] = ".machine.disks"
+	}
+
+	seen := map[string]struct{}{}
+
+	for i, vg := range m.MachineVolumeGroups {
+		if vg.VolumeGroupName == "" {
+			errs = append(errs, fmt.Errorf("volume group %d: name is required", i+1))
+
+			continue
+		}
+
+		if !lvmNameRegexp.MatchString(vg.VolumeGroupName) {
+			errs = append(errs, fmt.Errorf("volume group %q: invalid name", vg.VolumeGroupName))
+		}
+
+		if _, ok := seen[vg.VolumeGroupName]; ok {
+			errs = append(errs, fmt.Errorf("volume group %q is specified more than once", vg.VolumeGroupName))
+		}
+
+		seen[vg.VolumeGroupName] = struct{}{}
+
+		if len(vg.VolumeGroupDevices) == 0 {
+			errs = append(errs, fmt.Errorf("volume group %q: no devices specified", vg.VolumeGroupName))
+		}
+
+		for _, device := range vg.VolumeGroupDevices {
+			if usedBy, ok := usedDevices[device]; ok {
+				errs = append(errs, fmt.Errorf("volume group %q: device %q is already used by %s", vg.VolumeGroupName, device, usedBy))
+			}
+
+			usedDevices[device] = fmt.Sprintf("volume group %q", vg.VolumeGroupName)
+		}
+
+		seenVolumes := map[string]struct{}{}
+
+		for j, lv := range vg.VolumeGroupLogicalVolumes {
+			if lv.LogicalVolumeName == "" {
+				errs = append(errs, fmt.Errorf("logical volume %d in volume group %q: name is required", j+1, vg.VolumeGroupName))
+
+				continue
+			}
+
+			if !lvmNameRegexp.MatchString(lv.LogicalVolumeName) {
+				errs = append(errs, fmt.Errorf("logical volume %q in volume group %q: invalid name", lv.LogicalVolumeName, vg.VolumeGroupName))
+			}
+
+			if _, ok := seenVolumes[lv.LogicalVolumeName]; ok {
+				errs = append(errs, fmt.Errorf("logical volume %q in volume group %q is specified more than once", lv.LogicalVolumeName, vg.VolumeGroupName))
+			}
+
+			seenVolumes[lv.LogicalVolumeName] = struct{}{}
+
+			if lv.LogicalVolumeSize == 0 && j != len(vg.VolumeGroupLogicalVolumes)-1 {
+				errs = append(errs, fmt.Errorf("logical volume %q in volume group %q is set to occupy the rest of the volume group, but it's not the last logical volume in the list", lv.LogicalVolumeName, vg.VolumeGroupName))
+			}
+
+			switch lv.RAID() {
+			case "":
+			case config.RAID1:
+				if len(vg.VolumeGroupDevices) < 2 {
+					errs = append(errs, fmt.Errorf("logical volume %q in volume group %q: %s requires at least 2 devices", lv.LogicalVolumeName, vg.VolumeGroupName, lv.RAID()))
+				}
+			case config.RAID10:
+				if len(vg.VolumeGroupDevices) < 4 {
+					errs = append(errs, fmt.Errorf("logical volume %q in volume group %q: %s requires at least 4 devices", lv.LogicalVolumeName, vg.VolumeGroupName, lv.RAID()))
+				} else if len(vg.VolumeGroupDevices)%2 != 0 {
+					errs = append(errs, fmt.Errorf("logical volume %q in volume group %q: %s requires an even number of devices", lv.LogicalVolumeName, vg.VolumeGroupName, lv.RAID()))
+				}
+			default:
+				errs = append(errs, fmt.Errorf("logical volume %q in volume group %q: unsupported RAID level %q", lv.LogicalVolumeName, vg.VolumeGroupName, lv.RAID()))
+			}
+
+			switch lv.Filesystem() {
+			case config.FilesystemXFS, config.FilesystemExt4:
+			default:
+				errs = append(errs, fmt.Errorf("logical volume %q in volume group %q: unsupported filesystem %q", lv.LogicalVolumeName, vg.VolumeGroupName, lv.Filesystem()))
+			}
+		}
+	}
+
+	return errs
+}
+
+// gptMaxNameLength is the maximum length of the GPT partition name in UTF-16 code units.
+const gptMaxNameLength = 36
+
+func (i *InstallConfig) validateExtraPartitions() []error {
+	var errs []error
+
+	if len(i.InstallExtraPartitions) > 0 && i.InstallEphemeralSize == 0 {
+		errs = append(errs, fmt.Errorf("install extra partitions require ephemeralSize to be set"))
+	}
+
+	seen := map[string]struct{}{
+		constants.EFIPartitionLabel:       {},
+		constants.BIOSGrubPartitionLabel:  {},
+		constants.BootPartitionLabel:      {},
+		constants.MetaPartitionLabel:      {},
+		constants.StatePartitionLabel:     {},
+		constants.EphemeralPartitionLabel: {},
+	}
+
+	for j, partition := range i.InstallExtraPartitions {
+		switch {
+		case partition.PartitionLabel == "":
+			errs = append(errs, fmt.Errorf("install extra partition %d: label is required", j+1))
+		case len(utf16.Encode([]rune(partition.PartitionLabel))) > gptMaxNameLength:
+			errs = append(errs, fmt.Errorf("install extra partition %q: label is longer than %d characters", partition.PartitionLabel, gptMaxNameLength))
+		default:
+			if _, ok := seen[partition.PartitionLabel]; ok {
+				errs = append(errs, fmt.Errorf("install extra partition %q: label is already in use", partition.PartitionLabel))
+			}
+
+			seen[partition.PartitionLabel] = struct{}{}
+		}
+
+		if partition.PartitionSize == 0 && j != len(i.InstallExtraPartitions)-1 {
+			errs = append(errs, fmt.Errorf("install extra partition %d is set to occupy the rest of the disk, but it's not the last partition in the list", j+1))
+		}
+	}
+
+	return errs
+}
+
 // Validate validates the config.
 func (c *ClusterConfig) Validate() error {
 	var result *multierror.Error
@@ -548,3 +792,21 @@ func CheckDeviceRoutes(d *Device, bondedInterfaces map[string]string) error {
 
 	return result.ErrorOrNil()
 }
+
+// validateEncryptionKeys checks that key slots are unique and each key has settings.
+func validateEncryptionKeys(encryptionConfig config.Encryption) (errs []error) {
+	slotsInUse := map[int]bool{}
+	for _, key := range encryptionConfig.Keys() {
+		if slotsInUse[key.Slot()] {
+			errs = append(errs, fmt.Errorf("encryption key slot %d is already in use", key.Slot()))
+		}
+
+		slotsInUse[key.Slot()] = true
+
+		if key.NodeID() == nil && key.Static() == nil {
+			errs = append(errs, fmt.Errorf("encryption key at slot %d doesn't have any settings", key.Slot()))
+		}
+	}
+
+	return errs
+}
@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/talos-systems/crypto/x509"
 
 	"github.com/talos-systems/talos/pkg/machinery/config"
 	"github.com/talos-systems/talos/pkg/machinery/config/types/v1alpha1"
@@ -293,6 +295,29 @@ func TestValidate(t *testing.T) {
 			},
 			expectedError: "2 errors occurred:\n\t* inline manifest name can't be empty\n\t* inline manifest name \"foo\" is duplicate\n\n",
 		},
+		{
+			name: "AcceptedCAs",
+			config: &v1alpha1.Config{
+				ConfigVersion: "v1alpha1",
+				MachineConfig: &v1alpha1.MachineConfig{
+					MachineType: "controlplane",
+					MachineAcceptedCAs: []*x509.PEMEncodedCertificateAndKey{
+						{},
+						{
+							Crt: []byte("foo"),
+						},
+					},
+				},
+				ClusterConfig: &v1alpha1.ClusterConfig{
+					ControlPlane: &v1alpha1.ControlPlaneConfig{
+						Endpoint: &v1alpha1.Endpoint{
+							endpointURL,
+						},
+					},
+				},
+			},
+			expectedError: "2 errors occurred:\n\t* accepted CA 0: certificate is required\n\t* accepted CA 1: failed to parse PEM block\n\n",
+		},
 		{
 			name: "BondDefaultConfig",
 			config: &v1alpha1.Config{
@@ -486,6 +511,288 @@ func TestValidate(t *testing.T) {
 			expectedError: "3 errors occurred:\n\t* public key invalid: wrong key \"\" length: 0\n\t* public key invalid: wrong key \"4A3rogGVHuVjeZz5cbqryWXGkGBdIGC0E6+5mX2Iz1==\" length: 31\n" +
 				"\t* peer allowed IP \"10.2.0\" is invalid: invalid CIDR address: 10.2.0\n\n",
 		},
+		{
+			name: "ServiceOverrides",
+			config: &v1alpha1.Config{
+				ConfigVersion: "v1alpha1",
+				MachineConfig: &v1alpha1.MachineConfig{
+					MachineType: "worker",
+					MachineServices: []*v1alpha1.ServiceConfig{
+						{
+							ServiceName: "kubelet",
+							ServiceRestart: &v1alpha1.ServiceRestartConfig{
+								RestartEscalation: "reboot",
+							},
+						},
+						{
+							ServiceName: "kubelet",
+							ServiceRestart: &v1alpha1.ServiceRestartConfig{
+								RestartMaxRestarts: 3,
+								RestartEscalation:  "explode",
+							},
+						},
+						{},
+					},
+				},
+				ClusterConfig: &v1alpha1.ClusterConfig{
+					ControlPlane: &v1alpha1.ControlPlaneConfig{
+						Endpoint: &v1alpha1.Endpoint{
+							endpointURL,
+						},
+					},
+				},
+			},
+			expectedWarnings: []string{
+				"service \"kubelet\": escalation action is ignored without the maximum restarts",
+			},
+			expectedError: "3 errors occurred:\n\t* service \"kubelet\": duplicate override\n\t* service \"kubelet\": unknown escalation action \"explode\"\n" +
+				"\t* service override 2: name is required\n\n",
+		},
+		{
+			name: "DiskPartitions",
+			config: &v1alpha1.Config{
+				ConfigVersion: "v1alpha1",
+				MachineConfig: &v1alpha1.MachineConfig{
+					MachineType: "worker",
+					MachineDisks: []*v1alpha1.MachineDisk{
+						{
+							DeviceName: "/dev/sdb",
+							DiskPartitions: []*v1alpha1.DiskPartition{
+								{
+									DiskSize:       v1alpha1.DiskSize(1024 * 1024 * 1024),
+									DiskMountPoint: "/var/mnt/extra",
+									DiskFilesystem: "ext4",
+									DiskEncryption: &v1alpha1.EncryptionConfig{
+										EncryptionProvider: "luks2",
+										EncryptionKeys: []*v1alpha1.EncryptionKey{
+											{
+												KeyNodeID: &v1alpha1.EncryptionKeyNodeID{},
+											},
+											{
+												KeySlot: 1,
+											},
+										},
+									},
+								},
+								{
+									DiskMountPoint: "/var/mnt/data",
+									DiskFilesystem: "btrfs",
+									DiskEncryption: &v1alpha1.EncryptionConfig{
+										EncryptionProvider: "luks2",
+									},
+								},
+							},
+						},
+					},
+				},
+				ClusterConfig: &v1alpha1.ClusterConfig{
+					ControlPlane: &v1alpha1.ControlPlaneConfig{
+						Endpoint: &v1alpha1.Endpoint{
+							endpointURL,
+						},
+					},
+				},
+			},
+			expectedError: "3 errors occurred:\n\t* partition 1 for disk \"/dev/sdb\": encryption key at slot 1 doesn't have any settings\n\t* partition 2 for disk \"/dev/sdb\": unsupported filesystem \"btrfs\"\n\t* partition 2 for disk \"/dev/sdb\": no encryption keys provided\n\n",
+		},
+		{
+			name: "InstallExtraPartitions",
+			config: &v1alpha1.Config{
+				ConfigVersion: "v1alpha1",
+				MachineConfig: &v1alpha1.MachineConfig{
+					MachineType: "worker",
+					MachineInstall: &v1alpha1.InstallConfig{
+						InstallDisk: "/dev/vda",
+						InstallExtraPartitions: []*v1alpha1.InstallExtraPartition{
+							{
+								PartitionLabel: "DATA",
+							},
+							{
+								PartitionLabel: "STATE",
+								PartitionSize:  v1alpha1.DiskSize(1024 * 1024 * 1024),
+							},
+							{
+								PartitionSize: v1alpha1.DiskSize(1024 * 1024 * 1024),
+							},
+						},
+					},
+				},
+				ClusterConfig: &v1alpha1.ClusterConfig{
+					ControlPlane: &v1alpha1.ControlPlaneConfig{
+						Endpoint: &v1alpha1.Endpoint{
+							endpointURL,
+						},
+					},
+				},
+			},
+			expectedError: "4 errors occurred:\n\t* install extra partitions require ephemeralSize to be set\n\t* install extra partition 1 is set to occupy the rest of the disk, but it's not the last partition in the list\n\t* install extra partition \"STATE\": label is already in use\n\t* install extra partition 3: label is required\n\n",
+		},
+		{
+			name: "VolumeGroups",
+			config: &v1alpha1.Config{
+				ConfigVersion: "v1alpha1",
+				MachineConfig: &v1alpha1.MachineConfig{
+					MachineType: "worker",
+					MachineDisks: []*v1alpha1.MachineDisk{
+						{
+							DeviceName: "/dev/sdb",
+						},
+					},
+					MachineVolumeGroups: []*v1alpha1.VolumeGroupConfig{
+						{
+							VolumeGroupName:    "data",
+							VolumeGroupDevices: []string{"/dev/sdb", "/dev/sdc"},
+							VolumeGroupLogicalVolumes: []*v1alpha1.LogicalVolumeConfig{
+								{
+									LogicalVolumeName: "mirror",
+									LogicalVolumeRAID: "raid1",
+								},
+								{
+									LogicalVolumeName: "striped",
+									LogicalVolumeRAID: "raid10",
+								},
+								{
+									LogicalVolumeName:       "-bad",
+									LogicalVolumeRAID:       "raid5",
+									LogicalVolumeFilesystem: "btrfs",
+								},
+							},
+						},
+						{
+							VolumeGroupName: "data",
+						},
+					},
+				},
+				ClusterConfig: &v1alpha1.ClusterConfig{
+					ControlPlane: &v1alpha1.ControlPlaneConfig{
+						Endpoint: &v1alpha1.Endpoint{
+							endpointURL,
+						},
+					},
+				},
+			},
+			expectedError: "9 errors occurred:\n\t* volume group \"data\": device \"/dev/sdb\" is already used by .machine.disks\n\t* logical volume \"mirror\" in volume group \"data\" is set to occupy the rest of the volume group, but it's not the last logical volume in the list\n\t* logical volume \"striped\" in volume group \"data\" is set to occupy the rest of the volume group, but it's not the last logical volume in the list\n\t* logical volume \"striped\" in volume group \"data\": raid10 requires at least 4 devices\n\t* logical volume \"-bad\" in volume group \"data\": invalid name\n\t* logical volume \"-bad\" in volume group \"data\": unsupported RAID level \"raid5\"\n\t* logical volume \"-bad\" in volume group \"data\": unsupported filesystem \"btrfs\"\n\t* volume group \"data\" is specified more than once\n\t* volume group \"data\": no devices specified\n\n",
+		},
+		{
+			name: "VolumeGroupsRAID10OddDevices",
+			config: &v1alpha1.Config{
+				ConfigVersion: "v1alpha1",
+				MachineConfig: &v1alpha1.MachineConfig{
+					MachineType: "worker",
+					MachineVolumeGroups: []*v1alpha1.VolumeGroupConfig{
+						{
+							VolumeGroupName:    "data",
+							VolumeGroupDevices: []string{"/dev/sdb", "/dev/sdc", "/dev/sdd", "/dev/sde", "/dev/sdf"},
+							VolumeGroupLogicalVolumes: []*v1alpha1.LogicalVolumeConfig{
+								{
+									LogicalVolumeName: "striped",
+									LogicalVolumeRAID: "raid10",
+								},
+							},
+						},
+					},
+				},
+				ClusterConfig: &v1alpha1.ClusterConfig{
+					ControlPlane: &v1alpha1.ControlPlaneConfig{
+						Endpoint: &v1alpha1.Endpoint{
+							endpointURL,
+						},
+					},
+				},
+			},
+			expectedError: "1 error occurred:\n\t* logical volume \"striped\" in volume group \"data\": raid10 requires an even number of devices\n\n",
+		},
+		{
+			name: "TracingEndpoint",
+			config: &v1alpha1.Config{
+				ConfigVersion: "v1alpha1",
+				MachineConfig: &v1alpha1.MachineConfig{
+					MachineType: "worker",
+					MachineFeatures: &v1alpha1.FeaturesConfig{
+						TracingEndpointURL: "otel-collector:4318",
+					},
+				},
+				ClusterConfig: &v1alpha1.ClusterConfig{
+					ControlPlane: &v1alpha1.ControlPlaneConfig{
+						Endpoint: &v1alpha1.Endpoint{
+							endpointURL,
+						},
+					},
+				},
+			},
+			expectedError: "1 error occurred:\n\t* tracing endpoint \"otel-collector:4318\" should be an http or https URL\n\n",
+		},
+		{
+			name: "Watchdog",
+			config: &v1alpha1.Config{
+				ConfigVersion: "v1alpha1",
+				MachineConfig: &v1alpha1.MachineConfig{
+					MachineType: "worker",
+					MachineWatchdog: &v1alpha1.WatchdogConfig{
+						WatchdogDevice:  "watchdog0",
+						WatchdogTimeout: time.Second,
+					},
+				},
+				ClusterConfig: &v1alpha1.ClusterConfig{
+					ControlPlane: &v1alpha1.ControlPlaneConfig{
+						Endpoint: &v1alpha1.Endpoint{
+							endpointURL,
+						},
+					},
+				},
+			},
+			expectedError: "2 errors occurred:\n\t* watchdog device \"watchdog0\" should be an absolute path\n\t* watchdog timeout 1s should be at least 10s\n\n",
+		},
+		{
+			name: "FilesystemRepair",
+			config: &v1alpha1.Config{
+				ConfigVersion: "v1alpha1",
+				MachineConfig: &v1alpha1.MachineConfig{
+					MachineType: "worker",
+					MachineFilesystemRepair: &v1alpha1.FilesystemRepairConfig{
+						FilesystemRepairPolicy: "fsck",
+					},
+				},
+				ClusterConfig: &v1alpha1.ClusterConfig{
+					ControlPlane: &v1alpha1.ControlPlaneConfig{
+						Endpoint: &v1alpha1.Endpoint{
+							endpointURL,
+						},
+					},
+				},
+			},
+			expectedError: "1 error occurred:\n\t* unknown filesystem repair policy \"fsck\"\n\n",
+		},
+		{
+			name: "KernelModules",
+			config: &v1alpha1.Config{
+				ConfigVersion: "v1alpha1",
+				MachineConfig: &v1alpha1.MachineConfig{
+					MachineType: "worker",
+					MachineKernel: &v1alpha1.KernelConfig{
+						KernelModules: []*v1alpha1.KernelModuleConfig{
+							{
+								ModuleName: "nvme_tcp",
+							},
+							{
+								ModuleParameters: []string{"foo=bar"},
+							},
+							{
+								ModuleName: "nvme_tcp",
+							},
+						},
+					},
+				},
+				ClusterConfig: &v1alpha1.ClusterConfig{
+					ControlPlane: &v1alpha1.ControlPlaneConfig{
+						Endpoint: &v1alpha1.Endpoint{
+							endpointURL,
+						},
+					},
+				},
+			},
+			expectedError: "2 errors occurred:\n\t* kernel module 2: name is required\n\t* kernel module \"nvme_tcp\" is specified more than once\n\n",
+		},
 	} {
 		test := test
 
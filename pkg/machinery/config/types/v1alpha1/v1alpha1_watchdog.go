@@ -0,0 +1,42 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/talos-systems/talos/pkg/machinery/config"
+	"github.com/talos-systems/talos/pkg/machinery/constants"
+)
+
+// Watchdog implements the config.MachineConfig interface.
+//
+// Watchdog is enabled if the `watchdog` section is present in the config.
+func (m *MachineConfig) Watchdog() config.Watchdog {
+	return m.MachineWatchdog
+}
+
+// Enabled implements the config.Watchdog interface.
+func (w *WatchdogConfig) Enabled() bool {
+	return w != nil
+}
+
+// Device implements the config.Watchdog interface.
+func (w *WatchdogConfig) Device() string {
+	if w == nil || w.WatchdogDevice == "" {
+		return constants.DefaultWatchdogDevice
+	}
+
+	return w.WatchdogDevice
+}
+
+// Timeout implements the config.Watchdog interface.
+func (w *WatchdogConfig) Timeout() time.Duration {
+	if w == nil || w.WatchdogTimeout == 0 {
+		return constants.DefaultWatchdogTimeout
+	}
+
+	return w.WatchdogTimeout
+}
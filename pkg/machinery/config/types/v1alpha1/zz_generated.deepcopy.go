@@ -8,6 +8,10 @@
 
 package v1alpha1
 
+import (
+	x509 "github.com/talos-systems/crypto/x509"
+)
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServerConfig) DeepCopyInto(out *APIServerConfig) {
 	*out = *in
@@ -532,6 +536,16 @@ func (in *DeviceWireguardPeer) DeepCopy() *DeviceWireguardPeer {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DiskPartition) DeepCopyInto(out *DiskPartition) {
 	*out = *in
+	if in.DiskMountOptions != nil {
+		in, out := &in.DiskMountOptions, &out.DiskMountOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DiskEncryption != nil {
+		in, out := &in.DiskEncryption, &out.DiskEncryption
+		*out = new(EncryptionConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -707,6 +721,11 @@ func (in *FeaturesConfig) DeepCopyInto(out *FeaturesConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -720,6 +739,22 @@ func (in *FeaturesConfig) DeepCopy() *FeaturesConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemRepairConfig) DeepCopyInto(out *FilesystemRepairConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemRepairConfig.
+func (in *FilesystemRepairConfig) DeepCopy() *FilesystemRepairConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemRepairConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstallConfig) DeepCopyInto(out *InstallConfig) {
 	*out = *in
@@ -733,6 +768,17 @@ func (in *InstallConfig) DeepCopyInto(out *InstallConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.InstallExtraPartitions != nil {
+		in, out := &in.InstallExtraPartitions, &out.InstallExtraPartitions
+		*out = make([]*InstallExtraPartition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(InstallExtraPartition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	return
 }
 
@@ -776,6 +822,70 @@ func (in *InstallDiskSizeMatcher) DeepCopy() *InstallDiskSizeMatcher {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallExtraPartition) DeepCopyInto(out *InstallExtraPartition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallExtraPartition.
+func (in *InstallExtraPartition) DeepCopy() *InstallExtraPartition {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallExtraPartition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelConfig) DeepCopyInto(out *KernelConfig) {
+	*out = *in
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]*KernelModuleConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(KernelModuleConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelConfig.
+func (in *KernelConfig) DeepCopy() *KernelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelModuleConfig) DeepCopyInto(out *KernelModuleConfig) {
+	*out = *in
+	if in.ModuleParameters != nil {
+		in, out := &in.ModuleParameters, &out.ModuleParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelModuleConfig.
+func (in *KernelModuleConfig) DeepCopy() *KernelModuleConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelModuleConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeletConfig) DeepCopyInto(out *KubeletConfig) {
 	*out = *in
@@ -811,6 +921,27 @@ func (in *KubeletConfig) DeepCopy() *KubeletConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalVolumeConfig) DeepCopyInto(out *LogicalVolumeConfig) {
+	*out = *in
+	if in.LogicalVolumeMountOptions != nil {
+		in, out := &in.LogicalVolumeMountOptions, &out.LogicalVolumeMountOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalVolumeConfig.
+func (in *LogicalVolumeConfig) DeepCopy() *LogicalVolumeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalVolumeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineConfig) DeepCopyInto(out *MachineConfig) {
 	*out = *in
@@ -818,6 +949,16 @@ func (in *MachineConfig) DeepCopyInto(out *MachineConfig) {
 		in, out := &in.MachineCA, &out.MachineCA
 		*out = (*in).DeepCopy()
 	}
+	if in.MachineAcceptedCAs != nil {
+		in, out := &in.MachineAcceptedCAs, &out.MachineAcceptedCAs
+		*out = make([]*x509.PEMEncodedCertificateAndKey, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = (*in).DeepCopy()
+			}
+		}
+	}
 	if in.MachineCertSANs != nil {
 		in, out := &in.MachineCertSANs, &out.MachineCertSANs
 		*out = make([]string, len(*in))
@@ -844,6 +985,17 @@ func (in *MachineConfig) DeepCopyInto(out *MachineConfig) {
 			}
 		}
 	}
+	if in.MachineVolumeGroups != nil {
+		in, out := &in.MachineVolumeGroups, &out.MachineVolumeGroups
+		*out = make([]*VolumeGroupConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(VolumeGroupConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	if in.MachineInstall != nil {
 		in, out := &in.MachineInstall, &out.MachineInstall
 		*out = new(InstallConfig)
@@ -890,6 +1042,32 @@ func (in *MachineConfig) DeepCopyInto(out *MachineConfig) {
 		*out = new(FeaturesConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MachineServices != nil {
+		in, out := &in.MachineServices, &out.MachineServices
+		*out = make([]*ServiceConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ServiceConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.MachineWatchdog != nil {
+		in, out := &in.MachineWatchdog, &out.MachineWatchdog
+		*out = new(WatchdogConfig)
+		**out = **in
+	}
+	if in.MachineKernel != nil {
+		in, out := &in.MachineKernel, &out.MachineKernel
+		*out = new(KernelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MachineFilesystemRepair != nil {
+		in, out := &in.MachineFilesystemRepair, &out.MachineFilesystemRepair
+		*out = new(FilesystemRepairConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -913,7 +1091,7 @@ func (in *MachineDisk) DeepCopyInto(out *MachineDisk) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(DiskPartition)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
@@ -1206,6 +1384,64 @@ func (in *SchedulerConfig) DeepCopy() *SchedulerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceConfig) DeepCopyInto(out *ServiceConfig) {
+	*out = *in
+	if in.ServiceHealthCheck != nil {
+		in, out := &in.ServiceHealthCheck, &out.ServiceHealthCheck
+		*out = new(ServiceHealthCheckConfig)
+		**out = **in
+	}
+	if in.ServiceRestart != nil {
+		in, out := &in.ServiceRestart, &out.ServiceRestart
+		*out = new(ServiceRestartConfig)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceConfig.
+func (in *ServiceConfig) DeepCopy() *ServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceHealthCheckConfig) DeepCopyInto(out *ServiceHealthCheckConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceHealthCheckConfig.
+func (in *ServiceHealthCheckConfig) DeepCopy() *ServiceHealthCheckConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceHealthCheckConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceRestartConfig) DeepCopyInto(out *ServiceRestartConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceRestartConfig.
+func (in *ServiceRestartConfig) DeepCopy() *ServiceRestartConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceRestartConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemDiskEncryptionConfig) DeepCopyInto(out *SystemDiskEncryptionConfig) {
 	*out = *in
@@ -1280,6 +1516,38 @@ func (in *Vlan) DeepCopy() *Vlan {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeGroupConfig) DeepCopyInto(out *VolumeGroupConfig) {
+	*out = *in
+	if in.VolumeGroupDevices != nil {
+		in, out := &in.VolumeGroupDevices, &out.VolumeGroupDevices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeGroupLogicalVolumes != nil {
+		in, out := &in.VolumeGroupLogicalVolumes, &out.VolumeGroupLogicalVolumes
+		*out = make([]*LogicalVolumeConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(LogicalVolumeConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeGroupConfig.
+func (in *VolumeGroupConfig) DeepCopy() *VolumeGroupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeGroupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeMountConfig) DeepCopyInto(out *VolumeMountConfig) {
 	*out = *in
@@ -1295,3 +1563,19 @@ func (in *VolumeMountConfig) DeepCopy() *VolumeMountConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WatchdogConfig) DeepCopyInto(out *WatchdogConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WatchdogConfig.
+func (in *WatchdogConfig) DeepCopy() *WatchdogConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WatchdogConfig)
+	in.DeepCopyInto(out)
+	return out
+}
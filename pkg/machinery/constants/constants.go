@@ -311,6 +311,9 @@ const (
 	// TrustdPort is the port for the trustd service.
 	TrustdPort = 50001
 
+	// MetricsPort is the port for the Prometheus metrics endpoint served by apid.
+	MetricsPort = 50002
+
 	// DefaultContainerdVersion is the default container runtime version.
 	DefaultContainerdVersion = "1.5.3"
 
@@ -338,6 +341,9 @@ const (
 	// MachineSocketPath is the path to file socket of machine API.
 	MachineSocketPath = SystemRunPath + "/machined/machine.sock"
 
+	// MachineMetricsSocketPath is the path to file socket of machined Prometheus metrics.
+	MachineMetricsSocketPath = SystemRunPath + "/machined/metrics.sock"
+
 	// NetworkSocketPath is the path to file socket of network API.
 	NetworkSocketPath = SystemRunPath + "/networkd/networkd.sock"
 
@@ -365,6 +371,21 @@ const (
 	// RootfsAsset defines a well known name for our rootfs filename.
 	RootfsAsset = "rootfs.sqsh"
 
+	// SystemExtensionsPath is the path to the system extension images layered by the installer.
+	SystemExtensionsPath = "/usr/install/%s/extensions"
+
+	// SystemExtensionKeysPath is the path to the public keys trusted by the installer to verify system extensions.
+	SystemExtensionKeysPath = "/usr/install/extension-keys"
+
+	// ExtensionsConfigFile is the path to the list of the system extensions in the initramfs.
+	ExtensionsConfigFile = "/extensions.yaml"
+
+	// ExtensionsPath is the path to the system extension images in the initramfs.
+	ExtensionsPath = "/extensions"
+
+	// ExtensionKeysPath is the path to the public keys trusted to verify system extensions on boot in the initramfs.
+	ExtensionKeysPath = "/extension-keys"
+
 	// DefaultCertificateValidityDuration is the default duration for a certificate.
 	DefaultCertificateValidityDuration = x509.DefaultCertificateValidityDuration
 
@@ -384,9 +405,21 @@ const (
 	// SystemEtcPath is the path to the system etc directory.
 	SystemEtcPath = SystemPath + "/etc"
 
+	// ExtensionsRuntimePath is the path where system extension images are mounted.
+	ExtensionsRuntimePath = SystemPath + "/extensions"
+
+	// ExtensionsRuntimeConfigFile is the path to the status of the system extensions written on boot.
+	ExtensionsRuntimeConfigFile = SystemPath + "/extensions.yaml"
+
 	// SystemLibexecPath is the path to the system libexec directory.
 	SystemLibexecPath = SystemPath + "/libexec"
 
+	// PstoreMountPoint is the path where pstore filesystem is mounted.
+	PstoreMountPoint = "/sys/fs/pstore"
+
+	// PstoreArchivePath is the path where kernel crash records from the previous boot are saved.
+	PstoreArchivePath = "/var/log/pstore"
+
 	// FlannelCNI is the string to use Tanos-managed Flannel CNI (default).
 	FlannelCNI = "flannel"
 
@@ -418,6 +451,15 @@ const (
 	// BootTimeout is the timeout to run all services.
 	BootTimeout = 15 * time.Minute
 
+	// DefaultWatchdogDevice is the default watchdog timer device.
+	DefaultWatchdogDevice = "/dev/watchdog"
+
+	// DefaultWatchdogTimeout is the default watchdog timer timeout.
+	DefaultWatchdogTimeout = time.Minute
+
+	// MinWatchdogTimeout is the minimum supported watchdog timer timeout.
+	MinWatchdogTimeout = 10 * time.Second
+
 	// NodeReadyTimeout is the timeout to wait for the node to be ready (CNI to be running).
 	// For bootstrap API, this includes time to run bootstrap.
 	NodeReadyTimeout = BootTimeout
@@ -428,6 +470,9 @@ const (
 	// AnnotationCordonedValue is the annotation key for the nodes cordoned by Talos.
 	AnnotationCordonedValue = "true"
 
+	// AnnotationNodeAddresses is the annotation key for the node addresses published by Talos.
+	AnnotationNodeAddresses = "talos.dev/node-addresses"
+
 	// AnnotationStaticPodSecretsVersion is the annotation key for the static pod secret version.
 	AnnotationStaticPodSecretsVersion = "talos.dev/secrets-version"
 
@@ -445,6 +490,12 @@ const (
 
 	// DefaultSecondaryResolver is the default secondary DNS server.
 	DefaultSecondaryResolver = "8.8.8.8"
+
+	// CgroupSystem is the cgroup for Talos system services.
+	CgroupSystem = "/system"
+
+	// CgroupPodRuntime is the cgroup for the pod runtime: CRI containerd and kubelet.
+	CgroupPodRuntime = "/podruntime"
 )
 
 // See https://linux.die.net/man/3/klogctl
@@ -0,0 +1,11 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package block provides resources which describe block devices and partitions.
+package block
+
+import "github.com/cosi-project/runtime/pkg/resource"
+
+// NamespaceName contains resources related to block devices.
+const NamespaceName resource.Namespace = "block"
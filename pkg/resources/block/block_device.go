@@ -0,0 +1,108 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block
+
+import (
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// BlockDeviceType is type of BlockDevice resource.
+const BlockDeviceType = resource.Type("BlockDevices.block.talos.dev")
+
+// BlockDevice resource describes a block device (whole disk) discovered on the node.
+//
+// Resource ID is the device name (e.g. `sda`).
+type BlockDevice struct {
+	md   resource.Metadata
+	spec BlockDeviceSpec
+}
+
+// BlockDeviceSpec describes the block device.
+type BlockDeviceSpec struct {
+	DevPath    string `yaml:"devPath"`
+	Size       uint64 `yaml:"size"`
+	Model      string `yaml:"model,omitempty"`
+	Serial     string `yaml:"serial,omitempty"`
+	WWID       string `yaml:"wwid,omitempty"`
+	Modalias   string `yaml:"modalias,omitempty"`
+	Transport  string `yaml:"transport,omitempty"`
+	Rotational bool   `yaml:"rotational"`
+	ReadOnly   bool   `yaml:"readOnly"`
+	Removable  bool   `yaml:"removable"`
+	// PartitionTable type (e.g. `gpt`), empty if there's no partition table.
+	PartitionTable string `yaml:"partitionTable,omitempty"`
+	// Filesystem on the whole device (without a partition table).
+	Filesystem string `yaml:"filesystem,omitempty"`
+	MountPoint string `yaml:"mountPoint,omitempty"`
+}
+
+// NewBlockDevice initializes a BlockDevice resource.
+func NewBlockDevice(namespace resource.Namespace, id resource.ID) *BlockDevice {
+	r := &BlockDevice{
+		md:   resource.NewMetadata(namespace, BlockDeviceType, id, resource.VersionUndefined),
+		spec: BlockDeviceSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *BlockDevice) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *BlockDevice) Spec() interface{} {
+	return r.spec
+}
+
+func (r *BlockDevice) String() string {
+	return fmt.Sprintf("block.BlockDevice(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *BlockDevice) DeepCopy() resource.Resource {
+	return &BlockDevice{
+		md:   r.md,
+		spec: r.spec,
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *BlockDevice) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             BlockDeviceType,
+		Aliases:          []resource.Type{"disk", "disks"},
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Size",
+				JSONPath: "{.size}",
+			},
+			{
+				Name:     "Model",
+				JSONPath: "{.model}",
+			},
+			{
+				Name:     "Serial",
+				JSONPath: "{.serial}",
+			},
+			{
+				Name:     "Transport",
+				JSONPath: "{.transport}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *BlockDevice) TypedSpec() *BlockDeviceSpec {
+	return &r.spec
+}
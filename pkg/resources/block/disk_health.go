@@ -0,0 +1,114 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block
+
+import (
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// DiskHealthType is type of DiskHealth resource.
+const DiskHealthType = resource.Type("DiskHealths.block.talos.dev")
+
+// DiskHealth resource describes disk health as reported by ATA SMART or NVMe health log.
+//
+// Resource ID is the device name (e.g. `nvme0n1`).
+type DiskHealth struct {
+	md   resource.Metadata
+	spec DiskHealthSpec
+}
+
+// DiskHealthSpec describes the disk health.
+type DiskHealthSpec struct {
+	DevPath   string `yaml:"devPath"`
+	Transport string `yaml:"transport"`
+	// Temperature in degrees Celsius.
+	Temperature int `yaml:"temperature"`
+	// WearLevel is the percentage of the rated device life used.
+	WearLevel          int    `yaml:"wearLevel"`
+	ReallocatedSectors uint64 `yaml:"reallocatedSectors"`
+	PendingSectors     uint64 `yaml:"pendingSectors"`
+	MediaErrors        uint64 `yaml:"mediaErrors"`
+	PowerOnHours       uint64 `yaml:"powerOnHours"`
+	CriticalWarning    uint8  `yaml:"criticalWarning,omitempty"`
+	Healthy            bool   `yaml:"healthy"`
+	// Warnings lists the thresholds which are crossed.
+	Warnings []string `yaml:"warnings,omitempty"`
+}
+
+// NewDiskHealth initializes a DiskHealth resource.
+func NewDiskHealth(namespace resource.Namespace, id resource.ID) *DiskHealth {
+	r := &DiskHealth{
+		md:   resource.NewMetadata(namespace, DiskHealthType, id, resource.VersionUndefined),
+		spec: DiskHealthSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *DiskHealth) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *DiskHealth) Spec() interface{} {
+	return r.spec
+}
+
+func (r *DiskHealth) String() string {
+	return fmt.Sprintf("block.DiskHealth(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *DiskHealth) DeepCopy() resource.Resource {
+	spec := r.spec
+	spec.Warnings = append([]string(nil), r.spec.Warnings...)
+
+	return &DiskHealth{
+		md:   r.md,
+		spec: spec,
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *DiskHealth) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             DiskHealthType,
+		Aliases:          []resource.Type{"diskhealth", "dh"},
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Healthy",
+				JSONPath: "{.healthy}",
+			},
+			{
+				Name:     "Temperature",
+				JSONPath: "{.temperature}",
+			},
+			{
+				Name:     "Wear",
+				JSONPath: "{.wearLevel}",
+			},
+			{
+				Name:     "Reallocated",
+				JSONPath: "{.reallocatedSectors}",
+			},
+			{
+				Name:     "Media Errors",
+				JSONPath: "{.mediaErrors}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *DiskHealth) TypedSpec() *DiskHealthSpec {
+	return &r.spec
+}
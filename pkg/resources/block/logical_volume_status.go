@@ -0,0 +1,106 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block
+
+import (
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// LogicalVolumeStatusType is type of LogicalVolumeStatus resource.
+const LogicalVolumeStatusType = resource.Type("LogicalVolumeStatuses.block.talos.dev")
+
+// LogicalVolumeStatus resource describes the state of the LVM logical volume.
+//
+// Resource ID is `<volume group>/<logical volume>`.
+type LogicalVolumeStatus struct {
+	md   resource.Metadata
+	spec LogicalVolumeStatusSpec
+}
+
+// LogicalVolumeStatusSpec describes the logical volume state.
+type LogicalVolumeStatusSpec struct {
+	VolumeGroup string `yaml:"volumeGroup"`
+	Name        string `yaml:"name"`
+	DevPath     string `yaml:"devPath"`
+	Size        uint64 `yaml:"size"`
+	// SegmentType is `linear`, `raid1`, `raid10`, etc.
+	SegmentType string `yaml:"segmentType"`
+	// Degraded is set if some of the RAID devices are missing or failed.
+	Degraded bool   `yaml:"degraded"`
+	Health   string `yaml:"health,omitempty"`
+	// SyncAction is the current RAID sync action: `idle`, `resync`, `recover`, etc.
+	SyncAction string `yaml:"syncAction,omitempty"`
+	// SyncPercent is the RAID sync (rebuild) progress.
+	SyncPercent float64 `yaml:"syncPercent,omitempty"`
+}
+
+// NewLogicalVolumeStatus initializes a LogicalVolumeStatus resource.
+func NewLogicalVolumeStatus(namespace resource.Namespace, id resource.ID) *LogicalVolumeStatus {
+	r := &LogicalVolumeStatus{
+		md:   resource.NewMetadata(namespace, LogicalVolumeStatusType, id, resource.VersionUndefined),
+		spec: LogicalVolumeStatusSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *LogicalVolumeStatus) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *LogicalVolumeStatus) Spec() interface{} {
+	return r.spec
+}
+
+func (r *LogicalVolumeStatus) String() string {
+	return fmt.Sprintf("block.LogicalVolumeStatus(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *LogicalVolumeStatus) DeepCopy() resource.Resource {
+	return &LogicalVolumeStatus{
+		md:   r.md,
+		spec: r.spec,
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *LogicalVolumeStatus) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             LogicalVolumeStatusType,
+		Aliases:          []resource.Type{"lv", "lvs", "logicalvolume", "logicalvolumes"},
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Type",
+				JSONPath: "{.segmentType}",
+			},
+			{
+				Name:     "Size",
+				JSONPath: "{.size}",
+			},
+			{
+				Name:     "Degraded",
+				JSONPath: "{.degraded}",
+			},
+			{
+				Name:     "Sync",
+				JSONPath: "{.syncPercent}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *LogicalVolumeStatus) TypedSpec() *LogicalVolumeStatusSpec {
+	return &r.spec
+}
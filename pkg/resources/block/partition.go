@@ -0,0 +1,111 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package block
+
+import (
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// PartitionType is type of Partition resource.
+const PartitionType = resource.Type("Partitions.block.talos.dev")
+
+// Partition resource describes a partition of the block device.
+//
+// Resource ID is the partition device name (e.g. `sda1`).
+type Partition struct {
+	md   resource.Metadata
+	spec PartitionSpec
+}
+
+// PartitionSpec describes the partition.
+type PartitionSpec struct {
+	DevPath string `yaml:"devPath"`
+	// Parent is the ID of the BlockDevice.
+	Parent string `yaml:"parent"`
+	Number int    `yaml:"number"`
+	Size   uint64 `yaml:"size"`
+	// Label is the partition name in the partition table (e.g. `EPHEMERAL`).
+	Label    string `yaml:"label,omitempty"`
+	UUID     string `yaml:"uuid,omitempty"`
+	TypeUUID string `yaml:"typeUUID,omitempty"`
+	ReadOnly bool   `yaml:"readOnly"`
+
+	Filesystem      string `yaml:"filesystem,omitempty"`
+	FilesystemLabel string `yaml:"filesystemLabel,omitempty"`
+	MountPoint      string `yaml:"mountPoint,omitempty"`
+}
+
+// NewPartition initializes a Partition resource.
+func NewPartition(namespace resource.Namespace, id resource.ID) *Partition {
+	r := &Partition{
+		md:   resource.NewMetadata(namespace, PartitionType, id, resource.VersionUndefined),
+		spec: PartitionSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *Partition) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *Partition) Spec() interface{} {
+	return r.spec
+}
+
+func (r *Partition) String() string {
+	return fmt.Sprintf("block.Partition(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *Partition) DeepCopy() resource.Resource {
+	return &Partition{
+		md:   r.md,
+		spec: r.spec,
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *Partition) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             PartitionType,
+		Aliases:          []resource.Type{"partition", "partitions"},
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Parent",
+				JSONPath: "{.parent}",
+			},
+			{
+				Name:     "Label",
+				JSONPath: "{.label}",
+			},
+			{
+				Name:     "Size",
+				JSONPath: "{.size}",
+			},
+			{
+				Name:     "Filesystem",
+				JSONPath: "{.filesystem}",
+			},
+			{
+				Name:     "Mountpoint",
+				JSONPath: "{.mountPoint}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *Partition) TypedSpec() *PartitionSpec {
+	return &r.spec
+}
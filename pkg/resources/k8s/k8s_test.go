@@ -28,6 +28,7 @@ func TestRegisterResource(t *testing.T) {
 		&k8s.Endpoint{},
 		&k8s.ManifestStatus{},
 		&k8s.Manifest{},
+		&k8s.NodeCondition{},
 		&k8s.Nodename{},
 		&k8s.SecretsStatus{},
 		&k8s.StaticPodStatus{},
@@ -0,0 +1,102 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package k8s
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// NodeConditionType is type of NodeCondition resource.
+const NodeConditionType = resource.Type("NodeConditions.kubernetes.talos.dev")
+
+// NodeCondition IDs, each ID is the Kubernetes Node condition type.
+//
+// Condition status is true when the problem is present.
+const (
+	NodeConditionServiceUnhealthy      = resource.ID("TalosServiceUnhealthy")
+	NodeConditionTimeNotSynced         = resource.ID("TalosTimeNotSynced")
+	NodeConditionEphemeralDiskPressure = resource.ID("TalosEphemeralDiskPressure")
+	NodeConditionNetworkLinkDown       = resource.ID("TalosNetworkLinkDown")
+)
+
+// NodeCondition resource holds the state of the node-level problem mirrored to the Kubernetes Node conditions.
+type NodeCondition struct {
+	md   resource.Metadata
+	spec NodeConditionSpec
+}
+
+// NodeConditionSpec describes the node-level problem.
+type NodeConditionSpec struct {
+	Status             bool      `yaml:"status"`
+	Reason             string    `yaml:"reason"`
+	Message            string    `yaml:"message"`
+	LastTransitionTime time.Time `yaml:"lastTransitionTime"`
+}
+
+// NewNodeCondition initializes a NodeCondition resource.
+func NewNodeCondition(namespace resource.Namespace, id resource.ID) *NodeCondition {
+	r := &NodeCondition{
+		md:   resource.NewMetadata(namespace, NodeConditionType, id, resource.VersionUndefined),
+		spec: NodeConditionSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *NodeCondition) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *NodeCondition) Spec() interface{} {
+	return r.spec
+}
+
+func (r *NodeCondition) String() string {
+	return fmt.Sprintf("k8s.NodeCondition(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *NodeCondition) DeepCopy() resource.Resource {
+	return &NodeCondition{
+		md:   r.md,
+		spec: r.spec,
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *NodeCondition) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             NodeConditionType,
+		Aliases:          []resource.Type{},
+		DefaultNamespace: ControlPlaneNamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Status",
+				JSONPath: "{.status}",
+			},
+			{
+				Name:     "Reason",
+				JSONPath: "{.reason}",
+			},
+			{
+				Name:     "Message",
+				JSONPath: "{.message}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *NodeCondition) TypedSpec() *NodeConditionSpec {
+	return &r.spec
+}
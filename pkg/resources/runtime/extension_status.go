@@ -0,0 +1,103 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// ExtensionStatusType is type of ExtensionStatus resource.
+const ExtensionStatusType = resource.Type("ExtensionStatuses.runtime.talos.dev")
+
+// ExtensionStatus resource holds the status of the system extension layered over the rootfs.
+//
+// Resource ID is the extension name (e.g. `intel-ucode`).
+type ExtensionStatus struct {
+	md   resource.Metadata
+	spec ExtensionStatusSpec
+}
+
+// ExtensionStatusSpec describes the status of the system extension.
+type ExtensionStatusSpec struct {
+	// Image is the extension image file name.
+	Image string `yaml:"image"`
+	// Version of the extension.
+	Version string `yaml:"version"`
+	// Author of the extension.
+	Author string `yaml:"author,omitempty"`
+	// Description of the extension.
+	Description string `yaml:"description,omitempty"`
+	// Digest is the SHA-256 digest of the extension image.
+	Digest string `yaml:"digest"`
+	// Loaded is set if the extension was verified and layered over the rootfs.
+	Loaded bool `yaml:"loaded"`
+	// Error is the error encountered while verifying or mounting the extension.
+	Error string `yaml:"error,omitempty"`
+}
+
+// NewExtensionStatus initializes an ExtensionStatus resource.
+func NewExtensionStatus(namespace resource.Namespace, id resource.ID) *ExtensionStatus {
+	r := &ExtensionStatus{
+		md:   resource.NewMetadata(namespace, ExtensionStatusType, id, resource.VersionUndefined),
+		spec: ExtensionStatusSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *ExtensionStatus) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *ExtensionStatus) Spec() interface{} {
+	return r.spec
+}
+
+func (r *ExtensionStatus) String() string {
+	return fmt.Sprintf("runtime.ExtensionStatus(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *ExtensionStatus) DeepCopy() resource.Resource {
+	return &ExtensionStatus{
+		md:   r.md,
+		spec: r.spec,
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *ExtensionStatus) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             ExtensionStatusType,
+		Aliases:          []resource.Type{"extensions", "extension"},
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Version",
+				JSONPath: "{.version}",
+			},
+			{
+				Name:     "Loaded",
+				JSONPath: "{.loaded}",
+			},
+			{
+				Name:     "Error",
+				JSONPath: "{.error}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *ExtensionStatus) TypedSpec() *ExtensionStatusSpec {
+	return &r.spec
+}
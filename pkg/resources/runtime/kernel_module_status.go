@@ -0,0 +1,95 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// KernelModuleStatusType is type of KernelModuleStatus resource.
+const KernelModuleStatusType = resource.Type("KernelModuleStatuses.runtime.talos.dev")
+
+// KernelModuleStatus resource holds the status of the kernel module loaded by Talos.
+//
+// Resource ID is the kernel module name (e.g. `nvme_tcp`).
+type KernelModuleStatus struct {
+	md   resource.Metadata
+	spec KernelModuleStatusSpec
+}
+
+// KernelModuleStatusSpec describes the status of the kernel module.
+type KernelModuleStatusSpec struct {
+	// Parameters the module was loaded with.
+	Parameters []string `yaml:"parameters"`
+	// Loaded is set if the module was loaded successfully (or it is built into the kernel).
+	Loaded bool `yaml:"loaded"`
+	// Error is the last error encountered while loading the module.
+	Error string `yaml:"error,omitempty"`
+}
+
+// NewKernelModuleStatus initializes a KernelModuleStatus resource.
+func NewKernelModuleStatus(namespace resource.Namespace, id resource.ID) *KernelModuleStatus {
+	r := &KernelModuleStatus{
+		md:   resource.NewMetadata(namespace, KernelModuleStatusType, id, resource.VersionUndefined),
+		spec: KernelModuleStatusSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *KernelModuleStatus) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *KernelModuleStatus) Spec() interface{} {
+	return r.spec
+}
+
+func (r *KernelModuleStatus) String() string {
+	return fmt.Sprintf("runtime.KernelModuleStatus(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *KernelModuleStatus) DeepCopy() resource.Resource {
+	return &KernelModuleStatus{
+		md: r.md,
+		spec: KernelModuleStatusSpec{
+			Parameters: append([]string(nil), r.spec.Parameters...),
+			Loaded:     r.spec.Loaded,
+			Error:      r.spec.Error,
+		},
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *KernelModuleStatus) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             KernelModuleStatusType,
+		Aliases:          []resource.Type{},
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Loaded",
+				JSONPath: "{.loaded}",
+			},
+			{
+				Name:     "Error",
+				JSONPath: "{.error}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *KernelModuleStatus) TypedSpec() *KernelModuleStatusSpec {
+	return &r.spec
+}
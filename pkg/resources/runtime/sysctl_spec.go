@@ -0,0 +1,82 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// SysctlSpecType is type of SysctlSpec resource.
+const SysctlSpecType = resource.Type("SysctlSpecs.runtime.talos.dev")
+
+// SysctlSpec resource holds the desired value of the kernel parameter.
+//
+// Resource ID is the sysctl key (e.g. `net.ipv4.ip_forward`).
+type SysctlSpec struct {
+	md   resource.Metadata
+	spec SysctlSpecSpec
+}
+
+// SysctlSpecSpec describes the desired value of the kernel parameter.
+type SysctlSpecSpec struct {
+	Value string `yaml:"value"`
+}
+
+// NewSysctlSpec initializes a SysctlSpec resource.
+func NewSysctlSpec(namespace resource.Namespace, id resource.ID) *SysctlSpec {
+	r := &SysctlSpec{
+		md:   resource.NewMetadata(namespace, SysctlSpecType, id, resource.VersionUndefined),
+		spec: SysctlSpecSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *SysctlSpec) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *SysctlSpec) Spec() interface{} {
+	return r.spec
+}
+
+func (r *SysctlSpec) String() string {
+	return fmt.Sprintf("runtime.SysctlSpec(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *SysctlSpec) DeepCopy() resource.Resource {
+	return &SysctlSpec{
+		md:   r.md,
+		spec: r.spec,
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *SysctlSpec) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             SysctlSpecType,
+		Aliases:          []resource.Type{},
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Value",
+				JSONPath: "{.value}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *SysctlSpec) TypedSpec() *SysctlSpecSpec {
+	return &r.spec
+}
@@ -0,0 +1,103 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// SysctlStatusType is type of SysctlStatus resource.
+const SysctlStatusType = resource.Type("SysctlStatuses.runtime.talos.dev")
+
+// SysctlStatus resource holds the status of the kernel parameter managed by Talos.
+//
+// Resource ID is the sysctl key (e.g. `net.ipv4.ip_forward`).
+type SysctlStatus struct {
+	md   resource.Metadata
+	spec SysctlStatusSpec
+}
+
+// SysctlStatusSpec describes the status of the kernel parameter.
+type SysctlStatusSpec struct {
+	// Value applied by Talos.
+	Value string `yaml:"value"`
+	// CurrentValue as observed on the last check.
+	CurrentValue string `yaml:"currentValue"`
+	// OriginalValue before Talos applied the value, restored when the parameter is removed from the configuration.
+	OriginalValue string `yaml:"originalValue"`
+	// Mismatch is set if the kernel parameter was changed outside of Talos.
+	Mismatch bool `yaml:"mismatch"`
+	// Error is set if the kernel parameter failed to be applied.
+	Error string `yaml:"error,omitempty"`
+}
+
+// NewSysctlStatus initializes a SysctlStatus resource.
+func NewSysctlStatus(namespace resource.Namespace, id resource.ID) *SysctlStatus {
+	r := &SysctlStatus{
+		md:   resource.NewMetadata(namespace, SysctlStatusType, id, resource.VersionUndefined),
+		spec: SysctlStatusSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *SysctlStatus) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *SysctlStatus) Spec() interface{} {
+	return r.spec
+}
+
+func (r *SysctlStatus) String() string {
+	return fmt.Sprintf("runtime.SysctlStatus(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *SysctlStatus) DeepCopy() resource.Resource {
+	return &SysctlStatus{
+		md:   r.md,
+		spec: r.spec,
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *SysctlStatus) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             SysctlStatusType,
+		Aliases:          []resource.Type{},
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Value",
+				JSONPath: "{.value}",
+			},
+			{
+				Name:     "Current",
+				JSONPath: "{.currentValue}",
+			},
+			{
+				Name:     "Mismatch",
+				JSONPath: "{.mismatch}",
+			},
+			{
+				Name:     "Error",
+				JSONPath: "{.error}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *SysctlStatus) TypedSpec() *SysctlStatusSpec {
+	return &r.spec
+}
@@ -0,0 +1,87 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secrets
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// CertificateRenewalType is type of CertificateRenewal resource.
+const CertificateRenewalType = resource.Type("CertificateRenewals.secrets.talos.dev")
+
+// CertificateRenewalID is a resource ID of singleton instance.
+const CertificateRenewalID = resource.ID("renewal")
+
+// CertificateRenewal resource is updated when the certificates renewal is requested via the API.
+//
+// Controllers which issue certificates use it as an input to regenerate the certificates.
+type CertificateRenewal struct {
+	md   resource.Metadata
+	spec CertificateRenewalSpec
+}
+
+// CertificateRenewalSpec describes the last renewal request.
+type CertificateRenewalSpec struct {
+	RequestedAt time.Time `yaml:"requestedAt"`
+}
+
+// NewCertificateRenewal initializes a CertificateRenewal resource.
+func NewCertificateRenewal() *CertificateRenewal {
+	r := &CertificateRenewal{
+		md:   resource.NewMetadata(NamespaceName, CertificateRenewalType, CertificateRenewalID, resource.VersionUndefined),
+		spec: CertificateRenewalSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *CertificateRenewal) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *CertificateRenewal) Spec() interface{} {
+	return r.spec
+}
+
+func (r *CertificateRenewal) String() string {
+	return fmt.Sprintf("secrets.CertificateRenewal(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *CertificateRenewal) DeepCopy() resource.Resource {
+	return &CertificateRenewal{
+		md:   r.md,
+		spec: r.spec,
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *CertificateRenewal) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             CertificateRenewalType,
+		Aliases:          []resource.Type{},
+		DefaultNamespace: NamespaceName,
+		Sensitivity:      meta.NonSensitive,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Requested At",
+				JSONPath: "{.requestedAt}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *CertificateRenewal) TypedSpec() *CertificateRenewalSpec {
+	return &r.spec
+}
@@ -0,0 +1,106 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secrets
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// CertificateStatusType is type of CertificateStatus resource.
+const CertificateStatusType = resource.Type("CertificateStatuses.secrets.talos.dev")
+
+// CertificateStatus resource describes a certificate managed by Talos.
+//
+// CertificateStatus doesn't contain any secret material, so it's not sensitive.
+type CertificateStatus struct {
+	md   resource.Metadata
+	spec CertificateStatusSpec
+}
+
+// CertificateStatusSpec describes certificate attributes.
+type CertificateStatusSpec struct {
+	// Component is the set of certificates the certificate belongs to: kubernetes, etcd or talos.
+	Component string `yaml:"component"`
+
+	Subject     string    `yaml:"subject"`
+	Issuer      string    `yaml:"issuer"`
+	DNSNames    []string  `yaml:"dnsNames,omitempty"`
+	IPAddresses []string  `yaml:"ipAddresses,omitempty"`
+	IsCA        bool      `yaml:"isCA"`
+	NotBefore   time.Time `yaml:"notBefore"`
+	NotAfter    time.Time `yaml:"notAfter"`
+}
+
+// NewCertificateStatus initializes a CertificateStatus resource.
+func NewCertificateStatus(id resource.ID) *CertificateStatus {
+	r := &CertificateStatus{
+		md:   resource.NewMetadata(NamespaceName, CertificateStatusType, id, resource.VersionUndefined),
+		spec: CertificateStatusSpec{},
+	}
+
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (r *CertificateStatus) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+// Spec implements resource.Resource.
+func (r *CertificateStatus) Spec() interface{} {
+	return r.spec
+}
+
+func (r *CertificateStatus) String() string {
+	return fmt.Sprintf("secrets.CertificateStatus(%q)", r.md.ID())
+}
+
+// DeepCopy implements resource.Resource.
+func (r *CertificateStatus) DeepCopy() resource.Resource {
+	specCopy := r.spec
+
+	specCopy.DNSNames = append([]string(nil), r.spec.DNSNames...)
+	specCopy.IPAddresses = append([]string(nil), r.spec.IPAddresses...)
+
+	return &CertificateStatus{
+		md:   r.md,
+		spec: specCopy,
+	}
+}
+
+// ResourceDefinition implements meta.ResourceDefinitionProvider interface.
+func (r *CertificateStatus) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             CertificateStatusType,
+		Aliases:          []resource.Type{"certificate", "certificates"},
+		DefaultNamespace: NamespaceName,
+		Sensitivity:      meta.NonSensitive,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Component",
+				JSONPath: "{.component}",
+			},
+			{
+				Name:     "Subject",
+				JSONPath: "{.subject}",
+			},
+			{
+				Name:     "Not After",
+				JSONPath: "{.notAfter}",
+			},
+		},
+	}
+}
+
+// TypedSpec allows to access the Spec with the proper type.
+func (r *CertificateStatus) TypedSpec() *CertificateStatusSpec {
+	return &r.spec
+}
@@ -33,9 +33,10 @@ type Root struct {
 
 // RootOSSpec describes operating system CA.
 type RootOSSpec struct {
-	CA              *x509.PEMEncodedCertificateAndKey `yaml:"ca"`
-	CertSANIPs      []netaddr.IP                      `yaml:"certSANIPs"`
-	CertSANDNSNames []string                          `yaml:"certSANDNSNames"`
+	CA              *x509.PEMEncodedCertificateAndKey   `yaml:"ca"`
+	AcceptedCAs     []*x509.PEMEncodedCertificateAndKey `yaml:"acceptedCAs"`
+	CertSANIPs      []netaddr.IP                        `yaml:"certSANIPs"`
+	CertSANDNSNames []string                            `yaml:"certSANDNSNames"`
 
 	Token string `yaml:"token"`
 }
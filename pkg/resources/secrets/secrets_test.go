@@ -26,6 +26,8 @@ func TestRegisterResource(t *testing.T) {
 
 	for _, resource := range []resource.Resource{
 		&secrets.API{},
+		&secrets.CertificateRenewal{},
+		&secrets.CertificateStatus{},
 		&secrets.Etcd{},
 		&secrets.Kubernetes{},
 		&secrets.Root{},
@@ -0,0 +1,117 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package report implements signing and verification of the disk wipe reports.
+package report
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	stdlibx509 "crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/talos-systems/crypto/x509"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+)
+
+// Sign serializes and signs the wipe report with the node API certificate.
+func Sign(data *machine.WipeReportData, certAndKey *x509.PEMEncodedCertificateAndKey) (*machine.WipeReport, error) {
+	marshaled, err := proto.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := certAndKey.GetKey()
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+
+	var signature []byte
+
+	if _, ok = signer.Public().(ed25519.PublicKey); ok {
+		signature, err = signer.Sign(rand.Reader, marshaled, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(marshaled)
+
+		signature, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error signing wipe report: %w", err)
+	}
+
+	return &machine.WipeReport{
+		Data:        marshaled,
+		Signature:   signature,
+		Certificate: certAndKey.Crt,
+	}, nil
+}
+
+// Verify verifies the report signature and that the signing certificate is issued by the CA.
+//
+// The certificate is verified as of the report completion time, so old reports can be verified
+// after the node certificate expires.
+func Verify(report *machine.WipeReport, ca []byte) (*machine.WipeReportData, error) {
+	block, _ := pem.Decode(report.Certificate)
+	if block == nil {
+		return nil, errors.New("failed to parse report certificate PEM")
+	}
+
+	cert, err := stdlibx509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report certificate: %w", err)
+	}
+
+	var algorithm stdlibx509.SignatureAlgorithm
+
+	switch cert.PublicKey.(type) {
+	case ed25519.PublicKey:
+		algorithm = stdlibx509.PureEd25519
+	case *ecdsa.PublicKey:
+		algorithm = stdlibx509.ECDSAWithSHA256
+	case *rsa.PublicKey:
+		algorithm = stdlibx509.SHA256WithRSA
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", cert.PublicKey)
+	}
+
+	if err = cert.CheckSignature(algorithm, report.Data, report.Signature); err != nil {
+		return nil, fmt.Errorf("report signature is invalid: %w", err)
+	}
+
+	var data machine.WipeReportData
+
+	if err = proto.Unmarshal(report.Data, &data); err != nil {
+		return nil, err
+	}
+
+	roots := stdlibx509.NewCertPool()
+
+	if !roots.AppendCertsFromPEM(ca) {
+		return nil, errors.New("failed to parse CA certificate")
+	}
+
+	if _, err = cert.Verify(stdlibx509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: data.GetCompleted().AsTime(),
+		KeyUsages:   []stdlibx509.ExtKeyUsage{stdlibx509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, fmt.Errorf("report certificate is not trusted: %w", err)
+	}
+
+	return &data, nil
+}
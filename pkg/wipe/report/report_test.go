@@ -0,0 +1,63 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package report_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/talos-systems/crypto/x509"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/talos-systems/talos/pkg/machinery/api/machine"
+	"github.com/talos-systems/talos/pkg/wipe/report"
+)
+
+func TestSignVerify(t *testing.T) {
+	ca, err := x509.NewSelfSignedCertificateAuthority(x509.Organization("talos"))
+	require.NoError(t, err)
+
+	keyPair, err := x509.NewKeyPair(ca, x509.Organization("talos"), x509.CommonName("node"))
+	require.NoError(t, err)
+
+	otherCA, err := x509.NewSelfSignedCertificateAuthority(x509.Organization("other"))
+	require.NoError(t, err)
+
+	completed := time.Now()
+
+	data := &machine.WipeReportData{
+		Hostname:  "node",
+		Mode:      machine.WipeRequest_ZERO,
+		Started:   timestamppb.New(completed.Add(-time.Hour)),
+		Completed: timestamppb.New(completed),
+		Disks: []*machine.WipeDiskReport{
+			{
+				Device: "/dev/sda",
+				Serial: "1234",
+				Size:   1 << 30,
+				Method: "zero-fill",
+			},
+		},
+	}
+
+	signed, err := report.Sign(data, x509.NewCertificateAndKeyFromKeyPair(keyPair))
+	require.NoError(t, err)
+
+	verified, err := report.Verify(signed, ca.CrtPEM)
+	require.NoError(t, err)
+
+	assert.Equal(t, "node", verified.Hostname)
+	assert.Equal(t, "1234", verified.Disks[0].Serial)
+
+	_, err = report.Verify(signed, otherCA.CrtPEM)
+	assert.Error(t, err)
+
+	signed.Data[len(signed.Data)-1] ^= 0xff
+
+	_, err = report.Verify(signed, ca.CrtPEM)
+	assert.Error(t, err)
+}